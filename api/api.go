@@ -0,0 +1,9 @@
+// Package api встраивает OpenAPI-спецификацию сервиса в бинарник.
+package api
+
+import _ "embed"
+
+// Spec — openapi.yaml, по которому описаны маршруты Handler.Router.
+//
+//go:embed openapi.yaml
+var Spec []byte
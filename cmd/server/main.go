@@ -3,11 +3,16 @@ package main
 import (
 	"context"
 	"github.com/zxchelik/avito-test-task/internal/httpserver"
+	"os"
 	"os/signal"
 	"syscall"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(os.Args[2:]))
+	}
+
 	server, err := httpserver.NewServer()
 	if err != nil {
 		return
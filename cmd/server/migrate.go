@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pressly/goose/v3"
+	"github.com/zxchelik/avito-test-task/internal/application"
+	"github.com/zxchelik/avito-test-task/internal/infrastructure/pg"
+	"github.com/zxchelik/avito-test-task/pkg/logger"
+)
+
+const migrateUsage = "usage: server migrate up|down|status|redo"
+
+var errUnknownStorage = errors.New("migrate works only with postgres storage")
+
+// runMigrate выполняет подкоманду migrate над Postgres из конфига и возвращает код выхода.
+func runMigrate(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+	command := args[0]
+	switch command {
+	case "up", "down", "status", "redo":
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	cfg := application.MustLoad()
+	log := logger.New(cfg.Env)
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	if err := migrate(ctx, cfg, log, command, os.Stdout); err != nil {
+		log.Error("migrate failed", slog.String("command", command), slog.String("error", err.Error()))
+		return 1
+	}
+
+	return 0
+}
+
+// migrate применяет команду к базе из cfg и печатает результат в w.
+func migrate(ctx context.Context, cfg *application.Config, log *slog.Logger, command string, w io.Writer) error {
+	if cfg.Storage != application.StoragePostgres {
+		return fmt.Errorf("%w, got %q", errUnknownStorage, cfg.Storage)
+	}
+
+	db, err := application.NewDB(ctx, &cfg.Postgres, log)
+	if err != nil {
+		return err
+	}
+	defer db.Pool.Close()
+
+	migrator, err := pg.NewMigrator(db.Pool)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	var results []*goose.MigrationResult
+	switch command {
+	case "up":
+		results, err = migrator.Up(ctx)
+	case "down":
+		var res *goose.MigrationResult
+		if res, err = migrator.Down(ctx); res != nil {
+			results = append(results, res)
+		}
+	case "redo":
+		results, err = migrator.Redo(ctx)
+	case "status":
+		var statuses []*goose.MigrationStatus
+		if statuses, err = migrator.Status(ctx); err == nil {
+			printStatus(w, statuses)
+		}
+	default:
+		return fmt.Errorf("unknown migrate command %q", command)
+	}
+
+	for _, res := range results {
+		fmt.Fprintln(w, res)
+	}
+	if err != nil {
+		return err
+	}
+	if command == "up" && len(results) == 0 {
+		fmt.Fprintln(w, "no migrations to apply")
+	}
+
+	return nil
+}
+
+// printStatus выводит таблицу миграций в формате goose status.
+func printStatus(w io.Writer, statuses []*goose.MigrationStatus) {
+	tw := tabwriter.NewWriter(w, 0, 0, 4, ' ', 0)
+	fmt.Fprintln(tw, "Applied At\tMigration")
+	for _, st := range statuses {
+		appliedAt := "Pending"
+		if st.State == goose.StateApplied {
+			appliedAt = st.AppliedAt.Format(time.DateTime)
+		}
+		fmt.Fprintf(tw, "%s\t%s\n", appliedAt, path.Base(st.Source.Path))
+	}
+	_ = tw.Flush()
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/zxchelik/avito-test-task/internal/application"
+	"github.com/zxchelik/avito-test-task/internal/infrastructure/pg/pgtest"
+)
+
+func TestRunMigrateUsage(t *testing.T) {
+	for _, args := range [][]string{nil, {"sideways"}, {"up", "extra"}} {
+		if code := runMigrate(args); code != 2 {
+			t.Errorf("runMigrate(%q) = %d, want 2", args, code)
+		}
+	}
+}
+
+func TestMigrateRejectsOtherStorage(t *testing.T) {
+	cfg := &application.Config{Storage: application.StorageMemory}
+	err := migrate(context.Background(), cfg, discardLogger(), "up", io.Discard)
+	if !errors.Is(err, errUnknownStorage) {
+		t.Fatalf("migrate with memory storage: got %v, want %v", err, errUnknownStorage)
+	}
+}
+
+func TestMigrateCommands(t *testing.T) {
+	ctx := context.Background()
+	cfg := &application.Config{
+		Storage:  application.StoragePostgres,
+		Postgres: pgtest.Config(t, pgtest.NewDatabase(t)),
+	}
+
+	run := func(command string) string {
+		t.Helper()
+		var out bytes.Buffer
+		if err := migrate(ctx, cfg, discardLogger(), command, &out); err != nil {
+			t.Fatalf("migrate %s: %v", command, err)
+		}
+		return out.String()
+	}
+
+	if out := run("status"); strings.Count(out, "Pending") != 14 {
+		t.Fatalf("status before up: want 14 pending migrations, got:\n%s", out)
+	}
+	if out := run("up"); strings.Count(out, "OK") != 14 {
+		t.Fatalf("up: want 14 applied migrations, got:\n%s", out)
+	}
+	if out := run("up"); !strings.Contains(out, "no migrations to apply") {
+		t.Fatalf("second up: got:\n%s", out)
+	}
+	if out := run("down"); !strings.Contains(out, "down 00014_review_sla_weekday_hours.sql") {
+		t.Fatalf("down: got:\n%s", out)
+	}
+	if out := run("redo"); !strings.Contains(out, "down 00013_forge_identities.sql") || !strings.Contains(out, "up 00013_forge_identities.sql") {
+		t.Fatalf("redo: got:\n%s", out)
+	}
+	if out := run("status"); strings.Count(out, "Pending") != 1 || !strings.Contains(out, "00014_review_sla_weekday_hours.sql") {
+		t.Fatalf("status after down and redo: want only 00014 pending, got:\n%s", out)
+	}
+}
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
@@ -13,10 +13,25 @@ import (
 
 type Config struct {
 	Env      logger.EnvString `yaml:"env" env-default:"local" env-required:"true"`
+	Storage  string           `yaml:"storage" env:"STORAGE" env-default:"postgres"` // postgres | sqlite | memory
 	Postgres `yaml:"postgres"`
+	SQLite   `yaml:"sqlite"`
 	Server   `yaml:"server"`
+	Review   `yaml:"review"`
+	SLA      `yaml:"sla"`
+	Webhooks `yaml:"webhooks"`
+	Forge    `yaml:"forge"`
+	Auth     `yaml:"auth"`
+	OpenAPI  `yaml:"openapi"`
 }
 
+// Хранилища данных.
+const (
+	StoragePostgres = "postgres"
+	StorageSQLite   = "sqlite" // один файл базы, для установки на одной машине
+	StorageMemory   = "memory" // данные живут в памяти процесса и теряются при перезапуске
+)
+
 type Postgres struct {
 	Host              string        `yaml:"host" env:"POSTGRES_HOST" env-required:"true"`
 	Port              string        `yaml:"port" env:"POSTGRES_PORT" env-required:"true"`
@@ -28,12 +43,18 @@ type Postgres struct {
 	MaxConnLifetime   time.Duration `yaml:"maxConnLifetime" env-required:"true"`
 	MaxConnIdleTime   time.Duration `yaml:"maxConnIdleTime" env-required:"true"`
 	HealthCheckPeriod time.Duration `yaml:"healthCheckPeriod" env-required:"true"`
+	AutoMigrate       bool          `yaml:"auto_migrate" env:"AUTO_MIGRATE" env-default:"false"` // применять миграции при старте
 }
 
 func (p *Postgres) DSN() string {
 	return fmt.Sprintf("postgres://%s:%s@%s:%s/%s", p.Username, p.Password, p.Host, p.Port, p.Database)
 }
 
+// SQLite — файл базы для storage: sqlite; миграции применяются при старте.
+type SQLite struct {
+	Path string `yaml:"path" env:"SQLITE_PATH" env-default:"data/reviewer.db"`
+}
+
 type Server struct {
 	Host            string        `yaml:"host" env:"HOST" env-required:"true"`
 	Port            int           `yaml:"port" env:"PORT"  env-required:"true"`
@@ -46,6 +67,57 @@ func (s *Server) Address() string {
 	return fmt.Sprintf("%s:%d", s.Host, s.Port)
 }
 
+// Review — правила ревью.
+type Review struct {
+	MergeRule    string `yaml:"merge_rule" env:"MERGE_RULE" env-default:"none"` // none | all_approved | min_approvals
+	MinApprovals int    `yaml:"min_approvals" env:"MERGE_MIN_APPROVALS" env-default:"1"`
+	// SelectorSeed — seed стратегии seeded; одинаковый на всех инстансах, чтобы выбор совпадал.
+	SelectorSeed uint64 `yaml:"selector_seed" env:"REVIEWER_SELECTOR_SEED" env-default:"0"`
+}
+
+// SLA — фоновая проверка SLA ревью.
+type SLA struct {
+	Enabled  bool          `yaml:"enabled" env:"SLA_ENABLED" env-default:"true"`
+	Interval time.Duration `yaml:"interval" env:"SLA_INTERVAL" env-default:"5m"`
+}
+
+// Webhooks — доставка исходящих вебхуков из outbox.
+type Webhooks struct {
+	Enabled     bool          `yaml:"enabled" env:"WEBHOOKS_ENABLED" env-default:"true"`
+	Interval    time.Duration `yaml:"interval" env:"WEBHOOKS_INTERVAL" env-default:"5s"`
+	BatchSize   int           `yaml:"batch_size" env:"WEBHOOKS_BATCH_SIZE" env-default:"50"`
+	SendTimeout time.Duration `yaml:"timeout" env:"WEBHOOKS_TIMEOUT" env-default:"10s"`
+	MaxAttempts int           `yaml:"max_attempts" env:"WEBHOOKS_MAX_ATTEMPTS" env-default:"8"`
+}
+
+// Forge — приём вебхуков PR с GitHub и GitLab; пустой секрет отключает приём с хостинга.
+type Forge struct {
+	GitHubSecret string `yaml:"github_secret" env:"GITHUB_WEBHOOK_SECRET"`
+	GitLabToken  string `yaml:"gitlab_token" env:"GITLAB_WEBHOOK_TOKEN"`
+}
+
+// Auth — аутентификация API bearer-токенами.
+type Auth struct {
+	Enabled    bool          `yaml:"enabled" env:"AUTH_ENABLED" env-default:"true"` // выключить можно только в env local и dev
+	AdminToken string        `yaml:"admin_token" env:"AUTH_ADMIN_TOKEN"`            // статический токен роли admin
+	Tokens     []StaticToken `yaml:"tokens"`
+	JWKSFile   string        `yaml:"jwks_file" env:"AUTH_JWKS_FILE"` // ключи HS256/RS256 для JWT; пусто — JWT не принимаются
+	Issuer     string        `yaml:"issuer" env:"AUTH_ISSUER"`
+	Audience   string        `yaml:"audience" env:"AUTH_AUDIENCE"`
+}
+
+// OpenAPI — проверка запросов и ответов по встроенной спецификации; для dev-окружений.
+type OpenAPI struct {
+	Validate bool `yaml:"validate" env:"OPENAPI_VALIDATE"`
+}
+
+// StaticToken — заранее выданный токен.
+type StaticToken struct {
+	Token   string `yaml:"token"`
+	Subject string `yaml:"subject"` // users.id
+	Role    string `yaml:"role"`    // admin | team_lead | user
+}
+
 func MustLoad() *Config {
 	_ = godotenv.Load()
 
@@ -0,0 +1,32 @@
+package absence
+
+import "time"
+
+type AbsenceDTO struct {
+	ID          int64      `json:"id"`
+	UserID      string     `json:"user_id"`
+	StartsAt    time.Time  `json:"starts_at"`
+	EndsAt      time.Time  `json:"ends_at"`
+	Reason      string     `json:"reason"`
+	CancelledAt *time.Time `json:"cancelled_at,omitempty"`
+}
+
+type AbsenceAddRequest struct {
+	UserID   string    `json:"user_id"`
+	StartsAt time.Time `json:"starts_at"` // RFC 3339
+	EndsAt   time.Time `json:"ends_at"`   // RFC 3339, не включительно
+	Reason   string    `json:"reason"`    // vacation, sick leave, ...
+}
+
+type AbsenceCancelRequest struct {
+	ID int64 `json:"id"`
+}
+
+type AbsenceResponse struct {
+	Absence AbsenceDTO `json:"absence"`
+}
+
+type AbsencesResponse struct {
+	UserID   string       `json:"user_id"`
+	Absences []AbsenceDTO `json:"absences"`
+}
@@ -0,0 +1,98 @@
+package absence
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/zxchelik/avito-test-task/internal/httpserver/handlers/shared"
+	modelabsence "github.com/zxchelik/avito-test-task/internal/model/absence"
+	srvabsence "github.com/zxchelik/avito-test-task/internal/service/absence"
+	"log/slog"
+	"net/http"
+)
+
+type Handler struct {
+	svc *srvabsence.Service
+	log *slog.Logger
+}
+
+func New(svc *srvabsence.Service, log *slog.Logger) *Handler {
+	return &Handler{svc: svc, log: log}
+}
+
+// Register регистрирует маршруты отсутствий пользователей.
+func (h *Handler) Register(r chi.Router) {
+	r.Post("/users/absences/add", h.handleAbsenceAdd)
+	r.Get("/users/absences/list", h.handleAbsenceList)
+	r.Post("/users/absences/cancel", h.handleAbsenceCancel)
+}
+
+// POST /users/absences/add
+func (h *Handler) handleAbsenceAdd(w http.ResponseWriter, r *http.Request) {
+	var req AbsenceAddRequest
+	if !shared.DecodeJSON(w, r, &req) {
+		return
+	}
+	var v shared.Validation
+	v.RequireBody("user_id", req.UserID == "")
+	v.RequireBody("starts_at", req.StartsAt.IsZero())
+	v.RequireBody("ends_at", req.EndsAt.IsZero())
+	if v.Write(w, r) {
+		return
+	}
+
+	absence, err := h.svc.Add(r.Context(), &modelabsence.Absence{
+		UserID:   req.UserID,
+		StartsAt: req.StartsAt,
+		EndsAt:   req.EndsAt,
+		Reason:   req.Reason,
+	})
+	if err != nil {
+		shared.WriteServiceError(w, r, err, h.log)
+		return
+	}
+
+	shared.WriteJSON(w, http.StatusCreated, AbsenceResponse{Absence: toAbsenceDTO(absence)})
+}
+
+// GET /users/absences/list?user_id=...&include_cancelled=true
+func (h *Handler) handleAbsenceList(w http.ResponseWriter, r *http.Request) {
+	userID := r.URL.Query().Get("user_id")
+	var v shared.Validation
+	v.RequireQuery("user_id", userID == "")
+	if v.Write(w, r) {
+		return
+	}
+	includeCancelled := r.URL.Query().Get("include_cancelled") == "true"
+
+	absences, err := h.svc.List(r.Context(), userID, includeCancelled)
+	if err != nil {
+		shared.WriteServiceError(w, r, err, h.log)
+		return
+	}
+
+	resp := AbsencesResponse{
+		UserID:   userID,
+		Absences: toAbsenceDTOs(absences),
+	}
+	shared.WriteJSON(w, http.StatusOK, resp)
+}
+
+// POST /users/absences/cancel
+func (h *Handler) handleAbsenceCancel(w http.ResponseWriter, r *http.Request) {
+	var req AbsenceCancelRequest
+	if !shared.DecodeJSON(w, r, &req) {
+		return
+	}
+	var v shared.Validation
+	v.RequireBody("id", req.ID == 0)
+	if v.Write(w, r) {
+		return
+	}
+
+	absence, err := h.svc.Cancel(r.Context(), req.ID)
+	if err != nil {
+		shared.WriteServiceError(w, r, err, h.log)
+		return
+	}
+
+	shared.WriteJSON(w, http.StatusOK, AbsenceResponse{Absence: toAbsenceDTO(absence)})
+}
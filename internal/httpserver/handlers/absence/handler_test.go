@@ -0,0 +1,167 @@
+package absence_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	absencehandlers "github.com/zxchelik/avito-test-task/internal/httpserver/handlers/absence"
+	"github.com/zxchelik/avito-test-task/internal/httpserver/handlers/shared"
+	modelauth "github.com/zxchelik/avito-test-task/internal/model/auth"
+	"github.com/zxchelik/avito-test-task/internal/service"
+	srvabsence "github.com/zxchelik/avito-test-task/internal/service/absence"
+	"github.com/zxchelik/avito-test-task/internal/service/servicetest"
+)
+
+var now = time.Date(2025, 3, 3, 10, 0, 0, 0, time.UTC)
+
+// newRouter — маршруты отсутствий поверх хранилища в памяти; вызывающий берётся
+// из заголовков X-Subject и X-Role, без них запрос анонимный.
+func newRouter(t *testing.T) http.Handler {
+	t.Helper()
+
+	store := servicetest.NewStore()
+	store.AddTeam(t, servicetest.Team("backend", 1), "u1", "u2")
+	svc := srvabsence.NewService(store.Absences, store.Users).WithClock(func() time.Time { return now })
+	h := absencehandlers.New(svc, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if subject := r.Header.Get("X-Subject"); subject != "" {
+				p := &modelauth.Principal{Subject: subject, Role: modelauth.Role(r.Header.Get("X-Role"))}
+				r = r.WithContext(service.WithPrincipal(r.Context(), p))
+			}
+			next.ServeHTTP(w, r)
+		})
+	})
+	h.Register(r)
+	return r
+}
+
+func post(t *testing.T, h http.Handler, path, subject string, role modelauth.Role, body any) *httptest.ResponseRecorder {
+	t.Helper()
+
+	raw, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("marshal body: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(raw))
+	req.Header.Set("Content-Type", "application/json")
+	if subject != "" {
+		req.Header.Set("X-Subject", subject)
+		req.Header.Set("X-Role", string(role))
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func addRequest(userID string) absencehandlers.AbsenceAddRequest {
+	return absencehandlers.AbsenceAddRequest{
+		UserID:   userID,
+		StartsAt: now,
+		EndsAt:   now.Add(72 * time.Hour),
+		Reason:   "vacation",
+	}
+}
+
+func add(t *testing.T, h http.Handler, subject string, role modelauth.Role, userID string) absencehandlers.AbsenceDTO {
+	t.Helper()
+
+	rec := post(t, h, "/users/absences/add", subject, role, addRequest(userID))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("add for %s as %s: status %d: %s", userID, subject, rec.Code, rec.Body)
+	}
+	var resp absencehandlers.AbsenceResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	return resp.Absence
+}
+
+func wantError(t *testing.T, rec *httptest.ResponseRecorder, status int, code shared.ErrorCode) {
+	t.Helper()
+
+	if rec.Code != status {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, status, rec.Body)
+	}
+	var resp shared.ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if resp.Error.Code != code {
+		t.Fatalf("code = %s, want %s", resp.Error.Code, code)
+	}
+}
+
+func TestAddAllowedForSelfAndAdmin(t *testing.T) {
+	h := newRouter(t)
+
+	if a := add(t, h, "u1", modelauth.RoleUser, "u1"); a.UserID != "u1" {
+		t.Fatalf("own absence: %+v", a)
+	}
+	if a := add(t, h, "admin", modelauth.RoleAdmin, "u2"); a.UserID != "u2" {
+		t.Fatalf("absence added by admin: %+v", a)
+	}
+}
+
+func TestAddForbiddenForOtherUser(t *testing.T) {
+	h := newRouter(t)
+
+	for _, role := range []modelauth.Role{modelauth.RoleUser, modelauth.RoleTeamLead} {
+		rec := post(t, h, "/users/absences/add", "u1", role, addRequest("u2"))
+		wantError(t, rec, http.StatusForbidden, shared.ErrorCodeForbidden)
+	}
+
+	rec := post(t, h, "/users/absences/add", "", "", addRequest("u2"))
+	wantError(t, rec, http.StatusUnauthorized, shared.ErrorCodeUnauthorized)
+}
+
+func TestCancelAllowedForSelfAndAdmin(t *testing.T) {
+	h := newRouter(t)
+
+	for _, caller := range []struct {
+		subject string
+		role    modelauth.Role
+	}{
+		{"u1", modelauth.RoleUser},
+		{"admin", modelauth.RoleAdmin},
+	} {
+		a := add(t, h, "u1", modelauth.RoleUser, "u1")
+		rec := post(t, h, "/users/absences/cancel", caller.subject, caller.role, absencehandlers.AbsenceCancelRequest{ID: a.ID})
+		if rec.Code != http.StatusOK {
+			t.Fatalf("cancel as %s: status %d: %s", caller.subject, rec.Code, rec.Body)
+		}
+		var resp absencehandlers.AbsenceResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		if resp.Absence.CancelledAt == nil {
+			t.Fatalf("cancel as %s: absence not cancelled: %+v", caller.subject, resp.Absence)
+		}
+	}
+}
+
+func TestCancelForbiddenForOtherUser(t *testing.T) {
+	h := newRouter(t)
+	a := add(t, h, "u1", modelauth.RoleUser, "u1")
+
+	rec := post(t, h, "/users/absences/cancel", "u2", modelauth.RoleUser, absencehandlers.AbsenceCancelRequest{ID: a.ID})
+	wantError(t, rec, http.StatusForbidden, shared.ErrorCodeForbidden)
+
+	// отказ ничего не меняет: владелец по-прежнему может отменить отсутствие сам
+	rec = post(t, h, "/users/absences/cancel", "u1", modelauth.RoleUser, absencehandlers.AbsenceCancelRequest{ID: a.ID})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("cancel by owner: status %d: %s", rec.Code, rec.Body)
+	}
+
+	rec = post(t, h, "/users/absences/cancel", "u2", modelauth.RoleUser, absencehandlers.AbsenceCancelRequest{ID: a.ID + 100})
+	wantError(t, rec, http.StatusNotFound, shared.ErrorCodeNotFound)
+}
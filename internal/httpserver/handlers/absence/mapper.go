@@ -0,0 +1,24 @@
+package absence
+
+import (
+	modelabsence "github.com/zxchelik/avito-test-task/internal/model/absence"
+)
+
+func toAbsenceDTO(a *modelabsence.Absence) AbsenceDTO {
+	return AbsenceDTO{
+		ID:          a.ID,
+		UserID:      a.UserID,
+		StartsAt:    a.StartsAt,
+		EndsAt:      a.EndsAt,
+		Reason:      a.Reason,
+		CancelledAt: a.CancelledAt,
+	}
+}
+
+func toAbsenceDTOs(absences []*modelabsence.Absence) []AbsenceDTO {
+	res := make([]AbsenceDTO, 0, len(absences))
+	for _, a := range absences {
+		res = append(res, toAbsenceDTO(a))
+	}
+	return res
+}
@@ -0,0 +1,41 @@
+package code_owner
+
+type RuleDTO struct {
+	ID       int64    `json:"id"`
+	Pattern  string   `json:"pattern"`
+	Users    []string `json:"users"`
+	Teams    []string `json:"teams"`
+	Position int      `json:"position"`
+}
+
+type RuleAddRequest struct {
+	Pattern  string   `json:"pattern"`
+	Users    []string `json:"users"`
+	Teams    []string `json:"teams"`
+	Position int      `json:"position,omitempty"` // 0 — в конец списка
+}
+
+type RuleUpdateRequest struct {
+	ID       int64    `json:"id"`
+	Pattern  string   `json:"pattern"`
+	Users    []string `json:"users"`
+	Teams    []string `json:"teams"`
+	Position int      `json:"position,omitempty"` // 0 — не менять
+}
+
+type RuleDeleteRequest struct {
+	ID int64 `json:"id"`
+}
+
+type RuleResponse struct {
+	Rule RuleDTO `json:"rule"`
+}
+
+type RulesResponse struct {
+	Rules []RuleDTO `json:"rules"`
+}
+
+type ImportRequest struct {
+	Content string `json:"content"` // содержимое файла CODEOWNERS
+	Replace bool   `json:"replace"` // удалить существующие правила перед импортом
+}
@@ -0,0 +1,135 @@
+package code_owner
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/zxchelik/avito-test-task/internal/httpserver/handlers/shared"
+	modelco "github.com/zxchelik/avito-test-task/internal/model/code_owner"
+	srvco "github.com/zxchelik/avito-test-task/internal/service/code_owner"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+type Handler struct {
+	svc *srvco.Service
+	log *slog.Logger
+}
+
+func New(svc *srvco.Service, log *slog.Logger) *Handler {
+	return &Handler{svc: svc, log: log}
+}
+
+// Register регистрирует маршруты правил владения кодом.
+func (h *Handler) Register(r chi.Router) {
+	r.With(shared.AdminOnly).Post("/codeOwners/add", h.handleRuleAdd)
+	r.Get("/codeOwners/list", h.handleRuleList)
+	r.With(shared.AdminOnly).Post("/codeOwners/update", h.handleRuleUpdate)
+	r.With(shared.AdminOnly).Post("/codeOwners/delete", h.handleRuleDelete)
+	r.With(shared.AdminOnly).Post("/codeOwners/import", h.handleImport)
+}
+
+// POST /codeOwners/add
+func (h *Handler) handleRuleAdd(w http.ResponseWriter, r *http.Request) {
+	var req RuleAddRequest
+	if !shared.DecodeJSON(w, r, &req) {
+		return
+	}
+	var v shared.Validation
+	v.RequireBody("pattern", req.Pattern == "")
+	if v.Write(w, r) {
+		return
+	}
+
+	rule, err := h.svc.Add(r.Context(), &modelco.Rule{
+		Pattern:  req.Pattern,
+		Users:    req.Users,
+		Teams:    req.Teams,
+		Position: req.Position,
+	})
+	if err != nil {
+		shared.WriteServiceError(w, r, err, h.log)
+		return
+	}
+
+	shared.WriteJSON(w, http.StatusCreated, RuleResponse{Rule: toRuleDTO(rule)})
+}
+
+// GET /codeOwners/list
+func (h *Handler) handleRuleList(w http.ResponseWriter, r *http.Request) {
+	rules, err := h.svc.List(r.Context())
+	if err != nil {
+		shared.WriteServiceError(w, r, err, h.log)
+		return
+	}
+
+	shared.WriteJSON(w, http.StatusOK, RulesResponse{Rules: toRuleDTOs(rules)})
+}
+
+// POST /codeOwners/update
+func (h *Handler) handleRuleUpdate(w http.ResponseWriter, r *http.Request) {
+	var req RuleUpdateRequest
+	if !shared.DecodeJSON(w, r, &req) {
+		return
+	}
+	var v shared.Validation
+	v.RequireBody("id", req.ID == 0)
+	v.RequireBody("pattern", req.Pattern == "")
+	if v.Write(w, r) {
+		return
+	}
+
+	rule, err := h.svc.Update(r.Context(), &modelco.Rule{
+		ID:       req.ID,
+		Pattern:  req.Pattern,
+		Users:    req.Users,
+		Teams:    req.Teams,
+		Position: req.Position,
+	})
+	if err != nil {
+		shared.WriteServiceError(w, r, err, h.log)
+		return
+	}
+
+	shared.WriteJSON(w, http.StatusOK, RuleResponse{Rule: toRuleDTO(rule)})
+}
+
+// POST /codeOwners/delete
+func (h *Handler) handleRuleDelete(w http.ResponseWriter, r *http.Request) {
+	var req RuleDeleteRequest
+	if !shared.DecodeJSON(w, r, &req) {
+		return
+	}
+	var v shared.Validation
+	v.RequireBody("id", req.ID == 0)
+	if v.Write(w, r) {
+		return
+	}
+
+	if err := h.svc.Delete(r.Context(), req.ID); err != nil {
+		shared.WriteServiceError(w, r, err, h.log)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// POST /codeOwners/import
+func (h *Handler) handleImport(w http.ResponseWriter, r *http.Request) {
+	var req ImportRequest
+	if !shared.DecodeJSON(w, r, &req) {
+		return
+	}
+	var v shared.Validation
+	v.RequireBody("content", strings.TrimSpace(req.Content) == "")
+	if v.Write(w, r) {
+		return
+	}
+
+	rules, err := h.svc.Import(r.Context(), strings.NewReader(req.Content), req.Replace)
+	if err != nil {
+		shared.WriteServiceError(w, r, err, h.log)
+		return
+	}
+
+	shared.WriteJSON(w, http.StatusCreated, RulesResponse{Rules: toRuleDTOs(rules)})
+}
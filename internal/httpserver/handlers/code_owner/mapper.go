@@ -0,0 +1,30 @@
+package code_owner
+
+import (
+	modelco "github.com/zxchelik/avito-test-task/internal/model/code_owner"
+)
+
+func toRuleDTO(r *modelco.Rule) RuleDTO {
+	dto := RuleDTO{
+		ID:       r.ID,
+		Pattern:  r.Pattern,
+		Users:    r.Users,
+		Teams:    r.Teams,
+		Position: r.Position,
+	}
+	if dto.Users == nil {
+		dto.Users = []string{}
+	}
+	if dto.Teams == nil {
+		dto.Teams = []string{}
+	}
+	return dto
+}
+
+func toRuleDTOs(rules []*modelco.Rule) []RuleDTO {
+	res := make([]RuleDTO, 0, len(rules))
+	for _, r := range rules {
+		res = append(res, toRuleDTO(r))
+	}
+	return res
+}
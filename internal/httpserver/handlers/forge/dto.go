@@ -0,0 +1,85 @@
+package forge
+
+import "time"
+
+type IdentityDTO struct {
+	Forge     string    `json:"forge"` // github | gitlab
+	Username  string    `json:"username"`
+	UserID    string    `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type IdentityAddRequest struct {
+	Forge    string `json:"forge"`
+	Username string `json:"username"`
+	UserID   string `json:"user_id"`
+}
+
+type IdentityResponse struct {
+	Identity IdentityDTO `json:"identity"`
+}
+
+type IdentitiesResponse struct {
+	Identities []IdentityDTO `json:"identities"`
+}
+
+type IdentityDeleteRequest struct {
+	Forge    string `json:"forge"`
+	Username string `json:"username"`
+}
+
+// Статусы обработки входящего вебхука.
+const (
+	StatusProcessed = "processed"
+	StatusDuplicate = "duplicate" // доставка уже обработана
+	StatusIgnored   = "ignored"   // событие не затрагивает PR
+)
+
+type ReceiveResponse struct {
+	Status        string `json:"status"`
+	PullRequestID string `json:"pull_request_id,omitempty"`
+	PRStatus      string `json:"pr_status,omitempty"`
+}
+
+type forgeUser struct {
+	Login    string `json:"login"`    // GitHub
+	Username string `json:"username"` // GitLab
+}
+
+// githubPullRequestEvent — нужная часть события pull_request GitHub.
+type githubPullRequestEvent struct {
+	Action      string `json:"action"`
+	PullRequest struct {
+		Number int       `json:"number"`
+		Title  string    `json:"title"`
+		Draft  bool      `json:"draft"`
+		Merged bool      `json:"merged"`
+		User   forgeUser `json:"user"`
+	} `json:"pull_request"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	Sender forgeUser `json:"sender"`
+}
+
+// gitlabMergeRequestEvent — нужная часть события Merge Request Hook GitLab.
+type gitlabMergeRequestEvent struct {
+	ObjectKind string    `json:"object_kind"`
+	User       forgeUser `json:"user"`
+	Project    struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+	ObjectAttributes struct {
+		IID            int    `json:"iid"`
+		Title          string `json:"title"`
+		Action         string `json:"action"`
+		Draft          bool   `json:"draft"`
+		WorkInProgress bool   `json:"work_in_progress"`
+	} `json:"object_attributes"`
+	Changes struct {
+		Draft *struct {
+			Previous bool `json:"previous"`
+			Current  bool `json:"current"`
+		} `json:"draft"`
+	} `json:"changes"`
+}
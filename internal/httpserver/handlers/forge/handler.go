@@ -0,0 +1,216 @@
+package forge
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/zxchelik/avito-test-task/internal/httpserver/handlers/shared"
+	modelforge "github.com/zxchelik/avito-test-task/internal/model/forge"
+	srvforge "github.com/zxchelik/avito-test-task/internal/service/forge"
+	srvwh "github.com/zxchelik/avito-test-task/internal/service/webhook"
+	"io"
+	"log/slog"
+	"net/http"
+)
+
+// maxPayloadSize ограничивает тело входящего вебхука.
+const maxPayloadSize = 5 << 20
+
+// Secrets — секреты проверки входящих вебхуков; пустой секрет отключает приём с хостинга.
+type Secrets struct {
+	GitHub string // секрет для X-Hub-Signature-256
+	GitLab string // токен из X-Gitlab-Token
+}
+
+type Handler struct {
+	svc     *srvforge.Service
+	secrets Secrets
+	log     *slog.Logger
+}
+
+func New(svc *srvforge.Service, secrets Secrets, log *slog.Logger) *Handler {
+	return &Handler{svc: svc, secrets: secrets, log: log}
+}
+
+// RegisterReceivers регистрирует приём вебхуков с хостингов; они аутентифицируются
+// подписью, а не bearer-токеном, поэтому регистрируются вне аутентификации.
+func (h *Handler) RegisterReceivers(r chi.Router) {
+	r.Post("/forge/github", h.handleGitHub)
+	r.Post("/forge/gitlab", h.handleGitLab)
+}
+
+// Register регистрирует управление привязками логинов.
+func (h *Handler) Register(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(shared.AdminOnly)
+		r.Post("/forge/identities/add", h.handleIdentityAdd)
+		r.Get("/forge/identities/list", h.handleIdentityList)
+		r.Post("/forge/identities/delete", h.handleIdentityDelete)
+	})
+}
+
+// POST /forge/github
+func (h *Handler) handleGitHub(w http.ResponseWriter, r *http.Request) {
+	body, ok := readPayload(w, r)
+	if !ok {
+		return
+	}
+	signature := r.Header.Get("X-Hub-Signature-256")
+	if h.secrets.GitHub == "" || !hmacEqual(signature, srvwh.Sign(h.secrets.GitHub, body)) {
+		shared.WriteError(w, r, http.StatusUnauthorized, shared.ErrorCodeInvalidSignature, "invalid X-Hub-Signature-256")
+		return
+	}
+
+	deliveryID := r.Header.Get("X-GitHub-Delivery")
+	if deliveryID == "" {
+		shared.WriteValidationError(w, r, "request validation failed",
+			shared.FieldError{In: shared.InHeader, Field: "X-GitHub-Delivery", Reason: "is required"})
+		return
+	}
+	if r.Header.Get("X-GitHub-Event") != "pull_request" {
+		shared.WriteJSON(w, http.StatusOK, ReceiveResponse{Status: StatusIgnored})
+		return
+	}
+
+	var payload githubPullRequestEvent
+	if err := json.Unmarshal(body, &payload); err != nil {
+		shared.WriteValidationError(w, r, "invalid JSON", shared.FieldError{In: shared.InBody, Field: "/", Reason: err.Error()})
+		return
+	}
+
+	h.receive(w, r, fromGitHub(deliveryID, &payload))
+}
+
+// POST /forge/gitlab
+func (h *Handler) handleGitLab(w http.ResponseWriter, r *http.Request) {
+	body, ok := readPayload(w, r)
+	if !ok {
+		return
+	}
+	if h.secrets.GitLab == "" || !hmacEqual(r.Header.Get("X-Gitlab-Token"), h.secrets.GitLab) {
+		shared.WriteError(w, r, http.StatusUnauthorized, shared.ErrorCodeInvalidSignature, "invalid X-Gitlab-Token")
+		return
+	}
+
+	deliveryID := r.Header.Get("X-Gitlab-Event-UUID")
+	if deliveryID == "" {
+		shared.WriteValidationError(w, r, "request validation failed",
+			shared.FieldError{In: shared.InHeader, Field: "X-Gitlab-Event-UUID", Reason: "is required"})
+		return
+	}
+	if r.Header.Get("X-Gitlab-Event") != "Merge Request Hook" {
+		shared.WriteJSON(w, http.StatusOK, ReceiveResponse{Status: StatusIgnored})
+		return
+	}
+
+	var payload gitlabMergeRequestEvent
+	if err := json.Unmarshal(body, &payload); err != nil {
+		shared.WriteValidationError(w, r, "invalid JSON", shared.FieldError{In: shared.InBody, Field: "/", Reason: err.Error()})
+		return
+	}
+
+	h.receive(w, r, fromGitLab(deliveryID, &payload))
+}
+
+func (h *Handler) receive(w http.ResponseWriter, r *http.Request, ev *modelforge.Event) {
+	var v shared.Validation
+	v.RequireBody("repository", ev.Repository == "")
+	v.RequireBody("number", ev.Number == 0)
+	if v.Write(w, r) {
+		return
+	}
+
+	pr, err := h.svc.Handle(r.Context(), ev)
+	if err != nil {
+		if errors.Is(err, modelforge.ErrDuplicateDelivery) {
+			shared.WriteJSON(w, http.StatusOK, ReceiveResponse{Status: StatusDuplicate})
+			return
+		}
+		// хостинг покажет доставку неуспешной, и её можно повторить
+		shared.WriteServiceError(w, r, err, h.log)
+		return
+	}
+
+	if pr == nil {
+		shared.WriteJSON(w, http.StatusOK, ReceiveResponse{Status: StatusIgnored, PullRequestID: ev.PullRequestID()})
+		return
+	}
+	shared.WriteJSON(w, http.StatusOK, ReceiveResponse{
+		Status:        StatusProcessed,
+		PullRequestID: pr.ID,
+		PRStatus:      string(pr.Status),
+	})
+}
+
+// POST /forge/identities/add
+func (h *Handler) handleIdentityAdd(w http.ResponseWriter, r *http.Request) {
+	var req IdentityAddRequest
+	if !shared.DecodeJSON(w, r, &req) {
+		return
+	}
+	var v shared.Validation
+	v.RequireBody("username", req.Username == "")
+	v.RequireBody("user_id", req.UserID == "")
+	if v.Write(w, r) {
+		return
+	}
+
+	id, err := h.svc.MapIdentity(r.Context(), &modelforge.Identity{
+		Forge:    modelforge.Forge(req.Forge),
+		Username: req.Username,
+		UserID:   req.UserID,
+	})
+	if err != nil {
+		shared.WriteServiceError(w, r, err, h.log)
+		return
+	}
+
+	shared.WriteJSON(w, http.StatusOK, IdentityResponse{Identity: toIdentityDTO(id)})
+}
+
+// GET /forge/identities/list?user_id=...
+func (h *Handler) handleIdentityList(w http.ResponseWriter, r *http.Request) {
+	ids, err := h.svc.Identities(r.Context(), r.URL.Query().Get("user_id"))
+	if err != nil {
+		shared.WriteServiceError(w, r, err, h.log)
+		return
+	}
+
+	shared.WriteJSON(w, http.StatusOK, IdentitiesResponse{Identities: toIdentityDTOs(ids)})
+}
+
+// POST /forge/identities/delete
+func (h *Handler) handleIdentityDelete(w http.ResponseWriter, r *http.Request) {
+	var req IdentityDeleteRequest
+	if !shared.DecodeJSON(w, r, &req) {
+		return
+	}
+	var v shared.Validation
+	v.RequireBody("forge", req.Forge == "")
+	v.RequireBody("username", req.Username == "")
+	if v.Write(w, r) {
+		return
+	}
+
+	if err := h.svc.UnmapIdentity(r.Context(), modelforge.Forge(req.Forge), req.Username); err != nil {
+		shared.WriteServiceError(w, r, err, h.log)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func readPayload(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPayloadSize))
+	if err != nil {
+		shared.WriteValidationError(w, r, "cannot read body", shared.FieldError{In: shared.InBody, Field: "/", Reason: err.Error()})
+		return nil, false
+	}
+	return body, true
+}
+
+// hmacEqual сравнивает секреты за постоянное время.
+func hmacEqual(got, want string) bool {
+	return got != "" && subtle.ConstantTimeCompare([]byte(got), []byte(want)) == 1
+}
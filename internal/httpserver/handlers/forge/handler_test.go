@@ -0,0 +1,161 @@
+package forge_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	forgehandlers "github.com/zxchelik/avito-test-task/internal/httpserver/handlers/forge"
+	modelforge "github.com/zxchelik/avito-test-task/internal/model/forge"
+	modelpr "github.com/zxchelik/avito-test-task/internal/model/pull_request"
+	modelra "github.com/zxchelik/avito-test-task/internal/model/reviewer_assignment"
+	srvforge "github.com/zxchelik/avito-test-task/internal/service/forge"
+	"github.com/zxchelik/avito-test-task/internal/service/servicetest"
+)
+
+const secret = "forge-secret"
+
+// newGitHubReceiver — приём вебхуков GitHub поверх хранилища в памяти;
+// правило merge требует одобрения всех ревьюверов.
+func newGitHubReceiver(t *testing.T) (*servicetest.Store, http.Handler) {
+	t.Helper()
+
+	store := servicetest.NewStore()
+	store.AddTeam(t, servicetest.Team("backend", 1), "author", "r1")
+	if _, err := store.Forge.UpsertIdentity(context.Background(), &modelforge.Identity{
+		Forge: modelforge.GitHub, Username: "octocat", UserID: "author",
+	}); err != nil {
+		t.Fatalf("map identity: %v", err)
+	}
+
+	prs := store.PRService().WithMergeRule(modelpr.MergeRule{Kind: modelpr.MergeRuleAllApproved})
+	svc := srvforge.NewService(store.Forge, prs, store.Tx)
+	h := forgehandlers.New(svc, forgehandlers.Secrets{GitHub: secret}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	r := chi.NewRouter()
+	h.RegisterReceivers(r)
+	return store, r
+}
+
+func githubPayload(t *testing.T, action string, merged bool) []byte {
+	t.Helper()
+
+	body, err := json.Marshal(map[string]any{
+		"action": action,
+		"pull_request": map[string]any{
+			"number": 7,
+			"title":  "feature",
+			"merged": merged,
+			"user":   map[string]string{"login": "octocat"},
+		},
+		"repository": map[string]string{"full_name": "org/repo"},
+		"sender":     map[string]string{"login": "octocat"},
+	})
+	if err != nil {
+		t.Fatalf("marshal payload: %v", err)
+	}
+	return body
+}
+
+func sign(body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func deliver(t *testing.T, h http.Handler, deliveryID, signature string, body []byte) forgehandlers.ReceiveResponse {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/forge/github", bytes.NewReader(body))
+	req.Header.Set("X-GitHub-Event", "pull_request")
+	req.Header.Set("X-GitHub-Delivery", deliveryID)
+	req.Header.Set("X-Hub-Signature-256", signature)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("delivery %s: status %d: %s", deliveryID, rec.Code, rec.Body)
+	}
+	var resp forgehandlers.ReceiveResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	return resp
+}
+
+func TestGitHubMergeSkipsMergeRule(t *testing.T) {
+	store, h := newGitHubReceiver(t)
+	ctx := context.Background()
+
+	opened := githubPayload(t, "opened", false)
+	if resp := deliver(t, h, "d-1", sign(opened), opened); resp.PRStatus != string(modelpr.PROpen) {
+		t.Fatalf("opened: %+v", resp)
+	}
+
+	// ревьювер не одобрил PR, но на хостинге он уже смержен
+	merged := githubPayload(t, "closed", true)
+	resp := deliver(t, h, "d-2", sign(merged), merged)
+	if resp.Status != forgehandlers.StatusProcessed || resp.PRStatus != string(modelpr.PRMerged) {
+		t.Fatalf("merged: %+v", resp)
+	}
+
+	pr, err := store.PRs.GetByID(ctx, resp.PullRequestID)
+	if err != nil {
+		t.Fatalf("get PR: %v", err)
+	}
+	if pr.Status != modelpr.PRMerged || pr.MergedAt == nil {
+		t.Fatalf("PR not merged: %+v", pr)
+	}
+	history, err := store.Events.ListByPR(ctx, pr.ID)
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	if last := history[len(history)-1]; last.Type != modelra.EventMerged || last.ActorId != "author" {
+		t.Fatalf("last event: %+v", last)
+	}
+
+	// повтор доставки и повторный merge с хостинга не ошибки, иначе хостинг будет их повторять
+	if resp := deliver(t, h, "d-2", sign(merged), merged); resp.Status != forgehandlers.StatusDuplicate {
+		t.Fatalf("redelivery: %+v", resp)
+	}
+	if resp := deliver(t, h, "d-3", sign(merged), merged); resp.Status != forgehandlers.StatusIgnored {
+		t.Fatalf("second merge: %+v", resp)
+	}
+}
+
+func TestGitHubRejectsBadSignature(t *testing.T) {
+	store, h := newGitHubReceiver(t)
+
+	opened := githubPayload(t, "opened", false)
+	for name, signature := range map[string]string{
+		"missing":    "",
+		"wrong key":  "sha256=" + hex.EncodeToString(make([]byte, sha256.Size)),
+		"other body": sign(githubPayload(t, "opened", true)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/forge/github", bytes.NewReader(opened))
+			req.Header.Set("X-GitHub-Event", "pull_request")
+			req.Header.Set("X-GitHub-Delivery", "d-1")
+			req.Header.Set("X-Hub-Signature-256", signature)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusUnauthorized {
+				t.Fatalf("status %d, want 401", rec.Code)
+			}
+		})
+	}
+
+	if _, err := store.PRs.GetByID(context.Background(), "github:org/repo#7"); err == nil {
+		t.Fatal("PR created from unsigned delivery")
+	}
+}
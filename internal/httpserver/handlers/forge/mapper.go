@@ -0,0 +1,80 @@
+package forge
+
+import (
+	modelforge "github.com/zxchelik/avito-test-task/internal/model/forge"
+)
+
+func toIdentityDTO(id *modelforge.Identity) IdentityDTO {
+	return IdentityDTO{
+		Forge:     string(id.Forge),
+		Username:  id.Username,
+		UserID:    id.UserID,
+		CreatedAt: id.CreatedAt,
+	}
+}
+
+func toIdentityDTOs(ids []*modelforge.Identity) []IdentityDTO {
+	res := make([]IdentityDTO, 0, len(ids))
+	for _, id := range ids {
+		res = append(res, toIdentityDTO(id))
+	}
+	return res
+}
+
+func fromGitHub(deliveryID string, p *githubPullRequestEvent) *modelforge.Event {
+	ev := &modelforge.Event{
+		Forge:          modelforge.GitHub,
+		DeliveryID:     deliveryID,
+		Repository:     p.Repository.FullName,
+		Number:         p.PullRequest.Number,
+		Title:          p.PullRequest.Title,
+		Draft:          p.PullRequest.Draft,
+		AuthorUsername: p.PullRequest.User.Login,
+		SenderUsername: p.Sender.Login,
+	}
+	switch p.Action {
+	case "opened":
+		ev.Action = modelforge.ActionOpened
+	case "ready_for_review":
+		ev.Action = modelforge.ActionReady
+	case "reopened":
+		ev.Action = modelforge.ActionReopened
+	case "closed":
+		ev.Action = modelforge.ActionClosed
+		if p.PullRequest.Merged {
+			ev.Action = modelforge.ActionMerged
+		}
+	}
+	return ev
+}
+
+// fromGitLab приводит событие MR. GitLab не передаёт логин автора MR,
+// поэтому автором открытого MR считается тот, кто его открыл.
+func fromGitLab(deliveryID string, p *gitlabMergeRequestEvent) *modelforge.Event {
+	attrs := p.ObjectAttributes
+	ev := &modelforge.Event{
+		Forge:          modelforge.GitLab,
+		DeliveryID:     deliveryID,
+		Repository:     p.Project.PathWithNamespace,
+		Number:         attrs.IID,
+		Title:          attrs.Title,
+		Draft:          attrs.Draft || attrs.WorkInProgress,
+		AuthorUsername: p.User.Username,
+		SenderUsername: p.User.Username,
+	}
+	switch attrs.Action {
+	case "open":
+		ev.Action = modelforge.ActionOpened
+	case "reopen":
+		ev.Action = modelforge.ActionReopened
+	case "close":
+		ev.Action = modelforge.ActionClosed
+	case "merge":
+		ev.Action = modelforge.ActionMerged
+	case "update":
+		if d := p.Changes.Draft; d != nil && d.Previous && !d.Current {
+			ev.Action = modelforge.ActionReady
+		}
+	}
+	return ev
+}
@@ -1,43 +1,74 @@
 package handlers
 
 import (
+	"errors"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/metrics"
 	"log/slog"
 	"net/http"
+	"strings"
 
+	"github.com/zxchelik/avito-test-task/internal/httpserver/handlers/shared"
+	modelauth "github.com/zxchelik/avito-test-task/internal/model/auth"
+	"github.com/zxchelik/avito-test-task/internal/service"
+	srvabsence "github.com/zxchelik/avito-test-task/internal/service/absence"
+	srvco "github.com/zxchelik/avito-test-task/internal/service/code_owner"
+	srvforge "github.com/zxchelik/avito-test-task/internal/service/forge"
 	srvpr "github.com/zxchelik/avito-test-task/internal/service/pull_request"
 	srvteam "github.com/zxchelik/avito-test-task/internal/service/team"
 	srvuser "github.com/zxchelik/avito-test-task/internal/service/user"
+	srvwh "github.com/zxchelik/avito-test-task/internal/service/webhook"
 
+	absencehandlers "github.com/zxchelik/avito-test-task/internal/httpserver/handlers/absence"
+	cohandlers "github.com/zxchelik/avito-test-task/internal/httpserver/handlers/code_owner"
+	forgehandlers "github.com/zxchelik/avito-test-task/internal/httpserver/handlers/forge"
+	openapihandlers "github.com/zxchelik/avito-test-task/internal/httpserver/handlers/openapi"
 	prhandlers "github.com/zxchelik/avito-test-task/internal/httpserver/handlers/pull_request"
 	teamhandlers "github.com/zxchelik/avito-test-task/internal/httpserver/handlers/team"
 	userhandlers "github.com/zxchelik/avito-test-task/internal/httpserver/handlers/user"
+	whhandlers "github.com/zxchelik/avito-test-task/internal/httpserver/handlers/webhook"
 )
 
 type Handler struct {
-	teamSvc *srvteam.Service
-	userSvc *srvuser.Service
-	prSvc   *srvpr.Service
-	log     *slog.Logger
+	teamSvc      *srvteam.Service
+	userSvc      *srvuser.Service
+	prSvc        *srvpr.Service
+	coSvc        *srvco.Service
+	absSvc       *srvabsence.Service
+	whSvc        *srvwh.Service
+	forgeSvc     *srvforge.Service
+	forgeSecrets forgehandlers.Secrets
+	authn        Authenticator // nil — аутентификация выключена
+	validator    Validator     // nil — запросы не сверяются со спецификацией
+	log          *slog.Logger
 }
 
 func NewHandler(
 	teamSvc *srvteam.Service,
 	userSvc *srvuser.Service,
 	prSvc *srvpr.Service,
+	coSvc *srvco.Service,
+	absSvc *srvabsence.Service,
+	whSvc *srvwh.Service,
+	forgeSvc *srvforge.Service,
+	forgeSecrets forgehandlers.Secrets,
 	log *slog.Logger,
 ) *Handler {
 	return &Handler{
-		teamSvc: teamSvc,
-		userSvc: userSvc,
-		prSvc:   prSvc,
-		log:     log,
+		teamSvc:      teamSvc,
+		userSvc:      userSvc,
+		prSvc:        prSvc,
+		coSvc:        coSvc,
+		absSvc:       absSvc,
+		whSvc:        whSvc,
+		forgeSvc:     forgeSvc,
+		forgeSecrets: forgeSecrets,
+		log:          log,
 	}
 }
 
-// Router возвращает chi.Router, соответствующий openapi.yml.
+// Router возвращает chi.Router, соответствующий api/openapi.yaml.
 func (h *Handler) Router() chi.Router {
 	r := chi.NewRouter()
 
@@ -54,18 +85,119 @@ func (h *Handler) Router() chi.Router {
 	}))
 
 	r.Handle("/metrics", metrics.Handler())
+	openapihandlers.Register(r)
 
-	// Team endpoints
-	teamHandler := teamhandlers.New(h.teamSvc, h.log)
-	teamHandler.Register(r)
+	// Forge webhook receivers: проверяются подписью хостинга
+	forgeHandler := forgehandlers.New(h.forgeSvc, h.forgeSecrets, h.log)
+	forgeHandler.RegisterReceivers(r)
 
-	// User endpoints
-	userHandler := userhandlers.New(h.userSvc, h.log)
-	userHandler.Register(r)
+	r.Group(func(r chi.Router) {
+		if h.authn != nil {
+			r.Use(h.authenticate)
+		} else {
+			r.Use(anonymous, actorFromHeader)
+		}
+		if h.validator != nil {
+			r.Use(h.validator.Middleware)
+		}
 
-	// PullRequest endpoints
-	prHandler := prhandlers.New(h.prSvc, h.log)
-	prHandler.Register(r)
+		// Team endpoints
+		teamHandler := teamhandlers.New(h.teamSvc, h.log)
+		teamHandler.Register(r)
+
+		// User endpoints
+		userHandler := userhandlers.New(h.userSvc, h.log)
+		userHandler.Register(r)
+
+		// Absence endpoints
+		absenceHandler := absencehandlers.New(h.absSvc, h.log)
+		absenceHandler.Register(r)
+
+		// PullRequest endpoints
+		prHandler := prhandlers.New(h.prSvc, h.log)
+		prHandler.Register(r)
+
+		// CodeOwners endpoints
+		coHandler := cohandlers.New(h.coSvc, h.log)
+		coHandler.Register(r)
+
+		// Webhook endpoints
+		whHandler := whhandlers.New(h.whSvc, h.log)
+		whHandler.Register(r)
+
+		// Forge identity endpoints
+		forgeHandler.Register(r)
+	})
 
 	return r
 }
+
+// Validator сверяет запросы и ответы со спецификацией API.
+type Validator interface {
+	Middleware(next http.Handler) http.Handler
+}
+
+// WithValidator включает сверку запросов и ответов со спецификацией;
+// проверка идёт после аутентификации, чтобы анонимный запрос получал 401, а не 400.
+func (h *Handler) WithValidator(v Validator) *Handler {
+	h.validator = v
+	return h
+}
+
+// Authenticator определяет вызывающего по bearer-токену.
+type Authenticator interface {
+	Authenticate(token string) (*modelauth.Principal, error)
+}
+
+// WithAuthenticator включает аутентификацию: без неё запросы анонимны (с правами user),
+// а исполнитель берётся из заголовка ActorHeader.
+func (h *Handler) WithAuthenticator(authn Authenticator) *Handler {
+	h.authn = authn
+	return h
+}
+
+// authenticate требует bearer-токен и кладёт вызывающего в контекст;
+// он же становится исполнителем в истории ревью, ActorHeader игнорируется.
+func (h *Handler) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			shared.WriteError(w, r, http.StatusUnauthorized, shared.ErrorCodeUnauthorized, "bearer token is required")
+			return
+		}
+
+		p, err := h.authn.Authenticate(token)
+		if err != nil {
+			if !errors.Is(err, modelauth.ErrUnauthorized) {
+				h.log.Error("authentication failed", slog.String("error", err.Error()))
+			}
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			shared.WriteError(w, r, http.StatusUnauthorized, shared.ErrorCodeUnauthorized, "invalid or expired token")
+			return
+		}
+
+		ctx := service.WithPrincipal(r.Context(), p)
+		ctx = service.WithActor(ctx, p.Subject)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// anonymous кладёт в контекст auth.Anonymous: без аутентификации доступно только то, что разрешено роли user.
+func anonymous(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(service.WithPrincipal(r.Context(), modelauth.Anonymous)))
+	})
+}
+
+// ActorHeader — заголовок с идентификатором исполнителя запроса; попадает в историю ревью PR.
+const ActorHeader = "X-Actor-Id"
+
+func actorFromHeader(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if actor := r.Header.Get(ActorHeader); actor != "" {
+			r = r.WithContext(service.WithActor(r.Context(), actor))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
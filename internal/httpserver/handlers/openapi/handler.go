@@ -0,0 +1,52 @@
+package openapi
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/zxchelik/avito-test-task/api"
+)
+
+// SpecPath — адрес, по которому отдаётся спецификация.
+const SpecPath = "/openapi.yaml"
+
+// Register отдаёт встроенную спецификацию и страницу Swagger UI; оба маршрута без аутентификации.
+func Register(r chi.Router) {
+	r.Get(SpecPath, handleSpec)
+	r.Get("/docs", handleDocs)
+}
+
+// GET /openapi.yaml
+func handleSpec(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/yaml")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(api.Spec)
+}
+
+// GET /docs
+func handleDocs(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(swaggerUI))
+}
+
+// swaggerUI загружает Swagger UI с CDN и показывает SpecPath.
+const swaggerUI = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>PR Reviewer Assignment Service — API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = () => {
+      window.ui = SwaggerUIBundle({ url: "` + SpecPath + `", dom_id: "#swagger-ui" });
+    };
+  </script>
+</body>
+</html>
+`
@@ -0,0 +1,190 @@
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+
+	"github.com/zxchelik/avito-test-task/api"
+	"github.com/zxchelik/avito-test-task/internal/httpserver/handlers/shared"
+)
+
+// Load разбирает встроенную спецификацию и проверяет её корректность.
+func Load(ctx context.Context) (*openapi3.T, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(api.Spec)
+	if err != nil {
+		return nil, fmt.Errorf("load openapi spec: %w", err)
+	}
+	if err := doc.Validate(ctx); err != nil {
+		return nil, fmt.Errorf("invalid openapi spec: %w", err)
+	}
+	return doc, nil
+}
+
+// Validator сверяет запросы и ответы со спецификацией.
+// Запрос с нарушениями отклоняется с VALIDATION_ERROR и списком полей;
+// ответ, не совпавший со спецификацией, отдаётся как есть, а расхождение пишется в лог.
+// Маршруты, которых нет в спецификации, пропускаются без проверки.
+type Validator struct {
+	router  routers.Router
+	options *openapi3filter.Options
+	log     *slog.Logger
+}
+
+func NewValidator(doc *openapi3.T, log *slog.Logger) (*Validator, error) {
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, fmt.Errorf("build openapi router: %w", err)
+	}
+	return &Validator{
+		router: router,
+		options: &openapi3filter.Options{
+			MultiError:          true,
+			SkipSettingDefaults: true, // тело запроса доходит до обработчика без изменений
+			// токен проверяет аутентификация хендлера, здесь — только форма запроса
+			AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+		},
+		log: log,
+	}, nil
+}
+
+func (v *Validator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, pathParams, err := v.router.FindRoute(r)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		in := &openapi3filter.RequestValidationInput{
+			Request:    r,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    v.options,
+		}
+		if err := openapi3filter.ValidateRequest(r.Context(), in); err != nil {
+			shared.WriteValidationError(w, r, "request does not match API specification", fieldErrors(err, nil)...)
+			return
+		}
+
+		rec := &bufferedResponse{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		out := &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: in,
+			Status:                 rec.status,
+			Header:                 rec.Header(),
+			Options:                v.options,
+		}
+		out.SetBodyBytes(rec.body.Bytes())
+		if err := openapi3filter.ValidateResponse(r.Context(), out); err != nil {
+			v.log.Error("response does not match API specification",
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", rec.status),
+				slog.Any("problems", responseProblems(err)),
+			)
+		}
+
+		w.WriteHeader(rec.status)
+		_, _ = w.Write(rec.body.Bytes())
+	})
+}
+
+// fieldErrors раскладывает ошибку валидации запроса по отдельным полям.
+func fieldErrors(err error, out []shared.FieldError) []shared.FieldError {
+	// обёртки разбираются явно: errors.As провалился бы сквозь RequestError во вложенный MultiError
+	if me, ok := err.(openapi3.MultiError); ok {
+		for _, e := range me {
+			out = fieldErrors(e, out)
+		}
+		return out
+	}
+
+	re, ok := err.(*openapi3filter.RequestError)
+	if !ok {
+		return append(out, shared.FieldError{Reason: err.Error()})
+	}
+
+	switch {
+	case re.Parameter != nil:
+		return append(out, shared.FieldError{In: re.Parameter.In, Field: re.Parameter.Name, Reason: reason(re)})
+	case re.RequestBody != nil:
+		schemaErrs := schemaErrors(re.Err, nil)
+		if len(schemaErrs) == 0 {
+			return append(out, shared.FieldError{In: "body", Field: "/", Reason: reason(re)})
+		}
+		for _, se := range schemaErrs {
+			out = append(out, shared.FieldError{
+				In:     "body",
+				Field:  "/" + strings.Join(se.JSONPointer(), "/"),
+				Reason: se.Reason,
+			})
+		}
+		return out
+	default:
+		return append(out, shared.FieldError{Reason: re.Error()})
+	}
+}
+
+func schemaErrors(err error, out []*openapi3.SchemaError) []*openapi3.SchemaError {
+	if me, ok := err.(openapi3.MultiError); ok {
+		for _, e := range me {
+			out = schemaErrors(e, out)
+		}
+		return out
+	}
+	var se *openapi3.SchemaError
+	if errors.As(err, &se) {
+		out = append(out, se)
+	}
+	return out
+}
+
+// responseProblems сводит ошибку проверки ответа к строкам «поле: причина» без дампа схемы.
+func responseProblems(err error) []string {
+	schemaErrs := schemaErrors(err, nil)
+	if len(schemaErrs) == 0 {
+		return []string{err.Error()}
+	}
+	problems := make([]string, 0, len(schemaErrs))
+	for _, se := range schemaErrs {
+		problems = append(problems, "/"+strings.Join(se.JSONPointer(), "/")+": "+se.Reason)
+	}
+	return problems
+}
+
+func reason(re *openapi3filter.RequestError) string {
+	var se *openapi3.SchemaError
+	if errors.As(re.Err, &se) {
+		return se.Reason
+	}
+	if re.Err != nil {
+		return re.Err.Error()
+	}
+	return re.Reason
+}
+
+// bufferedResponse придерживает ответ обработчика до проверки по спецификации.
+type bufferedResponse struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) WriteHeader(status int) {
+	b.status = status
+}
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	return b.body.Write(p)
+}
@@ -1,18 +1,24 @@
 // handlers/pull_request/dto.go
 package pull_request
 
+import "time"
+
 type PullRequestDTO struct {
 	PullRequestID     string   `json:"pull_request_id"`
 	PullRequestName   string   `json:"pull_request_name"`
 	AuthorID          string   `json:"author_id"`
-	Status            string   `json:"status"`             // OPEN | MERGED
-	AssignedReviewers []string `json:"assigned_reviewers"` // 0..2 user_id
+	Status            string   `json:"status"`                       // DRAFT | OPEN | MERGED | CLOSED
+	AssignedReviewers []string `json:"assigned_reviewers"`           // 0..max_reviewers команды, user_id
+	FallbackReviewers []string `json:"fallback_reviewers,omitempty"` // подмножество assigned_reviewers из резервных команд
 }
 
 type PullRequestCreateRequest struct {
-	PullRequestID   string `json:"pull_request_id"`
-	PullRequestName string `json:"pull_request_name"`
-	AuthorID        string `json:"author_id"`
+	PullRequestID   string   `json:"pull_request_id"`
+	PullRequestName string   `json:"pull_request_name"`
+	AuthorID        string   `json:"author_id"`
+	ReviewersCount  *int     `json:"reviewers_count,omitempty"` // min_reviewers..max_reviewers команды
+	ChangedFiles    []string `json:"changed_files,omitempty"`   // пути для подбора владельцев кода
+	Draft           bool     `json:"draft,omitempty"`           // создать DRAFT без ревьюверов
 }
 
 type PullRequestCreateResponse struct {
@@ -33,6 +39,86 @@ type PullRequestReassignRequest struct {
 }
 
 type PullRequestReassignResponse struct {
-	PR         PullRequestDTO `json:"pr"`
-	ReplacedBy string         `json:"replaced_by"`
+	PR                 PullRequestDTO `json:"pr"`
+	ReplacedBy         string         `json:"replaced_by"`
+	ReplacedByFallback bool           `json:"replaced_by_fallback"`
+}
+
+type PullRequestReviewRequest struct {
+	PullRequestID string `json:"pull_request_id"`
+	ReviewerID    string `json:"reviewer_id"`
+	Verdict       string `json:"verdict"` // APPROVED | CHANGES_REQUESTED | COMMENTED
+}
+
+type ReviewDTO struct {
+	ReviewerID  string     `json:"reviewer_id"`
+	Verdict     *string    `json:"verdict"` // null — вердикта ещё нет
+	SubmittedAt *time.Time `json:"submitted_at,omitempty"`
+}
+
+type PullRequestReviewResponse struct {
+	PR      PullRequestDTO `json:"pr"`
+	Reviews []ReviewDTO    `json:"reviews"`
+}
+
+// PullRequestReadyRequest — параметры подбора ревьюверов, как при создании PR.
+type PullRequestReadyRequest struct {
+	PullRequestID  string   `json:"pull_request_id"`
+	ReviewersCount *int     `json:"reviewers_count,omitempty"`
+	ChangedFiles   []string `json:"changed_files,omitempty"`
+}
+
+type PullRequestStatusRequest struct {
+	PullRequestID string `json:"pull_request_id"`
+}
+
+type PullRequestStatusResponse struct {
+	PR PullRequestDTO `json:"pr"`
+}
+
+// EventDTO — событие истории ревью; поля, не относящиеся к типу события, опускаются.
+type EventDTO struct {
+	ID             int64     `json:"id"`
+	Type           string    `json:"type"` // ASSIGNED | UNASSIGNED | REPLACED | VERDICT | STATUS_CHANGED | MERGED
+	UserID         string    `json:"user_id,omitempty"`
+	PreviousUserID string    `json:"previous_user_id,omitempty"`
+	Verdict        string    `json:"verdict,omitempty"`
+	Status         string    `json:"status,omitempty"`
+	ActorID        string    `json:"actor_id,omitempty"` // пусто — действие системы
+	Reason         string    `json:"reason,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type PullRequestHistoryResponse struct {
+	PullRequestID string     `json:"pull_request_id"`
+	Events        []EventDTO `json:"events"`
+}
+
+type ReviewerDTO struct {
+	UserID        string     `json:"user_id"`
+	AssignedAt    time.Time  `json:"assigned_at"`
+	Fallback      bool       `json:"fallback"`
+	Verdict       *string    `json:"verdict"` // null — вердикта ещё нет
+	VerdictAt     *time.Time `json:"verdict_at,omitempty"`
+	SLABreachedAt *time.Time `json:"sla_breached_at,omitempty"`
+}
+
+type PullRequestDetailsDTO struct {
+	PullRequestID   string        `json:"pull_request_id"`
+	PullRequestName string        `json:"pull_request_name"`
+	AuthorID        string        `json:"author_id"`
+	Status          string        `json:"status"`
+	CreatedAt       time.Time     `json:"created_at"`
+	MergedAt        *time.Time    `json:"merged_at,omitempty"`
+	ClosedAt        *time.Time    `json:"closed_at,omitempty"`
+	Reviewers       []ReviewerDTO `json:"reviewers"`
+}
+
+type PullRequestGetResponse struct {
+	PR PullRequestDetailsDTO `json:"pr"`
+}
+
+type PullRequestListResponse struct {
+	PullRequests []PullRequestDetailsDTO `json:"pull_requests"`
+	NextCursor   string                  `json:"next_cursor,omitempty"` // пусто — страница последняя
 }
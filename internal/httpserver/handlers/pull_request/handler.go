@@ -1,18 +1,15 @@
 package pull_request
 
 import (
-	"encoding/json"
-	"errors"
 	"github.com/zxchelik/avito-test-task/internal/httpserver/handlers/shared"
 	"log/slog"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
 
-	"github.com/zxchelik/avito-test-task/internal/model"
+	modelauth "github.com/zxchelik/avito-test-task/internal/model/auth"
 	modelpr "github.com/zxchelik/avito-test-task/internal/model/pull_request"
 	modelra "github.com/zxchelik/avito-test-task/internal/model/reviewer_assignment"
-	modeluser "github.com/zxchelik/avito-test-task/internal/model/user"
 	srvpr "github.com/zxchelik/avito-test-task/internal/service/pull_request"
 )
 
@@ -33,19 +30,28 @@ func New(
 
 func (h *Handler) Register(r chi.Router) {
 	r.Post("/pullRequest/create", h.handlePullRequestCreate)
-	r.Post("/pullRequest/merge", h.handlePullRequestMerge)
+	r.With(shared.RequireRole(modelauth.RoleAdmin, modelauth.RoleTeamLead)).Post("/pullRequest/merge", h.handlePullRequestMerge)
 	r.Post("/pullRequest/reassign", h.handlePullRequestReassign)
+	r.Post("/pullRequest/review", h.handlePullRequestReview)
+	r.Post("/pullRequest/ready", h.handlePullRequestReady)
+	r.Post("/pullRequest/close", h.handlePullRequestClose)
+	r.Post("/pullRequest/reopen", h.handlePullRequestReopen)
+	r.Get("/pullRequest/history", h.handlePullRequestHistory)
+	r.Get("/pullRequest/get", h.handlePullRequestGet)
+	r.Get("/pullRequest/list", h.handlePullRequestList)
 }
 
 // POST /pullRequest/create
 func (h *Handler) handlePullRequestCreate(w http.ResponseWriter, r *http.Request) {
 	var req PullRequestCreateRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		shared.WriteError(w, http.StatusBadRequest, shared.ErrorCodeInternal, "invalid JSON")
+	if !shared.DecodeJSON(w, r, &req) {
 		return
 	}
-	if req.PullRequestID == "" || req.PullRequestName == "" || req.AuthorID == "" {
-		shared.WriteError(w, http.StatusBadRequest, shared.ErrorCodeInternal, "pull_request_id, pull_request_name and author_id are required")
+	var v shared.Validation
+	v.RequireBody("pull_request_id", req.PullRequestID == "")
+	v.RequireBody("pull_request_name", req.PullRequestName == "")
+	v.RequireBody("author_id", req.AuthorID == "")
+	if v.Write(w, r) {
 		return
 	}
 
@@ -55,26 +61,19 @@ func (h *Handler) handlePullRequestCreate(w http.ResponseWriter, r *http.Request
 		AuthorID: req.AuthorID,
 		Status:   modelpr.PROpen,
 	}
+	if req.Draft {
+		prModel.Status = modelpr.PRDraft
+	}
+
+	opts := srvpr.CreateOptions{
+		ReviewersCount: req.ReviewersCount,
+		ChangedFiles:   req.ChangedFiles,
+	}
 
-	created, reviewers, err := h.svc.Create(r.Context(), prModel)
+	created, reviewers, err := h.svc.Create(r.Context(), prModel, opts)
 	if err != nil {
-		switch {
-		case errors.Is(err, model.ErrNotFound):
-			shared.WriteError(w, http.StatusNotFound, shared.ErrorCodeNotFound, "author or team not found")
-			return
-		case errors.Is(err, model.ErrAlreadyExists):
-			shared.WriteError(w, http.StatusConflict, shared.ErrorCodePRExists, "PR id already exists")
-			return
-		case errors.Is(err, modeluser.ErrUserInactive):
-			shared.WriteError(w, http.StatusConflict, shared.ErrorCodeNoCandidate, "author is inactive")
-			return
-		case errors.Is(err, modelra.ErrNoReviewerCandidatesLeft):
-			shared.WriteError(w, http.StatusConflict, shared.ErrorCodeNoCandidate, "no active reviewer candidates in team")
-			return
-		default:
-			shared.WriteInternalError(w, err, h.log)
-			return
-		}
+		shared.WriteServiceError(w, r, err, h.log)
+		return
 	}
 
 	resp := PullRequestCreateResponse{
@@ -86,22 +85,18 @@ func (h *Handler) handlePullRequestCreate(w http.ResponseWriter, r *http.Request
 // POST /pullRequest/merge
 func (h *Handler) handlePullRequestMerge(w http.ResponseWriter, r *http.Request) {
 	var req PullRequestMergeRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		shared.WriteError(w, http.StatusBadRequest, shared.ErrorCodeInternal, "invalid JSON")
+	if !shared.DecodeJSON(w, r, &req) {
 		return
 	}
-	if req.PullRequestID == "" {
-		shared.WriteError(w, http.StatusBadRequest, shared.ErrorCodeInternal, "pull_request_id is required")
+	var v shared.Validation
+	v.RequireBody("pull_request_id", req.PullRequestID == "")
+	if v.Write(w, r) {
 		return
 	}
 
 	pr, err := h.svc.Merge(r.Context(), req.PullRequestID)
 	if err != nil {
-		if errors.Is(err, model.ErrNotFound) {
-			shared.WriteError(w, http.StatusNotFound, shared.ErrorCodeNotFound, "PR not found")
-			return
-		}
-		shared.WriteInternalError(w, err, h.log)
+		shared.WriteServiceError(w, r, err, h.log)
 		return
 	}
 
@@ -114,49 +109,196 @@ func (h *Handler) handlePullRequestMerge(w http.ResponseWriter, r *http.Request)
 // POST /pullRequest/reassign
 func (h *Handler) handlePullRequestReassign(w http.ResponseWriter, r *http.Request) {
 	var req PullRequestReassignRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		shared.WriteError(w, http.StatusBadRequest, shared.ErrorCodeInternal, "invalid JSON")
+	if !shared.DecodeJSON(w, r, &req) {
 		return
 	}
-	if req.PullRequestID == "" || req.OldUserID == "" {
-		shared.WriteError(w, http.StatusBadRequest, shared.ErrorCodeInternal, "pull_request_id and old_user_id are required")
+	var v shared.Validation
+	v.RequireBody("pull_request_id", req.PullRequestID == "")
+	v.RequireBody("old_user_id", req.OldUserID == "")
+	if v.Write(w, r) {
 		return
 	}
 
 	newReviewer, err := h.svc.Reassign(r.Context(), req.PullRequestID, req.OldUserID)
 	if err != nil {
-		switch {
-		case errors.Is(err, model.ErrNotFound):
-			shared.WriteError(w, http.StatusNotFound, shared.ErrorCodeNotFound, "PR or user not found")
-			return
-		case errors.Is(err, modelra.ErrReviewerNotFoundInPR):
-			shared.WriteError(w, http.StatusConflict, shared.ErrorCodeNotAssigned, "reviewer is not assigned to this PR")
-			return
-		case errors.Is(err, modelra.ErrNoReviewerCandidatesLeft):
-			shared.WriteError(w, http.StatusConflict, shared.ErrorCodeNoCandidate, "no active replacement candidate in team")
-			return
-		case errors.Is(err, modelpr.ErrPRAlreadyMerged):
-			shared.WriteError(w, http.StatusConflict, shared.ErrorCodePRMerged, "cannot reassign on merged PR")
-			return
-		default:
-			shared.WriteError(w, http.StatusConflict, shared.ErrorCodePRMerged, "cannot reassign on merged PR")
-			return
-		}
-	}
-
-	pr, err := h.svc.GetByID(r.Context(), req.PullRequestID)
+		shared.WriteServiceError(w, r, err, h.log)
+		return
+	}
+
+	pr, reviewers, err := h.svc.Get(r.Context(), req.PullRequestID)
 	if err != nil {
-		if errors.Is(err, model.ErrNotFound) {
-			shared.WriteError(w, http.StatusNotFound, shared.ErrorCodeNotFound, "PR not found")
-			return
-		}
-		shared.WriteInternalError(w, err, h.log)
+		shared.WriteServiceError(w, r, err, h.log)
 		return
 	}
 
 	resp := PullRequestReassignResponse{
-		PR:         toPullRequestDTO(pr, nil),
-		ReplacedBy: newReviewer.ID,
+		PR:                 toPullRequestDTO(pr, reviewers),
+		ReplacedBy:         newReviewer.UserId,
+		ReplacedByFallback: newReviewer.Fallback,
+	}
+	shared.WriteJSON(w, http.StatusOK, resp)
+}
+
+// POST /pullRequest/review
+func (h *Handler) handlePullRequestReview(w http.ResponseWriter, r *http.Request) {
+	var req PullRequestReviewRequest
+	if !shared.DecodeJSON(w, r, &req) {
+		return
+	}
+	var v shared.Validation
+	v.RequireBody("pull_request_id", req.PullRequestID == "")
+	v.RequireBody("reviewer_id", req.ReviewerID == "")
+	v.RequireBody("verdict", req.Verdict == "")
+	if v.Write(w, r) {
+		return
+	}
+
+	pr, reviews, err := h.svc.Review(r.Context(), req.PullRequestID, req.ReviewerID, modelra.Verdict(req.Verdict))
+	if err != nil {
+		shared.WriteServiceError(w, r, err, h.log)
+		return
+	}
+
+	resp := PullRequestReviewResponse{
+		PR:      toPullRequestDTO(pr, reviews),
+		Reviews: toReviewDTOs(reviews),
 	}
 	shared.WriteJSON(w, http.StatusOK, resp)
 }
+
+// POST /pullRequest/ready
+func (h *Handler) handlePullRequestReady(w http.ResponseWriter, r *http.Request) {
+	var req PullRequestReadyRequest
+	if !shared.DecodeJSON(w, r, &req) {
+		return
+	}
+	var v shared.Validation
+	v.RequireBody("pull_request_id", req.PullRequestID == "")
+	if v.Write(w, r) {
+		return
+	}
+
+	opts := srvpr.CreateOptions{
+		ReviewersCount: req.ReviewersCount,
+		ChangedFiles:   req.ChangedFiles,
+	}
+
+	pr, reviewers, err := h.svc.Ready(r.Context(), req.PullRequestID, opts)
+	if err != nil {
+		shared.WriteServiceError(w, r, err, h.log)
+		return
+	}
+
+	resp := PullRequestStatusResponse{
+		PR: toPullRequestDTO(pr, reviewers),
+	}
+	shared.WriteJSON(w, http.StatusOK, resp)
+}
+
+// POST /pullRequest/close
+func (h *Handler) handlePullRequestClose(w http.ResponseWriter, r *http.Request) {
+	var req PullRequestStatusRequest
+	if !shared.DecodeJSON(w, r, &req) {
+		return
+	}
+	var v shared.Validation
+	v.RequireBody("pull_request_id", req.PullRequestID == "")
+	if v.Write(w, r) {
+		return
+	}
+
+	pr, err := h.svc.Close(r.Context(), req.PullRequestID)
+	if err != nil {
+		shared.WriteServiceError(w, r, err, h.log)
+		return
+	}
+
+	resp := PullRequestStatusResponse{
+		PR: toPullRequestDTO(pr, nil),
+	}
+	shared.WriteJSON(w, http.StatusOK, resp)
+}
+
+// POST /pullRequest/reopen
+func (h *Handler) handlePullRequestReopen(w http.ResponseWriter, r *http.Request) {
+	var req PullRequestStatusRequest
+	if !shared.DecodeJSON(w, r, &req) {
+		return
+	}
+	var v shared.Validation
+	v.RequireBody("pull_request_id", req.PullRequestID == "")
+	if v.Write(w, r) {
+		return
+	}
+
+	pr, reviewers, err := h.svc.Reopen(r.Context(), req.PullRequestID)
+	if err != nil {
+		shared.WriteServiceError(w, r, err, h.log)
+		return
+	}
+
+	resp := PullRequestStatusResponse{
+		PR: toPullRequestDTO(pr, reviewers),
+	}
+	shared.WriteJSON(w, http.StatusOK, resp)
+}
+
+// GET /pullRequest/history?pull_request_id=...
+func (h *Handler) handlePullRequestHistory(w http.ResponseWriter, r *http.Request) {
+	prID := r.URL.Query().Get("pull_request_id")
+	var v shared.Validation
+	v.RequireQuery("pull_request_id", prID == "")
+	if v.Write(w, r) {
+		return
+	}
+
+	events, err := h.svc.History(r.Context(), prID)
+	if err != nil {
+		shared.WriteServiceError(w, r, err, h.log)
+		return
+	}
+
+	resp := PullRequestHistoryResponse{
+		PullRequestID: prID,
+		Events:        toEventDTOs(events),
+	}
+	shared.WriteJSON(w, http.StatusOK, resp)
+}
+
+// GET /pullRequest/get?pull_request_id=...
+func (h *Handler) handlePullRequestGet(w http.ResponseWriter, r *http.Request) {
+	prID := r.URL.Query().Get("pull_request_id")
+	var v shared.Validation
+	v.RequireQuery("pull_request_id", prID == "")
+	if v.Write(w, r) {
+		return
+	}
+
+	pr, reviewers, err := h.svc.Get(r.Context(), prID)
+	if err != nil {
+		shared.WriteServiceError(w, r, err, h.log)
+		return
+	}
+
+	resp := PullRequestGetResponse{
+		PR: toPullRequestDetailsDTO(pr, reviewers),
+	}
+	shared.WriteJSON(w, http.StatusOK, resp)
+}
+
+// GET /pullRequest/list?status=&author_id=&team_name=&reviewer_id=&created_from=&created_to=&merged_from=&merged_to=&limit=&cursor=
+func (h *Handler) handlePullRequestList(w http.ResponseWriter, r *http.Request) {
+	var v shared.Validation
+	filter := parseListFilter(r.URL.Query(), &v)
+	if v.Write(w, r) {
+		return
+	}
+
+	page, err := h.svc.List(r.Context(), filter)
+	if err != nil {
+		shared.WriteServiceError(w, r, err, h.log)
+		return
+	}
+
+	shared.WriteJSON(w, http.StatusOK, toPullRequestListResponse(page))
+}
@@ -2,11 +2,18 @@
 package pull_request
 
 import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zxchelik/avito-test-task/internal/httpserver/handlers/shared"
 	modelpr "github.com/zxchelik/avito-test-task/internal/model/pull_request"
-	modeluser "github.com/zxchelik/avito-test-task/internal/model/user"
+	modelra "github.com/zxchelik/avito-test-task/internal/model/reviewer_assignment"
+	srvpr "github.com/zxchelik/avito-test-task/internal/service/pull_request"
 )
 
-func toPullRequestDTO(pr *modelpr.PullRequest, reviewers []*modeluser.User) PullRequestDTO {
+func toPullRequestDTO(pr *modelpr.PullRequest, reviewers []*modelra.ReviewerAssignment) PullRequestDTO {
 	dto := PullRequestDTO{
 		PullRequestID:   pr.ID,
 		PullRequestName: pr.Title,
@@ -17,7 +24,10 @@ func toPullRequestDTO(pr *modelpr.PullRequest, reviewers []*modeluser.User) Pull
 	if len(reviewers) > 0 {
 		dto.AssignedReviewers = make([]string, len(reviewers))
 		for i, rv := range reviewers {
-			dto.AssignedReviewers[i] = rv.ID
+			dto.AssignedReviewers[i] = rv.UserId
+			if rv.Fallback {
+				dto.FallbackReviewers = append(dto.FallbackReviewers, rv.UserId)
+			}
 		}
 	} else {
 		dto.AssignedReviewers = []string{}
@@ -25,3 +35,138 @@ func toPullRequestDTO(pr *modelpr.PullRequest, reviewers []*modeluser.User) Pull
 
 	return dto
 }
+
+func toReviewDTOs(reviews []*modelra.ReviewerAssignment) []ReviewDTO {
+	res := make([]ReviewDTO, 0, len(reviews))
+	for _, rv := range reviews {
+		dto := ReviewDTO{
+			ReviewerID:  rv.UserId,
+			SubmittedAt: rv.VerdictAt,
+		}
+		if rv.Verdict != modelra.VerdictNone {
+			v := string(rv.Verdict)
+			dto.Verdict = &v
+		}
+		res = append(res, dto)
+	}
+	return res
+}
+
+func toEventDTOs(events []*modelra.Event) []EventDTO {
+	res := make([]EventDTO, 0, len(events))
+	for _, e := range events {
+		res = append(res, EventDTO{
+			ID:             e.ID,
+			Type:           string(e.Type),
+			UserID:         e.UserId,
+			PreviousUserID: e.PreviousUserId,
+			Verdict:        string(e.Verdict),
+			Status:         e.Status,
+			ActorID:        e.ActorId,
+			Reason:         e.Reason,
+			CreatedAt:      e.CreatedAt,
+		})
+	}
+	return res
+}
+
+func toPullRequestDetailsDTO(pr *modelpr.PullRequest, reviewers []*modelra.ReviewerAssignment) PullRequestDetailsDTO {
+	dto := PullRequestDetailsDTO{
+		PullRequestID:   pr.ID,
+		PullRequestName: pr.Title,
+		AuthorID:        pr.AuthorID,
+		Status:          string(pr.Status),
+		CreatedAt:       pr.CreatedAt,
+		MergedAt:        pr.MergedAt,
+		ClosedAt:        pr.ClosedAt,
+		Reviewers:       make([]ReviewerDTO, 0, len(reviewers)),
+	}
+	for _, rv := range reviewers {
+		reviewer := ReviewerDTO{
+			UserID:        rv.UserId,
+			AssignedAt:    rv.AssignedAt,
+			Fallback:      rv.Fallback,
+			VerdictAt:     rv.VerdictAt,
+			SLABreachedAt: rv.SLABreachedAt,
+		}
+		if rv.Verdict != modelra.VerdictNone {
+			v := string(rv.Verdict)
+			reviewer.Verdict = &v
+		}
+		dto.Reviewers = append(dto.Reviewers, reviewer)
+	}
+	return dto
+}
+
+func toPullRequestListResponse(page *srvpr.ListPage) PullRequestListResponse {
+	resp := PullRequestListResponse{
+		PullRequests: make([]PullRequestDetailsDTO, 0, len(page.PullRequests)),
+	}
+	for _, pr := range page.PullRequests {
+		resp.PullRequests = append(resp.PullRequests, toPullRequestDetailsDTO(pr, page.Reviewers[pr.ID]))
+	}
+	if page.Next != nil {
+		resp.NextCursor = shared.EncodeCursor(page.Next.CreatedAt, page.Next.ID)
+	}
+	return resp
+}
+
+// parseListFilter читает фильтр списка PR из query; статусы — через запятую или повтором параметра.
+// parseListFilter разбирает параметры запроса; ошибки в значениях копятся в v.
+func parseListFilter(q url.Values, v *shared.Validation) modelpr.ListFilter {
+	filter := modelpr.ListFilter{
+		AuthorID:   q.Get("author_id"),
+		TeamName:   q.Get("team_name"),
+		ReviewerID: q.Get("reviewer_id"),
+	}
+
+	for _, v := range q["status"] {
+		for _, st := range strings.Split(v, ",") {
+			if st = strings.TrimSpace(st); st != "" {
+				filter.Statuses = append(filter.Statuses, modelpr.PRStatus(strings.ToUpper(st)))
+			}
+		}
+	}
+
+	bounds := []struct {
+		name string
+		dst  **time.Time
+	}{
+		{"created_from", &filter.CreatedFrom},
+		{"created_to", &filter.CreatedTo},
+		{"merged_from", &filter.MergedFrom},
+		{"merged_to", &filter.MergedTo},
+	}
+	for _, b := range bounds {
+		raw := q.Get(b.name)
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			v.Add(shared.InQuery, b.name, "must be an RFC 3339 timestamp")
+			continue
+		}
+		*b.dst = &t
+	}
+
+	if raw := q.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			v.Add(shared.InQuery, "limit", "must be a positive integer")
+		} else {
+			filter.Limit = limit
+		}
+	}
+
+	if raw := q.Get("cursor"); raw != "" {
+		at, id, err := shared.DecodeCursor(raw)
+		if err != nil {
+			v.Add(shared.InQuery, "cursor", err.Error())
+		} else {
+			filter.After = &modelpr.Cursor{CreatedAt: at, ID: id}
+		}
+	}
+
+	return filter
+}
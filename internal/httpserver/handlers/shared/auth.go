@@ -0,0 +1,30 @@
+package shared
+
+import (
+	"net/http"
+
+	modelauth "github.com/zxchelik/avito-test-task/internal/model/auth"
+	"github.com/zxchelik/avito-test-task/internal/service"
+)
+
+// RequireRole пропускает запрос, только если у вызывающего одна из ролей.
+// Без вызывающего в контексте запрос отклоняется.
+func RequireRole(roles ...modelauth.Role) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			p, ok := service.PrincipalFrom(r.Context())
+			if !ok {
+				WriteError(w, r, http.StatusUnauthorized, ErrorCodeUnauthorized, "bearer token is required")
+				return
+			}
+			if !p.HasRole(roles...) {
+				WriteError(w, r, http.StatusForbidden, ErrorCodeForbidden, "insufficient role")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// AdminOnly — маршруты управления командами, пользователями и интеграциями.
+var AdminOnly = RequireRole(modelauth.RoleAdmin)
@@ -3,20 +3,44 @@ package shared
 type ErrorCode string
 
 const (
-	ErrorCodeTeamExists  ErrorCode = "TEAM_EXISTS"
-	ErrorCodePRExists    ErrorCode = "PR_EXISTS"
-	ErrorCodePRMerged    ErrorCode = "PR_MERGED"
-	ErrorCodeNotAssigned ErrorCode = "NOT_ASSIGNED"
-	ErrorCodeNoCandidate ErrorCode = "NO_CANDIDATE"
-	ErrorCodeNotFound    ErrorCode = "NOT_FOUND"
-	ErrorCodeInternal    ErrorCode = "INTERNAL_ERROR"
+	ErrorCodeTeamExists        ErrorCode = "TEAM_EXISTS"
+	ErrorCodePRExists          ErrorCode = "PR_EXISTS"
+	ErrorCodePRMerged          ErrorCode = "PR_MERGED"
+	ErrorCodePRNotOpen         ErrorCode = "PR_NOT_OPEN"
+	ErrorCodeInvalidTransition ErrorCode = "INVALID_TRANSITION"
+	ErrorCodeNotAssigned       ErrorCode = "NOT_ASSIGNED"
+	ErrorCodeNotApproved       ErrorCode = "NOT_APPROVED"
+	ErrorCodeNoCandidate       ErrorCode = "NO_CANDIDATE"
+	ErrorCodeAtCapacity        ErrorCode = "REVIEWERS_AT_CAPACITY"
+	ErrorCodeNotFound          ErrorCode = "NOT_FOUND"
+	ErrorCodeAlreadyCancelled  ErrorCode = "ALREADY_CANCELLED"
+	ErrorCodeNotDeadLetter     ErrorCode = "NOT_DEAD_LETTER"
+	ErrorCodeIdentityNotMapped ErrorCode = "IDENTITY_NOT_MAPPED"
+	ErrorCodeInvalidSignature  ErrorCode = "INVALID_SIGNATURE"
+	ErrorCodeUnauthorized      ErrorCode = "UNAUTHORIZED"
+	ErrorCodeForbidden         ErrorCode = "FORBIDDEN"
+	ErrorCodeValidation        ErrorCode = "VALIDATION_ERROR"
+	ErrorCodeInternal          ErrorCode = "INTERNAL_ERROR"
 )
 
 type errorBody struct {
 	Code    ErrorCode `json:"code"`
 	Message string    `json:"message"`
+	Details any       `json:"details,omitempty"`
 }
 
 type ErrorResponse struct {
 	Error errorBody `json:"error"`
 }
+
+// ValidationDetails — details ошибки VALIDATION_ERROR.
+type ValidationDetails struct {
+	Fields []FieldError `json:"fields"`
+}
+
+// FieldError — нарушение в одном поле запроса.
+type FieldError struct {
+	In     string `json:"in,omitempty"` // body | query | header | path
+	Field  string `json:"field"`        // имя параметра или JSON Pointer поля тела
+	Reason string `json:"reason"`
+}
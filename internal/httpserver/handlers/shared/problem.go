@@ -0,0 +1,41 @@
+package shared
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// ProblemContentType — тип ответа об ошибке по RFC 7807.
+const ProblemContentType = "application/problem+json"
+
+// Problem — ошибка в формате RFC 7807; code и details — расширения с теми же значениями,
+// что и в ErrorResponse.
+type Problem struct {
+	Type     string    `json:"type"`
+	Title    string    `json:"title"`
+	Status   int       `json:"status"`
+	Detail   string    `json:"detail,omitempty"`
+	Instance string    `json:"instance,omitempty"`
+	Code     ErrorCode `json:"code"`
+	Details  any       `json:"details,omitempty"`
+}
+
+// wantsProblem — клиент явно попросил application/problem+json в Accept.
+func wantsProblem(r *http.Request) bool {
+	return r != nil && strings.Contains(r.Header.Get("Accept"), ProblemContentType)
+}
+
+func writeProblem(w http.ResponseWriter, r *http.Request, status int, code ErrorCode, msg string, details any) {
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(Problem{
+		Type:     "about:blank", // код ошибки — в code, отдельных страниц с описанием нет
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   msg,
+		Instance: r.URL.Path,
+		Code:     code,
+		Details:  details,
+	})
+}
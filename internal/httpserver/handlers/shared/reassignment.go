@@ -0,0 +1,9 @@
+package shared
+
+// ReassignmentFailure возвращает код и текст причины, по которой ревьюверу не нашлась замена.
+func ReassignmentFailure(err error) (ErrorCode, string) {
+	if code, msg, ok := DescribeError(err); ok {
+		return code, msg
+	}
+	return ErrorCodeNoCandidate, "no active replacement candidate"
+}
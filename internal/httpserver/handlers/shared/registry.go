@@ -0,0 +1,166 @@
+package shared
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/zxchelik/avito-test-task/internal/model"
+	modelabsence "github.com/zxchelik/avito-test-task/internal/model/absence"
+	modelauth "github.com/zxchelik/avito-test-task/internal/model/auth"
+	modelco "github.com/zxchelik/avito-test-task/internal/model/code_owner"
+	modelforge "github.com/zxchelik/avito-test-task/internal/model/forge"
+	modelpr "github.com/zxchelik/avito-test-task/internal/model/pull_request"
+	modelra "github.com/zxchelik/avito-test-task/internal/model/reviewer_assignment"
+	modelteam "github.com/zxchelik/avito-test-task/internal/model/team"
+	modeluser "github.com/zxchelik/avito-test-task/internal/model/user"
+	modelwh "github.com/zxchelik/avito-test-task/internal/model/webhook"
+)
+
+// errorMapping — как доменная ошибка выглядит в API.
+type errorMapping struct {
+	target  error
+	status  int
+	code    ErrorCode
+	message string          // пусто — текст самой ошибки
+	field   *FieldError     // для VALIDATION_ERROR: поле запроса, к которому относится ошибка
+	details func(error) any // машиночитаемые подробности, если они есть
+}
+
+// errorRegistry — единственное место, где ошибки из internal/model сопоставляются
+// со статусом, кодом и текстом ответа. Побеждает первая подходящая запись,
+// поэтому частные ошибки стоят раньше общих.
+var errorRegistry = []errorMapping{
+	// Существование
+	{target: modelteam.ErrTeamExists, status: http.StatusBadRequest, code: ErrorCodeTeamExists, message: "team_name already exists"},
+	{target: modelpr.ErrPRExists, status: http.StatusConflict, code: ErrorCodePRExists, message: "PR id already exists"},
+	{target: modelteam.ErrFallbackTeamNotFound, status: http.StatusNotFound, code: ErrorCodeNotFound, message: "fallback team not found"},
+	{target: modeluser.ErrTeamNotFound, status: http.StatusNotFound, code: ErrorCodeNotFound, message: "team not found"},
+	{target: model.ErrNotFound, status: http.StatusNotFound, code: ErrorCodeNotFound, message: "resource not found"},
+
+	// Доступ
+	{target: modelauth.ErrUnauthorized, status: http.StatusUnauthorized, code: ErrorCodeUnauthorized, message: "invalid or expired token"},
+	{target: modelauth.ErrForbidden, status: http.StatusForbidden, code: ErrorCodeForbidden, message: "operation is not allowed for this caller"},
+
+	// Состояние PR
+	{target: modelpr.ErrNotApproved, status: http.StatusConflict, code: ErrorCodeNotApproved, message: "PR does not satisfy the merge rule", details: notApprovedDetails},
+	{target: modelpr.ErrPRAlreadyMerged, status: http.StatusConflict, code: ErrorCodePRMerged, message: "PR is already merged"},
+	{target: modelpr.ErrPRNotOpen, status: http.StatusConflict, code: ErrorCodePRNotOpen, message: "PR is DRAFT or CLOSED"},
+	{target: modelpr.ErrInvalidTransition, status: http.StatusConflict, code: ErrorCodeInvalidTransition, message: "PR status transition is not allowed"},
+
+	// Подбор ревьюверов
+	{target: modelra.ErrReviewerNotFoundInPR, status: http.StatusConflict, code: ErrorCodeNotAssigned, message: "reviewer is not assigned to this PR"},
+	{target: modelra.ErrNoReviewerCandidatesLeft, status: http.StatusConflict, code: ErrorCodeNoCandidate, message: "no active reviewer candidate in team and its fallback teams"},
+	{target: modelra.ErrReviewersAtCapacity, status: http.StatusConflict, code: ErrorCodeAtCapacity, message: "all reviewer candidates are at their open reviews limit"},
+	{target: modeluser.ErrUserInactive, status: http.StatusConflict, code: ErrorCodeNoCandidate, message: "author is inactive"},
+
+	// Прочие конфликты
+	{target: modelabsence.ErrAlreadyCancelled, status: http.StatusConflict, code: ErrorCodeAlreadyCancelled},
+	{target: modelwh.ErrNotDead, status: http.StatusConflict, code: ErrorCodeNotDeadLetter},
+	{target: modelforge.ErrIdentityNotMapped, status: http.StatusUnprocessableEntity, code: ErrorCodeIdentityNotMapped, message: "pull request author is not mapped to a user"},
+
+	// Невалидные значения полей
+	invalidField(modelpr.ErrReviewersCountOutOfBounds, InBody, "/reviewers_count", "reviewers_count is out of team min_reviewers..max_reviewers bounds"),
+	invalidField(modelpr.ErrInvalidListFilter, InQuery, "status", "status must be DRAFT, OPEN, MERGED or CLOSED; limit must be within 1..200"),
+	invalidField(modelra.ErrUnknownVerdict, InBody, "/verdict", "verdict must be APPROVED, CHANGES_REQUESTED or COMMENTED"),
+	invalidField(modelteam.ErrUnknownReviewerStrategy, InBody, "/reviewer_strategy", "unknown reviewer_strategy"),
+	invalidField(modelteam.ErrInvalidReviewerBounds, InBody, "/min_reviewers", ""),
+	invalidField(modelteam.ErrInvalidFallbackTeams, InBody, "/fallback_teams", ""),
+	invalidField(modelteam.ErrInvalidMaxOpenReviews, InBody, "/default_max_open_reviews", ""),
+	invalidField(modelteam.ErrInvalidReviewSLA, InBody, "/review_sla_weekday_hours", ""),
+	invalidField(modeluser.ErrInvalidMaxOpenReviews, InBody, "/max_open_reviews", ""),
+	invalidField(modeluser.ErrNotTeamMember, InBody, "/user_ids", "user_ids contain users outside the team"),
+	invalidField(modelabsence.ErrReasonRequired, InBody, "/reason", ""),
+	invalidField(modelabsence.ErrInvalidPeriod, InBody, "/ends_at", ""),
+	invalidField(modelabsence.ErrAlreadyEnded, InBody, "/ends_at", ""),
+	invalidField(modelco.ErrInvalidPattern, InBody, "/pattern", ""),
+	invalidField(modelwh.ErrInvalidURL, InBody, "/url", ""),
+	invalidField(modelwh.ErrEmptySecret, InBody, "/secret", ""),
+	invalidField(modelwh.ErrNoEventTypes, InBody, "/event_types", ""),
+	invalidField(modelwh.ErrUnknownEventType, InBody, "/event_types", ""),
+	invalidField(modelforge.ErrUnknownForge, InBody, "/forge", "forge must be github or gitlab"),
+}
+
+func invalidField(target error, in, field, message string) errorMapping {
+	return errorMapping{
+		target:  target,
+		status:  http.StatusBadRequest,
+		code:    ErrorCodeValidation,
+		message: message,
+		field:   &FieldError{In: in, Field: field},
+	}
+}
+
+// WriteServiceError пишет ответ для ошибки сервиса по errorRegistry;
+// ошибка, которой там нет, логируется и отдаётся как INTERNAL_ERROR (500).
+func WriteServiceError(w http.ResponseWriter, r *http.Request, err error, log *slog.Logger) {
+	m, ok := lookupError(err)
+	if !ok {
+		WriteInternalError(w, r, err, log)
+		return
+	}
+
+	msg := m.messageFor(err)
+	var details any
+	switch {
+	case m.details != nil:
+		details = m.details(err)
+	case m.field != nil:
+		f := *m.field
+		f.Reason = msg
+		details = ValidationDetails{Fields: []FieldError{f}}
+	}
+	WriteErrorDetails(w, r, m.status, m.code, msg, details)
+}
+
+// DescribeError возвращает код и текст ответа для доменной ошибки; ok=false — ошибки нет в реестре.
+func DescribeError(err error) (code ErrorCode, message string, ok bool) {
+	m, ok := lookupError(err)
+	if !ok {
+		return "", "", false
+	}
+	return m.code, m.messageFor(err), true
+}
+
+func lookupError(err error) (errorMapping, bool) {
+	for _, m := range errorRegistry {
+		if errors.Is(err, m.target) {
+			return m, true
+		}
+	}
+	return errorMapping{}, false
+}
+
+func (m errorMapping) messageFor(err error) string {
+	if m.message != "" {
+		return m.message
+	}
+	return err.Error()
+}
+
+// NotApprovedDetails — details ошибки NOT_APPROVED.
+type NotApprovedDetails struct {
+	MergeRule         string   `json:"merge_rule"`
+	RequiredApprovals int      `json:"required_approvals,omitempty"` // для min_approvals
+	Approvals         int      `json:"approvals"`
+	BlockingReviewers []string `json:"blocking_reviewers"`
+}
+
+func notApprovedDetails(err error) any {
+	var notApproved *modelpr.NotApprovedError
+	if !errors.As(err, &notApproved) {
+		return nil
+	}
+	dto := NotApprovedDetails{
+		MergeRule:         string(notApproved.Rule.Kind),
+		Approvals:         notApproved.Approvals,
+		BlockingReviewers: notApproved.Blocking,
+	}
+	if notApproved.Rule.Kind == modelpr.MergeRuleMinApprovals {
+		dto.RequiredApprovals = notApproved.Rule.MinApprovals
+	}
+	if dto.BlockingReviewers == nil {
+		dto.BlockingReviewers = []string{}
+	}
+	return dto
+}
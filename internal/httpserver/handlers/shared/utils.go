@@ -15,17 +15,27 @@ func WriteJSON(w http.ResponseWriter, status int, v any) {
 	_ = json.NewEncoder(w).Encode(v)
 }
 
-func WriteError(w http.ResponseWriter, status int, code ErrorCode, msg string) {
+func WriteError(w http.ResponseWriter, r *http.Request, status int, code ErrorCode, msg string) {
+	WriteErrorDetails(w, r, status, code, msg, nil)
+}
+
+// WriteErrorDetails пишет ошибку с дополнительными машиночитаемыми данными.
+// Клиент, принимающий application/problem+json, получает её в формате RFC 7807.
+func WriteErrorDetails(w http.ResponseWriter, r *http.Request, status int, code ErrorCode, msg string, details any) {
+	if wantsProblem(r) {
+		writeProblem(w, r, status, code, msg, details)
+		return
+	}
 	WriteJSON(w, status, ErrorResponse{
 		Error: errorBody{
 			Code:    code,
 			Message: msg,
+			Details: details,
 		},
 	})
 }
 
-func WriteInternalError(w http.ResponseWriter, err error, log *slog.Logger) {
-	// В проде сюда бы логгер.
+func WriteInternalError(w http.ResponseWriter, r *http.Request, err error, log *slog.Logger) {
 	log.Error("Internal Server Error", slog.String("error", err.Error()))
-	WriteError(w, http.StatusInternalServerError, ErrorCodeInternal, "internal server error")
+	WriteError(w, r, http.StatusInternalServerError, ErrorCodeInternal, "internal server error")
 }
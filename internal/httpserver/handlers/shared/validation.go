@@ -0,0 +1,70 @@
+package shared
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Где находится поле с ошибкой валидации.
+const (
+	InBody   = "body"
+	InQuery  = "query"
+	InHeader = "header"
+)
+
+// DecodeJSON разбирает тело запроса в v; если тело не разбирается,
+// пишет VALIDATION_ERROR и возвращает false.
+func DecodeJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	err := json.NewDecoder(r.Body).Decode(v)
+	if err == nil {
+		return true
+	}
+
+	field := FieldError{In: InBody, Field: "/", Reason: err.Error()}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		field.Field = "/" + strings.ReplaceAll(typeErr.Field, ".", "/")
+		field.Reason = "must be " + typeErr.Type.String()
+	}
+	WriteValidationError(w, r, "invalid JSON", field)
+	return false
+}
+
+// WriteValidationError пишет VALIDATION_ERROR (400) с перечнем полей.
+func WriteValidationError(w http.ResponseWriter, r *http.Request, msg string, fields ...FieldError) {
+	WriteErrorDetails(w, r, http.StatusBadRequest, ErrorCodeValidation, msg, ValidationDetails{Fields: fields})
+}
+
+// Validation собирает ошибки полей запроса, чтобы вернуть их одним ответом.
+type Validation struct {
+	fields []FieldError
+}
+
+// RequireBody отмечает незаполненное обязательное поле тела.
+func (v *Validation) RequireBody(field string, missing bool) {
+	if missing {
+		v.Add(InBody, "/"+field, "is required")
+	}
+}
+
+// RequireQuery отмечает отсутствующий обязательный параметр запроса.
+func (v *Validation) RequireQuery(param string, missing bool) {
+	if missing {
+		v.Add(InQuery, param, "is required")
+	}
+}
+
+func (v *Validation) Add(in, field, reason string) {
+	v.fields = append(v.fields, FieldError{In: in, Field: field, Reason: reason})
+}
+
+// Write пишет VALIDATION_ERROR, если ошибки есть, и сообщает, был ли записан ответ.
+func (v *Validation) Write(w http.ResponseWriter, r *http.Request) bool {
+	if len(v.fields) == 0 {
+		return false
+	}
+	WriteValidationError(w, r, "request validation failed", v.fields...)
+	return true
+}
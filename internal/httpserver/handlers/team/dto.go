@@ -1,21 +1,90 @@
 package team
 
 type TeamMemberDTO struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	IsActive bool   `json:"is_active"`
+	UserID         string `json:"user_id"`
+	Username       string `json:"username"`
+	IsActive       bool   `json:"is_active"`
+	MaxOpenReviews int    `json:"max_open_reviews,omitempty"` // 0 — прежний личный лимит или лимит команды
 }
 
 type TeamDTO struct {
-	TeamName string          `json:"team_name"`
-	Members  []TeamMemberDTO `json:"members"`
+	TeamName              string          `json:"team_name"`
+	ReviewerStrategy      string          `json:"reviewer_strategy"` // random | round_robin | least_loaded | seeded
+	MinReviewers          int             `json:"min_reviewers"`
+	MaxReviewers          int             `json:"max_reviewers"`
+	FallbackTeams         []string        `json:"fallback_teams"`
+	DefaultMaxOpenReviews int             `json:"default_max_open_reviews"` // 0 — без ограничений
+	ReviewSLAWeekdayHours int             `json:"review_sla_weekday_hours"` // 0 — без SLA
+	SLAAutoReassign       bool            `json:"sla_auto_reassign"`
+	Members               []TeamMemberDTO `json:"members"`
 }
 
 type TeamAddRequest struct {
-	TeamName string          `json:"team_name"`
-	Members  []TeamMemberDTO `json:"members"`
+	TeamName              string          `json:"team_name"`
+	ReviewerStrategy      string          `json:"reviewer_strategy,omitempty"` // по умолчанию least_loaded
+	MinReviewers          *int            `json:"min_reviewers,omitempty"`     // по умолчанию 1
+	MaxReviewers          *int            `json:"max_reviewers,omitempty"`     // по умолчанию 2
+	FallbackTeams         []string        `json:"fallback_teams,omitempty"`    // резервные команды по приоритету
+	DefaultMaxOpenReviews int             `json:"default_max_open_reviews,omitempty"`
+	ReviewSLAWeekdayHours int             `json:"review_sla_weekday_hours,omitempty"` // часов по будням на первый вердикт
+	SLAAutoReassign       bool            `json:"sla_auto_reassign,omitempty"`
+	Members               []TeamMemberDTO `json:"members"`
 }
 
 type TeamAddResponse struct {
 	Team TeamDTO `json:"team"`
 }
+
+type TeamSettingsDTO struct {
+	TeamName              string   `json:"team_name"`
+	ReviewerStrategy      string   `json:"reviewer_strategy"`
+	MinReviewers          int      `json:"min_reviewers"`
+	MaxReviewers          int      `json:"max_reviewers"`
+	FallbackTeams         []string `json:"fallback_teams"`
+	DefaultMaxOpenReviews int      `json:"default_max_open_reviews"`
+	ReviewSLAWeekdayHours int      `json:"review_sla_weekday_hours"`
+	SLAAutoReassign       bool     `json:"sla_auto_reassign"`
+}
+
+// TeamSettingsRequest — частичное обновление: отсутствующие поля не меняются.
+type TeamSettingsRequest struct {
+	TeamName              string    `json:"team_name"`
+	ReviewerStrategy      *string   `json:"reviewer_strategy,omitempty"`
+	MinReviewers          *int      `json:"min_reviewers,omitempty"`
+	MaxReviewers          *int      `json:"max_reviewers,omitempty"`
+	FallbackTeams         *[]string `json:"fallback_teams,omitempty"`           // [] очищает список
+	DefaultMaxOpenReviews *int      `json:"default_max_open_reviews,omitempty"` // 0 снимает лимит
+	ReviewSLAWeekdayHours *int      `json:"review_sla_weekday_hours,omitempty"` // 0 отключает SLA
+	SLAAutoReassign       *bool     `json:"sla_auto_reassign,omitempty"`
+}
+
+type TeamSettingsResponse struct {
+	Settings TeamSettingsDTO `json:"settings"`
+}
+
+// TeamDeactivateRequest — пустой user_ids деактивирует всю команду.
+type TeamDeactivateRequest struct {
+	TeamName string   `json:"team_name"`
+	UserIDs  []string `json:"user_ids,omitempty"`
+}
+
+type ReassignedReviewDTO struct {
+	PullRequestID string `json:"pull_request_id"`
+	OldUserID     string `json:"old_user_id"`
+	ReplacedBy    string `json:"replaced_by"`
+}
+
+// UncoveredReviewDTO — ревью, для которого замена не нашлась; old_user_id остаётся назначенным.
+type UncoveredReviewDTO struct {
+	PullRequestID string `json:"pull_request_id"`
+	OldUserID     string `json:"old_user_id"`
+	Code          string `json:"code"`
+	Reason        string `json:"reason"`
+}
+
+type TeamDeactivateResponse struct {
+	TeamName      string                `json:"team_name"`
+	Deactivated   []string              `json:"deactivated_user_ids"`
+	Reassigned    []ReassignedReviewDTO `json:"reassigned"`
+	NotReassigned []UncoveredReviewDTO  `json:"not_reassigned"`
+}
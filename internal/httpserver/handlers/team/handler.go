@@ -1,11 +1,8 @@
 package team
 
 import (
-	"encoding/json"
-	"errors"
 	"github.com/go-chi/chi/v5"
 	"github.com/zxchelik/avito-test-task/internal/httpserver/handlers/shared"
-	"github.com/zxchelik/avito-test-task/internal/model"
 	modelteam "github.com/zxchelik/avito-test-task/internal/model/team"
 	modeluser "github.com/zxchelik/avito-test-task/internal/model/user"
 	srvteam "github.com/zxchelik/avito-test-task/internal/service/team"
@@ -24,41 +21,51 @@ func New(svc *srvteam.Service, log *slog.Logger) *Handler {
 
 // Register регистрирует маршруты команды.
 func (h *Handler) Register(r chi.Router) {
-	r.Post("/team/add", h.handleTeamAdd)
+	r.With(shared.AdminOnly).Post("/team/add", h.handleTeamAdd)
 	r.Get("/team/get", h.handleTeamGet)
+	r.With(shared.AdminOnly).Post("/team/settings", h.handleTeamSettings)
+	r.With(shared.AdminOnly).Post("/team/deactivate", h.handleTeamDeactivate)
 }
 
 // POST /team/add
 func (h *Handler) handleTeamAdd(w http.ResponseWriter, r *http.Request) {
 	var req TeamAddRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		shared.WriteError(w, http.StatusBadRequest, shared.ErrorCodeInternal, "invalid JSON")
+	if !shared.DecodeJSON(w, r, &req) {
 		return
 	}
-	if req.TeamName == "" {
-		shared.WriteError(w, http.StatusBadRequest, shared.ErrorCodeInternal, "team_name is required")
+	var v shared.Validation
+	v.RequireBody("team_name", req.TeamName == "")
+	if v.Write(w, r) {
 		return
 	}
 
 	team := &modelteam.Team{
-		Name: req.TeamName,
+		Name:                  req.TeamName,
+		ReviewerStrategy:      modelteam.ReviewerStrategy(req.ReviewerStrategy),
+		MinReviewers:          modelteam.DefaultMinReviewers,
+		MaxReviewers:          modelteam.DefaultMaxReviewers,
+		FallbackTeams:         req.FallbackTeams,
+		DefaultMaxOpenReviews: req.DefaultMaxOpenReviews,
+		ReviewSLAWeekdayHours: req.ReviewSLAWeekdayHours,
+		SLAAutoReassign:       req.SLAAutoReassign,
 	}
+	modelteam.SettingsUpdate{
+		MinReviewers: req.MinReviewers,
+		MaxReviewers: req.MaxReviewers,
+	}.Apply(team)
 	members := make([]*modeluser.User, 0, len(req.Members))
 	for _, m := range req.Members {
 		members = append(members, &modeluser.User{
-			ID:       m.UserID,
-			Username: m.Username,
-			IsActive: m.IsActive,
+			ID:             m.UserID,
+			Username:       m.Username,
+			IsActive:       m.IsActive,
+			MaxOpenReviews: m.MaxOpenReviews,
 		})
 	}
 
 	createdTeam, createdMembers, err := h.svc.Add(r.Context(), team, members)
 	if err != nil {
-		if errors.Is(err, model.ErrAlreadyExists) {
-			shared.WriteError(w, http.StatusBadRequest, shared.ErrorCodeTeamExists, "team_name already exists")
-			return
-		}
-		shared.WriteInternalError(w, err, h.log)
+		shared.WriteServiceError(w, r, err, h.log)
 		return
 	}
 
@@ -71,21 +78,63 @@ func (h *Handler) handleTeamAdd(w http.ResponseWriter, r *http.Request) {
 // GET /team/get?team_name=...
 func (h *Handler) handleTeamGet(w http.ResponseWriter, r *http.Request) {
 	teamName := r.URL.Query().Get("team_name")
-	if teamName == "" {
-		shared.WriteError(w, http.StatusBadRequest, shared.ErrorCodeInternal, "team_name is required")
+	var v shared.Validation
+	v.RequireQuery("team_name", teamName == "")
+	if v.Write(w, r) {
 		return
 	}
 
 	team, members, err := h.svc.Get(r.Context(), teamName)
 	if err != nil {
-		if errors.Is(err, model.ErrNotFound) {
-			shared.WriteError(w, http.StatusNotFound, shared.ErrorCodeNotFound, "team not found")
-			return
-		}
-		shared.WriteInternalError(w, err, h.log)
+		shared.WriteServiceError(w, r, err, h.log)
 		return
 	}
 
 	resp := toTeamDTO(team, members)
 	shared.WriteJSON(w, http.StatusOK, resp)
 }
+
+// POST /team/settings
+func (h *Handler) handleTeamSettings(w http.ResponseWriter, r *http.Request) {
+	var req TeamSettingsRequest
+	if !shared.DecodeJSON(w, r, &req) {
+		return
+	}
+	var v shared.Validation
+	v.RequireBody("team_name", req.TeamName == "")
+	if v.Write(w, r) {
+		return
+	}
+
+	team, err := h.svc.UpdateSettings(r.Context(), req.TeamName, toSettingsUpdate(req))
+	if err != nil {
+		shared.WriteServiceError(w, r, err, h.log)
+		return
+	}
+
+	resp := TeamSettingsResponse{
+		Settings: toTeamSettingsDTO(team),
+	}
+	shared.WriteJSON(w, http.StatusOK, resp)
+}
+
+// POST /team/deactivate
+func (h *Handler) handleTeamDeactivate(w http.ResponseWriter, r *http.Request) {
+	var req TeamDeactivateRequest
+	if !shared.DecodeJSON(w, r, &req) {
+		return
+	}
+	var v shared.Validation
+	v.RequireBody("team_name", req.TeamName == "")
+	if v.Write(w, r) {
+		return
+	}
+
+	users, results, err := h.svc.Deactivate(r.Context(), req.TeamName, req.UserIDs)
+	if err != nil {
+		shared.WriteServiceError(w, r, err, h.log)
+		return
+	}
+
+	shared.WriteJSON(w, http.StatusOK, toTeamDeactivateResponse(req.TeamName, users, results))
+}
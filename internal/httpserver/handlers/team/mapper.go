@@ -1,6 +1,8 @@
 package team
 
 import (
+	"github.com/zxchelik/avito-test-task/internal/httpserver/handlers/shared"
+	modelra "github.com/zxchelik/avito-test-task/internal/model/reviewer_assignment"
 	modelteam "github.com/zxchelik/avito-test-task/internal/model/team"
 	modeluser "github.com/zxchelik/avito-test-task/internal/model/user"
 )
@@ -12,9 +14,10 @@ func toTeamMemberDTOs(users []*modeluser.User) []TeamMemberDTO {
 			continue
 		}
 		res = append(res, TeamMemberDTO{
-			UserID:   u.ID,       // скорректируй поля, если в модели другое имя
-			Username: u.Username, // и тут
-			IsActive: u.IsActive,
+			UserID:         u.ID,       // скорректируй поля, если в модели другое имя
+			Username:       u.Username, // и тут
+			IsActive:       u.IsActive,
+			MaxOpenReviews: u.MaxOpenReviews,
 		})
 	}
 	return res
@@ -22,7 +25,85 @@ func toTeamMemberDTOs(users []*modeluser.User) []TeamMemberDTO {
 
 func toTeamDTO(team *modelteam.Team, members []*modeluser.User) TeamDTO {
 	return TeamDTO{
-		TeamName: team.Name, // поправь, если поле называется иначе
-		Members:  toTeamMemberDTOs(members),
+		TeamName:              team.Name, // поправь, если поле называется иначе
+		ReviewerStrategy:      string(team.ReviewerStrategy),
+		MinReviewers:          team.MinReviewers,
+		MaxReviewers:          team.MaxReviewers,
+		FallbackTeams:         fallbackTeams(team),
+		DefaultMaxOpenReviews: team.DefaultMaxOpenReviews,
+		ReviewSLAWeekdayHours: team.ReviewSLAWeekdayHours,
+		SLAAutoReassign:       team.SLAAutoReassign,
+		Members:               toTeamMemberDTOs(members),
 	}
 }
+
+func toTeamSettingsDTO(team *modelteam.Team) TeamSettingsDTO {
+	return TeamSettingsDTO{
+		TeamName:              team.Name,
+		ReviewerStrategy:      string(team.ReviewerStrategy),
+		MinReviewers:          team.MinReviewers,
+		MaxReviewers:          team.MaxReviewers,
+		FallbackTeams:         fallbackTeams(team),
+		DefaultMaxOpenReviews: team.DefaultMaxOpenReviews,
+		ReviewSLAWeekdayHours: team.ReviewSLAWeekdayHours,
+		SLAAutoReassign:       team.SLAAutoReassign,
+	}
+}
+
+func fallbackTeams(team *modelteam.Team) []string {
+	if team.FallbackTeams == nil {
+		return []string{}
+	}
+	return team.FallbackTeams
+}
+
+func toSettingsUpdate(req TeamSettingsRequest) modelteam.SettingsUpdate {
+	update := modelteam.SettingsUpdate{
+		MinReviewers:          req.MinReviewers,
+		MaxReviewers:          req.MaxReviewers,
+		FallbackTeams:         req.FallbackTeams,
+		DefaultMaxOpenReviews: req.DefaultMaxOpenReviews,
+		ReviewSLAWeekdayHours: req.ReviewSLAWeekdayHours,
+		SLAAutoReassign:       req.SLAAutoReassign,
+	}
+	if req.ReviewerStrategy != nil {
+		strategy := modelteam.ReviewerStrategy(*req.ReviewerStrategy)
+		update.ReviewerStrategy = &strategy
+	}
+	return update
+}
+
+func toTeamDeactivateResponse(
+	teamName string,
+	users []*modeluser.User,
+	results []*modelra.Reassignment,
+) TeamDeactivateResponse {
+	resp := TeamDeactivateResponse{
+		TeamName:      teamName,
+		Deactivated:   make([]string, 0, len(users)),
+		Reassigned:    make([]ReassignedReviewDTO, 0, len(results)),
+		NotReassigned: []UncoveredReviewDTO{},
+	}
+	for _, u := range users {
+		resp.Deactivated = append(resp.Deactivated, u.ID)
+	}
+	for _, r := range results {
+		if r.Replaced() {
+			resp.Reassigned = append(resp.Reassigned, ReassignedReviewDTO{
+				PullRequestID: r.PrId,
+				OldUserID:     r.OldUserId,
+				ReplacedBy:    r.NewUserId,
+			})
+			continue
+		}
+
+		code, reason := shared.ReassignmentFailure(r.Reason)
+		resp.NotReassigned = append(resp.NotReassigned, UncoveredReviewDTO{
+			PullRequestID: r.PrId,
+			OldUserID:     r.OldUserId,
+			Code:          string(code),
+			Reason:        reason,
+		})
+	}
+	return resp
+}
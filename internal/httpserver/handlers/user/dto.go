@@ -1,11 +1,14 @@
 // handlers/user/dto.go
 package user
 
+import "time"
+
 type UserDTO struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	TeamName string `json:"team_name"`
-	IsActive bool   `json:"is_active"`
+	UserID         string `json:"user_id"`
+	Username       string `json:"username"`
+	TeamName       string `json:"team_name"`
+	IsActive       bool   `json:"is_active"`
+	MaxOpenReviews *int   `json:"max_open_reviews"` // null — личный лимит не задан
 }
 
 type SetIsActiveRequest struct {
@@ -15,16 +18,47 @@ type SetIsActiveRequest struct {
 
 type SetIsActiveResponse struct {
 	User UserDTO `json:"user"`
+	// Заполняются только при деактивации.
+	Reassigned    []ReassignedReviewDTO `json:"reassigned,omitempty"`
+	NotReassigned []KeptReviewDTO       `json:"not_reassigned,omitempty"`
+}
+
+type ReassignedReviewDTO struct {
+	PullRequestID string `json:"pull_request_id"`
+	ReplacedBy    string `json:"replaced_by"`
+	Fallback      bool   `json:"replaced_by_fallback"`
+}
+
+// KeptReviewDTO — PR, на котором замена не нашлась и пользователь остался ревьювером.
+type KeptReviewDTO struct {
+	PullRequestID string `json:"pull_request_id"`
+	Code          string `json:"code"`
+	Reason        string `json:"reason"`
+}
+
+type SetMaxOpenReviewsRequest struct {
+	UserID         string `json:"user_id"`
+	MaxOpenReviews int    `json:"max_open_reviews"` // 0 — снять лимит
+}
+
+type SetMaxOpenReviewsResponse struct {
+	User UserDTO `json:"user"`
 }
 
 type PullRequestShortDTO struct {
-	PullRequestID   string `json:"pull_request_id"`
-	PullRequestName string `json:"pull_request_name"`
-	AuthorID        string `json:"author_id"`
-	Status          string `json:"status"`
+	PullRequestID   string     `json:"pull_request_id"`
+	PullRequestName string     `json:"pull_request_name"`
+	AuthorID        string     `json:"author_id"`
+	Status          string     `json:"status"`
+	AssignedAt      time.Time  `json:"assigned_at"`
+	Verdict         *string    `json:"verdict"` // вердикт пользователя, null — ещё нет
+	VerdictAt       *time.Time `json:"verdict_at,omitempty"`
 }
 
 type UserReviewsResponse struct {
-	UserID       string                `json:"user_id"`
-	PullRequests []PullRequestShortDTO `json:"pull_requests"`
+	UserID         string                `json:"user_id"`
+	OpenReviews    int                   `json:"open_reviews"`
+	MaxOpenReviews *int                  `json:"max_open_reviews"` // действующий лимит, null — без ограничений
+	PullRequests   []PullRequestShortDTO `json:"pull_requests"`
+	NextCursor     string                `json:"next_cursor,omitempty"` // пусто — страница последняя
 }
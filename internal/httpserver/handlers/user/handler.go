@@ -1,15 +1,12 @@
 package user
 
 import (
-	"encoding/json"
-	"errors"
 	"github.com/zxchelik/avito-test-task/internal/httpserver/handlers/shared"
 	"log/slog"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
 
-	"github.com/zxchelik/avito-test-task/internal/model"
 	srvuser "github.com/zxchelik/avito-test-task/internal/service/user"
 )
 
@@ -23,65 +20,88 @@ func New(svc *srvuser.Service, log *slog.Logger) *Handler {
 }
 
 func (h *Handler) Register(r chi.Router) {
-	r.Post("/users/setIsActive", h.handleUsersSetIsActive)
+	r.With(shared.AdminOnly).Post("/users/setIsActive", h.handleUsersSetIsActive)
 	r.Get("/users/getReview", h.handleUsersGetReview)
+	r.With(shared.AdminOnly).Post("/users/setMaxOpenReviews", h.handleUsersSetMaxOpenReviews)
 }
 
 // POST /users/setIsActive
 func (h *Handler) handleUsersSetIsActive(w http.ResponseWriter, r *http.Request) {
 	var req SetIsActiveRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		shared.WriteError(w, http.StatusBadRequest, shared.ErrorCodeInternal, "invalid JSON")
+	if !shared.DecodeJSON(w, r, &req) {
 		return
 	}
-	if req.UserID == "" {
-		shared.WriteError(w, http.StatusBadRequest, shared.ErrorCodeInternal, "user_id is required")
+	var v shared.Validation
+	v.RequireBody("user_id", req.UserID == "")
+	if v.Write(w, r) {
 		return
 	}
 
-	user, err := h.svc.SetIsActive(r.Context(), req.UserID, req.IsActive)
+	user, results, err := h.svc.SetIsActive(r.Context(), req.UserID, req.IsActive)
 	if err != nil {
-		if errors.Is(err, model.ErrNotFound) {
-			shared.WriteError(w, http.StatusNotFound, shared.ErrorCodeNotFound, "user not found")
-			return
-		}
-		shared.WriteInternalError(w, err, h.log)
+		shared.WriteServiceError(w, r, err, h.log)
 		return
 	}
 
 	resp := SetIsActiveResponse{
 		User: toUserDTO(user),
 	}
+	resp.Reassigned, resp.NotReassigned = toReassignmentDTOs(results)
 	shared.WriteJSON(w, http.StatusOK, resp)
 }
 
-// GET /users/getReview?user_id=...
+// POST /users/setMaxOpenReviews
+func (h *Handler) handleUsersSetMaxOpenReviews(w http.ResponseWriter, r *http.Request) {
+	var req SetMaxOpenReviewsRequest
+	if !shared.DecodeJSON(w, r, &req) {
+		return
+	}
+	var v shared.Validation
+	v.RequireBody("user_id", req.UserID == "")
+	if v.Write(w, r) {
+		return
+	}
+
+	user, err := h.svc.SetMaxOpenReviews(r.Context(), req.UserID, req.MaxOpenReviews)
+	if err != nil {
+		shared.WriteServiceError(w, r, err, h.log)
+		return
+	}
+
+	resp := SetMaxOpenReviewsResponse{
+		User: toUserDTO(user),
+	}
+	shared.WriteJSON(w, http.StatusOK, resp)
+}
+
+// GET /users/getReview?user_id=...&status=&since=&limit=&cursor=
 func (h *Handler) handleUsersGetReview(w http.ResponseWriter, r *http.Request) {
-	userID := r.URL.Query().Get("user_id")
-	if userID == "" {
-		shared.WriteError(w, http.StatusBadRequest, shared.ErrorCodeInternal, "user_id is required")
+	var v shared.Validation
+	filter := parseReviewFilter(r.URL.Query(), &v)
+	v.RequireQuery("user_id", filter.ReviewerID == "")
+	if v.Write(w, r) {
 		return
 	}
 
-	prs, err := h.svc.ListUserReviews(r.Context(), userID)
+	page, load, err := h.svc.ListUserReviews(r.Context(), filter)
 	if err != nil {
-		if errors.Is(err, model.ErrNotFound) {
-			// Если сервис вернёт ErrNotFound для пользователя.
-			shared.WriteError(w, http.StatusNotFound, shared.ErrorCodeNotFound, "user not found")
-			return
-		}
-		shared.WriteInternalError(w, err, h.log)
+		shared.WriteServiceError(w, r, err, h.log)
 		return
 	}
 
-	out := make([]PullRequestShortDTO, 0, len(prs))
-	for _, pr := range prs {
-		out = append(out, toPullRequestShortDTO(pr))
+	out := make([]PullRequestShortDTO, 0, len(page.Reviews))
+	for _, rv := range page.Reviews {
+		out = append(out, toPullRequestShortDTO(rv))
 	}
 
 	resp := UserReviewsResponse{
-		UserID:       userID,
-		PullRequests: out,
+		UserID:         filter.ReviewerID,
+		OpenReviews:    load.Open,
+		MaxOpenReviews: optionalLimit(load.Capacity),
+		PullRequests:   out,
+	}
+	if page.Next != nil {
+		resp.NextCursor = shared.EncodeCursor(page.Next.AssignedAt, page.Next.PRID)
 	}
 	shared.WriteJSON(w, http.StatusOK, resp)
 }
@@ -1,24 +1,117 @@
 package user
 
 import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zxchelik/avito-test-task/internal/httpserver/handlers/shared"
 	modelpr "github.com/zxchelik/avito-test-task/internal/model/pull_request"
+	modelra "github.com/zxchelik/avito-test-task/internal/model/reviewer_assignment"
 	modeluser "github.com/zxchelik/avito-test-task/internal/model/user"
 )
 
 func toUserDTO(u *modeluser.User) UserDTO {
 	return UserDTO{
-		UserID:   u.ID,
-		Username: u.Username,
-		TeamName: u.TeamName,
-		IsActive: u.IsActive,
+		UserID:         u.ID,
+		Username:       u.Username,
+		TeamName:       u.TeamName,
+		IsActive:       u.IsActive,
+		MaxOpenReviews: optionalLimit(u.MaxOpenReviews),
 	}
 }
 
-func toPullRequestShortDTO(pr *modelpr.PullRequest) PullRequestShortDTO {
-	return PullRequestShortDTO{
+// optionalLimit превращает лимит 0 («без ограничений») в null.
+func optionalLimit(limit int) *int {
+	if limit <= 0 {
+		return nil
+	}
+	return &limit
+}
+
+func toPullRequestShortDTO(rv *modelpr.AssignedReview) PullRequestShortDTO {
+	pr := rv.PullRequest
+	dto := PullRequestShortDTO{
 		PullRequestID:   pr.ID,
 		PullRequestName: pr.Title,
 		AuthorID:        pr.AuthorID,
 		Status:          string(pr.Status),
+		AssignedAt:      rv.AssignedAt,
+		VerdictAt:       rv.VerdictAt,
+	}
+	if rv.Verdict != modelra.VerdictNone {
+		v := string(rv.Verdict)
+		dto.Verdict = &v
+	}
+	return dto
+}
+
+// parseReviewFilter читает фильтр ревью пользователя из query.
+// parseReviewFilter разбирает параметры запроса; ошибки в значениях копятся в v.
+func parseReviewFilter(q url.Values, v *shared.Validation) modelpr.ReviewFilter {
+	filter := modelpr.ReviewFilter{ReviewerID: q.Get("user_id")}
+
+	for _, v := range q["status"] {
+		for _, st := range strings.Split(v, ",") {
+			if st = strings.TrimSpace(st); st != "" {
+				filter.Statuses = append(filter.Statuses, modelpr.PRStatus(strings.ToUpper(st)))
+			}
+		}
+	}
+
+	if raw := q.Get("since"); raw != "" {
+		since, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			v.Add(shared.InQuery, "since", "must be an RFC 3339 timestamp")
+		} else {
+			filter.Since = &since
+		}
+	}
+
+	if raw := q.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			v.Add(shared.InQuery, "limit", "must be a positive integer")
+		} else {
+			filter.Limit = limit
+		}
+	}
+
+	if raw := q.Get("cursor"); raw != "" {
+		at, id, err := shared.DecodeCursor(raw)
+		if err != nil {
+			v.Add(shared.InQuery, "cursor", err.Error())
+		} else {
+			filter.After = &modelpr.ReviewCursor{AssignedAt: at, PRID: id}
+		}
+	}
+
+	return filter
+}
+
+// toReassignmentDTOs раскладывает итоги деактивации на заменённые и оставшиеся ревью.
+func toReassignmentDTOs(results []*modelra.Reassignment) ([]ReassignedReviewDTO, []KeptReviewDTO) {
+	var (
+		reassigned []ReassignedReviewDTO
+		kept       []KeptReviewDTO
+	)
+	for _, r := range results {
+		if r.Replaced() {
+			reassigned = append(reassigned, ReassignedReviewDTO{
+				PullRequestID: r.PrId,
+				ReplacedBy:    r.NewUserId,
+				Fallback:      r.Fallback,
+			})
+			continue
+		}
+
+		code, reason := shared.ReassignmentFailure(r.Reason)
+		kept = append(kept, KeptReviewDTO{
+			PullRequestID: r.PrId,
+			Code:          string(code),
+			Reason:        reason,
+		})
 	}
+	return reassigned, kept
 }
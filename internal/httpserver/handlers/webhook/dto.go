@@ -0,0 +1,53 @@
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// SubscriptionDTO — подписка без секрета; секрет виден только в ответе на создание.
+type SubscriptionDTO struct {
+	ID         int64     `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type SubscriptionAddRequest struct {
+	URL        string   `json:"url"`
+	Secret     string   `json:"secret"`
+	EventTypes []string `json:"event_types"` // pull_request.created | pull_request.merged | reviewer.assigned | reviewer.replaced | reviewer.sla_breached
+}
+
+type SubscriptionAddResponse struct {
+	Subscription SubscriptionDTO `json:"subscription"`
+	Secret       string          `json:"secret"`
+}
+
+type SubscriptionsResponse struct {
+	Subscriptions []SubscriptionDTO `json:"subscriptions"`
+}
+
+type SubscriptionDeleteRequest struct {
+	ID int64 `json:"id"`
+}
+
+type DeliveryDTO struct {
+	ID             int64           `json:"id"`
+	SubscriptionID int64           `json:"subscription_id"`
+	URL            string          `json:"url"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Attempts       int             `json:"attempts"`
+	LastError      string          `json:"last_error"`
+	CreatedAt      time.Time       `json:"created_at"`
+	LastAttemptAt  time.Time       `json:"last_attempt_at"`
+}
+
+type DeadLettersResponse struct {
+	Deliveries []DeliveryDTO `json:"deliveries"`
+}
+
+type RedeliverRequest struct {
+	ID int64 `json:"id"`
+}
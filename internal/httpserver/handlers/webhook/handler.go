@@ -0,0 +1,128 @@
+package webhook
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/zxchelik/avito-test-task/internal/httpserver/handlers/shared"
+	modelwh "github.com/zxchelik/avito-test-task/internal/model/webhook"
+	srvwh "github.com/zxchelik/avito-test-task/internal/service/webhook"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type Handler struct {
+	svc *srvwh.Service
+	log *slog.Logger
+}
+
+func New(svc *srvwh.Service, log *slog.Logger) *Handler {
+	return &Handler{svc: svc, log: log}
+}
+
+// Register регистрирует маршруты подписок на вебхуки.
+func (h *Handler) Register(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(shared.AdminOnly)
+		r.Post("/webhooks/add", h.handleSubscriptionAdd)
+		r.Get("/webhooks/list", h.handleSubscriptionList)
+		r.Post("/webhooks/delete", h.handleSubscriptionDelete)
+		r.Get("/webhooks/deadLetters", h.handleDeadLetters)
+		r.Post("/webhooks/redeliver", h.handleRedeliver)
+	})
+}
+
+// POST /webhooks/add
+func (h *Handler) handleSubscriptionAdd(w http.ResponseWriter, r *http.Request) {
+	var req SubscriptionAddRequest
+	if !shared.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	sub, err := h.svc.Subscribe(r.Context(), &modelwh.Subscription{
+		URL:        req.URL,
+		Secret:     req.Secret,
+		EventTypes: toEventTypes(req.EventTypes),
+	})
+	if err != nil {
+		shared.WriteServiceError(w, r, err, h.log)
+		return
+	}
+
+	shared.WriteJSON(w, http.StatusCreated, SubscriptionAddResponse{
+		Subscription: toSubscriptionDTO(sub),
+		Secret:       sub.Secret,
+	})
+}
+
+// GET /webhooks/list
+func (h *Handler) handleSubscriptionList(w http.ResponseWriter, r *http.Request) {
+	subs, err := h.svc.Subscriptions(r.Context())
+	if err != nil {
+		shared.WriteServiceError(w, r, err, h.log)
+		return
+	}
+
+	shared.WriteJSON(w, http.StatusOK, SubscriptionsResponse{Subscriptions: toSubscriptionDTOs(subs)})
+}
+
+// POST /webhooks/delete
+func (h *Handler) handleSubscriptionDelete(w http.ResponseWriter, r *http.Request) {
+	var req SubscriptionDeleteRequest
+	if !shared.DecodeJSON(w, r, &req) {
+		return
+	}
+	var v shared.Validation
+	v.RequireBody("id", req.ID == 0)
+	if v.Write(w, r) {
+		return
+	}
+
+	if err := h.svc.Unsubscribe(r.Context(), req.ID); err != nil {
+		shared.WriteServiceError(w, r, err, h.log)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GET /webhooks/deadLetters?limit=...
+func (h *Handler) handleDeadLetters(w http.ResponseWriter, r *http.Request) {
+	limit := 0
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			shared.WriteValidationError(w, r, "request validation failed",
+				shared.FieldError{In: shared.InQuery, Field: "limit", Reason: "must be a positive integer"})
+			return
+		}
+		limit = n
+	}
+
+	deliveries, err := h.svc.DeadLetters(r.Context(), limit)
+	if err != nil {
+		shared.WriteServiceError(w, r, err, h.log)
+		return
+	}
+
+	shared.WriteJSON(w, http.StatusOK, DeadLettersResponse{Deliveries: toDeliveryDTOs(deliveries)})
+}
+
+// POST /webhooks/redeliver
+func (h *Handler) handleRedeliver(w http.ResponseWriter, r *http.Request) {
+	var req RedeliverRequest
+	if !shared.DecodeJSON(w, r, &req) {
+		return
+	}
+	var v shared.Validation
+	v.RequireBody("id", req.ID == 0)
+	if v.Write(w, r) {
+		return
+	}
+
+	if err := h.svc.Redeliver(r.Context(), req.ID); err != nil {
+		shared.WriteServiceError(w, r, err, h.log)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,53 @@
+package webhook
+
+import (
+	modelwh "github.com/zxchelik/avito-test-task/internal/model/webhook"
+)
+
+func toSubscriptionDTO(s *modelwh.Subscription) SubscriptionDTO {
+	types := make([]string, 0, len(s.EventTypes))
+	for _, t := range s.EventTypes {
+		types = append(types, string(t))
+	}
+	return SubscriptionDTO{
+		ID:         s.ID,
+		URL:        s.URL,
+		EventTypes: types,
+		CreatedAt:  s.CreatedAt,
+	}
+}
+
+func toSubscriptionDTOs(subs []*modelwh.Subscription) []SubscriptionDTO {
+	res := make([]SubscriptionDTO, 0, len(subs))
+	for _, s := range subs {
+		res = append(res, toSubscriptionDTO(s))
+	}
+	return res
+}
+
+func toEventTypes(types []string) []modelwh.EventType {
+	res := make([]modelwh.EventType, 0, len(types))
+	for _, t := range types {
+		res = append(res, modelwh.EventType(t))
+	}
+	return res
+}
+
+func toDeliveryDTOs(deliveries []*modelwh.Delivery) []DeliveryDTO {
+	res := make([]DeliveryDTO, 0, len(deliveries))
+	for _, d := range deliveries {
+		res = append(res, DeliveryDTO{
+			ID:             d.ID,
+			SubscriptionID: d.SubscriptionID,
+			URL:            d.URL,
+			EventType:      string(d.EventType),
+			Payload:        d.Payload,
+			Attempts:       d.Attempts,
+			LastError:      d.LastError,
+			CreatedAt:      d.CreatedAt,
+			// у DEAD next_attempt_at хранит время последней попытки
+			LastAttemptAt: d.NextAttemptAt,
+		})
+	}
+	return res
+}
@@ -3,20 +3,29 @@ package httpserver
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/zxchelik/avito-test-task/internal/application"
 	"github.com/zxchelik/avito-test-task/internal/httpserver/handlers"
-	"github.com/zxchelik/avito-test-task/internal/infrastructure/pg"
-	prRep "github.com/zxchelik/avito-test-task/internal/repository/pull_request"
-	raRep "github.com/zxchelik/avito-test-task/internal/repository/reviewer_assignment"
-	teamRep "github.com/zxchelik/avito-test-task/internal/repository/team"
-	userRep "github.com/zxchelik/avito-test-task/internal/repository/user"
+	forgehandlers "github.com/zxchelik/avito-test-task/internal/httpserver/handlers/forge"
+	openapihandlers "github.com/zxchelik/avito-test-task/internal/httpserver/handlers/openapi"
+	"github.com/zxchelik/avito-test-task/internal/infrastructure/auth"
+	modelauth "github.com/zxchelik/avito-test-task/internal/model/auth"
+	modelpr "github.com/zxchelik/avito-test-task/internal/model/pull_request"
+	modelteam "github.com/zxchelik/avito-test-task/internal/model/team"
+	modelwh "github.com/zxchelik/avito-test-task/internal/model/webhook"
+	absenceSvc "github.com/zxchelik/avito-test-task/internal/service/absence"
+	coSvc "github.com/zxchelik/avito-test-task/internal/service/code_owner"
+	forgeSvc "github.com/zxchelik/avito-test-task/internal/service/forge"
 	prSvc "github.com/zxchelik/avito-test-task/internal/service/pull_request"
+	slaSvc "github.com/zxchelik/avito-test-task/internal/service/sla"
 	teamSvc "github.com/zxchelik/avito-test-task/internal/service/team"
 	userSvc "github.com/zxchelik/avito-test-task/internal/service/user"
+	webhookSvc "github.com/zxchelik/avito-test-task/internal/service/webhook"
 
 	"github.com/zxchelik/avito-test-task/pkg/logger"
 	"log/slog"
 	"net/http"
+	"sync"
 	"time"
 )
 
@@ -24,6 +33,11 @@ type Server struct {
 	Http *http.Server
 	Log  *slog.Logger
 	Cfg  *application.Config
+
+	slaWorker     *slaSvc.Worker     // nil, если проверка SLA выключена
+	webhookWorker *webhookSvc.Worker // nil, если доставка вебхуков выключена
+	cancelWorkers context.CancelFunc
+	workersDone   sync.WaitGroup
 }
 
 func NewServer() (*Server, error) {
@@ -31,26 +45,74 @@ func NewServer() (*Server, error) {
 
 	log := logger.New(cfg.Env)
 
-	db, err := application.NewDB(context.Background(), &cfg.Postgres, log)
+	store, err := newStorage(context.Background(), cfg, log)
 	if err != nil {
 		log.Error(err.Error())
 		return nil, err
 	}
 
-	txManager := pg.NewTxManager(db.Pool)
-
-	// Репозитории
-	userRepo := userRep.NewPGRepository(db.Pool)
-	teamRepo := teamRep.NewPGRepository(db.Pool)
-	prRepo := prRep.NewPGRepository(db.Pool)
-	raRepo := raRep.NewPGRepository(db.Pool)
+	mergeRule := modelpr.MergeRule{
+		Kind:         modelpr.MergeRuleKind(cfg.Review.MergeRule),
+		MinApprovals: cfg.Review.MinApprovals,
+	}
+	if err := mergeRule.Validate(); err != nil {
+		log.Error("invalid review config", slog.String("merge_rule", cfg.Review.MergeRule), slog.String("error", err.Error()))
+		return nil, err
+	}
 
 	// Сервисы
-	userService := userSvc.NewService(userRepo, prRepo, raRepo)
-	teamService := teamSvc.NewService(teamRepo, userRepo, txManager)
-	prService := prSvc.NewService(prRepo, userRepo, raRepo, txManager)
+	prService := prSvc.NewService(store.prs, store.users, store.teams, store.reviews, store.owners, store.absences, store.events, store.webhooks, store.tx).
+		WithMergeRule(mergeRule).
+		WithSelector(modelteam.StrategySeeded, prSvc.NewSeededSelector(cfg.Review.SelectorSeed))
+	userService := userSvc.NewService(store.users, store.teams, store.prs, store.reviews, prService, store.tx)
+	teamService := teamSvc.NewService(store.teams, store.users, store.reviews, store.events, store.webhooks, store.tx)
+	coService := coSvc.NewService(store.owners, store.tx)
+	absenceService := absenceSvc.NewService(store.absences, store.users)
+	forgeService := forgeSvc.NewService(store.forge, prService, store.tx)
+
+	webhookService := webhookSvc.NewService(store.webhooks, webhookSvc.NewHTTPSender(&http.Client{Timeout: cfg.Webhooks.SendTimeout})).
+		WithRetryPolicy(modelwh.RetryPolicy{
+			MaxAttempts: cfg.Webhooks.MaxAttempts,
+			BaseDelay:   modelwh.DefaultRetryPolicy.BaseDelay,
+			MaxDelay:    modelwh.DefaultRetryPolicy.MaxDelay,
+		}).
+		WithLease(2 * cfg.Webhooks.SendTimeout)
+
+	var webhookWorker *webhookSvc.Worker
+	if cfg.Webhooks.Enabled {
+		webhookWorker = webhookSvc.NewWorker(webhookService, cfg.Webhooks.Interval, cfg.Webhooks.BatchSize, log)
+	}
+
+	var slaWorker *slaSvc.Worker
+	if cfg.SLA.Enabled {
+		slaService := slaSvc.NewService(store.reviews, store.events, store.webhooks, prService, store.tx)
+		slaWorker = slaSvc.NewWorker(slaService, cfg.SLA.Interval, log)
+	}
+
+	authn, err := newAuthenticator(cfg.Env, &cfg.Auth)
+	if err != nil {
+		log.Error("invalid auth config", slog.String("error", err.Error()))
+		return nil, err
+	}
 
-	handler := handlers.NewHandler(teamService, userService, prService, log)
+	handler := handlers.NewHandler(teamService, userService, prService, coService, absenceService, webhookService, forgeService,
+		forgehandlers.Secrets{GitHub: cfg.Forge.GitHubSecret, GitLab: cfg.Forge.GitLabToken}, log)
+	if authn != nil {
+		handler.WithAuthenticator(authn)
+	}
+	if cfg.OpenAPI.Validate {
+		doc, err := openapihandlers.Load(context.Background())
+		if err != nil {
+			log.Error("invalid openapi spec", slog.String("error", err.Error()))
+			return nil, err
+		}
+		validator, err := openapihandlers.NewValidator(doc, log)
+		if err != nil {
+			log.Error("invalid openapi spec", slog.String("error", err.Error()))
+			return nil, err
+		}
+		handler.WithValidator(validator)
+	}
 
 	return &Server{
 		Http: &http.Server{
@@ -60,12 +122,46 @@ func NewServer() (*Server, error) {
 			WriteTimeout: cfg.Timeout,
 			IdleTimeout:  cfg.IdleTimeout,
 		},
-		Log: log,
-		Cfg: cfg,
+		Log:           log,
+		Cfg:           cfg,
+		slaWorker:     slaWorker,
+		webhookWorker: webhookWorker,
 	}, nil
 }
 
+// newAuthenticator собирает проверку токенов из конфига; nil — аутентификация выключена.
+// Выключить её можно только в env local и dev; включённая требует токенов или JWKS.
+func newAuthenticator(env logger.EnvString, cfg *application.Auth) (*auth.Authenticator, error) {
+	if !cfg.Enabled {
+		if !env.Dev() {
+			return nil, fmt.Errorf("auth can be disabled only in local or dev env, got %q", env)
+		}
+		return nil, nil
+	}
+
+	static := make([]auth.StaticToken, 0, len(cfg.Tokens)+1)
+	if cfg.AdminToken != "" {
+		static = append(static, auth.StaticToken{Token: cfg.AdminToken, Subject: "admin", Role: modelauth.RoleAdmin})
+	}
+	for _, t := range cfg.Tokens {
+		static = append(static, auth.StaticToken{Token: t.Token, Subject: t.Subject, Role: modelauth.Role(t.Role)})
+	}
+
+	var opts []auth.Option
+	if cfg.JWKSFile != "" {
+		keys, err := auth.LoadJWKS(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, auth.WithJWKS(keys), auth.WithIssuer(cfg.Issuer), auth.WithAudience(cfg.Audience))
+	}
+
+	return auth.NewAuthenticator(static, opts...)
+}
+
 func (s *Server) Run(ctx context.Context) error {
+	s.startWorkers()
+
 	errCh := make(chan error, 1)
 	go func() {
 		s.Log.Info("starting server", slog.String("env", string(s.Cfg.Env)), slog.String("address", s.Cfg.Address()))
@@ -95,4 +191,46 @@ func (s *Server) Shutdown(ctx context.Context) {
 	} else {
 		s.Log.Info("server stopped gracefully")
 	}
+	s.stopWorkers(ctx)
+}
+
+// startWorkers запускает фоновые задачи; они живут до Shutdown, а не до ctx из Run,
+// чтобы остановиться после того, как HTTP-сервер перестал принимать запросы.
+func (s *Server) startWorkers() {
+	workerCtx, cancel := context.WithCancel(context.Background())
+	s.cancelWorkers = cancel
+
+	if s.slaWorker != nil {
+		s.workersDone.Add(1)
+		go func() {
+			defer s.workersDone.Done()
+			s.slaWorker.Run(workerCtx)
+		}()
+	}
+	if s.webhookWorker != nil {
+		s.workersDone.Add(1)
+		go func() {
+			defer s.workersDone.Done()
+			s.webhookWorker.Run(workerCtx)
+		}()
+	}
+}
+
+// stopWorkers отменяет фоновые задачи и ждёт их завершения не дольше ctx.
+func (s *Server) stopWorkers(ctx context.Context) {
+	if s.cancelWorkers == nil {
+		return
+	}
+	s.cancelWorkers()
+
+	done := make(chan struct{})
+	go func() {
+		s.workersDone.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		s.Log.Error("background workers did not stop in time", slog.String("error", ctx.Err().Error()))
+	}
 }
@@ -0,0 +1,41 @@
+package httpserver
+
+import (
+	"testing"
+
+	"github.com/zxchelik/avito-test-task/internal/application"
+	"github.com/zxchelik/avito-test-task/pkg/logger"
+)
+
+func TestNewAuthenticator(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      logger.EnvString
+		cfg      application.Auth
+		wantErr  bool
+		wantAuth bool
+	}{
+		{name: "enabled with admin token", env: "prod", cfg: application.Auth{Enabled: true, AdminToken: "secret"}, wantAuth: true},
+		{name: "enabled with static tokens", env: "prod", cfg: application.Auth{
+			Enabled: true,
+			Tokens:  []application.StaticToken{{Token: "t1", Subject: "u1", Role: "user"}},
+		}, wantAuth: true},
+		{name: "enabled without tokens", env: "prod", cfg: application.Auth{Enabled: true}, wantErr: true},
+		{name: "enabled without tokens in local", env: "local", cfg: application.Auth{Enabled: true}, wantErr: true},
+		{name: "disabled in local", env: "local", cfg: application.Auth{}},
+		{name: "disabled in dev", env: "dev", cfg: application.Auth{}},
+		{name: "disabled in prod", env: "prod", cfg: application.Auth{}, wantErr: true},
+		{name: "disabled in unknown env", env: "staging", cfg: application.Auth{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authn, err := newAuthenticator(tt.env, &tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+			if (authn != nil) != tt.wantAuth {
+				t.Fatalf("authenticator = %v, want one %v", authn, tt.wantAuth)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package httpserver
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/zxchelik/avito-test-task/internal/application"
+	"github.com/zxchelik/avito-test-task/internal/infrastructure/memory"
+	"github.com/zxchelik/avito-test-task/internal/infrastructure/pg"
+	"github.com/zxchelik/avito-test-task/internal/infrastructure/sqlite"
+	absenceRep "github.com/zxchelik/avito-test-task/internal/repository/absence"
+	eventRep "github.com/zxchelik/avito-test-task/internal/repository/assignment_event"
+	coRep "github.com/zxchelik/avito-test-task/internal/repository/code_owner"
+	forgeRep "github.com/zxchelik/avito-test-task/internal/repository/forge"
+	prRep "github.com/zxchelik/avito-test-task/internal/repository/pull_request"
+	raRep "github.com/zxchelik/avito-test-task/internal/repository/reviewer_assignment"
+	teamRep "github.com/zxchelik/avito-test-task/internal/repository/team"
+	userRep "github.com/zxchelik/avito-test-task/internal/repository/user"
+	webhookRep "github.com/zxchelik/avito-test-task/internal/repository/webhook"
+	"github.com/zxchelik/avito-test-task/internal/service"
+)
+
+// storage — репозитории и менеджер транзакций выбранного хранилища.
+type storage struct {
+	tx       service.TxManager
+	users    service.UserRepository
+	teams    service.TeamRepository
+	prs      service.PRRepository
+	reviews  service.ReviewerAssignmentRepository
+	owners   service.CodeOwnerRepository
+	absences service.AbsenceRepository
+	events   service.AssignmentEventRepository
+	webhooks service.WebhookRepository
+	forge    service.ForgeRepository
+}
+
+// newStorage собирает хранилище по cfg.Storage.
+func newStorage(ctx context.Context, cfg *application.Config, log *slog.Logger) (*storage, error) {
+	switch cfg.Storage {
+	case application.StoragePostgres:
+		db, err := application.NewDB(ctx, &cfg.Postgres, log)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.Postgres.AutoMigrate {
+			log.Info("applying migrations")
+			if err := pg.Migrate(ctx, db.Pool); err != nil {
+				return nil, err
+			}
+		}
+		return &storage{
+			tx:       pg.NewTxManager(db.Pool),
+			users:    userRep.NewPGRepository(db.Pool),
+			teams:    teamRep.NewPGRepository(db.Pool),
+			prs:      prRep.NewPGRepository(db.Pool),
+			reviews:  raRep.NewPGRepository(db.Pool),
+			owners:   coRep.NewPGRepository(db.Pool),
+			absences: absenceRep.NewPGRepository(db.Pool),
+			events:   eventRep.NewPGRepository(db.Pool),
+			webhooks: webhookRep.NewPGRepository(db.Pool),
+			forge:    forgeRep.NewPGRepository(db.Pool),
+		}, nil
+	case application.StorageSQLite:
+		db, err := sqlite.Open(ctx, cfg.SQLite.Path)
+		if err != nil {
+			return nil, err
+		}
+		return &storage{
+			tx:       sqlite.NewTxManager(db),
+			users:    userRep.NewSQLiteRepository(db),
+			teams:    teamRep.NewSQLiteRepository(db),
+			prs:      prRep.NewSQLiteRepository(db),
+			reviews:  raRep.NewSQLiteRepository(db),
+			owners:   coRep.NewSQLiteRepository(db),
+			absences: absenceRep.NewSQLiteRepository(db),
+			events:   eventRep.NewSQLiteRepository(db),
+			webhooks: webhookRep.NewSQLiteRepository(db),
+			forge:    forgeRep.NewSQLiteRepository(db),
+		}, nil
+	case application.StorageMemory:
+		log.Warn("using in-memory storage, data will be lost on restart")
+		db := memory.NewDB()
+		return &storage{
+			tx:       memory.NewTxManager(db),
+			users:    userRep.NewMemoryRepository(db),
+			teams:    teamRep.NewMemoryRepository(db),
+			prs:      prRep.NewMemoryRepository(db),
+			reviews:  raRep.NewMemoryRepository(db),
+			owners:   coRep.NewMemoryRepository(db),
+			absences: absenceRep.NewMemoryRepository(db),
+			events:   eventRep.NewMemoryRepository(db),
+			webhooks: webhookRep.NewMemoryRepository(db),
+			forge:    forgeRep.NewMemoryRepository(db),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown storage %q", cfg.Storage)
+	}
+}
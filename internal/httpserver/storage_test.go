@@ -0,0 +1,51 @@
+package httpserver
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/zxchelik/avito-test-task/internal/application"
+	"github.com/zxchelik/avito-test-task/internal/infrastructure/pg/pgtest"
+	modelteam "github.com/zxchelik/avito-test-task/internal/model/team"
+)
+
+func TestNewStorageAutoMigrate(t *testing.T) {
+	for _, autoMigrate := range []bool{false, true} {
+		t.Run(map[bool]string{false: "off", true: "on"}[autoMigrate], func(t *testing.T) {
+			ctx := context.Background()
+			dsn := pgtest.NewDatabase(t)
+
+			cfg := &application.Config{Storage: application.StoragePostgres, Postgres: pgtest.Config(t, dsn)}
+			cfg.Postgres.AutoMigrate = autoMigrate
+			store, err := newStorage(ctx, cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+			if err != nil {
+				t.Fatalf("newStorage: %v", err)
+			}
+
+			conn, err := pgx.Connect(ctx, dsn)
+			if err != nil {
+				t.Fatalf("connect: %v", err)
+			}
+			defer conn.Close(ctx)
+
+			var exists bool
+			if err := conn.QueryRow(ctx, `SELECT to_regclass('teams') IS NOT NULL`).Scan(&exists); err != nil {
+				t.Fatalf("check schema: %v", err)
+			}
+			if exists != autoMigrate {
+				t.Fatalf("teams table exists = %v, want %v", exists, autoMigrate)
+			}
+
+			if autoMigrate {
+				if err := store.teams.Create(ctx, &modelteam.Team{
+					Name: "backend", ReviewerStrategy: modelteam.DefaultReviewerStrategy, MinReviewers: 1, MaxReviewers: 2,
+				}); err != nil {
+					t.Fatalf("storage is not usable after migration: %v", err)
+				}
+			}
+		})
+	}
+}
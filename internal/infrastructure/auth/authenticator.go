@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+	modelauth "github.com/zxchelik/avito-test-task/internal/model/auth"
+)
+
+// StaticToken — заранее выданный токен с фиксированными субъектом и ролью.
+type StaticToken struct {
+	Token   string
+	Subject string
+	Role    modelauth.Role
+}
+
+// Authenticator проверяет bearer-токены: сначала статические, затем JWT.
+type Authenticator struct {
+	static   []StaticToken
+	keys     *KeySet // nil — JWT не принимаются
+	issuer   string
+	audience string
+}
+
+// Option настраивает Authenticator.
+type Option func(*Authenticator)
+
+// WithJWKS включает проверку JWT ключами из keys.
+func WithJWKS(keys *KeySet) Option {
+	return func(a *Authenticator) { a.keys = keys }
+}
+
+// WithIssuer требует совпадения claim iss.
+func WithIssuer(issuer string) Option {
+	return func(a *Authenticator) { a.issuer = issuer }
+}
+
+// WithAudience требует наличия audience в claim aud.
+func WithAudience(audience string) Option {
+	return func(a *Authenticator) { a.audience = audience }
+}
+
+func NewAuthenticator(static []StaticToken, opts ...Option) (*Authenticator, error) {
+	for _, t := range static {
+		if t.Token == "" || !t.Role.Valid() {
+			return nil, fmt.Errorf("static token for %q: empty token or unknown role %q", t.Subject, t.Role)
+		}
+	}
+	a := &Authenticator{static: static}
+	for _, opt := range opts {
+		opt(a)
+	}
+	if len(a.static) == 0 && a.keys == nil {
+		return nil, fmt.Errorf("no static tokens and no jwks configured")
+	}
+	return a, nil
+}
+
+// claims — ожидаемые claims JWT: sub — users.id, role — роль.
+type claims struct {
+	Role modelauth.Role `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// Authenticate возвращает вызывающего по токену.
+// Ошибки:
+//   - auth.ErrUnauthorized — токен неизвестен, подпись неверна, истёк или без роли
+func (a *Authenticator) Authenticate(token string) (*modelauth.Principal, error) {
+	for _, t := range a.static {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(t.Token)) == 1 {
+			return &modelauth.Principal{Subject: t.Subject, Role: t.Role}, nil
+		}
+	}
+	if a.keys == nil {
+		return nil, modelauth.ErrUnauthorized
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg(), jwt.SigningMethodRS256.Alg()}),
+		jwt.WithExpirationRequired(),
+	}
+	if a.issuer != "" {
+		opts = append(opts, jwt.WithIssuer(a.issuer))
+	}
+	if a.audience != "" {
+		opts = append(opts, jwt.WithAudience(a.audience))
+	}
+
+	var c claims
+	if _, err := jwt.ParseWithClaims(token, &c, a.key, opts...); err != nil {
+		return nil, fmt.Errorf("%w: %v", modelauth.ErrUnauthorized, err)
+	}
+	if c.Subject == "" || !c.Role.Valid() {
+		return nil, fmt.Errorf("%w: token has no sub or unknown role", modelauth.ErrUnauthorized)
+	}
+
+	return &modelauth.Principal{Subject: c.Subject, Role: c.Role}, nil
+}
+
+// key выбирает ключ по alg и kid; тип ключа соответствует алгоритму,
+// поэтому RSA-ключ не может быть использован как HMAC-секрет.
+func (a *Authenticator) key(t *jwt.Token) (any, error) {
+	kid, _ := t.Header["kid"].(string)
+	switch t.Method.Alg() {
+	case jwt.SigningMethodHS256.Alg():
+		if key, ok := lookup(a.keys.hmac, kid); ok {
+			return key, nil
+		}
+	case jwt.SigningMethodRS256.Alg():
+		if key, ok := lookup(a.keys.rsa, kid); ok {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("no key for alg %s kid %q", t.Method.Alg(), kid)
+}
@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+// KeySet — ключи проверки JWT из локального JWKS-файла.
+type KeySet struct {
+	rsa  map[string]*rsa.PublicKey // kid → ключ RS256
+	hmac map[string][]byte         // kid → секрет HS256
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"` // RSA
+	E   string `json:"e"` // RSA
+	K   string `json:"k"` // oct
+}
+
+// LoadJWKS читает JWKS-файл. Поддерживаются ключи RSA (RS256) и oct (HS256);
+// ключи другого типа и с use, отличным от sig, пропускаются.
+func LoadJWKS(path string) (*KeySet, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("parse jwks: %w", err)
+	}
+
+	set := &KeySet{rsa: map[string]*rsa.PublicKey{}, hmac: map[string][]byte{}}
+	for _, k := range doc.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		switch k.Kty {
+		case "RSA":
+			pub, err := rsaKey(k)
+			if err != nil {
+				return nil, fmt.Errorf("jwks key %q: %w", k.Kid, err)
+			}
+			set.rsa[k.Kid] = pub
+		case "oct":
+			secret, err := base64.RawURLEncoding.DecodeString(k.K)
+			if err != nil || len(secret) == 0 {
+				return nil, fmt.Errorf("jwks key %q: invalid k", k.Kid)
+			}
+			set.hmac[k.Kid] = secret
+		}
+	}
+	if len(set.rsa) == 0 && len(set.hmac) == 0 {
+		return nil, fmt.Errorf("jwks %s has no usable keys", path)
+	}
+
+	return set, nil
+}
+
+func rsaKey(k jwk) (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("invalid n: %w", err)
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, fmt.Errorf("invalid e: %w", err)
+	}
+	exp := new(big.Int).SetBytes(e)
+	if len(n) == 0 || !exp.IsInt64() || exp.Int64() < 3 {
+		return nil, fmt.Errorf("invalid modulus or exponent")
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exp.Int64())}, nil
+}
+
+// lookup возвращает ключ по kid; без kid подходит единственный ключ нужного типа.
+func lookup[K any](keys map[string]K, kid string) (K, bool) {
+	if key, ok := keys[kid]; ok {
+		return key, true
+	}
+	var zero K
+	if kid == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key, true
+		}
+	}
+	return zero, false
+}
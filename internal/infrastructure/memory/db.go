@@ -0,0 +1,137 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	modelabsence "github.com/zxchelik/avito-test-task/internal/model/absence"
+	modelco "github.com/zxchelik/avito-test-task/internal/model/code_owner"
+	modelforge "github.com/zxchelik/avito-test-task/internal/model/forge"
+	modelpr "github.com/zxchelik/avito-test-task/internal/model/pull_request"
+	modelra "github.com/zxchelik/avito-test-task/internal/model/reviewer_assignment"
+	modelteam "github.com/zxchelik/avito-test-task/internal/model/team"
+	modeluser "github.com/zxchelik/avito-test-task/internal/model/user"
+	modelwh "github.com/zxchelik/avito-test-task/internal/model/webhook"
+)
+
+// ForgeKey identifies a forge identity or a forge delivery.
+type ForgeKey struct {
+	Forge modelforge.Forge
+	ID    string // username or delivery id
+}
+
+// Tables is the whole in-memory data set.
+// Repositories own the rows: values are copied in and out, never shared with callers,
+// so a shallow copy of every row is enough to snapshot the tables.
+type Tables struct {
+	Teams           map[string]*modelteam.Team
+	Users           map[string]*modeluser.User
+	PullRequests    map[string]*modelpr.PullRequest
+	Reviewers       map[string]map[string]*modelra.ReviewerAssignment // pr_id → user_id
+	Events          []*modelra.Event
+	CodeOwnerRules  map[int64]*modelco.Rule
+	Absences        map[int64]*modelabsence.Absence
+	Subscriptions   map[int64]*modelwh.Subscription
+	Deliveries      map[int64]*modelwh.Delivery
+	Identities      map[ForgeKey]*modelforge.Identity
+	ForgeDeliveries map[ForgeKey]time.Time
+
+	sequences map[string]int64 // table → last issued id
+}
+
+// NextID returns a new id for a row of the table, like a BIGSERIAL column.
+func (t *Tables) NextID(table string) int64 {
+	t.sequences[table]++
+	return t.sequences[table]
+}
+
+func newTables() *Tables {
+	return &Tables{
+		Teams:           make(map[string]*modelteam.Team),
+		Users:           make(map[string]*modeluser.User),
+		PullRequests:    make(map[string]*modelpr.PullRequest),
+		Reviewers:       make(map[string]map[string]*modelra.ReviewerAssignment),
+		CodeOwnerRules:  make(map[int64]*modelco.Rule),
+		Absences:        make(map[int64]*modelabsence.Absence),
+		Subscriptions:   make(map[int64]*modelwh.Subscription),
+		Deliveries:      make(map[int64]*modelwh.Delivery),
+		Identities:      make(map[ForgeKey]*modelforge.Identity),
+		ForgeDeliveries: make(map[ForgeKey]time.Time),
+		sequences:       make(map[string]int64),
+	}
+}
+
+// snapshot copies the tables so that a failed transaction can be rolled back.
+func (t *Tables) snapshot() *Tables {
+	reviewers := make(map[string]map[string]*modelra.ReviewerAssignment, len(t.Reviewers))
+	for prID, byUser := range t.Reviewers {
+		reviewers[prID] = copyRows(byUser)
+	}
+
+	return &Tables{
+		Teams:           copyRows(t.Teams),
+		Users:           copyRows(t.Users),
+		PullRequests:    copyRows(t.PullRequests),
+		Reviewers:       reviewers,
+		Events:          slices.Clone(t.Events), // append-only, rows never change
+		CodeOwnerRules:  copyRows(t.CodeOwnerRules),
+		Absences:        copyRows(t.Absences),
+		Subscriptions:   copyRows(t.Subscriptions),
+		Deliveries:      copyRows(t.Deliveries),
+		Identities:      copyRows(t.Identities),
+		ForgeDeliveries: maps.Clone(t.ForgeDeliveries),
+		sequences:       maps.Clone(t.sequences),
+	}
+}
+
+func copyRows[K comparable, V any](rows map[K]*V) map[K]*V {
+	res := make(map[K]*V, len(rows))
+	for k, v := range rows {
+		row := *v
+		res[k] = &row
+	}
+	return res
+}
+
+// DB is a thread-safe in-memory store shared by the memory repositories.
+// A single mutex guards all tables; a transaction holds it until it ends,
+// so transactions are serializable.
+type DB struct {
+	mu     sync.Mutex
+	tables *Tables
+	now    func() time.Time
+}
+
+func NewDB() *DB {
+	return &DB{
+		tables: newTables(),
+		now:    func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Now returns the current time with the precision Postgres stores (microseconds),
+// so values survive the same round-trips (cursors, equality checks) as in Postgres.
+func (db *DB) Now() time.Time {
+	return Timestamp(db.now())
+}
+
+// Timestamp truncates t to the precision the store keeps.
+func Timestamp(t time.Time) time.Time {
+	return t.Truncate(time.Microsecond)
+}
+
+// Acquire returns the tables for a repository operation and a function releasing them.
+// Inside a transaction the tables are already held by it and release does nothing;
+// otherwise the operation runs alone, like a single statement in autocommit mode.
+// The repository must not call Acquire again before release.
+func (db *DB) Acquire(ctx context.Context) (*Tables, func()) {
+	if owner, ok := ctx.Value(txKey{}).(*DB); ok && owner == db {
+		return db.tables, func() {}
+	}
+
+	db.mu.Lock()
+	return db.tables, db.mu.Unlock
+}
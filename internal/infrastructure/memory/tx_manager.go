@@ -0,0 +1,59 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/zxchelik/avito-test-task/internal/service"
+)
+
+type txKey struct{}
+
+// TxManager runs transactions over a DB.
+// Transactions are serialized, so every isolation level is honored trivially
+// and there are no serialization failures to retry; TxOptions are ignored.
+type TxManager struct {
+	db *DB
+}
+
+func NewTxManager(db *DB) *TxManager {
+	return &TxManager{db: db}
+}
+
+// WithinTransaction runs fn in a transaction.
+// If ctx already carries a transaction, fn runs in a savepoint of it.
+func (m *TxManager) WithinTransaction(
+	ctx context.Context,
+	fn func(ctx context.Context) error,
+) error {
+	return m.WithinTransactionOptions(ctx, service.TxOptions{}, fn)
+}
+
+// WithinTransactionOptions runs fn holding the DB exclusively.
+// An error of fn restores the tables as they were before fn started;
+// in a nested call that undoes only the changes of fn (a savepoint).
+func (m *TxManager) WithinTransactionOptions(
+	ctx context.Context,
+	_ service.TxOptions,
+	fn func(ctx context.Context) error,
+) error {
+	if owner, ok := ctx.Value(txKey{}).(*DB); ok && owner == m.db {
+		return m.run(ctx, fn)
+	}
+
+	m.db.mu.Lock()
+	defer m.db.mu.Unlock()
+
+	return m.run(context.WithValue(ctx, txKey{}, m.db), fn)
+}
+
+// run calls fn and rolls the tables back if it fails. The caller holds the DB.
+func (m *TxManager) run(ctx context.Context, fn func(ctx context.Context) error) error {
+	saved := m.db.tables.snapshot()
+
+	if err := fn(ctx); err != nil {
+		m.db.tables = saved
+		return err
+	}
+
+	return nil
+}
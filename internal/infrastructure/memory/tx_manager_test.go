@@ -0,0 +1,95 @@
+package memory_test
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/zxchelik/avito-test-task/internal/infrastructure/memory"
+	modelteam "github.com/zxchelik/avito-test-task/internal/model/team"
+	"github.com/zxchelik/avito-test-task/internal/service"
+)
+
+var errAbort = errors.New("abort")
+
+func put(ctx context.Context, db *memory.DB, name string) error {
+	tables, release := db.Acquire(ctx)
+	defer release()
+
+	tables.Teams[name] = &modelteam.Team{Name: name}
+	return nil
+}
+
+func teams(db *memory.DB) []string {
+	tables, release := db.Acquire(context.Background())
+	defer release()
+
+	return slices.Sorted(maps.Keys(tables.Teams))
+}
+
+func TestNestedRollbackKeepsOuterWork(t *testing.T) {
+	db := memory.NewDB()
+	txm := memory.NewTxManager(db)
+
+	err := txm.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		if err := put(ctx, db, "outer"); err != nil {
+			return err
+		}
+		err := txm.WithinTransaction(ctx, func(ctx context.Context) error {
+			_ = put(ctx, db, "inner")
+			return errAbort
+		})
+		if !errors.Is(err, errAbort) {
+			t.Fatalf("nested: got %v, want errAbort", err)
+		}
+		return put(ctx, db, "after")
+	})
+	if err != nil {
+		t.Fatalf("transaction: %v", err)
+	}
+
+	if got, want := teams(db), []string{"after", "outer"}; !slices.Equal(got, want) {
+		t.Fatalf("teams = %v, want %v", got, want)
+	}
+}
+
+func TestOuterRollbackDiscardsReleasedSavepoint(t *testing.T) {
+	db := memory.NewDB()
+	txm := memory.NewTxManager(db)
+
+	err := txm.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		if err := txm.WithinTransaction(ctx, func(ctx context.Context) error {
+			return put(ctx, db, "inner")
+		}); err != nil {
+			t.Fatalf("nested: %v", err)
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("transaction: got %v, want errAbort", err)
+	}
+
+	if got := teams(db); len(got) != 0 {
+		t.Fatalf("teams = %v, want none", got)
+	}
+}
+
+func TestNestedOptionsIgnored(t *testing.T) {
+	db := memory.NewDB()
+	txm := memory.NewTxManager(db)
+
+	err := txm.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		return txm.WithinTransactionOptions(ctx, service.TxOptions{ReadOnly: true}, func(ctx context.Context) error {
+			return put(ctx, db, "inner")
+		})
+	})
+	if err != nil {
+		t.Fatalf("transaction: %v", err)
+	}
+
+	if got := teams(db); !slices.Equal(got, []string{"inner"}) {
+		t.Fatalf("teams = %v, want [inner]", got)
+	}
+}
@@ -0,0 +1,128 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+	"github.com/zxchelik/avito-test-task/migrations"
+)
+
+// Migrator applies the embedded migrations from /migrations.
+// Every operation holds a Postgres advisory lock, so replicas starting
+// at the same time apply the migrations once and the rest wait.
+type Migrator struct {
+	db       *sql.DB
+	provider *goose.Provider
+	// unlocked runs migrations without taking the lock itself;
+	// used by operations that hold the lock across several steps.
+	unlocked *goose.Provider
+}
+
+// NewMigrator builds a Migrator on top of the pool. The connections it takes
+// are returned to the pool by Close; the pool itself stays open.
+func NewMigrator(pool *pgxpool.Pool) (*Migrator, error) {
+	locker, err := lock.NewPostgresSessionLocker()
+	if err != nil {
+		return nil, err
+	}
+
+	db := stdlib.OpenDBFromPool(pool)
+	provider, err := goose.NewProvider(goose.DialectPostgres, db, migrations.FS, goose.WithSessionLocker(locker))
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to load migrations: %w", err)
+	}
+	unlocked, err := goose.NewProvider(goose.DialectPostgres, db, migrations.FS)
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	return &Migrator{db: db, provider: provider, unlocked: unlocked}, nil
+}
+
+// Up applies all pending migrations.
+func (m *Migrator) Up(ctx context.Context) ([]*goose.MigrationResult, error) {
+	return m.provider.Up(ctx)
+}
+
+// Down rolls back the latest applied migration.
+func (m *Migrator) Down(ctx context.Context) (*goose.MigrationResult, error) {
+	return m.provider.Down(ctx)
+}
+
+// Redo rolls back the latest applied migration and applies it again.
+// Both steps run under one advisory lock, so another replica can't
+// migrate in between.
+func (m *Migrator) Redo(ctx context.Context) ([]*goose.MigrationResult, error) {
+	var results []*goose.MigrationResult
+	err := m.withLock(ctx, func() error {
+		down, err := m.unlocked.Down(ctx)
+		if err != nil {
+			return err
+		}
+		results = append(results, down)
+
+		up, err := m.unlocked.UpByOne(ctx)
+		if err != nil {
+			return err
+		}
+		results = append(results, up)
+
+		return nil
+	})
+
+	return results, err
+}
+
+// Status reports every known migration and whether it has been applied.
+func (m *Migrator) Status(ctx context.Context) ([]*goose.MigrationStatus, error) {
+	return m.provider.Status(ctx)
+}
+
+// Close releases the connections taken from the pool.
+func (m *Migrator) Close() error {
+	return m.db.Close()
+}
+
+// withLock runs fn holding the same session advisory lock goose takes
+// in Up and Down. The lock lives on a dedicated connection for the whole call.
+func (m *Migrator) withLock(ctx context.Context, fn func() error) (err error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, lock.DefaultLockID); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer func() {
+		// разблокируем и при отменённом ctx, иначе лок останется на соединении в пуле
+		if _, unlockErr := conn.ExecContext(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1)`, lock.DefaultLockID); unlockErr != nil && err == nil {
+			err = fmt.Errorf("failed to release migration lock: %w", unlockErr)
+		}
+	}()
+
+	return fn()
+}
+
+// Migrate applies all pending migrations.
+func Migrate(ctx context.Context, pool *pgxpool.Pool) error {
+	m, err := NewMigrator(pool)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	if _, err := m.Up(ctx); err != nil {
+		return fmt.Errorf("failed to apply migrations: %w", err)
+	}
+
+	return nil
+}
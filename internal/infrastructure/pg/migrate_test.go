@@ -0,0 +1,185 @@
+package pg_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/pressly/goose/v3"
+	"github.com/zxchelik/avito-test-task/internal/infrastructure/pg"
+	"github.com/zxchelik/avito-test-task/internal/infrastructure/pg/pgtest"
+)
+
+// migrationsCount is the number of files in /migrations.
+const migrationsCount = 14
+
+func newMigrator(t *testing.T, dsn string) *pg.Migrator {
+	t.Helper()
+
+	m, err := pg.NewMigrator(pgtest.Connect(t, dsn))
+	if err != nil {
+		t.Fatalf("NewMigrator: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close() })
+
+	return m
+}
+
+// applied returns the versions the status reports as applied.
+func applied(t *testing.T, m *pg.Migrator) []int64 {
+	t.Helper()
+
+	statuses, err := m.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if len(statuses) != migrationsCount {
+		t.Fatalf("Status: got %d migrations, want %d", len(statuses), migrationsCount)
+	}
+
+	var versions []int64
+	for _, st := range statuses {
+		if st.State == goose.StateApplied {
+			versions = append(versions, st.Source.Version)
+		}
+	}
+	return versions
+}
+
+func TestMigratorUpDownRedoStatus(t *testing.T) {
+	ctx := context.Background()
+	m := newMigrator(t, pgtest.NewDatabase(t))
+
+	if got := applied(t, m); len(got) != 0 {
+		t.Fatalf("fresh database: applied %v, want none", got)
+	}
+
+	results, err := m.Up(ctx)
+	if err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	if len(results) != migrationsCount {
+		t.Fatalf("Up: applied %d migrations, want %d", len(results), migrationsCount)
+	}
+	if got := applied(t, m); len(got) != migrationsCount {
+		t.Fatalf("after Up: applied %d, want %d", len(got), migrationsCount)
+	}
+
+	results, err = m.Up(ctx)
+	if err != nil {
+		t.Fatalf("second Up: %v", err)
+	}
+	if len(results) != 0 {
+		t.Fatalf("second Up: applied %d migrations, want 0", len(results))
+	}
+
+	down, err := m.Down(ctx)
+	if err != nil {
+		t.Fatalf("Down: %v", err)
+	}
+	if down.Source.Version != migrationsCount || down.Direction != "down" {
+		t.Fatalf("Down: got %s, want down of version %d", down, migrationsCount)
+	}
+	if got := applied(t, m); len(got) != migrationsCount-1 {
+		t.Fatalf("after Down: applied %d, want %d", len(got), migrationsCount-1)
+	}
+
+	results, err = m.Redo(ctx)
+	if err != nil {
+		t.Fatalf("Redo: %v", err)
+	}
+	if len(results) != 2 ||
+		results[0].Direction != "down" || results[0].Source.Version != migrationsCount-1 ||
+		results[1].Direction != "up" || results[1].Source.Version != migrationsCount-1 {
+		t.Fatalf("Redo: got %v, want down and up of version %d", results, migrationsCount-1)
+	}
+	if got := applied(t, m); len(got) != migrationsCount-1 {
+		t.Fatalf("after Redo: applied %d, want %d", len(got), migrationsCount-1)
+	}
+
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatalf("Up after Redo: %v", err)
+	}
+	if got := applied(t, m); len(got) != migrationsCount {
+		t.Fatalf("after final Up: applied %d, want %d", len(got), migrationsCount)
+	}
+}
+
+// Replicas starting together must apply every migration exactly once.
+func TestMigratorConcurrentReplicas(t *testing.T) {
+	dsn := pgtest.NewDatabase(t)
+
+	const replicas = 4
+	migrators := make([]*pg.Migrator, replicas)
+	for i := range migrators {
+		migrators[i] = newMigrator(t, dsn)
+	}
+
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		total int
+	)
+	for _, m := range migrators {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results, err := m.Up(context.Background())
+			if err != nil {
+				t.Errorf("Up: %v", err)
+				return
+			}
+			mu.Lock()
+			total += len(results)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	if total != migrationsCount {
+		t.Fatalf("replicas applied %d migrations in total, want %d", total, migrationsCount)
+	}
+}
+
+// Redo holds the lock for both of its steps, so an Up racing it either
+// runs before or after and never sees the half-redone schema.
+func TestMigratorRedoRacingUp(t *testing.T) {
+	ctx := context.Background()
+	dsn := pgtest.NewDatabase(t)
+	first, second := newMigrator(t, dsn), newMigrator(t, dsn)
+
+	if _, err := first.Up(ctx); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+
+	for range 5 {
+		var (
+			wg          sync.WaitGroup
+			redoErr     error
+			upResults   []*goose.MigrationResult
+			upErr       error
+			redoResults []*goose.MigrationResult
+		)
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			redoResults, redoErr = first.Redo(ctx)
+		}()
+		go func() {
+			defer wg.Done()
+			upResults, upErr = second.Up(ctx)
+		}()
+		wg.Wait()
+
+		if redoErr != nil || upErr != nil {
+			t.Fatalf("Redo: %v, Up: %v", redoErr, upErr)
+		}
+		if len(redoResults) != 2 {
+			t.Fatalf("Redo: got %v, want down and up", redoResults)
+		}
+		// Up между шагами Redo применил бы откаченную миграцию, и второй шаг Redo упал бы.
+		if len(upResults) != 0 {
+			t.Fatalf("Up applied %v while Redo held the lock", upResults)
+		}
+	}
+}
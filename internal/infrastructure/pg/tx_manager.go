@@ -2,26 +2,104 @@ package pg
 
 import (
 	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/zxchelik/avito-test-task/internal/service"
 )
 
 type txKey struct{}
 
+// DefaultMaxRetries is how many times a transaction aborted by a serialization
+// failure or a deadlock is re-run by default.
+const DefaultMaxRetries = 3
+
+// retryBaseDelay is the backoff before the first retry; it grows linearly with jitter.
+const retryBaseDelay = 10 * time.Millisecond
+
 type TxManager struct {
 	pool *pgxpool.Pool
+	opts service.TxOptions
 }
 
 func NewTxManager(pool *pgxpool.Pool) *TxManager {
 	return &TxManager{pool: pool}
 }
 
+// WithOptions sets the options used by WithinTransaction.
+func (m *TxManager) WithOptions(opts service.TxOptions) *TxManager {
+	m.opts = opts
+	return m
+}
+
+// WithinTransaction runs fn in a transaction with the manager's default options.
+// If ctx already carries a transaction, fn runs in a savepoint of it.
 func (m *TxManager) WithinTransaction(
 	ctx context.Context,
 	fn func(ctx context.Context) error,
 ) error {
-	tx, err := m.pool.BeginTx(ctx, pgx.TxOptions{})
+	return m.WithinTransactionOptions(ctx, m.opts, fn)
+}
+
+// WithinTransactionOptions runs fn in a transaction with the given options.
+// A transaction aborted by a serialization failure (40001) or a deadlock (40P01)
+// is rolled back and fn is run again, so fn must be safe to re-run from scratch.
+//
+// If ctx already carries a transaction, fn runs in a SAVEPOINT of it:
+// an error of fn rolls back to the savepoint and is returned to the caller,
+// which may handle it and go on with the outer transaction.
+// opts of the nested call are ignored rather than rejected: the savepoint runs
+// with the options of the outermost call, which alone retries the transaction.
+func (m *TxManager) WithinTransactionOptions(
+	ctx context.Context,
+	opts service.TxOptions,
+	fn func(ctx context.Context) error,
+) error {
+	if outer, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return runInTx(ctx, outer.Begin, fn)
+	}
+
+	maxRetries := opts.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = DefaultMaxRetries
+	}
+
+	txOpts := pgx.TxOptions{IsoLevel: pgx.TxIsoLevel(opts.Isolation)}
+	if opts.ReadOnly {
+		txOpts.AccessMode = pgx.ReadOnly
+	}
+	begin := func(ctx context.Context) (pgx.Tx, error) {
+		return m.pool.BeginTx(ctx, txOpts)
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := runInTx(ctx, begin, fn)
+		if err == nil || attempt >= maxRetries || !IsRetryable(err) {
+			return err
+		}
+
+		delay := retryBaseDelay * time.Duration(attempt+1)
+		delay += rand.N(delay)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
+
+// runInTx begins a transaction (or a savepoint, when begin is Tx.Begin),
+// runs fn with it in the context and commits, or rolls back if fn fails.
+func runInTx(
+	ctx context.Context,
+	begin func(ctx context.Context) (pgx.Tx, error),
+	fn func(ctx context.Context) error,
+) error {
+	tx, err := begin(ctx)
 	if err != nil {
 		return err
 	}
@@ -35,3 +113,13 @@ func (m *TxManager) WithinTransaction(
 
 	return tx.Commit(ctx)
 }
+
+// IsRetryable reports whether err aborted the transaction because of
+// a serialization failure or a deadlock, so the transaction may succeed if re-run.
+func IsRetryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == "40001" || pgErr.Code == "40P01"
+}
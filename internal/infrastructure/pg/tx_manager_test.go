@@ -0,0 +1,265 @@
+package pg_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/zxchelik/avito-test-task/internal/infrastructure/pg"
+	"github.com/zxchelik/avito-test-task/internal/infrastructure/pg/pgtest"
+	"github.com/zxchelik/avito-test-task/internal/service"
+)
+
+var errAbort = errors.New("abort")
+
+// newScratchPool connects to an empty test database with a scratch table items.
+func newScratchPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+
+	pool := pgtest.Connect(t, pgtest.NewDatabase(t))
+	if _, err := pool.Exec(context.Background(), "CREATE TABLE items (name TEXT PRIMARY KEY)"); err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+	return pool
+}
+
+func put(ctx context.Context, pool *pgxpool.Pool, name string) error {
+	_, err := pg.GetQuerierFromContext(ctx, pool).Exec(ctx, "INSERT INTO items (name) VALUES ($1)", name)
+	return err
+}
+
+func items(t *testing.T, pool *pgxpool.Pool) []string {
+	t.Helper()
+
+	rows, err := pool.Query(context.Background(), "SELECT name FROM items ORDER BY name")
+	if err != nil {
+		t.Fatalf("select: %v", err)
+	}
+	res, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	return res
+}
+
+func TestNestedRollbackKeepsOuterWork(t *testing.T) {
+	pool := newScratchPool(t)
+	txm := pg.NewTxManager(pool)
+
+	err := txm.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		if err := put(ctx, pool, "outer"); err != nil {
+			return err
+		}
+		err := txm.WithinTransaction(ctx, func(ctx context.Context) error {
+			if err := put(ctx, pool, "inner"); err != nil {
+				return err
+			}
+			return errAbort
+		})
+		if !errors.Is(err, errAbort) {
+			t.Fatalf("nested: got %v, want errAbort", err)
+		}
+		return put(ctx, pool, "after")
+	})
+	if err != nil {
+		t.Fatalf("transaction: %v", err)
+	}
+
+	if got, want := items(t, pool), []string{"after", "outer"}; !slices.Equal(got, want) {
+		t.Fatalf("items = %v, want %v", got, want)
+	}
+}
+
+func TestNestedFailedStatementKeepsOuterWork(t *testing.T) {
+	pool := newScratchPool(t)
+	txm := pg.NewTxManager(pool)
+
+	// a failed statement aborts only the savepoint, not the outer transaction
+	err := txm.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		if err := put(ctx, pool, "outer"); err != nil {
+			return err
+		}
+		if err := txm.WithinTransaction(ctx, func(ctx context.Context) error {
+			return put(ctx, pool, "outer") // duplicate key
+		}); err == nil {
+			t.Fatal("nested: duplicate insert succeeded")
+		}
+		return put(ctx, pool, "after")
+	})
+	if err != nil {
+		t.Fatalf("transaction: %v", err)
+	}
+
+	if got, want := items(t, pool), []string{"after", "outer"}; !slices.Equal(got, want) {
+		t.Fatalf("items = %v, want %v", got, want)
+	}
+}
+
+func TestOuterRollbackDiscardsReleasedSavepoint(t *testing.T) {
+	pool := newScratchPool(t)
+	txm := pg.NewTxManager(pool)
+
+	err := txm.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		if err := txm.WithinTransaction(ctx, func(ctx context.Context) error {
+			return txm.WithinTransaction(ctx, func(ctx context.Context) error {
+				return put(ctx, pool, "inner")
+			})
+		}); err != nil {
+			t.Fatalf("nested: %v", err)
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("transaction: got %v, want errAbort", err)
+	}
+
+	if got := items(t, pool); len(got) != 0 {
+		t.Fatalf("items = %v, want none", got)
+	}
+}
+
+func TestNestedOptionsIgnored(t *testing.T) {
+	pool := newScratchPool(t)
+	txm := pg.NewTxManager(pool)
+	ctx := context.Background()
+
+	// a read-only savepoint of a read-write transaction can still write
+	err := txm.WithinTransaction(ctx, func(ctx context.Context) error {
+		return txm.WithinTransactionOptions(ctx, service.TxOptions{ReadOnly: true}, func(ctx context.Context) error {
+			return put(ctx, pool, "inner")
+		})
+	})
+	if err != nil {
+		t.Fatalf("transaction: %v", err)
+	}
+
+	// and the options of the outer call still apply inside the savepoint
+	err = txm.WithinTransactionOptions(ctx, service.TxOptions{ReadOnly: true}, func(ctx context.Context) error {
+		return txm.WithinTransaction(ctx, func(ctx context.Context) error {
+			return put(ctx, pool, "read-only")
+		})
+	})
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != "25006" { // read_only_sql_transaction
+		t.Fatalf("write in a read-only transaction: got %v, want 25006", err)
+	}
+
+	// a savepoint is never retried on its own, whatever its MaxRetries
+	calls := 0
+	err = txm.WithinTransactionOptions(ctx, service.TxOptions{MaxRetries: -1}, func(ctx context.Context) error {
+		return txm.WithinTransactionOptions(ctx, service.TxOptions{MaxRetries: 5}, func(ctx context.Context) error {
+			calls++
+			return &pgconn.PgError{Code: "40001"}
+		})
+	})
+	if !pg.IsRetryable(err) || calls != 1 {
+		t.Fatalf("got %v after %d calls, want a serialization failure after 1", err, calls)
+	}
+
+	if got := items(t, pool); !slices.Equal(got, []string{"inner"}) {
+		t.Fatalf("items = %v, want [inner]", got)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{&pgconn.PgError{Code: "40001"}, true}, // serialization_failure
+		{&pgconn.PgError{Code: "40P01"}, true}, // deadlock_detected
+		{fmt.Errorf("commit: %w", &pgconn.PgError{Code: "40001"}), true},
+		{&pgconn.PgError{Code: "23505"}, false}, // unique_violation
+		{errAbort, false},
+		{nil, false},
+	}
+	for _, c := range cases {
+		if got := pg.IsRetryable(c.err); got != c.want {
+			t.Errorf("IsRetryable(%v) = %v, want %v", c.err, got, c.want)
+		}
+	}
+}
+
+func TestRetriesRetryableErrors(t *testing.T) {
+	pool := newScratchPool(t)
+	ctx := context.Background()
+
+	for _, code := range []string{"40001", "40P01"} {
+		t.Run(code, func(t *testing.T) {
+			calls := 0
+			err := pg.NewTxManager(pool).WithinTransaction(ctx, func(ctx context.Context) error {
+				calls++
+				return &pgconn.PgError{Code: code}
+			})
+			if !pg.IsRetryable(err) || calls != pg.DefaultMaxRetries+1 {
+				t.Fatalf("got %v after %d calls, want %s after %d", err, calls, code, pg.DefaultMaxRetries+1)
+			}
+		})
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		calls := 0
+		err := pg.NewTxManager(pool).WithinTransactionOptions(ctx, service.TxOptions{MaxRetries: -1}, func(ctx context.Context) error {
+			calls++
+			return &pgconn.PgError{Code: "40001"}
+		})
+		if !pg.IsRetryable(err) || calls != 1 {
+			t.Fatalf("got %v after %d calls, want a serialization failure after 1", err, calls)
+		}
+	})
+}
+
+func TestRetriesSerializationFailure(t *testing.T) {
+	pool := newScratchPool(t)
+	txm := pg.NewTxManager(pool).WithOptions(service.TxOptions{Isolation: service.IsolationSerializable})
+
+	// Both transactions read the whole table and then insert a row, so they cannot
+	// both commit as serializable: one fails with 40001 and is re-run by the manager.
+	var (
+		read     sync.WaitGroup
+		attempts atomic.Int32
+	)
+	read.Add(2)
+	insert := func(name string) error {
+		first := true
+		return txm.WithinTransaction(context.Background(), func(ctx context.Context) error {
+			attempts.Add(1)
+			var n int
+			if err := pg.GetQuerierFromContext(ctx, pool).QueryRow(ctx, "SELECT count(*) FROM items").Scan(&n); err != nil {
+				return err
+			}
+			if first {
+				first = false
+				read.Done()
+				read.Wait()
+			}
+			return put(ctx, pool, fmt.Sprintf("%s-%d", name, n))
+		})
+	}
+
+	errs := make(chan error, 2)
+	for _, name := range []string{"a", "b"} {
+		go func() { errs <- insert(name) }()
+	}
+	for range 2 {
+		if err := <-errs; err != nil {
+			t.Fatalf("transaction: %v", err)
+		}
+	}
+
+	if got := attempts.Load(); got < 3 {
+		t.Fatalf("attempts = %d, want at least one retry", got)
+	}
+	// serializable order: the retried transaction saw the row of the committed one
+	got := items(t, pool)
+	if len(got) != 2 || !slices.ContainsFunc(got, func(s string) bool { return s == "a-1" || s == "b-1" }) {
+		t.Fatalf("items = %v, want one of them inserted after the other", got)
+	}
+}
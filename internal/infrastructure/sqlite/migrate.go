@@ -0,0 +1,43 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+
+	"github.com/pressly/goose/v3"
+)
+
+// migrations is the SQLite schema in goose format, like the Postgres migrations in /migrations.
+//
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Migrate applies pending migrations.
+func Migrate(ctx context.Context, db *sql.DB) error {
+	provider, err := newProvider(db)
+	if err != nil {
+		return err
+	}
+	if _, err := provider.Up(ctx); err != nil {
+		return fmt.Errorf("failed to apply sqlite migrations: %w", err)
+	}
+
+	return nil
+}
+
+// newProvider returns a goose provider of the embedded migrations over db.
+func newProvider(db *sql.DB) (*goose.Provider, error) {
+	fsys, err := fs.Sub(migrations, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := goose.NewProvider(goose.DialectSQLite3, db, fsys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sqlite migrations: %w", err)
+	}
+	return provider, nil
+}
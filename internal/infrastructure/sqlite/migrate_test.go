@@ -0,0 +1,116 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+)
+
+// migrationsCount is the number of files in migrations/.
+const migrationsCount = 2
+
+func version(t *testing.T, db *sql.DB) int64 {
+	t.Helper()
+
+	provider, err := newProvider(db)
+	if err != nil {
+		t.Fatalf("provider: %v", err)
+	}
+	v, err := provider.GetDBVersion(context.Background())
+	if err != nil {
+		t.Fatalf("version: %v", err)
+	}
+	return v
+}
+
+func slaHours(t *testing.T, db *sql.DB, column string) int {
+	t.Helper()
+
+	var hours int
+	if err := db.QueryRow("SELECT " + column + " FROM teams WHERE name = 'backend'").Scan(&hours); err != nil {
+		t.Fatalf("select %s: %v", column, err)
+	}
+	return hours
+}
+
+func TestOpenAppliesEmbeddedMigrations(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "review.db")
+
+	db, err := Open(ctx, path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if got := version(t, db); got != migrationsCount {
+		t.Fatalf("version = %d, want %d", got, migrationsCount)
+	}
+	if _, err := db.Exec("INSERT INTO teams (name, review_sla_weekday_hours) VALUES ('backend', 8)"); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	// reopening an up-to-date database applies nothing and keeps the data
+	db, err = Open(ctx, path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer db.Close()
+	if got := version(t, db); got != migrationsCount {
+		t.Fatalf("version after reopen = %d, want %d", got, migrationsCount)
+	}
+	if got := slaHours(t, db, "review_sla_weekday_hours"); got != 8 {
+		t.Fatalf("review_sla_weekday_hours = %d, want 8", got)
+	}
+}
+
+func TestMigrationsDownAndUp(t *testing.T) {
+	ctx := context.Background()
+	db, err := Open(ctx, filepath.Join(t.TempDir(), "review.db"))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer db.Close()
+	if _, err := db.Exec("INSERT INTO teams (name, review_sla_weekday_hours) VALUES ('backend', 8)"); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	provider, err := newProvider(db)
+	if err != nil {
+		t.Fatalf("provider: %v", err)
+	}
+
+	// the rename is undone and redone without losing the value
+	if _, err := provider.Down(ctx); err != nil {
+		t.Fatalf("down: %v", err)
+	}
+	if got := slaHours(t, db, "review_sla_hours"); got != 8 {
+		t.Fatalf("review_sla_hours after down = %d, want 8", got)
+	}
+	if _, err := provider.Up(ctx); err != nil {
+		t.Fatalf("up: %v", err)
+	}
+	if got := slaHours(t, db, "review_sla_weekday_hours"); got != 8 {
+		t.Fatalf("review_sla_weekday_hours after up = %d, want 8", got)
+	}
+
+	// the whole schema goes away and comes back
+	if _, err := provider.DownTo(ctx, 0); err != nil {
+		t.Fatalf("down to 0: %v", err)
+	}
+	var tables int
+	if err := db.QueryRow("SELECT count(*) FROM sqlite_master WHERE type = 'table' AND name = 'teams'").Scan(&tables); err != nil {
+		t.Fatalf("check schema: %v", err)
+	}
+	if tables != 0 {
+		t.Fatal("teams table survived down to 0")
+	}
+	if err := Migrate(ctx, db); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if got := version(t, db); got != migrationsCount {
+		t.Fatalf("version = %d, want %d", got, migrationsCount)
+	}
+}
@@ -0,0 +1,186 @@
+-- Схема SQLite повторяет итоговую схему Postgres из /migrations.
+-- Время хранится текстом в UTC с микросекундами (2006-01-02T15:04:05.000000Z),
+-- поэтому строки сравниваются и сортируются как моменты времени.
+-- Массивы хранятся JSON-массивами, перечисления — TEXT с CHECK.
+
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE teams (
+                       name                     TEXT PRIMARY KEY,
+                       reviewer_strategy        TEXT    NOT NULL DEFAULT 'least_loaded',
+                       min_reviewers            INTEGER NOT NULL DEFAULT 1,
+                       max_reviewers            INTEGER NOT NULL DEFAULT 2,
+                       default_max_open_reviews INTEGER NULL CHECK (default_max_open_reviews > 0),
+                       review_sla_hours         INTEGER NULL CHECK (review_sla_hours > 0),
+                       sla_auto_reassign        INTEGER NOT NULL DEFAULT 0,
+                       CHECK (min_reviewers >= 0 AND max_reviewers >= min_reviewers)
+);
+
+CREATE TABLE team_fallbacks (
+                                team_name     TEXT    NOT NULL REFERENCES teams(name) ON DELETE CASCADE,
+                                fallback_team TEXT    NOT NULL REFERENCES teams(name) ON DELETE CASCADE,
+                                position      INTEGER NOT NULL,
+                                PRIMARY KEY (team_name, fallback_team),
+                                CHECK (team_name <> fallback_team)
+);
+
+CREATE TABLE users (
+                       id               TEXT PRIMARY KEY,
+                       name             TEXT    NOT NULL,
+                       team_name        TEXT    NOT NULL REFERENCES teams(name) ON DELETE RESTRICT,
+                       is_active        INTEGER NOT NULL DEFAULT 1,
+                       max_open_reviews INTEGER NULL CHECK (max_open_reviews > 0),
+                       created_at       TEXT    NOT NULL
+);
+
+CREATE INDEX idx_users_team ON users(team_name);
+
+CREATE TABLE pull_requests (
+                               id         TEXT PRIMARY KEY,
+                               title      TEXT NOT NULL,
+                               author_id  TEXT NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+                               status     TEXT NOT NULL DEFAULT 'OPEN' CHECK (status IN ('DRAFT', 'OPEN', 'MERGED', 'CLOSED')),
+                               created_at TEXT NOT NULL,
+                               merged_at  TEXT NULL,
+                               closed_at  TEXT NULL
+);
+
+CREATE INDEX idx_pr_author ON pull_requests(author_id);
+CREATE INDEX idx_pr_created ON pull_requests(created_at, id);
+
+CREATE TABLE pull_request_reviewers (
+                                        pr_id           TEXT    NOT NULL REFERENCES pull_requests(id) ON DELETE CASCADE,
+                                        user_id         TEXT    NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+                                        assigned_at     TEXT    NOT NULL,
+                                        is_fallback     INTEGER NOT NULL DEFAULT 0,
+                                        verdict         TEXT    NULL CHECK (verdict IN ('APPROVED', 'CHANGES_REQUESTED', 'COMMENTED')),
+                                        verdict_at      TEXT    NULL,
+                                        sla_breached_at TEXT    NULL,
+                                        PRIMARY KEY (pr_id, user_id)
+);
+
+CREATE INDEX idx_reviews_user ON pull_request_reviewers(user_id);
+CREATE INDEX idx_reviews_sla_pending ON pull_request_reviewers(assigned_at)
+    WHERE verdict IS NULL AND sla_breached_at IS NULL;
+
+CREATE TABLE code_owner_rules (
+                                  id          INTEGER PRIMARY KEY AUTOINCREMENT,
+                                  pattern     TEXT    NOT NULL,
+                                  owner_users TEXT    NOT NULL DEFAULT '[]',
+                                  owner_teams TEXT    NOT NULL DEFAULT '[]',
+                                  position    INTEGER NOT NULL,
+                                  created_at  TEXT    NOT NULL
+);
+
+CREATE INDEX idx_code_owner_rules_position ON code_owner_rules(position, id);
+
+CREATE TABLE user_absences (
+                               id           INTEGER PRIMARY KEY AUTOINCREMENT,
+                               user_id      TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                               starts_at    TEXT NOT NULL,
+                               ends_at      TEXT NOT NULL,
+                               reason       TEXT NOT NULL,
+                               created_at   TEXT NOT NULL,
+                               cancelled_at TEXT NULL,
+                               CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX idx_absences_user_period ON user_absences(user_id, starts_at, ends_at)
+    WHERE cancelled_at IS NULL;
+
+CREATE TABLE reviewer_assignment_events (
+                                            id               INTEGER PRIMARY KEY AUTOINCREMENT,
+                                            pr_id            TEXT NOT NULL REFERENCES pull_requests(id) ON DELETE RESTRICT,
+                                            event_type       TEXT NOT NULL CHECK (event_type IN (
+                                                'ASSIGNED', 'UNASSIGNED', 'REPLACED', 'VERDICT', 'STATUS_CHANGED', 'MERGED', 'SLA_BREACHED'
+                                            )),
+                                            user_id          TEXT NULL,
+                                            previous_user_id TEXT NULL,
+                                            verdict          TEXT NULL CHECK (verdict IN ('APPROVED', 'CHANGES_REQUESTED', 'COMMENTED')),
+                                            status           TEXT NULL CHECK (status IN ('DRAFT', 'OPEN', 'MERGED', 'CLOSED')),
+                                            actor_id         TEXT NULL,
+                                            reason           TEXT NOT NULL DEFAULT '',
+                                            created_at       TEXT NOT NULL
+);
+
+CREATE INDEX idx_ra_events_pr ON reviewer_assignment_events(pr_id, id);
+
+-- Журнал только дописывается.
+CREATE TRIGGER trg_ra_events_no_update
+    BEFORE UPDATE ON reviewer_assignment_events
+BEGIN
+    SELECT RAISE(ABORT, 'reviewer_assignment_events is append-only');
+END;
+
+CREATE TRIGGER trg_ra_events_no_delete
+    BEFORE DELETE ON reviewer_assignment_events
+BEGIN
+    SELECT RAISE(ABORT, 'reviewer_assignment_events is append-only');
+END;
+
+CREATE TABLE webhook_subscriptions (
+                                       id          INTEGER PRIMARY KEY AUTOINCREMENT,
+                                       url         TEXT NOT NULL,
+                                       secret      TEXT NOT NULL,
+                                       event_types TEXT NOT NULL CHECK (json_array_length(event_types) > 0),
+                                       created_at  TEXT NOT NULL
+);
+
+-- Outbox: по строке на пару (событие, подписка), пишется в транзакции изменения.
+CREATE TABLE webhook_deliveries (
+                                    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+                                    subscription_id INTEGER NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+                                    event_type      TEXT    NOT NULL,
+                                    payload         TEXT    NOT NULL CHECK (json_valid(payload)),
+                                    status          TEXT    NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'DELIVERED', 'DEAD')),
+                                    attempts        INTEGER NOT NULL DEFAULT 0,
+                                    next_attempt_at TEXT    NOT NULL,
+                                    last_error      TEXT    NOT NULL DEFAULT '',
+                                    created_at      TEXT    NOT NULL,
+                                    delivered_at    TEXT    NULL
+);
+
+CREATE INDEX idx_webhook_deliveries_subscription ON webhook_deliveries(subscription_id);
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at, id)
+    WHERE status = 'PENDING';
+CREATE INDEX idx_webhook_deliveries_dead ON webhook_deliveries(id)
+    WHERE status = 'DEAD';
+
+CREATE TABLE forge_identities (
+                                  forge      TEXT NOT NULL CHECK (forge IN ('github', 'gitlab')),
+                                  username   TEXT NOT NULL,
+                                  user_id    TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                  created_at TEXT NOT NULL,
+                                  PRIMARY KEY (forge, username)
+);
+
+CREATE INDEX idx_forge_identities_user ON forge_identities(user_id);
+
+-- Принятые доставки входящих вебхуков для отсева повторов.
+CREATE TABLE forge_deliveries (
+                                  forge       TEXT NOT NULL,
+                                  delivery_id TEXT NOT NULL,
+                                  received_at TEXT NOT NULL,
+                                  PRIMARY KEY (forge, delivery_id)
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS forge_deliveries;
+DROP TABLE IF EXISTS forge_identities;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_subscriptions;
+DROP TABLE IF EXISTS reviewer_assignment_events;
+DROP TABLE IF EXISTS user_absences;
+DROP TABLE IF EXISTS code_owner_rules;
+DROP TABLE IF EXISTS pull_request_reviewers;
+DROP TABLE IF EXISTS pull_requests;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS team_fallbacks;
+DROP TABLE IF EXISTS teams;
+
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+)
+
+type Querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func GetQuerierFromContext(ctx context.Context, db *sql.DB) Querier {
+	if t, ok := ctx.Value(txKey{}).(*tx); ok {
+		return t.Tx
+	}
+	return db
+}
@@ -0,0 +1,164 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/zxchelik/avito-test-task/internal/service"
+	msqlite "modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+type txKey struct{}
+
+// tx is the transaction carried by the context; depth is the number of open savepoints.
+type tx struct {
+	*sql.Tx
+	depth int
+}
+
+// DefaultMaxRetries is how many times a transaction that found the database
+// locked by another process is re-run by default.
+const DefaultMaxRetries = 3
+
+// retryBaseDelay is the backoff before the first retry; it grows linearly with jitter.
+const retryBaseDelay = 10 * time.Millisecond
+
+type TxManager struct {
+	db   *sql.DB
+	opts service.TxOptions
+}
+
+func NewTxManager(db *sql.DB) *TxManager {
+	return &TxManager{db: db}
+}
+
+// WithOptions sets the options used by WithinTransaction.
+func (m *TxManager) WithOptions(opts service.TxOptions) *TxManager {
+	m.opts = opts
+	return m
+}
+
+// WithinTransaction runs fn in a transaction with the manager's default options.
+// If ctx already carries a transaction, fn runs in a savepoint of it.
+func (m *TxManager) WithinTransaction(
+	ctx context.Context,
+	fn func(ctx context.Context) error,
+) error {
+	return m.WithinTransactionOptions(ctx, m.opts, fn)
+}
+
+// WithinTransactionOptions runs fn in a transaction with the given options.
+// SQLite transactions are always serializable, so opts.Isolation is ignored;
+// opts.ReadOnly makes the transaction fail on any write.
+// A transaction that found the database busy or locked by another process
+// is rolled back and fn is run again, so fn must be safe to re-run from scratch.
+//
+// If ctx already carries a transaction, fn runs in a SAVEPOINT of it:
+// an error of fn rolls back to the savepoint and is returned to the caller,
+// which may handle it and go on with the outer transaction.
+// opts of the nested call are ignored rather than rejected: the savepoint runs
+// with the options of the outermost call, which alone retries the transaction.
+func (m *TxManager) WithinTransactionOptions(
+	ctx context.Context,
+	opts service.TxOptions,
+	fn func(ctx context.Context) error,
+) error {
+	if outer, ok := ctx.Value(txKey{}).(*tx); ok {
+		return runInSavepoint(ctx, outer, fn)
+	}
+
+	maxRetries := opts.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = DefaultMaxRetries
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := m.runInTx(ctx, &sql.TxOptions{ReadOnly: opts.ReadOnly}, fn)
+		if err == nil || attempt >= maxRetries || !IsRetryable(err) {
+			return err
+		}
+
+		delay := retryBaseDelay * time.Duration(attempt+1)
+		delay += rand.N(delay)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
+
+// runInTx begins a transaction, runs fn with it in the context and commits,
+// or rolls back if fn fails.
+func (m *TxManager) runInTx(
+	ctx context.Context,
+	txOpts *sql.TxOptions,
+	fn func(ctx context.Context) error,
+) error {
+	sqlTx, err := m.db.BeginTx(ctx, txOpts)
+	if err != nil {
+		return err
+	}
+
+	// the driver does not enforce sql.TxOptions.ReadOnly, so the single connection
+	// is switched to query_only for the transaction and back before it ends
+	reset := func() {}
+	if txOpts.ReadOnly {
+		if _, err := sqlTx.ExecContext(ctx, "PRAGMA query_only = ON"); err != nil {
+			_ = sqlTx.Rollback()
+			return err
+		}
+		reset = func() { _, _ = sqlTx.ExecContext(context.WithoutCancel(ctx), "PRAGMA query_only = OFF") }
+	}
+
+	ctxWithTx := context.WithValue(ctx, txKey{}, &tx{Tx: sqlTx})
+
+	if err := fn(ctxWithTx); err != nil {
+		reset()
+		_ = sqlTx.Rollback()
+		return err
+	}
+
+	reset()
+	return sqlTx.Commit()
+}
+
+// runInSavepoint runs fn in a savepoint of the outer transaction,
+// rolling back to it if fn fails.
+func runInSavepoint(
+	ctx context.Context,
+	outer *tx,
+	fn func(ctx context.Context) error,
+) error {
+	inner := &tx{Tx: outer.Tx, depth: outer.depth + 1}
+	name := fmt.Sprintf("sp_%d", inner.depth)
+
+	if _, err := inner.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return err
+	}
+
+	if err := fn(context.WithValue(ctx, txKey{}, inner)); err != nil {
+		_, _ = inner.ExecContext(ctx, "ROLLBACK TO "+name)
+		_, _ = inner.ExecContext(ctx, "RELEASE "+name)
+		return err
+	}
+
+	_, err := inner.ExecContext(ctx, "RELEASE "+name)
+	return err
+}
+
+// IsRetryable reports whether err aborted the transaction because the database
+// was busy or locked, so the transaction may succeed if re-run.
+func IsRetryable(err error) bool {
+	var sqliteErr *msqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	code := sqliteErr.Code() & 0xff // primary result code
+	return code == sqlite3.SQLITE_BUSY || code == sqlite3.SQLITE_LOCKED
+}
@@ -0,0 +1,196 @@
+package sqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/zxchelik/avito-test-task/internal/infrastructure/sqlite"
+	"github.com/zxchelik/avito-test-task/internal/service"
+)
+
+var errAbort = errors.New("abort")
+
+// newDB opens a migrated database in a temporary directory with a scratch table items.
+func newDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	ctx := context.Background()
+	db, err := sqlite.Open(ctx, filepath.Join(t.TempDir(), "review.db"))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	if _, err := db.ExecContext(ctx, "CREATE TABLE items (name TEXT PRIMARY KEY)"); err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+	return db
+}
+
+func put(ctx context.Context, db *sql.DB, name string) error {
+	_, err := sqlite.GetQuerierFromContext(ctx, db).ExecContext(ctx, "INSERT INTO items (name) VALUES (?)", name)
+	return err
+}
+
+func items(t *testing.T, db *sql.DB) []string {
+	t.Helper()
+
+	rows, err := db.QueryContext(context.Background(), "SELECT name FROM items ORDER BY name")
+	if err != nil {
+		t.Fatalf("select: %v", err)
+	}
+	defer rows.Close()
+
+	var res []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			t.Fatalf("scan: %v", err)
+		}
+		res = append(res, name)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("rows: %v", err)
+	}
+	return res
+}
+
+func TestNestedRollbackKeepsOuterWork(t *testing.T) {
+	db := newDB(t)
+	txm := sqlite.NewTxManager(db)
+
+	err := txm.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		if err := put(ctx, db, "outer"); err != nil {
+			return err
+		}
+		err := txm.WithinTransaction(ctx, func(ctx context.Context) error {
+			if err := put(ctx, db, "inner"); err != nil {
+				return err
+			}
+			return errAbort
+		})
+		if !errors.Is(err, errAbort) {
+			t.Fatalf("nested: got %v, want errAbort", err)
+		}
+		return put(ctx, db, "after")
+	})
+	if err != nil {
+		t.Fatalf("transaction: %v", err)
+	}
+
+	if got, want := items(t, db), []string{"after", "outer"}; !slices.Equal(got, want) {
+		t.Fatalf("items = %v, want %v", got, want)
+	}
+}
+
+func TestOuterRollbackDiscardsReleasedSavepoint(t *testing.T) {
+	db := newDB(t)
+	txm := sqlite.NewTxManager(db)
+
+	err := txm.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		if err := txm.WithinTransaction(ctx, func(ctx context.Context) error {
+			return txm.WithinTransaction(ctx, func(ctx context.Context) error {
+				return put(ctx, db, "inner")
+			})
+		}); err != nil {
+			t.Fatalf("nested: %v", err)
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("transaction: got %v, want errAbort", err)
+	}
+
+	if got := items(t, db); len(got) != 0 {
+		t.Fatalf("items = %v, want none", got)
+	}
+}
+
+func TestNestedOptionsIgnored(t *testing.T) {
+	db := newDB(t)
+	txm := sqlite.NewTxManager(db)
+
+	// a read-only savepoint of a read-write transaction can still write
+	err := txm.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		return txm.WithinTransactionOptions(ctx, service.TxOptions{ReadOnly: true}, func(ctx context.Context) error {
+			return put(ctx, db, "inner")
+		})
+	})
+	if err != nil {
+		t.Fatalf("transaction: %v", err)
+	}
+	if got := items(t, db); !slices.Equal(got, []string{"inner"}) {
+		t.Fatalf("items = %v, want [inner]", got)
+	}
+
+	// and the options of the outer call still apply inside the savepoint
+	err = txm.WithinTransactionOptions(context.Background(), service.TxOptions{ReadOnly: true}, func(ctx context.Context) error {
+		return txm.WithinTransaction(ctx, func(ctx context.Context) error {
+			return put(ctx, db, "read-only")
+		})
+	})
+	if err == nil {
+		t.Fatal("write in a read-only transaction succeeded")
+	}
+
+	// the connection is writable again once the read-only transaction ends
+	if err := put(context.Background(), db, "later"); err != nil {
+		t.Fatalf("write after read-only transaction: %v", err)
+	}
+	if got, want := items(t, db), []string{"inner", "later"}; !slices.Equal(got, want) {
+		t.Fatalf("items = %v, want %v", got, want)
+	}
+}
+
+func TestGetQuerierFromContext(t *testing.T) {
+	db := newDB(t)
+	txm := sqlite.NewTxManager(db)
+	ctx := context.Background()
+
+	if q := sqlite.GetQuerierFromContext(ctx, db); q != sqlite.Querier(db) {
+		t.Fatalf("outside a transaction got %T, want the database", q)
+	}
+
+	err := txm.WithinTransaction(ctx, func(ctx context.Context) error {
+		outer, ok := sqlite.GetQuerierFromContext(ctx, db).(*sql.Tx)
+		if !ok {
+			t.Fatalf("inside a transaction got %T, want *sql.Tx", sqlite.GetQuerierFromContext(ctx, db))
+		}
+		// savepoints run on the same transaction
+		return txm.WithinTransaction(ctx, func(ctx context.Context) error {
+			if inner := sqlite.GetQuerierFromContext(ctx, db); inner != sqlite.Querier(outer) {
+				t.Fatalf("inside a savepoint got %v, want the outer transaction", inner)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatalf("transaction: %v", err)
+	}
+
+	// the pool holds one connection, so a statement that bypassed the transaction
+	// would wait for it forever; through the context it sees the uncommitted row
+	wait, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	err = txm.WithinTransaction(wait, func(ctx context.Context) error {
+		if err := put(ctx, db, "uncommitted"); err != nil {
+			return err
+		}
+		var n int
+		if err := sqlite.GetQuerierFromContext(ctx, db).QueryRowContext(ctx, "SELECT count(*) FROM items").Scan(&n); err != nil {
+			return err
+		}
+		if n != 1 {
+			t.Fatalf("transaction sees %d rows, want 1", n)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("transaction: %v", err)
+	}
+}
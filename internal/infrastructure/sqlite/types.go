@@ -0,0 +1,140 @@
+package sqlite
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	msqlite "modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// timeLayout stores timestamps as UTC text with the precision Postgres keeps (microseconds).
+// The width is fixed, so the text compares and sorts like the moments it encodes.
+const timeLayout = "2006-01-02T15:04:05.000000Z"
+
+// Time formats t for a timestamp column.
+func Time(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+// NullTime formats t for a nullable timestamp column; nil is stored as NULL.
+func NullTime(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return Time(*t)
+}
+
+// ScanTime scans a timestamp column into dst.
+func ScanTime(dst *time.Time) sql.Scanner {
+	return timeScanner{dst: dst}
+}
+
+// ScanNullTime scans a nullable timestamp column into dst; NULL leaves dst nil.
+func ScanNullTime(dst **time.Time) sql.Scanner {
+	return nullTimeScanner{dst: dst}
+}
+
+type timeScanner struct {
+	dst *time.Time
+}
+
+func (s timeScanner) Scan(src any) error {
+	var text string
+	switch v := src.(type) {
+	case string:
+		text = v
+	case []byte:
+		text = string(v)
+	case time.Time:
+		*s.dst = v.UTC()
+		return nil
+	default:
+		return fmt.Errorf("sqlite: cannot scan %T into time.Time", src)
+	}
+
+	t, err := time.Parse(timeLayout, text)
+	if err != nil {
+		return err
+	}
+	*s.dst = t
+	return nil
+}
+
+type nullTimeScanner struct {
+	dst **time.Time
+}
+
+func (s nullTimeScanner) Scan(src any) error {
+	if src == nil {
+		*s.dst = nil
+		return nil
+	}
+
+	var t time.Time
+	if err := (timeScanner{dst: &t}).Scan(src); err != nil {
+		return err
+	}
+	*s.dst = &t
+	return nil
+}
+
+// Array stores a string slice in a TEXT column as a JSON array, since SQLite has no arrays.
+// Queries unnest it with json_each. A nil slice is stored as an empty array.
+type Array[T ~string] []T
+
+func (a Array[T]) Value() (driver.Value, error) {
+	if a == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal([]T(a))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (a *Array[T]) Scan(src any) error {
+	var b []byte
+	switch v := src.(type) {
+	case string:
+		b = []byte(v)
+	case []byte:
+		b = v
+	default:
+		return fmt.Errorf("sqlite: cannot scan %T into array", src)
+	}
+
+	res := []T{}
+	if err := json.Unmarshal(b, &res); err != nil {
+		return err
+	}
+	*a = res
+	return nil
+}
+
+// Placeholders returns "(?, ?), (?, ?)" for a multi-row VALUES list.
+func Placeholders(rows, cols int) string {
+	row := "(" + strings.Repeat("?, ", cols-1) + "?)"
+	return strings.Repeat(row+", ", rows-1) + row
+}
+
+// IsForeignKeyViolation reports whether err is a FOREIGN KEY constraint failure.
+func IsForeignKeyViolation(err error) bool {
+	return hasCode(err, sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY)
+}
+
+// IsUniqueViolation reports whether err is a PRIMARY KEY or UNIQUE constraint failure.
+func IsUniqueViolation(err error) bool {
+	return hasCode(err, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY) || hasCode(err, sqlite3.SQLITE_CONSTRAINT_UNIQUE)
+}
+
+func hasCode(err error, code int) bool {
+	var sqliteErr *msqlite.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.Code() == code
+}
@@ -0,0 +1,20 @@
+package absence
+
+import "time"
+
+// Absence — запланированное отсутствие пользователя (отпуск, больничный и т.п.)
+// на полуинтервале [StartsAt, EndsAt).
+type Absence struct {
+	ID          int64
+	UserID      string
+	StartsAt    time.Time
+	EndsAt      time.Time
+	Reason      string
+	CreatedAt   time.Time
+	CancelledAt *time.Time
+}
+
+// Covers сообщает, отсутствует ли пользователь в момент t.
+func (a *Absence) Covers(t time.Time) bool {
+	return a.CancelledAt == nil && !t.Before(a.StartsAt) && t.Before(a.EndsAt)
+}
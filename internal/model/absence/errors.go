@@ -0,0 +1,10 @@
+package absence
+
+import "errors"
+
+var (
+	ErrInvalidPeriod    = errors.New("absence must end after it starts")
+	ErrAlreadyEnded     = errors.New("absence has already ended")
+	ErrAlreadyCancelled = errors.New("absence is already cancelled")
+	ErrReasonRequired   = errors.New("absence reason is required")
+)
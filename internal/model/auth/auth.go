@@ -0,0 +1,50 @@
+package auth
+
+// Role — роль вызывающего API.
+type Role string
+
+const (
+	RoleAdmin    Role = "admin"     // управление командами, пользователями и интеграциями
+	RoleTeamLead Role = "team_lead" // merge PR своей команды
+	RoleUser     Role = "user"
+	// RoleSystem — действия самого сервиса: воркеры и события с хостингов.
+	// Токенам не выдаётся: Valid для неё false.
+	RoleSystem Role = "system"
+)
+
+// Valid сообщает, известна ли роль.
+func (r Role) Valid() bool {
+	switch r {
+	case RoleAdmin, RoleTeamLead, RoleUser:
+		return true
+	default:
+		return false
+	}
+}
+
+// Principal — аутентифицированный вызывающий.
+type Principal struct {
+	Subject string // users.id; для служебных токенов может не совпадать с пользователем
+	Role    Role
+}
+
+// System — вызывающий для действий, которые выполняет сам сервис.
+var System = &Principal{Role: RoleSystem}
+
+// Anonymous — вызывающий при выключенной аутентификации (только local и dev): права как у user.
+var Anonymous = &Principal{Role: RoleUser}
+
+// HasRole сообщает, есть ли у вызывающего одна из ролей.
+func (p *Principal) HasRole(roles ...Role) bool {
+	for _, r := range roles {
+		if p.Role == r {
+			return true
+		}
+	}
+	return false
+}
+
+// Privileged сообщает, что вызывающему доступны любые PR: это admin или сама система.
+func (p *Principal) Privileged() bool {
+	return p.HasRole(RoleAdmin, RoleSystem)
+}
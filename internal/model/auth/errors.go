@@ -0,0 +1,8 @@
+package auth
+
+import "errors"
+
+var (
+	ErrUnauthorized = errors.New("missing or invalid credentials")
+	ErrForbidden    = errors.New("operation is not allowed for this caller")
+)
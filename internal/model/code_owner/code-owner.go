@@ -0,0 +1,14 @@
+package code_owner
+
+import "time"
+
+// Rule — правило владения кодом в стиле CODEOWNERS.
+// Правила применяются по возрастанию Position; для файла действует последнее совпавшее.
+type Rule struct {
+	ID        int64
+	Pattern   string
+	Users     []string
+	Teams     []string
+	Position  int
+	CreatedAt time.Time
+}
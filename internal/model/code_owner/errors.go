@@ -0,0 +1,7 @@
+package code_owner
+
+import "errors"
+
+var (
+	ErrInvalidPattern = errors.New("invalid ownership pattern")
+)
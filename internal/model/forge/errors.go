@@ -0,0 +1,9 @@
+package forge
+
+import "errors"
+
+var (
+	ErrUnknownForge      = errors.New("unknown forge")
+	ErrIdentityNotMapped = errors.New("forge user is not mapped to a user")
+	ErrDuplicateDelivery = errors.New("webhook delivery already processed")
+)
@@ -0,0 +1,56 @@
+package forge
+
+import (
+	"fmt"
+	"time"
+)
+
+// Forge — хостинг репозиториев, присылающий вебхуки.
+type Forge string
+
+const (
+	GitHub Forge = "github"
+	GitLab Forge = "gitlab"
+)
+
+// Valid сообщает, поддерживается ли хостинг.
+func (f Forge) Valid() bool {
+	return f == GitHub || f == GitLab
+}
+
+// Identity связывает логин на хостинге с пользователем сервиса.
+type Identity struct {
+	Forge     Forge
+	Username  string
+	UserID    string
+	CreatedAt time.Time
+}
+
+// Action — что произошло с PR на хостинге.
+type Action string
+
+const (
+	ActionOpened   Action = "opened"
+	ActionReady    Action = "ready_for_review" // черновик готов к ревью
+	ActionClosed   Action = "closed"           // закрыт без merge
+	ActionMerged   Action = "merged"
+	ActionReopened Action = "reopened"
+)
+
+// Event — событие PR с хостинга, приведённое к общему виду.
+type Event struct {
+	Forge          Forge
+	DeliveryID     string
+	Action         Action // пусто — событие не влияет на PR
+	Repository     string // org/repo или group/project
+	Number         int    // номер PR (GitHub) или iid MR (GitLab)
+	Title          string
+	Draft          bool
+	AuthorUsername string
+	SenderUsername string // кто выполнил действие на хостинге
+}
+
+// PullRequestID — идентификатор PR сервиса для PR с хостинга.
+func (e *Event) PullRequestID() string {
+	return fmt.Sprintf("%s:%s#%d", e.Forge, e.Repository, e.Number)
+}
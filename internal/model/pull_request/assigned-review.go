@@ -0,0 +1,38 @@
+package pull_request
+
+import (
+	"time"
+
+	modelra "github.com/zxchelik/avito-test-task/internal/model/reviewer_assignment"
+)
+
+// AssignedReview — PR с точки зрения назначенного на него ревьювера.
+type AssignedReview struct {
+	PullRequest *PullRequest
+	AssignedAt  time.Time
+	Verdict     modelra.Verdict
+	VerdictAt   *time.Time
+}
+
+// ReviewCursor — позиция в списке ревью, упорядоченном по (assigned_at, pr_id) по убыванию.
+type ReviewCursor struct {
+	AssignedAt time.Time
+	PRID       string
+}
+
+// ReviewFilter — условия выборки ревью пользователя.
+type ReviewFilter struct {
+	ReviewerID string
+	Statuses   []PRStatus
+	Since      *time.Time    // назначен не раньше
+	After      *ReviewCursor // вернуть ревью строго после курсора
+	Limit      int           // 0 — без ограничения
+}
+
+// Validate проверяет статусы и лимит.
+func (f *ReviewFilter) Validate() error {
+	if f.Limit < 0 || f.Limit > MaxListLimit {
+		return ErrInvalidListFilter
+	}
+	return validateStatuses(f.Statuses)
+}
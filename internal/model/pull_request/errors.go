@@ -1,7 +1,35 @@
 package pull_request
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/zxchelik/avito-test-task/internal/model"
+)
 
 var (
-	ErrPRAlreadyMerged = errors.New("PR is already merged")
+	ErrPRAlreadyMerged           = errors.New("PR is already merged")
+	ErrReviewersCountOutOfBounds = errors.New("reviewers count is out of team bounds")
+	ErrPRNotOpen                 = errors.New("PR is not open")
+	ErrInvalidTransition         = errors.New("PR status transition is not allowed")
+	ErrNotApproved               = errors.New("PR is not approved")
+	ErrInvalidMergeRule          = errors.New("invalid merge rule")
+	ErrInvalidListFilter         = errors.New("invalid pull request list filter")
+	// ErrPRExists — PR с таким id уже есть; errors.Is(err, model.ErrAlreadyExists) тоже верно.
+	ErrPRExists = fmt.Errorf("PR %w", model.ErrAlreadyExists)
 )
+
+// NotApprovedError — merge запрещён правилом; Blocking — ревьюверы, чьи вердикты мешают merge.
+type NotApprovedError struct {
+	Rule      MergeRule
+	Approvals int
+	Blocking  []string
+}
+
+func (e *NotApprovedError) Error() string {
+	return fmt.Sprintf("%s: rule %s, %d approvals, blocked by %v", ErrNotApproved, e.Rule.Kind, e.Approvals, e.Blocking)
+}
+
+func (e *NotApprovedError) Unwrap() error {
+	return ErrNotApproved
+}
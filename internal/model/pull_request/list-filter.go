@@ -0,0 +1,50 @@
+package pull_request
+
+import "time"
+
+const (
+	DefaultListLimit = 50
+	MaxListLimit     = 200
+)
+
+// Cursor — позиция в списке PR, упорядоченном по (created_at, id).
+type Cursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// ListFilter — условия выборки PR; пустые поля не ограничивают выборку.
+type ListFilter struct {
+	Statuses    []PRStatus
+	AuthorID    string
+	TeamName    string // команда автора
+	ReviewerID  string
+	CreatedFrom *time.Time // включительно
+	CreatedTo   *time.Time // не включительно
+	MergedFrom  *time.Time
+	MergedTo    *time.Time
+	After       *Cursor // вернуть PR строго после курсора
+	Limit       int
+}
+
+// Normalize подставляет лимит по умолчанию и проверяет фильтр.
+func (f *ListFilter) Normalize() error {
+	if f.Limit == 0 {
+		f.Limit = DefaultListLimit
+	}
+	if f.Limit < 0 || f.Limit > MaxListLimit {
+		return ErrInvalidListFilter
+	}
+	return validateStatuses(f.Statuses)
+}
+
+func validateStatuses(statuses []PRStatus) error {
+	for _, st := range statuses {
+		switch st {
+		case PRDraft, PROpen, PRMerged, PRClosed:
+		default:
+			return ErrInvalidListFilter
+		}
+	}
+	return nil
+}
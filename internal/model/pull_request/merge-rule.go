@@ -0,0 +1,74 @@
+package pull_request
+
+import (
+	modelra "github.com/zxchelik/avito-test-task/internal/model/reviewer_assignment"
+)
+
+// MergeRuleKind — правило, по которому PR допускается к merge.
+type MergeRuleKind string
+
+const (
+	// MergeRuleNone — merge без проверки ревью.
+	MergeRuleNone MergeRuleKind = "none"
+	// MergeRuleAllApproved — все назначенные ревьюверы одобрили PR.
+	MergeRuleAllApproved MergeRuleKind = "all_approved"
+	// MergeRuleMinApprovals — не меньше MinApprovals одобрений и ни одного CHANGES_REQUESTED.
+	MergeRuleMinApprovals MergeRuleKind = "min_approvals"
+)
+
+// MergeRule — настроенное правило merge.
+type MergeRule struct {
+	Kind         MergeRuleKind
+	MinApprovals int
+}
+
+// Validate проверяет настройки правила.
+func (r MergeRule) Validate() error {
+	switch r.Kind {
+	case MergeRuleNone, MergeRuleAllApproved:
+		return nil
+	case MergeRuleMinApprovals:
+		if r.MinApprovals < 1 {
+			return ErrInvalidMergeRule
+		}
+		return nil
+	default:
+		return ErrInvalidMergeRule
+	}
+}
+
+// Check возвращает *NotApprovedError, если ревью не удовлетворяют правилу.
+func (r MergeRule) Check(reviews []*modelra.ReviewerAssignment) error {
+	var (
+		approvals int
+		pending   []string // ещё не одобрили
+		changes   []string // запросили изменения
+	)
+	for _, rv := range reviews {
+		switch rv.Verdict {
+		case modelra.VerdictApproved:
+			approvals++
+		case modelra.VerdictChangesRequested:
+			changes = append(changes, rv.UserId)
+		default:
+			pending = append(pending, rv.UserId)
+		}
+	}
+
+	switch r.Kind {
+	case MergeRuleAllApproved:
+		blocking := append(changes, pending...)
+		if len(blocking) > 0 {
+			return &NotApprovedError{Rule: r, Approvals: approvals, Blocking: blocking}
+		}
+	case MergeRuleMinApprovals:
+		blocking := changes
+		if approvals < r.MinApprovals {
+			blocking = append(blocking, pending...)
+		}
+		if len(changes) > 0 || approvals < r.MinApprovals {
+			return &NotApprovedError{Rule: r, Approvals: approvals, Blocking: blocking}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package pull_request
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	modelra "github.com/zxchelik/avito-test-task/internal/model/reviewer_assignment"
+)
+
+func TestMergeRuleCheck(t *testing.T) {
+	reviews := func(verdicts ...modelra.Verdict) []*modelra.ReviewerAssignment {
+		res := make([]*modelra.ReviewerAssignment, len(verdicts))
+		for i, v := range verdicts {
+			res[i] = &modelra.ReviewerAssignment{UserId: string(rune('a' + i)), Verdict: v}
+		}
+		return res
+	}
+	var (
+		approved = modelra.VerdictApproved
+		changes  = modelra.VerdictChangesRequested
+		comment  = modelra.VerdictCommented
+		none     = modelra.VerdictNone
+	)
+	allApproved := MergeRule{Kind: MergeRuleAllApproved}
+	twoApprovals := MergeRule{Kind: MergeRuleMinApprovals, MinApprovals: 2}
+
+	cases := []struct {
+		name     string
+		rule     MergeRule
+		reviews  []*modelra.ReviewerAssignment
+		blocking []string // nil — merge разрешён
+	}{
+		{"none ignores verdicts", MergeRule{Kind: MergeRuleNone}, reviews(changes, none), nil},
+		{"all approved", allApproved, reviews(approved, approved), nil},
+		{"all approved without reviewers", allApproved, nil, nil},
+		{"all approved, one pending", allApproved, reviews(approved, none), []string{"b"}},
+		// сначала запросившие изменения, затем остальные неодобрившие
+		{"all approved, comment and changes", allApproved, reviews(comment, approved, changes), []string{"c", "a"}},
+		{"min approvals reached", twoApprovals, reviews(approved, none, approved), nil},
+		{"min approvals not reached", twoApprovals, reviews(approved, comment), []string{"b"}},
+		// одобрений хватает, но запрос изменений блокирует
+		{"min approvals with changes requested", twoApprovals, reviews(approved, approved, changes, none), []string{"c"}},
+		{"min approvals neither", twoApprovals, reviews(changes, approved, none), []string{"a", "c"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.rule.Check(c.reviews)
+			if c.blocking == nil {
+				if err != nil {
+					t.Fatalf("got %v, want merge allowed", err)
+				}
+				return
+			}
+
+			var notApproved *NotApprovedError
+			if !errors.As(err, &notApproved) || !errors.Is(err, ErrNotApproved) {
+				t.Fatalf("got %v, want NotApprovedError", err)
+			}
+			if !slices.Equal(notApproved.Blocking, c.blocking) {
+				t.Fatalf("blocking = %v, want %v", notApproved.Blocking, c.blocking)
+			}
+		})
+	}
+}
+
+func TestMergeRuleValidate(t *testing.T) {
+	for _, r := range []MergeRule{
+		{Kind: MergeRuleNone},
+		{Kind: MergeRuleAllApproved},
+		{Kind: MergeRuleMinApprovals, MinApprovals: 1},
+	} {
+		if err := r.Validate(); err != nil {
+			t.Errorf("%+v: %v", r, err)
+		}
+	}
+	for _, r := range []MergeRule{
+		{Kind: "majority"},
+		{Kind: MergeRuleMinApprovals},
+		{Kind: MergeRuleMinApprovals, MinApprovals: -1},
+	} {
+		if err := r.Validate(); !errors.Is(err, ErrInvalidMergeRule) {
+			t.Errorf("%+v: got %v, want ErrInvalidMergeRule", r, err)
+		}
+	}
+}
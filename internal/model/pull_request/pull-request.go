@@ -9,4 +9,5 @@ type PullRequest struct {
 	Status    PRStatus
 	CreatedAt time.Time
 	MergedAt  *time.Time
+	ClosedAt  *time.Time
 }
@@ -3,6 +3,25 @@ package pull_request
 type PRStatus string
 
 const (
+	PRDraft  PRStatus = "DRAFT" // ревьюверы не назначаются до перевода в OPEN
 	PROpen   PRStatus = "OPEN"
 	PRMerged PRStatus = "MERGED"
+	PRClosed PRStatus = "CLOSED" // закрыт без merge, может быть переоткрыт
 )
+
+// transitions — допустимые переходы жизненного цикла PR.
+var transitions = map[PRStatus][]PRStatus{
+	PRDraft:  {PROpen, PRClosed},
+	PROpen:   {PRMerged, PRClosed},
+	PRClosed: {PROpen},
+}
+
+// CanTransitionTo сообщает, допустим ли переход из s в to.
+func (s PRStatus) CanTransitionTo(to PRStatus) bool {
+	for _, next := range transitions[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
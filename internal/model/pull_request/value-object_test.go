@@ -0,0 +1,21 @@
+package pull_request
+
+import "testing"
+
+func TestCanTransitionTo(t *testing.T) {
+	allowed := map[[2]PRStatus]bool{
+		{PRDraft, PROpen}:   true,
+		{PRDraft, PRClosed}: true,
+		{PROpen, PRMerged}:  true,
+		{PROpen, PRClosed}:  true,
+		{PRClosed, PROpen}:  true,
+	}
+	statuses := []PRStatus{PRDraft, PROpen, PRMerged, PRClosed}
+	for _, from := range statuses {
+		for _, to := range statuses {
+			if got := from.CanTransitionTo(to); got != allowed[[2]PRStatus{from, to}] {
+				t.Errorf("%s -> %s: got %v", from, to, got)
+			}
+		}
+	}
+}
@@ -8,4 +8,6 @@ var (
 	ErrReviewerSameAsAuthor     = errors.New("reviewer cannot be PR author")
 	ErrReviewerDuplication      = errors.New("reviewer already assigned")
 	ErrNoReviewerCandidatesLeft = errors.New("no available reviewer candidates")
+	ErrReviewersAtCapacity      = errors.New("all reviewer candidates are at capacity")
+	ErrUnknownVerdict           = errors.New("unknown review verdict")
 )
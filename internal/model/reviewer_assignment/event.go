@@ -0,0 +1,41 @@
+package reviewer_assignment
+
+import "time"
+
+// EventType — вид события в истории ревью PR.
+type EventType string
+
+const (
+	EventAssigned      EventType = "ASSIGNED"       // ревьювер назначен
+	EventUnassigned    EventType = "UNASSIGNED"     // ревьювер снят без замены
+	EventReplaced      EventType = "REPLACED"       // PreviousUserId заменён на UserId
+	EventVerdict       EventType = "VERDICT"        // ревьювер отправил вердикт
+	EventStatusChanged EventType = "STATUS_CHANGED" // PR перешёл в Status
+	EventMerged        EventType = "MERGED"         // PR смержен
+	EventSLABreached   EventType = "SLA_BREACHED"   // ревьювер не отправил вердикт в срок SLA
+)
+
+// Причины назначений и замен.
+const (
+	ReasonPRCreated    = "pr_created"
+	ReasonReady        = "ready_for_review"
+	ReasonReopened     = "reopened"
+	ReasonManual       = "manual_reassign"
+	ReasonDeactivated  = "reviewer_deactivated"
+	ReasonTeamOffboard = "team_deactivated"
+	ReasonSLABreached  = "sla_breached"
+)
+
+// Event — неизменяемая запись истории ревью PR.
+type Event struct {
+	ID             int64
+	PrId           string
+	Type           EventType
+	UserId         string  // ревьювер, к которому относится событие
+	PreviousUserId string  // для REPLACED — кого заменили
+	Verdict        Verdict // для VERDICT
+	Status         string  // для STATUS_CHANGED и MERGED — новый статус PR
+	ActorId        string  // кто выполнил действие; пусто — система
+	Reason         string
+	CreatedAt      time.Time
+}
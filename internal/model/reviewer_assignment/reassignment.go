@@ -0,0 +1,15 @@
+package reviewer_assignment
+
+// Reassignment — итог попытки заменить ревьювера на PR.
+type Reassignment struct {
+	PrId      string
+	OldUserId string
+	NewUserId string // пусто, если замена не найдена
+	Fallback  bool   // замена взята из резервной команды
+	Reason    error  // почему замена не найдена
+}
+
+// Replaced сообщает, нашлась ли замена.
+func (r *Reassignment) Replaced() bool {
+	return r.NewUserId != ""
+}
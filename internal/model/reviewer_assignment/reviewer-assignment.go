@@ -3,7 +3,11 @@ package reviewer_assignment
 import "time"
 
 type ReviewerAssignment struct {
-	PrId       string
-	UserId     string
-	AssignedAt time.Time
+	PrId          string
+	UserId        string
+	AssignedAt    time.Time
+	Fallback      bool       // ревьювер взят из резервной команды
+	Verdict       Verdict    // последний отправленный вердикт
+	VerdictAt     *time.Time // когда отправлен вердикт
+	SLABreachedAt *time.Time // когда зафиксировано нарушение SLA
 }
@@ -0,0 +1,38 @@
+package reviewer_assignment
+
+import "time"
+
+// SLAPending — назначение без вердикта на PR команды с настроенным SLA.
+type SLAPending struct {
+	ReviewerAssignment
+	TeamName     string // команда автора PR, чей SLA действует
+	SLAHours     int    // часов по будням на первый вердикт
+	AutoReassign bool   // переназначать ревью при нарушении
+}
+
+// Breached сообщает, истёк ли SLA к моменту now.
+func (p *SLAPending) Breached(now time.Time) bool {
+	if p.SLAHours <= 0 {
+		return false
+	}
+	return WeekdayDuration(p.AssignedAt, now) >= time.Duration(p.SLAHours)*time.Hour
+}
+
+// WeekdayDuration считает время между from и to по часам, исключая субботу и воскресенье:
+// будни учитываются целиком, все 24 часа, без рабочего дня и часового пояса команды.
+// Границы суток берутся в UTC.
+func WeekdayDuration(from, to time.Time) time.Duration {
+	var total time.Duration
+	cur, to := from.UTC(), to.UTC()
+	for cur.Before(to) {
+		end := time.Date(cur.Year(), cur.Month(), cur.Day()+1, 0, 0, 0, 0, time.UTC)
+		if to.Before(end) {
+			end = to
+		}
+		if wd := cur.Weekday(); wd != time.Saturday && wd != time.Sunday {
+			total += end.Sub(cur)
+		}
+		cur = end
+	}
+	return total
+}
@@ -0,0 +1,31 @@
+package reviewer_assignment
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWeekdayDuration(t *testing.T) {
+	at := func(day, hour int) time.Time { return time.Date(2025, 3, day, hour, 0, 0, 0, time.UTC) } // 3 марта — понедельник
+
+	cases := []struct {
+		name     string
+		from, to time.Time
+		want     time.Duration
+	}{
+		{"same weekday", at(3, 9), at(3, 17), 8 * time.Hour},
+		{"whole weekday counts", at(4, 0), at(5, 0), 24 * time.Hour},
+		{"friday to monday", at(7, 22), at(10, 2), 4 * time.Hour},
+		{"weekend only", at(8, 1), at(9, 23), 0},
+		{"full week", at(3, 0), at(10, 0), 5 * 24 * time.Hour},
+		{"reversed", at(4, 0), at(3, 0), 0},
+		{"other zone", at(7, 22).In(time.FixedZone("UTC+3", 3*3600)), at(10, 2), 4 * time.Hour},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := WeekdayDuration(c.from, c.to); got != c.want {
+				t.Fatalf("WeekdayDuration(%s, %s) = %s, want %s", c.from, c.to, got, c.want)
+			}
+		})
+	}
+}
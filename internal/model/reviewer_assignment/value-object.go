@@ -0,0 +1,21 @@
+package reviewer_assignment
+
+// Verdict — итог ревью, выставленный ревьювером.
+type Verdict string
+
+const (
+	VerdictNone             Verdict = "" // ревьювер ещё ничего не отправил
+	VerdictApproved         Verdict = "APPROVED"
+	VerdictChangesRequested Verdict = "CHANGES_REQUESTED"
+	VerdictCommented        Verdict = "COMMENTED"
+)
+
+// Valid сообщает, можно ли отправить такой вердикт.
+func (v Verdict) Valid() bool {
+	switch v {
+	case VerdictApproved, VerdictChangesRequested, VerdictCommented:
+		return true
+	default:
+		return false
+	}
+}
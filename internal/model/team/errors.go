@@ -1,7 +1,21 @@
 package team
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/zxchelik/avito-test-task/internal/model"
+)
 
 var (
-	ErrNoEligibleReviewers = errors.New("no eligible reviewers found")
+	ErrNoEligibleReviewers     = errors.New("no eligible reviewers found")
+	ErrUnknownReviewerStrategy = errors.New("unknown reviewer strategy")
+	ErrInvalidReviewerBounds   = errors.New("invalid reviewer bounds: expected 0 <= min_reviewers <= max_reviewers")
+	ErrInvalidFallbackTeams    = errors.New("fallback teams must be unique and differ from the team itself")
+	ErrFallbackTeamNotFound    = errors.New("fallback team not found")
+	ErrInvalidMaxOpenReviews   = errors.New("default_max_open_reviews must not be negative")
+	ErrInvalidReviewSLA        = errors.New("review_sla_weekday_hours must not be negative")
+
+	// ErrTeamExists — команда с таким именем уже есть; errors.Is(err, model.ErrAlreadyExists) тоже верно.
+	ErrTeamExists = fmt.Errorf("team %w", model.ErrAlreadyExists)
 )
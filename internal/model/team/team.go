@@ -1,5 +1,75 @@
 package team
 
 type Team struct {
-	Name string
+	Name                  string
+	ReviewerStrategy      ReviewerStrategy
+	MinReviewers          int
+	MaxReviewers          int
+	FallbackTeams         []string // резервные команды в порядке приоритета
+	DefaultMaxOpenReviews int      // лимит открытых ревью участника по умолчанию, 0 — без ограничений
+	ReviewSLAWeekdayHours int      // часов по будням (пн–пт, UTC) на первый вердикт, 0 — без SLA
+	SLAAutoReassign       bool     // переназначать ревью при нарушении SLA
+}
+
+// SettingsUpdate — частичное обновление настроек команды; nil-поля не меняются.
+type SettingsUpdate struct {
+	ReviewerStrategy      *ReviewerStrategy
+	MinReviewers          *int
+	MaxReviewers          *int
+	FallbackTeams         *[]string
+	DefaultMaxOpenReviews *int
+	ReviewSLAWeekdayHours *int
+	SLAAutoReassign       *bool
+}
+
+// Apply применяет обновление к команде.
+func (u SettingsUpdate) Apply(t *Team) {
+	if u.ReviewerStrategy != nil {
+		t.ReviewerStrategy = *u.ReviewerStrategy
+	}
+	if u.MinReviewers != nil {
+		t.MinReviewers = *u.MinReviewers
+	}
+	if u.MaxReviewers != nil {
+		t.MaxReviewers = *u.MaxReviewers
+	}
+	if u.FallbackTeams != nil {
+		t.FallbackTeams = *u.FallbackTeams
+	}
+	if u.DefaultMaxOpenReviews != nil {
+		t.DefaultMaxOpenReviews = *u.DefaultMaxOpenReviews
+	}
+	if u.ReviewSLAWeekdayHours != nil {
+		t.ReviewSLAWeekdayHours = *u.ReviewSLAWeekdayHours
+	}
+	if u.SLAAutoReassign != nil {
+		t.SLAAutoReassign = *u.SLAAutoReassign
+	}
+}
+
+// Validate проверяет настройки команды.
+func (t *Team) Validate() error {
+	if !t.ReviewerStrategy.Valid() {
+		return ErrUnknownReviewerStrategy
+	}
+	if t.MinReviewers < 0 || t.MaxReviewers < t.MinReviewers {
+		return ErrInvalidReviewerBounds
+	}
+	if t.DefaultMaxOpenReviews < 0 {
+		return ErrInvalidMaxOpenReviews
+	}
+	if t.ReviewSLAWeekdayHours < 0 {
+		return ErrInvalidReviewSLA
+	}
+	seen := make(map[string]struct{}, len(t.FallbackTeams))
+	for _, f := range t.FallbackTeams {
+		if f == "" || f == t.Name {
+			return ErrInvalidFallbackTeams
+		}
+		if _, dup := seen[f]; dup {
+			return ErrInvalidFallbackTeams
+		}
+		seen[f] = struct{}{}
+	}
+	return nil
 }
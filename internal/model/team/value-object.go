@@ -0,0 +1,28 @@
+package team
+
+// ReviewerStrategy — алгоритм выбора ревьюверов, настраиваемый для команды.
+type ReviewerStrategy string
+
+const (
+	StrategyRandom      ReviewerStrategy = "random"
+	StrategyRoundRobin  ReviewerStrategy = "round_robin"
+	StrategyLeastLoaded ReviewerStrategy = "least_loaded"
+	StrategySeeded      ReviewerStrategy = "seeded"
+
+	DefaultReviewerStrategy = StrategyLeastLoaded
+)
+
+const (
+	DefaultMinReviewers = 1
+	DefaultMaxReviewers = 2
+)
+
+// Valid сообщает, известна ли стратегия.
+func (s ReviewerStrategy) Valid() bool {
+	switch s {
+	case StrategyRandom, StrategyRoundRobin, StrategyLeastLoaded, StrategySeeded:
+		return true
+	default:
+		return false
+	}
+}
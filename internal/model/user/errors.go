@@ -3,6 +3,8 @@ package user
 import "errors"
 
 var (
-	ErrUserInactive = errors.New("user is inactive")
-	ErrTeamNotFound = errors.New("team not found")
+	ErrUserInactive          = errors.New("user is inactive")
+	ErrTeamNotFound          = errors.New("team not found")
+	ErrInvalidMaxOpenReviews = errors.New("max_open_reviews must not be negative")
+	ErrNotTeamMember         = errors.New("user is not a member of the team")
 )
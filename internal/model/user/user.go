@@ -3,9 +3,29 @@ package user
 import "time"
 
 type User struct {
-	ID        string
-	Username  string
-	TeamName  string
-	IsActive  bool
-	CreatedAt time.Time
+	ID             string
+	Username       string
+	TeamName       string
+	IsActive       bool
+	MaxOpenReviews int // 0 — лимит не задан, действует лимит команды
+	CreatedAt      time.Time
+}
+
+// Capacity возвращает действующий лимит открытых ревью (0 — без ограничений).
+func (u *User) Capacity(teamDefault int) int {
+	if u.MaxOpenReviews > 0 {
+		return u.MaxOpenReviews
+	}
+	return teamDefault
+}
+
+// ReviewLoad — текущая нагрузка пользователя относительно лимита.
+type ReviewLoad struct {
+	Open     int
+	Capacity int // 0 — без ограничений
+}
+
+// AtCapacity сообщает, достигнут ли лимит открытых ревью.
+func (l ReviewLoad) AtCapacity() bool {
+	return l.Capacity > 0 && l.Open >= l.Capacity
 }
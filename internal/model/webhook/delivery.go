@@ -0,0 +1,55 @@
+package webhook
+
+import "time"
+
+// DeliveryStatus — состояние доставки из outbox.
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "PENDING"
+	DeliveryDelivered DeliveryStatus = "DELIVERED"
+	DeliveryDead      DeliveryStatus = "DEAD" // попытки исчерпаны
+)
+
+// Delivery — сообщение outbox для одной подписки.
+type Delivery struct {
+	ID             int64
+	SubscriptionID int64
+	URL            string
+	Secret         string
+	EventType      EventType
+	Payload        []byte
+	Status         DeliveryStatus
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastError      string
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+}
+
+// RetryPolicy — экспоненциальные повторы доставки.
+type RetryPolicy struct {
+	MaxAttempts int           // после стольких неудач доставка уходит в DEAD
+	BaseDelay   time.Duration // задержка после первой неудачи, дальше удваивается
+	MaxDelay    time.Duration
+}
+
+// DefaultRetryPolicy — 8 попыток с задержкой от 30 секунд до часа.
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 8, BaseDelay: 30 * time.Second, MaxDelay: time.Hour}
+
+// Backoff возвращает задержку перед следующей попыткой после attempts неудач.
+func (p RetryPolicy) Backoff(attempts int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempts && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay
+}
+
+// Exhausted сообщает, что после attempts неудач повторять больше нельзя.
+func (p RetryPolicy) Exhausted(attempts int) bool {
+	return attempts >= p.MaxAttempts
+}
@@ -0,0 +1,11 @@
+package webhook
+
+import "errors"
+
+var (
+	ErrInvalidURL       = errors.New("webhook url must be an absolute http(s) url")
+	ErrEmptySecret      = errors.New("webhook secret is required")
+	ErrUnknownEventType = errors.New("unknown webhook event type")
+	ErrNoEventTypes     = errors.New("at least one webhook event type is required")
+	ErrNotDead          = errors.New("webhook delivery is not in dead-letter state")
+)
@@ -0,0 +1,101 @@
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+
+	modelra "github.com/zxchelik/avito-test-task/internal/model/reviewer_assignment"
+)
+
+// EventType — вид события, на который можно подписаться.
+type EventType string
+
+const (
+	EventPRCreated         EventType = "pull_request.created"
+	EventPRMerged          EventType = "pull_request.merged"
+	EventReviewerAssigned  EventType = "reviewer.assigned"
+	EventReviewerReplaced  EventType = "reviewer.replaced"
+	EventReviewSLABreached EventType = "reviewer.sla_breached"
+)
+
+// Valid сообщает, известен ли тип события.
+func (t EventType) Valid() bool {
+	switch t {
+	case EventPRCreated, EventPRMerged, EventReviewerAssigned, EventReviewerReplaced, EventReviewSLABreached:
+		return true
+	default:
+		return false
+	}
+}
+
+// Message — событие для outbox; расходится по всем подпискам на его тип.
+type Message struct {
+	Type      EventType
+	Payload   []byte // JSON-тело запроса
+	CreatedAt time.Time
+}
+
+// Payload — тело исходящего вебхука.
+type Payload struct {
+	Event          EventType `json:"event"`
+	PullRequestID  string    `json:"pull_request_id"`
+	UserID         string    `json:"user_id,omitempty"`
+	PreviousUserID string    `json:"previous_user_id,omitempty"`
+	Reason         string    `json:"reason,omitempty"`
+	ActorID        string    `json:"actor_id,omitempty"`
+	OccurredAt     time.Time `json:"occurred_at"`
+}
+
+// MessageFor строит сообщение по событию истории ревью.
+// Возвращает nil, если событие не публикуется наружу.
+func MessageFor(e *modelra.Event) (*Message, error) {
+	typ, ok := eventTypeFor(e)
+	if !ok {
+		return nil, nil
+	}
+	payload, err := json.Marshal(Payload{
+		Event:          typ,
+		PullRequestID:  e.PrId,
+		UserID:         e.UserId,
+		PreviousUserID: e.PreviousUserId,
+		Reason:         e.Reason,
+		ActorID:        e.ActorId,
+		OccurredAt:     e.CreatedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Message{Type: typ, Payload: payload, CreatedAt: e.CreatedAt}, nil
+}
+
+// MessagesFor строит сообщения по событиям, пропуская те, что не публикуются наружу.
+func MessagesFor(events []*modelra.Event) ([]*Message, error) {
+	msgs := make([]*Message, 0, len(events))
+	for _, e := range events {
+		msg, err := MessageFor(e)
+		if err != nil {
+			return nil, err
+		}
+		if msg != nil {
+			msgs = append(msgs, msg)
+		}
+	}
+	return msgs, nil
+}
+
+func eventTypeFor(e *modelra.Event) (EventType, bool) {
+	switch {
+	case e.Type == modelra.EventAssigned:
+		return EventReviewerAssigned, true
+	case e.Type == modelra.EventReplaced:
+		return EventReviewerReplaced, true
+	case e.Type == modelra.EventMerged:
+		return EventPRMerged, true
+	case e.Type == modelra.EventSLABreached:
+		return EventReviewSLABreached, true
+	case e.Type == modelra.EventStatusChanged && e.Reason == modelra.ReasonPRCreated:
+		return EventPRCreated, true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,35 @@
+package webhook
+
+import (
+	"net/url"
+	"time"
+)
+
+// Subscription — получатель исходящих вебхуков.
+type Subscription struct {
+	ID         int64
+	URL        string
+	Secret     string // ключ HMAC-подписи тела запроса
+	EventTypes []EventType
+	CreatedAt  time.Time
+}
+
+// Validate проверяет подписку перед сохранением.
+func (s *Subscription) Validate() error {
+	u, err := url.Parse(s.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidURL
+	}
+	if s.Secret == "" {
+		return ErrEmptySecret
+	}
+	if len(s.EventTypes) == 0 {
+		return ErrNoEventTypes
+	}
+	for _, t := range s.EventTypes {
+		if !t.Valid() {
+			return ErrUnknownEventType
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package absence
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/zxchelik/avito-test-task/internal/infrastructure/memory"
+	"github.com/zxchelik/avito-test-task/internal/model"
+	"github.com/zxchelik/avito-test-task/internal/model/absence"
+)
+
+type MemoryRepository struct {
+	db *memory.DB
+}
+
+func NewMemoryRepository(db *memory.DB) *MemoryRepository {
+	return &MemoryRepository{db: db}
+}
+
+// Create inserts a new absence.
+// Returns model.ErrNotFound if user does not exist.
+func (r *MemoryRepository) Create(ctx context.Context, a *absence.Absence) (*absence.Absence, error) {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	if _, ok := tables.Users[a.UserID]; !ok {
+		return nil, model.ErrNotFound
+	}
+
+	stored := &absence.Absence{
+		ID:        tables.NextID("user_absences"),
+		UserID:    a.UserID,
+		StartsAt:  memory.Timestamp(a.StartsAt),
+		EndsAt:    memory.Timestamp(a.EndsAt),
+		Reason:    a.Reason,
+		CreatedAt: r.db.Now(),
+	}
+	tables.Absences[stored.ID] = stored
+
+	c := *stored
+	return &c, nil
+}
+
+// GetByID returns an absence by id.
+// Returns model.ErrNotFound if absence doesn't exist.
+func (r *MemoryRepository) GetByID(ctx context.Context, id int64) (*absence.Absence, error) {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	a, ok := tables.Absences[id]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+
+	c := *a
+	return &c, nil
+}
+
+// ListByUser returns absences of a user ordered by start time.
+// Cancelled absences are included only when includeCancelled is set.
+func (r *MemoryRepository) ListByUser(ctx context.Context, userID string, includeCancelled bool) ([]*absence.Absence, error) {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	var res []*absence.Absence
+	for _, a := range tables.Absences {
+		if a.UserID != userID || (!includeCancelled && a.CancelledAt != nil) {
+			continue
+		}
+		c := *a
+		res = append(res, &c)
+	}
+	slices.SortFunc(res, func(a, b *absence.Absence) int {
+		return cmp.Or(a.StartsAt.Compare(b.StartsAt), cmp.Compare(a.ID, b.ID))
+	})
+
+	return res, nil
+}
+
+// Cancel marks an absence as cancelled at the given moment.
+// Returns:
+//   - model.ErrNotFound — if absence doesn't exist
+//   - absence.ErrAlreadyCancelled — if absence was cancelled before
+func (r *MemoryRepository) Cancel(ctx context.Context, id int64, at time.Time) (*absence.Absence, error) {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	a, ok := tables.Absences[id]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+	if a.CancelledAt != nil {
+		return nil, absence.ErrAlreadyCancelled
+	}
+	cancelledAt := memory.Timestamp(at)
+	a.CancelledAt = &cancelledAt
+
+	c := *a
+	return &c, nil
+}
+
+// ListAbsentUserIDs returns ids of the given users that are absent at the given moment.
+func (r *MemoryRepository) ListAbsentUserIDs(ctx context.Context, userIDs []string, at time.Time) (map[string]struct{}, error) {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	absent := make(map[string]struct{})
+	for _, a := range tables.Absences {
+		if slices.Contains(userIDs, a.UserID) && a.Covers(at) {
+			absent[a.UserID] = struct{}{}
+		}
+	}
+
+	return absent, nil
+}
@@ -0,0 +1,168 @@
+package absence
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/zxchelik/avito-test-task/internal/infrastructure/pg"
+	"github.com/zxchelik/avito-test-task/internal/model"
+	"github.com/zxchelik/avito-test-task/internal/model/absence"
+	"time"
+)
+
+type PGRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPGRepository(pool *pgxpool.Pool) *PGRepository {
+	return &PGRepository{pool: pool}
+}
+
+// Create inserts a new absence.
+// Returns model.ErrNotFound if user does not exist (FK violation).
+func (r *PGRepository) Create(ctx context.Context, a *absence.Absence) (*absence.Absence, error) {
+	q := pg.GetQuerierFromContext(ctx, r.pool)
+	const query = `
+		INSERT INTO user_absences (user_id, starts_at, ends_at, reason)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, user_id, starts_at, ends_at, reason, created_at, cancelled_at
+	`
+
+	var created absence.Absence
+	err := q.QueryRow(ctx, query, a.UserID, a.StartsAt, a.EndsAt, a.Reason).Scan(
+		&created.ID, &created.UserID, &created.StartsAt, &created.EndsAt,
+		&created.Reason, &created.CreatedAt, &created.CancelledAt,
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return nil, model.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// GetByID returns an absence by id.
+// Returns model.ErrNotFound if absence doesn't exist.
+func (r *PGRepository) GetByID(ctx context.Context, id int64) (*absence.Absence, error) {
+	q := pg.GetQuerierFromContext(ctx, r.pool)
+	const query = `
+		SELECT id, user_id, starts_at, ends_at, reason, created_at, cancelled_at
+		FROM user_absences
+		WHERE id = $1
+	`
+
+	var a absence.Absence
+	err := q.QueryRow(ctx, query, id).Scan(
+		&a.ID, &a.UserID, &a.StartsAt, &a.EndsAt, &a.Reason, &a.CreatedAt, &a.CancelledAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &a, nil
+}
+
+// ListByUser returns absences of a user ordered by start time.
+// Cancelled absences are included only when includeCancelled is set.
+func (r *PGRepository) ListByUser(ctx context.Context, userID string, includeCancelled bool) ([]*absence.Absence, error) {
+	q := pg.GetQuerierFromContext(ctx, r.pool)
+	const query = `
+		SELECT id, user_id, starts_at, ends_at, reason, created_at, cancelled_at
+		FROM user_absences
+		WHERE user_id = $1 AND ($2 OR cancelled_at IS NULL)
+		ORDER BY starts_at, id
+	`
+
+	rows, err := q.Query(ctx, query, userID, includeCancelled)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []*absence.Absence
+	for rows.Next() {
+		var a absence.Absence
+		if err := rows.Scan(
+			&a.ID, &a.UserID, &a.StartsAt, &a.EndsAt, &a.Reason, &a.CreatedAt, &a.CancelledAt,
+		); err != nil {
+			return nil, err
+		}
+		res = append(res, &a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Cancel marks an absence as cancelled at the given moment.
+// Returns:
+//   - model.ErrNotFound — if absence doesn't exist
+//   - absence.ErrAlreadyCancelled — if absence was cancelled before
+func (r *PGRepository) Cancel(ctx context.Context, id int64, at time.Time) (*absence.Absence, error) {
+	q := pg.GetQuerierFromContext(ctx, r.pool)
+	const query = `
+		UPDATE user_absences
+		SET cancelled_at = $2
+		WHERE id = $1 AND cancelled_at IS NULL
+		RETURNING id, user_id, starts_at, ends_at, reason, created_at, cancelled_at
+	`
+
+	var a absence.Absence
+	err := q.QueryRow(ctx, query, id, at).Scan(
+		&a.ID, &a.UserID, &a.StartsAt, &a.EndsAt, &a.Reason, &a.CreatedAt, &a.CancelledAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// различаем «нет такой записи» и «уже отменена»
+		if _, getErr := r.GetByID(ctx, id); getErr != nil {
+			return nil, getErr
+		}
+		return nil, absence.ErrAlreadyCancelled
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &a, nil
+}
+
+// ListAbsentUserIDs returns ids of the given users that are absent at the given moment.
+func (r *PGRepository) ListAbsentUserIDs(ctx context.Context, userIDs []string, at time.Time) (map[string]struct{}, error) {
+	q := pg.GetQuerierFromContext(ctx, r.pool)
+	const query = `
+		SELECT DISTINCT user_id
+		FROM user_absences
+		WHERE user_id = ANY($1)
+		  AND cancelled_at IS NULL
+		  AND starts_at <= $2 AND ends_at > $2
+	`
+
+	rows, err := q.Query(ctx, query, userIDs, at)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	absent := make(map[string]struct{})
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		absent[id] = struct{}{}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return absent, nil
+}
@@ -0,0 +1,168 @@
+package absence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/zxchelik/avito-test-task/internal/infrastructure/sqlite"
+	"github.com/zxchelik/avito-test-task/internal/model"
+	"github.com/zxchelik/avito-test-task/internal/model/absence"
+)
+
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteRepository(db *sql.DB) *SQLiteRepository {
+	return &SQLiteRepository{db: db}
+}
+
+// Create inserts a new absence.
+// Returns model.ErrNotFound if user does not exist (FK violation).
+func (r *SQLiteRepository) Create(ctx context.Context, a *absence.Absence) (*absence.Absence, error) {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+	const query = `
+		INSERT INTO user_absences (user_id, starts_at, ends_at, reason, created_at)
+		VALUES (?1, ?2, ?3, ?4, ?5)
+		RETURNING id, user_id, starts_at, ends_at, reason, created_at, cancelled_at
+	`
+
+	var created absence.Absence
+	err := q.QueryRowContext(ctx, query,
+		a.UserID, sqlite.Time(a.StartsAt), sqlite.Time(a.EndsAt), a.Reason, sqlite.Time(time.Now()),
+	).Scan(scanTargets(&created)...)
+	if err != nil {
+		if sqlite.IsForeignKeyViolation(err) {
+			return nil, model.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// GetByID returns an absence by id.
+// Returns model.ErrNotFound if absence doesn't exist.
+func (r *SQLiteRepository) GetByID(ctx context.Context, id int64) (*absence.Absence, error) {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+	const query = `
+		SELECT id, user_id, starts_at, ends_at, reason, created_at, cancelled_at
+		FROM user_absences
+		WHERE id = ?1
+	`
+
+	var a absence.Absence
+	err := q.QueryRowContext(ctx, query, id).Scan(scanTargets(&a)...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &a, nil
+}
+
+// ListByUser returns absences of a user ordered by start time.
+// Cancelled absences are included only when includeCancelled is set.
+func (r *SQLiteRepository) ListByUser(ctx context.Context, userID string, includeCancelled bool) ([]*absence.Absence, error) {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+	const query = `
+		SELECT id, user_id, starts_at, ends_at, reason, created_at, cancelled_at
+		FROM user_absences
+		WHERE user_id = ?1 AND (?2 OR cancelled_at IS NULL)
+		ORDER BY starts_at, id
+	`
+
+	rows, err := q.QueryContext(ctx, query, userID, includeCancelled)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []*absence.Absence
+	for rows.Next() {
+		var a absence.Absence
+		if err := rows.Scan(scanTargets(&a)...); err != nil {
+			return nil, err
+		}
+		res = append(res, &a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Cancel marks an absence as cancelled at the given moment.
+// Returns:
+//   - model.ErrNotFound — if absence doesn't exist
+//   - absence.ErrAlreadyCancelled — if absence was cancelled before
+func (r *SQLiteRepository) Cancel(ctx context.Context, id int64, at time.Time) (*absence.Absence, error) {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+	const query = `
+		UPDATE user_absences
+		SET cancelled_at = ?2
+		WHERE id = ?1 AND cancelled_at IS NULL
+		RETURNING id, user_id, starts_at, ends_at, reason, created_at, cancelled_at
+	`
+
+	var a absence.Absence
+	err := q.QueryRowContext(ctx, query, id, sqlite.Time(at)).Scan(scanTargets(&a)...)
+	if errors.Is(err, sql.ErrNoRows) {
+		// различаем «нет такой записи» и «уже отменена»
+		if _, getErr := r.GetByID(ctx, id); getErr != nil {
+			return nil, getErr
+		}
+		return nil, absence.ErrAlreadyCancelled
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &a, nil
+}
+
+// ListAbsentUserIDs returns ids of the given users that are absent at the given moment.
+func (r *SQLiteRepository) ListAbsentUserIDs(ctx context.Context, userIDs []string, at time.Time) (map[string]struct{}, error) {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+	const query = `
+		SELECT DISTINCT user_id
+		FROM user_absences
+		WHERE user_id IN (SELECT value FROM json_each(?1))
+		  AND cancelled_at IS NULL
+		  AND starts_at <= ?2 AND ends_at > ?2
+	`
+
+	rows, err := q.QueryContext(ctx, query, sqlite.Array[string](userIDs), sqlite.Time(at))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	absent := make(map[string]struct{})
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		absent[id] = struct{}{}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return absent, nil
+}
+
+// scanTargets returns scan destinations for
+// id, user_id, starts_at, ends_at, reason, created_at, cancelled_at.
+func scanTargets(a *absence.Absence) []any {
+	return []any{
+		&a.ID, &a.UserID, sqlite.ScanTime(&a.StartsAt), sqlite.ScanTime(&a.EndsAt),
+		&a.Reason, sqlite.ScanTime(&a.CreatedAt), sqlite.ScanNullTime(&a.CancelledAt),
+	}
+}
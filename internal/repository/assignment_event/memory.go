@@ -0,0 +1,54 @@
+package assignment_event
+
+import (
+	"context"
+
+	"github.com/zxchelik/avito-test-task/internal/infrastructure/memory"
+	"github.com/zxchelik/avito-test-task/internal/model"
+	reva "github.com/zxchelik/avito-test-task/internal/model/reviewer_assignment"
+)
+
+type MemoryRepository struct {
+	db *memory.DB
+}
+
+func NewMemoryRepository(db *memory.DB) *MemoryRepository {
+	return &MemoryRepository{db: db}
+}
+
+// Append writes events preserving their order.
+// Returns model.ErrNotFound if an event refers to a missing PR; nothing is written then.
+func (r *MemoryRepository) Append(ctx context.Context, events ...*reva.Event) error {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	for _, e := range events {
+		if _, ok := tables.PullRequests[e.PrId]; !ok {
+			return model.ErrNotFound
+		}
+	}
+	for _, e := range events {
+		stored := *e
+		stored.ID = tables.NextID("reviewer_assignment_events")
+		stored.CreatedAt = memory.Timestamp(e.CreatedAt)
+		tables.Events = append(tables.Events, &stored)
+	}
+
+	return nil
+}
+
+// ListByPR returns the PR timeline in the order events were written.
+func (r *MemoryRepository) ListByPR(ctx context.Context, prID string) ([]*reva.Event, error) {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	var res []*reva.Event
+	for _, e := range tables.Events {
+		if e.PrId == prID {
+			c := *e
+			res = append(res, &c)
+		}
+	}
+
+	return res, nil
+}
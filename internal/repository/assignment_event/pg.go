@@ -0,0 +1,108 @@
+package assignment_event
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/zxchelik/avito-test-task/internal/infrastructure/pg"
+	"github.com/zxchelik/avito-test-task/internal/model"
+	reva "github.com/zxchelik/avito-test-task/internal/model/reviewer_assignment"
+	"time"
+)
+
+type PGRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPGRepository(pool *pgxpool.Pool) *PGRepository {
+	return &PGRepository{pool: pool}
+}
+
+// Append writes events in a single statement, preserving their order.
+// Returns model.ErrNotFound if an event refers to a missing PR; nothing is written then.
+func (r *PGRepository) Append(ctx context.Context, events ...*reva.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	q := pg.GetQuerierFromContext(ctx, r.pool)
+	const query = `
+		INSERT INTO reviewer_assignment_events
+			(pr_id, event_type, user_id, previous_user_id, verdict, status, actor_id, reason, created_at)
+		SELECT e.pr_id, e.event_type, NULLIF(e.user_id, ''), NULLIF(e.previous_user_id, ''),
+		       NULLIF(e.verdict, '')::review_verdict, NULLIF(e.status, '')::pr_status,
+		       NULLIF(e.actor_id, ''), e.reason, e.created_at
+		FROM unnest($1::text[], $2::text[], $3::text[], $4::text[], $5::text[], $6::text[], $7::text[], $8::text[], $9::timestamptz[])
+		     WITH ORDINALITY AS e(pr_id, event_type, user_id, previous_user_id, verdict, status, actor_id, reason, created_at, ord)
+		ORDER BY e.ord
+	`
+
+	n := len(events)
+	var (
+		prIDs     = make([]string, n)
+		types     = make([]string, n)
+		users     = make([]string, n)
+		previous  = make([]string, n)
+		verdicts  = make([]string, n)
+		statuses  = make([]string, n)
+		actors    = make([]string, n)
+		reasons   = make([]string, n)
+		createdAt = make([]time.Time, n)
+	)
+	for i, e := range events {
+		prIDs[i] = e.PrId
+		types[i] = string(e.Type)
+		users[i] = e.UserId
+		previous[i] = e.PreviousUserId
+		verdicts[i] = string(e.Verdict)
+		statuses[i] = e.Status
+		actors[i] = e.ActorId
+		reasons[i] = e.Reason
+		createdAt[i] = e.CreatedAt
+	}
+
+	_, err := q.Exec(ctx, query, prIDs, types, users, previous, verdicts, statuses, actors, reasons, createdAt)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+		return model.ErrNotFound
+	}
+	return err
+}
+
+// ListByPR returns the PR timeline in the order events were written.
+func (r *PGRepository) ListByPR(ctx context.Context, prID string) ([]*reva.Event, error) {
+	q := pg.GetQuerierFromContext(ctx, r.pool)
+	const query = `
+		SELECT id, pr_id, event_type,
+		       COALESCE(user_id, ''), COALESCE(previous_user_id, ''),
+		       COALESCE(verdict::text, ''), COALESCE(status::text, ''),
+		       COALESCE(actor_id, ''), reason, created_at
+		FROM reviewer_assignment_events
+		WHERE pr_id = $1
+		ORDER BY id
+	`
+
+	rows, err := q.Query(ctx, query, prID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []*reva.Event
+	for rows.Next() {
+		var e reva.Event
+		if err := rows.Scan(
+			&e.ID, &e.PrId, &e.Type, &e.UserId, &e.PreviousUserId,
+			&e.Verdict, &e.Status, &e.ActorId, &e.Reason, &e.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		res = append(res, &e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
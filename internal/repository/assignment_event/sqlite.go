@@ -0,0 +1,93 @@
+package assignment_event
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/zxchelik/avito-test-task/internal/infrastructure/sqlite"
+	"github.com/zxchelik/avito-test-task/internal/model"
+	reva "github.com/zxchelik/avito-test-task/internal/model/reviewer_assignment"
+)
+
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteRepository(db *sql.DB) *SQLiteRepository {
+	return &SQLiteRepository{db: db}
+}
+
+// Append writes events in a single statement, preserving their order.
+// Returns model.ErrNotFound if an event refers to a missing PR; nothing is written then.
+func (r *SQLiteRepository) Append(ctx context.Context, events ...*reva.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+	// строки VALUES вставляются по порядку, поэтому id растут в порядке событий
+	query := `
+		INSERT INTO reviewer_assignment_events
+			(pr_id, event_type, user_id, previous_user_id, verdict, status, actor_id, reason, created_at)
+		VALUES ` + sqlite.Placeholders(len(events), 9)
+
+	args := make([]any, 0, len(events)*9)
+	for _, e := range events {
+		args = append(args,
+			e.PrId, string(e.Type), nullIfEmpty(e.UserId), nullIfEmpty(e.PreviousUserId),
+			nullIfEmpty(string(e.Verdict)), nullIfEmpty(e.Status), nullIfEmpty(e.ActorId),
+			e.Reason, sqlite.Time(e.CreatedAt),
+		)
+	}
+
+	_, err := q.ExecContext(ctx, query, args...)
+	if sqlite.IsForeignKeyViolation(err) {
+		return model.ErrNotFound
+	}
+	return err
+}
+
+// ListByPR returns the PR timeline in the order events were written.
+func (r *SQLiteRepository) ListByPR(ctx context.Context, prID string) ([]*reva.Event, error) {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+	const query = `
+		SELECT id, pr_id, event_type,
+		       COALESCE(user_id, ''), COALESCE(previous_user_id, ''),
+		       COALESCE(verdict, ''), COALESCE(status, ''),
+		       COALESCE(actor_id, ''), reason, created_at
+		FROM reviewer_assignment_events
+		WHERE pr_id = ?1
+		ORDER BY id
+	`
+
+	rows, err := q.QueryContext(ctx, query, prID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []*reva.Event
+	for rows.Next() {
+		var e reva.Event
+		if err := rows.Scan(
+			&e.ID, &e.PrId, &e.Type, &e.UserId, &e.PreviousUserId,
+			&e.Verdict, &e.Status, &e.ActorId, &e.Reason, sqlite.ScanTime(&e.CreatedAt),
+		); err != nil {
+			return nil, err
+		}
+		res = append(res, &e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// nullIfEmpty stores an empty optional value as NULL, like NULLIF(x, ”) in the Postgres query.
+func nullIfEmpty(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,130 @@
+package code_owner
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/zxchelik/avito-test-task/internal/infrastructure/memory"
+	"github.com/zxchelik/avito-test-task/internal/model"
+	co "github.com/zxchelik/avito-test-task/internal/model/code_owner"
+)
+
+type MemoryRepository struct {
+	db *memory.DB
+}
+
+func NewMemoryRepository(db *memory.DB) *MemoryRepository {
+	return &MemoryRepository{db: db}
+}
+
+// Create inserts a new ownership rule.
+// Position 0 appends the rule after all existing ones.
+func (r *MemoryRepository) Create(ctx context.Context, rule *co.Rule) (*co.Rule, error) {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	position := rule.Position
+	if position <= 0 {
+		position = 1
+		for _, existing := range tables.CodeOwnerRules {
+			position = max(position, existing.Position+1)
+		}
+	}
+
+	stored := &co.Rule{
+		ID:        tables.NextID("code_owner_rules"),
+		Pattern:   rule.Pattern,
+		Users:     nonNil(slices.Clone(rule.Users)),
+		Teams:     nonNil(slices.Clone(rule.Teams)),
+		Position:  position,
+		CreatedAt: r.db.Now(),
+	}
+	tables.CodeOwnerRules[stored.ID] = stored
+
+	return cloneRule(stored), nil
+}
+
+// GetByID returns a rule by id.
+// Returns model.ErrNotFound if rule doesn't exist.
+func (r *MemoryRepository) GetByID(ctx context.Context, id int64) (*co.Rule, error) {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	rule, ok := tables.CodeOwnerRules[id]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+
+	return cloneRule(rule), nil
+}
+
+// List returns all rules in evaluation order.
+func (r *MemoryRepository) List(ctx context.Context) ([]*co.Rule, error) {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	var rules []*co.Rule
+	for _, rule := range tables.CodeOwnerRules {
+		rules = append(rules, cloneRule(rule))
+	}
+	slices.SortFunc(rules, func(a, b *co.Rule) int {
+		return cmp.Or(cmp.Compare(a.Position, b.Position), cmp.Compare(a.ID, b.ID))
+	})
+
+	return rules, nil
+}
+
+// Update overwrites pattern, owners and position of a rule.
+// Returns model.ErrNotFound if rule doesn't exist.
+func (r *MemoryRepository) Update(ctx context.Context, rule *co.Rule) (*co.Rule, error) {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	existing, ok := tables.CodeOwnerRules[rule.ID]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+
+	stored := &co.Rule{
+		ID:        existing.ID,
+		Pattern:   rule.Pattern,
+		Users:     nonNil(slices.Clone(rule.Users)),
+		Teams:     nonNil(slices.Clone(rule.Teams)),
+		Position:  rule.Position,
+		CreatedAt: existing.CreatedAt,
+	}
+	tables.CodeOwnerRules[stored.ID] = stored
+
+	return cloneRule(stored), nil
+}
+
+// Delete removes a rule.
+// Returns model.ErrNotFound if rule doesn't exist.
+func (r *MemoryRepository) Delete(ctx context.Context, id int64) error {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	if _, ok := tables.CodeOwnerRules[id]; !ok {
+		return model.ErrNotFound
+	}
+	delete(tables.CodeOwnerRules, id)
+
+	return nil
+}
+
+// DeleteAll removes every rule (used by CODEOWNERS import with replace).
+func (r *MemoryRepository) DeleteAll(ctx context.Context) error {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	clear(tables.CodeOwnerRules)
+	return nil
+}
+
+func cloneRule(rule *co.Rule) *co.Rule {
+	c := *rule
+	c.Users = slices.Clone(rule.Users)
+	c.Teams = slices.Clone(rule.Teams)
+	return &c
+}
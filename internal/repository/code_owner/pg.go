@@ -0,0 +1,165 @@
+package code_owner
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/zxchelik/avito-test-task/internal/infrastructure/pg"
+	"github.com/zxchelik/avito-test-task/internal/model"
+	co "github.com/zxchelik/avito-test-task/internal/model/code_owner"
+)
+
+type PGRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPGRepository(pool *pgxpool.Pool) *PGRepository {
+	return &PGRepository{pool: pool}
+}
+
+// Create inserts a new ownership rule.
+// Position 0 appends the rule after all existing ones.
+func (r *PGRepository) Create(ctx context.Context, rule *co.Rule) (*co.Rule, error) {
+	q := pg.GetQuerierFromContext(ctx, r.pool)
+	const query = `
+		INSERT INTO code_owner_rules (pattern, owner_users, owner_teams, position)
+		VALUES (
+			$1, $2, $3,
+			CASE WHEN $4 > 0 THEN $4
+			     ELSE (SELECT COALESCE(MAX(position), 0) + 1 FROM code_owner_rules)
+			END
+		)
+		RETURNING id, pattern, owner_users, owner_teams, position, created_at
+	`
+
+	var created co.Rule
+	err := q.QueryRow(ctx, query, rule.Pattern, nonNil(rule.Users), nonNil(rule.Teams), rule.Position).Scan(
+		&created.ID, &created.Pattern, &created.Users, &created.Teams, &created.Position, &created.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// GetByID returns a rule by id.
+// Returns model.ErrNotFound if rule doesn't exist.
+func (r *PGRepository) GetByID(ctx context.Context, id int64) (*co.Rule, error) {
+	q := pg.GetQuerierFromContext(ctx, r.pool)
+	const query = `
+		SELECT id, pattern, owner_users, owner_teams, position, created_at
+		FROM code_owner_rules
+		WHERE id = $1
+	`
+
+	var rule co.Rule
+	err := q.QueryRow(ctx, query, id).Scan(
+		&rule.ID, &rule.Pattern, &rule.Users, &rule.Teams, &rule.Position, &rule.CreatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &rule, nil
+}
+
+// List returns all rules in evaluation order.
+func (r *PGRepository) List(ctx context.Context) ([]*co.Rule, error) {
+	q := pg.GetQuerierFromContext(ctx, r.pool)
+	const query = `
+		SELECT id, pattern, owner_users, owner_teams, position, created_at
+		FROM code_owner_rules
+		ORDER BY position, id
+	`
+
+	rows, err := q.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []*co.Rule
+	for rows.Next() {
+		var rule co.Rule
+		if err := rows.Scan(
+			&rule.ID, &rule.Pattern, &rule.Users, &rule.Teams, &rule.Position, &rule.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		rules = append(rules, &rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// Update overwrites pattern, owners and position of a rule.
+// Returns model.ErrNotFound if rule doesn't exist.
+func (r *PGRepository) Update(ctx context.Context, rule *co.Rule) (*co.Rule, error) {
+	q := pg.GetQuerierFromContext(ctx, r.pool)
+	const query = `
+		UPDATE code_owner_rules
+		SET pattern = $2,
+		    owner_users = $3,
+		    owner_teams = $4,
+		    position = $5
+		WHERE id = $1
+		RETURNING id, pattern, owner_users, owner_teams, position, created_at
+	`
+
+	var updated co.Rule
+	err := q.QueryRow(ctx, query,
+		rule.ID, rule.Pattern, nonNil(rule.Users), nonNil(rule.Teams), rule.Position,
+	).Scan(
+		&updated.ID, &updated.Pattern, &updated.Users, &updated.Teams, &updated.Position, &updated.CreatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// Delete removes a rule.
+// Returns model.ErrNotFound if rule doesn't exist.
+func (r *PGRepository) Delete(ctx context.Context, id int64) error {
+	q := pg.GetQuerierFromContext(ctx, r.pool)
+	const query = `DELETE FROM code_owner_rules WHERE id = $1`
+
+	ct, err := q.Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() == 0 {
+		return model.ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteAll removes every rule (used by CODEOWNERS import with replace).
+func (r *PGRepository) DeleteAll(ctx context.Context) error {
+	q := pg.GetQuerierFromContext(ctx, r.pool)
+	const query = `DELETE FROM code_owner_rules`
+
+	_, err := q.Exec(ctx, query)
+	return err
+}
+
+// nonNil avoids writing NULL into NOT NULL array columns.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
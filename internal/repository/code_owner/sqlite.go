@@ -0,0 +1,165 @@
+package code_owner
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/zxchelik/avito-test-task/internal/infrastructure/sqlite"
+	"github.com/zxchelik/avito-test-task/internal/model"
+	co "github.com/zxchelik/avito-test-task/internal/model/code_owner"
+)
+
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteRepository(db *sql.DB) *SQLiteRepository {
+	return &SQLiteRepository{db: db}
+}
+
+// Create inserts a new ownership rule.
+// Position 0 appends the rule after all existing ones.
+func (r *SQLiteRepository) Create(ctx context.Context, rule *co.Rule) (*co.Rule, error) {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+	const query = `
+		INSERT INTO code_owner_rules (pattern, owner_users, owner_teams, position, created_at)
+		VALUES (
+			?1, ?2, ?3,
+			CASE WHEN ?4 > 0 THEN ?4
+			     ELSE (SELECT COALESCE(MAX(position), 0) + 1 FROM code_owner_rules)
+			END,
+			?5
+		)
+		RETURNING id, pattern, owner_users, owner_teams, position, created_at
+	`
+
+	var created co.Rule
+	err := q.QueryRowContext(ctx, query,
+		rule.Pattern, sqlite.Array[string](rule.Users), sqlite.Array[string](rule.Teams), rule.Position,
+		sqlite.Time(time.Now()),
+	).Scan(scanTargets(&created)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// GetByID returns a rule by id.
+// Returns model.ErrNotFound if rule doesn't exist.
+func (r *SQLiteRepository) GetByID(ctx context.Context, id int64) (*co.Rule, error) {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+	const query = `
+		SELECT id, pattern, owner_users, owner_teams, position, created_at
+		FROM code_owner_rules
+		WHERE id = ?1
+	`
+
+	var rule co.Rule
+	err := q.QueryRowContext(ctx, query, id).Scan(scanTargets(&rule)...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &rule, nil
+}
+
+// List returns all rules in evaluation order.
+func (r *SQLiteRepository) List(ctx context.Context) ([]*co.Rule, error) {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+	const query = `
+		SELECT id, pattern, owner_users, owner_teams, position, created_at
+		FROM code_owner_rules
+		ORDER BY position, id
+	`
+
+	rows, err := q.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []*co.Rule
+	for rows.Next() {
+		var rule co.Rule
+		if err := rows.Scan(scanTargets(&rule)...); err != nil {
+			return nil, err
+		}
+		rules = append(rules, &rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// Update overwrites pattern, owners and position of a rule.
+// Returns model.ErrNotFound if rule doesn't exist.
+func (r *SQLiteRepository) Update(ctx context.Context, rule *co.Rule) (*co.Rule, error) {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+	const query = `
+		UPDATE code_owner_rules
+		SET pattern = ?2,
+		    owner_users = ?3,
+		    owner_teams = ?4,
+		    position = ?5
+		WHERE id = ?1
+		RETURNING id, pattern, owner_users, owner_teams, position, created_at
+	`
+
+	var updated co.Rule
+	err := q.QueryRowContext(ctx, query,
+		rule.ID, rule.Pattern, sqlite.Array[string](rule.Users), sqlite.Array[string](rule.Teams), rule.Position,
+	).Scan(scanTargets(&updated)...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// Delete removes a rule.
+// Returns model.ErrNotFound if rule doesn't exist.
+func (r *SQLiteRepository) Delete(ctx context.Context, id int64) error {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+	const query = `DELETE FROM code_owner_rules WHERE id = ?1`
+
+	res, err := q.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return model.ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteAll removes every rule (used by CODEOWNERS import with replace).
+func (r *SQLiteRepository) DeleteAll(ctx context.Context) error {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+	const query = `DELETE FROM code_owner_rules`
+
+	_, err := q.ExecContext(ctx, query)
+	return err
+}
+
+// scanTargets returns scan destinations for
+// id, pattern, owner_users, owner_teams, position, created_at.
+func scanTargets(rule *co.Rule) []any {
+	return []any{
+		&rule.ID, &rule.Pattern, (*sqlite.Array[string])(&rule.Users), (*sqlite.Array[string])(&rule.Teams),
+		&rule.Position, sqlite.ScanTime(&rule.CreatedAt),
+	}
+}
@@ -0,0 +1,110 @@
+package forge
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/zxchelik/avito-test-task/internal/infrastructure/memory"
+	"github.com/zxchelik/avito-test-task/internal/model"
+	"github.com/zxchelik/avito-test-task/internal/model/forge"
+)
+
+type MemoryRepository struct {
+	db *memory.DB
+}
+
+func NewMemoryRepository(db *memory.DB) *MemoryRepository {
+	return &MemoryRepository{db: db}
+}
+
+// UpsertIdentity maps a forge username to a user, replacing a previous mapping.
+// Returns model.ErrNotFound if the user doesn't exist.
+func (r *MemoryRepository) UpsertIdentity(ctx context.Context, id *forge.Identity) (*forge.Identity, error) {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	if _, ok := tables.Users[id.UserID]; !ok {
+		return nil, model.ErrNotFound
+	}
+
+	key := memory.ForgeKey{Forge: id.Forge, ID: id.Username}
+	stored := &forge.Identity{
+		Forge:     id.Forge,
+		Username:  id.Username,
+		UserID:    id.UserID,
+		CreatedAt: r.db.Now(),
+	}
+	if existing, ok := tables.Identities[key]; ok {
+		stored.CreatedAt = existing.CreatedAt
+	}
+	tables.Identities[key] = stored
+
+	c := *stored
+	return &c, nil
+}
+
+// ResolveUser returns the user id mapped to a forge username.
+// Returns forge.ErrIdentityNotMapped if there is no mapping.
+func (r *MemoryRepository) ResolveUser(ctx context.Context, f forge.Forge, username string) (string, error) {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	id, ok := tables.Identities[memory.ForgeKey{Forge: f, ID: username}]
+	if !ok {
+		return "", forge.ErrIdentityNotMapped
+	}
+
+	return id.UserID, nil
+}
+
+// ListIdentities returns mappings, optionally only for one user.
+func (r *MemoryRepository) ListIdentities(ctx context.Context, userID string) ([]*forge.Identity, error) {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	var res []*forge.Identity
+	for _, id := range tables.Identities {
+		if userID == "" || id.UserID == userID {
+			c := *id
+			res = append(res, &c)
+		}
+	}
+	slices.SortFunc(res, func(a, b *forge.Identity) int {
+		return cmp.Or(strings.Compare(string(a.Forge), string(b.Forge)), strings.Compare(a.Username, b.Username))
+	})
+
+	return res, nil
+}
+
+// DeleteIdentity removes a mapping.
+// Returns model.ErrNotFound if there is no mapping.
+func (r *MemoryRepository) DeleteIdentity(ctx context.Context, f forge.Forge, username string) error {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	key := memory.ForgeKey{Forge: f, ID: username}
+	if _, ok := tables.Identities[key]; !ok {
+		return model.ErrNotFound
+	}
+	delete(tables.Identities, key)
+
+	return nil
+}
+
+// RegisterDelivery remembers a processed delivery id.
+// Returns forge.ErrDuplicateDelivery if it has been seen before.
+func (r *MemoryRepository) RegisterDelivery(ctx context.Context, f forge.Forge, deliveryID string, at time.Time) error {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	key := memory.ForgeKey{Forge: f, ID: deliveryID}
+	if _, ok := tables.ForgeDeliveries[key]; ok {
+		return forge.ErrDuplicateDelivery
+	}
+	tables.ForgeDeliveries[key] = memory.Timestamp(at)
+
+	return nil
+}
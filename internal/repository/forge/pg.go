@@ -0,0 +1,137 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/zxchelik/avito-test-task/internal/infrastructure/pg"
+	"github.com/zxchelik/avito-test-task/internal/model"
+	"github.com/zxchelik/avito-test-task/internal/model/forge"
+	"time"
+)
+
+type PGRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPGRepository(pool *pgxpool.Pool) *PGRepository {
+	return &PGRepository{pool: pool}
+}
+
+// UpsertIdentity maps a forge username to a user, replacing a previous mapping.
+// Returns model.ErrNotFound if the user doesn't exist.
+func (r *PGRepository) UpsertIdentity(ctx context.Context, id *forge.Identity) (*forge.Identity, error) {
+	q := pg.GetQuerierFromContext(ctx, r.pool)
+	const query = `
+		INSERT INTO forge_identities (forge, username, user_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (forge, username) DO UPDATE SET user_id = EXCLUDED.user_id
+		RETURNING forge, username, user_id, created_at
+	`
+
+	var res forge.Identity
+	err := q.QueryRow(ctx, query, id.Forge, id.Username, id.UserID).Scan(
+		&res.Forge, &res.Username, &res.UserID, &res.CreatedAt,
+	)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23503" { // foreign_key_violation
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// ResolveUser returns the user id mapped to a forge username.
+// Returns forge.ErrIdentityNotMapped if there is no mapping.
+func (r *PGRepository) ResolveUser(ctx context.Context, f forge.Forge, username string) (string, error) {
+	q := pg.GetQuerierFromContext(ctx, r.pool)
+	const query = `
+		SELECT user_id FROM forge_identities
+		WHERE forge = $1 AND username = $2
+	`
+
+	var userID string
+	err := q.QueryRow(ctx, query, f, username).Scan(&userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", forge.ErrIdentityNotMapped
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return userID, nil
+}
+
+// ListIdentities returns mappings, optionally only for one user.
+func (r *PGRepository) ListIdentities(ctx context.Context, userID string) ([]*forge.Identity, error) {
+	q := pg.GetQuerierFromContext(ctx, r.pool)
+	const query = `
+		SELECT forge, username, user_id, created_at
+		FROM forge_identities
+		WHERE $1 = '' OR user_id = $1
+		ORDER BY forge, username
+	`
+
+	rows, err := q.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []*forge.Identity
+	for rows.Next() {
+		var id forge.Identity
+		if err := rows.Scan(&id.Forge, &id.Username, &id.UserID, &id.CreatedAt); err != nil {
+			return nil, err
+		}
+		res = append(res, &id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// DeleteIdentity removes a mapping.
+// Returns model.ErrNotFound if there is no mapping.
+func (r *PGRepository) DeleteIdentity(ctx context.Context, f forge.Forge, username string) error {
+	q := pg.GetQuerierFromContext(ctx, r.pool)
+	const query = `DELETE FROM forge_identities WHERE forge = $1 AND username = $2`
+
+	ct, err := q.Exec(ctx, query, f, username)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() == 0 {
+		return model.ErrNotFound
+	}
+
+	return nil
+}
+
+// RegisterDelivery remembers a processed delivery id.
+// Returns forge.ErrDuplicateDelivery if it has been seen before.
+func (r *PGRepository) RegisterDelivery(ctx context.Context, f forge.Forge, deliveryID string, at time.Time) error {
+	q := pg.GetQuerierFromContext(ctx, r.pool)
+	const query = `
+		INSERT INTO forge_deliveries (forge, delivery_id, received_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING
+	`
+
+	ct, err := q.Exec(ctx, query, f, deliveryID, at)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() == 0 {
+		return forge.ErrDuplicateDelivery
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package forge
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/zxchelik/avito-test-task/internal/infrastructure/sqlite"
+	"github.com/zxchelik/avito-test-task/internal/model"
+	"github.com/zxchelik/avito-test-task/internal/model/forge"
+)
+
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteRepository(db *sql.DB) *SQLiteRepository {
+	return &SQLiteRepository{db: db}
+}
+
+// UpsertIdentity maps a forge username to a user, replacing a previous mapping.
+// Returns model.ErrNotFound if the user doesn't exist.
+func (r *SQLiteRepository) UpsertIdentity(ctx context.Context, id *forge.Identity) (*forge.Identity, error) {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+	const query = `
+		INSERT INTO forge_identities (forge, username, user_id, created_at)
+		VALUES (?1, ?2, ?3, ?4)
+		ON CONFLICT (forge, username) DO UPDATE SET user_id = excluded.user_id
+		RETURNING forge, username, user_id, created_at
+	`
+
+	var res forge.Identity
+	err := q.QueryRowContext(ctx, query, id.Forge, id.Username, id.UserID, sqlite.Time(time.Now())).Scan(
+		&res.Forge, &res.Username, &res.UserID, sqlite.ScanTime(&res.CreatedAt),
+	)
+	if sqlite.IsForeignKeyViolation(err) {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// ResolveUser returns the user id mapped to a forge username.
+// Returns forge.ErrIdentityNotMapped if there is no mapping.
+func (r *SQLiteRepository) ResolveUser(ctx context.Context, f forge.Forge, username string) (string, error) {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+	const query = `
+		SELECT user_id FROM forge_identities
+		WHERE forge = ?1 AND username = ?2
+	`
+
+	var userID string
+	err := q.QueryRowContext(ctx, query, f, username).Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", forge.ErrIdentityNotMapped
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return userID, nil
+}
+
+// ListIdentities returns mappings, optionally only for one user.
+func (r *SQLiteRepository) ListIdentities(ctx context.Context, userID string) ([]*forge.Identity, error) {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+	const query = `
+		SELECT forge, username, user_id, created_at
+		FROM forge_identities
+		WHERE ?1 = '' OR user_id = ?1
+		ORDER BY forge, username
+	`
+
+	rows, err := q.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []*forge.Identity
+	for rows.Next() {
+		var id forge.Identity
+		if err := rows.Scan(&id.Forge, &id.Username, &id.UserID, sqlite.ScanTime(&id.CreatedAt)); err != nil {
+			return nil, err
+		}
+		res = append(res, &id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// DeleteIdentity removes a mapping.
+// Returns model.ErrNotFound if there is no mapping.
+func (r *SQLiteRepository) DeleteIdentity(ctx context.Context, f forge.Forge, username string) error {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+	const query = `DELETE FROM forge_identities WHERE forge = ?1 AND username = ?2`
+
+	res, err := q.ExecContext(ctx, query, f, username)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return model.ErrNotFound
+	}
+
+	return nil
+}
+
+// RegisterDelivery remembers a processed delivery id.
+// Returns forge.ErrDuplicateDelivery if it has been seen before.
+func (r *SQLiteRepository) RegisterDelivery(ctx context.Context, f forge.Forge, deliveryID string, at time.Time) error {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+	const query = `
+		INSERT INTO forge_deliveries (forge, delivery_id, received_at)
+		VALUES (?1, ?2, ?3)
+		ON CONFLICT DO NOTHING
+	`
+
+	res, err := q.ExecContext(ctx, query, f, deliveryID, sqlite.Time(at))
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return forge.ErrDuplicateDelivery
+	}
+
+	return nil
+}
@@ -0,0 +1,237 @@
+package pull_request
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/zxchelik/avito-test-task/internal/infrastructure/memory"
+	"github.com/zxchelik/avito-test-task/internal/model"
+	preq "github.com/zxchelik/avito-test-task/internal/model/pull_request"
+)
+
+type MemoryRepository struct {
+	db *memory.DB
+}
+
+func NewMemoryRepository(db *memory.DB) *MemoryRepository {
+	return &MemoryRepository{db: db}
+}
+
+// Create inserts a new PR if not exists.
+// Returns:
+//   - preq.ErrPRExists (a model.ErrAlreadyExists) — if PR already exists
+//   - model.ErrNotFound — if author does not exist
+func (r *MemoryRepository) Create(ctx context.Context, pr *preq.PullRequest) (*preq.PullRequest, error) {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	if _, ok := tables.PullRequests[pr.ID]; ok {
+		return nil, preq.ErrPRExists
+	}
+	if _, ok := tables.Users[pr.AuthorID]; !ok {
+		return nil, model.ErrNotFound
+	}
+
+	pr.CreatedAt = r.db.Now()
+	pr.MergedAt = nil
+	pr.ClosedAt = nil
+	stored := *pr
+	tables.PullRequests[pr.ID] = &stored
+
+	return pr, nil
+}
+
+// GetByID returns a PR by pull_request_id.
+// Returns model.ErrNotFound when PR doesn't exist.
+func (r *MemoryRepository) GetByID(ctx context.Context, id string) (*preq.PullRequest, error) {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	pr, ok := tables.PullRequests[id]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+
+	c := *pr
+	return &c, nil
+}
+
+// GetByIDForUpdate is GetByID: a transaction already holds the whole store exclusively.
+func (r *MemoryRepository) GetByIDForUpdate(ctx context.Context, id string) (*preq.PullRequest, error) {
+	return r.GetByID(ctx, id)
+}
+
+// MarkMerged sets status to MERGED and updates merged_at timestamp.
+// Returns:
+//   - preq.ErrPRAlreadyMerged — if status is already MERGED
+//   - model.ErrNotFound — if PR not found
+func (r *MemoryRepository) MarkMerged(ctx context.Context, id string) (*preq.PullRequest, error) {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	pr, ok := tables.PullRequests[id]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+
+	if pr.Status == preq.PRMerged {
+		c := *pr
+		return &c, preq.ErrPRAlreadyMerged
+	}
+
+	now := r.db.Now()
+	pr.Status = preq.PRMerged
+	pr.MergedAt = &now
+
+	c := *pr
+	return &c, nil
+}
+
+// UpdateStatus moves a PR from status `from` to `to`.
+// closed_at is set when the PR gets CLOSED and cleared otherwise.
+// Returns:
+//   - model.ErrNotFound — if PR not found
+//   - preq.ErrInvalidTransition — if PR is not in status `from` anymore
+func (r *MemoryRepository) UpdateStatus(
+	ctx context.Context,
+	id string,
+	from, to preq.PRStatus,
+	at time.Time,
+) (*preq.PullRequest, error) {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	pr, ok := tables.PullRequests[id]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+	if pr.Status != from {
+		return nil, preq.ErrInvalidTransition
+	}
+
+	pr.Status = to
+	pr.ClosedAt = nil
+	if to == preq.PRClosed {
+		closedAt := memory.Timestamp(at)
+		pr.ClosedAt = &closedAt
+	}
+
+	c := *pr
+	return &c, nil
+}
+
+// List returns PRs matching the filter ordered by (created_at, id).
+// At most filter.Limit rows are returned; callers request one extra row to detect the next page.
+func (r *MemoryRepository) List(ctx context.Context, filter preq.ListFilter) ([]*preq.PullRequest, error) {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	var prs []*preq.PullRequest
+	for _, pr := range tables.PullRequests {
+		if len(filter.Statuses) > 0 && !slices.Contains(filter.Statuses, pr.Status) {
+			continue
+		}
+		if filter.AuthorID != "" && pr.AuthorID != filter.AuthorID {
+			continue
+		}
+		if filter.TeamName != "" {
+			author, ok := tables.Users[pr.AuthorID]
+			if !ok || author.TeamName != filter.TeamName {
+				continue
+			}
+		}
+		if filter.ReviewerID != "" {
+			if _, ok := tables.Reviewers[pr.ID][filter.ReviewerID]; !ok {
+				continue
+			}
+		}
+		if !inRange(&pr.CreatedAt, filter.CreatedFrom, filter.CreatedTo) ||
+			!inRange(pr.MergedAt, filter.MergedFrom, filter.MergedTo) {
+			continue
+		}
+		if filter.After != nil && compareCursor(pr.CreatedAt, pr.ID, filter.After.CreatedAt, filter.After.ID) <= 0 {
+			continue
+		}
+
+		c := *pr
+		prs = append(prs, &c)
+	}
+
+	slices.SortFunc(prs, func(a, b *preq.PullRequest) int {
+		return compareCursor(a.CreatedAt, a.ID, b.CreatedAt, b.ID)
+	})
+	if len(prs) > filter.Limit {
+		prs = prs[:filter.Limit]
+	}
+
+	return prs, nil
+}
+
+// ListByReviewer returns PRs the reviewer is assigned to together with assignment time and verdict,
+// ordered by (assigned_at, pr_id) descending. filter.Limit 0 means no limit.
+func (r *MemoryRepository) ListByReviewer(ctx context.Context, filter preq.ReviewFilter) ([]*preq.AssignedReview, error) {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	var res []*preq.AssignedReview
+	for prID, byUser := range tables.Reviewers {
+		ra, ok := byUser[filter.ReviewerID]
+		if !ok {
+			continue
+		}
+		pr := tables.PullRequests[prID]
+		if len(filter.Statuses) > 0 && !slices.Contains(filter.Statuses, pr.Status) {
+			continue
+		}
+		if filter.Since != nil && ra.AssignedAt.Before(*filter.Since) {
+			continue
+		}
+		if filter.After != nil && compareCursor(ra.AssignedAt, prID, filter.After.AssignedAt, filter.After.PRID) >= 0 {
+			continue
+		}
+
+		c := *pr
+		res = append(res, &preq.AssignedReview{
+			PullRequest: &c,
+			AssignedAt:  ra.AssignedAt,
+			Verdict:     ra.Verdict,
+			VerdictAt:   ra.VerdictAt,
+		})
+	}
+
+	slices.SortFunc(res, func(a, b *preq.AssignedReview) int {
+		return compareCursor(b.AssignedAt, b.PullRequest.ID, a.AssignedAt, a.PullRequest.ID)
+	})
+	if filter.Limit > 0 && len(res) > filter.Limit {
+		res = res[:filter.Limit]
+	}
+
+	return res, nil
+}
+
+// inRange reports whether t lies in [from, to); nil bounds don't restrict, nil t matches no bound.
+func inRange(t, from, to *time.Time) bool {
+	if from == nil && to == nil {
+		return true
+	}
+	if t == nil {
+		return false
+	}
+	if from != nil && t.Before(*from) {
+		return false
+	}
+	if to != nil && !t.Before(*to) {
+		return false
+	}
+	return true
+}
+
+// compareCursor orders rows by (time, id) the way Postgres compares row values.
+func compareCursor(at time.Time, id string, otherAt time.Time, otherID string) int {
+	if c := at.Compare(otherAt); c != 0 {
+		return c
+	}
+	return strings.Compare(id, otherID)
+}
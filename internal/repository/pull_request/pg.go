@@ -9,6 +9,7 @@ import (
 	"github.com/zxchelik/avito-test-task/internal/infrastructure/pg"
 	"github.com/zxchelik/avito-test-task/internal/model"
 	preq "github.com/zxchelik/avito-test-task/internal/model/pull_request"
+	"time"
 )
 
 type PGRepository struct {
@@ -21,7 +22,7 @@ func NewPGRepository(pool *pgxpool.Pool) *PGRepository {
 
 // Create inserts a new PR if not exists.
 // Returns:
-//   - model.ErrAlreadyExists — if PR already exists (PK conflict)
+//   - preq.ErrPRExists (a model.ErrAlreadyExists) — if PR already exists (PK conflict)
 //   - model.ErrNotFound — if author does not exist (FK violation)
 func (r *PGRepository) Create(ctx context.Context, pr *preq.PullRequest) (*preq.PullRequest, error) {
 	q := pg.GetQuerierFromContext(ctx, r.pool)
@@ -29,17 +30,17 @@ func (r *PGRepository) Create(ctx context.Context, pr *preq.PullRequest) (*preq.
 		INSERT INTO pull_requests (id, title, author_id, status)
 		VALUES ($1, $2, $3, $4)
 		ON CONFLICT (id) DO NOTHING
-		RETURNING id, title, author_id, status, created_at, merged_at
+		RETURNING id, title, author_id, status, created_at, merged_at, closed_at
 	`
 
 	err := q.QueryRow(ctx, query,
 		pr.ID, pr.Title, pr.AuthorID, pr.Status,
-	).Scan(&pr.ID, &pr.Title, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt)
+	).Scan(&pr.ID, &pr.Title, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt, &pr.ClosedAt)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			// ON CONFLICT с существующим PR
-			return nil, model.ErrAlreadyExists
+			return nil, preq.ErrPRExists
 		}
 
 		// FK на author_id
@@ -57,17 +58,35 @@ func (r *PGRepository) Create(ctx context.Context, pr *preq.PullRequest) (*preq.
 // GetByID returns a PR by pull_request_id.
 // Returns model.ErrNotFound when PR doesn't exist.
 func (r *PGRepository) GetByID(ctx context.Context, id string) (*preq.PullRequest, error) {
-	q := pg.GetQuerierFromContext(ctx, r.pool)
 	const query = `
-		SELECT id, title, author_id, status, created_at, merged_at
+		SELECT id, title, author_id, status, created_at, merged_at, closed_at
+		FROM pull_requests
+		WHERE id = $1
+	`
+	return r.getOne(ctx, query, id)
+}
+
+// GetByIDForUpdate is GetByID that also locks the PR row (SELECT ... FOR UPDATE)
+// until the surrounding transaction ends, serializing concurrent changes of the PR.
+// Outside a transaction the lock is released right away.
+// Returns model.ErrNotFound when PR doesn't exist.
+func (r *PGRepository) GetByIDForUpdate(ctx context.Context, id string) (*preq.PullRequest, error) {
+	const query = `
+		SELECT id, title, author_id, status, created_at, merged_at, closed_at
 		FROM pull_requests
 		WHERE id = $1
+		FOR UPDATE
 	`
+	return r.getOne(ctx, query, id)
+}
+
+func (r *PGRepository) getOne(ctx context.Context, query, id string) (*preq.PullRequest, error) {
+	q := pg.GetQuerierFromContext(ctx, r.pool)
 
 	var pr preq.PullRequest
 
 	err := q.QueryRow(ctx, query, id).Scan(
-		&pr.ID, &pr.Title, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt,
+		&pr.ID, &pr.Title, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt, &pr.ClosedAt,
 	)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, model.ErrNotFound
@@ -84,35 +103,187 @@ func (r *PGRepository) GetByID(ctx context.Context, id string) (*preq.PullReques
 //   - preq.ErrPRAlreadyMerged — if status is already MERGED
 //   - model.ErrNotFound — if PR not found
 func (r *PGRepository) MarkMerged(ctx context.Context, id string) (*preq.PullRequest, error) {
-	pr, err := r.GetByID(ctx, id)
+	q := pg.GetQuerierFromContext(ctx, r.pool)
+	const query = `
+		UPDATE pull_requests
+		SET status = 'MERGED', merged_at = NOW()
+		WHERE id = $1 AND status <> 'MERGED'
+		RETURNING id, title, author_id, status, created_at, merged_at, closed_at
+	`
+
+	var newPR preq.PullRequest
+
+	err := q.QueryRow(ctx, query, id).Scan(
+		&newPR.ID, &newPR.Title, &newPR.AuthorID, &newPR.Status, &newPR.CreatedAt, &newPR.MergedAt, &newPR.ClosedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// различаем «нет PR» и «уже смержен»
+		pr, getErr := r.GetByID(ctx, id)
+		if getErr != nil {
+			return nil, getErr
+		}
+		return pr, preq.ErrPRAlreadyMerged
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	if pr.Status == preq.PRMerged {
-		return pr, preq.ErrPRAlreadyMerged
-	}
+	return &newPR, nil
+}
 
+// UpdateStatus moves a PR from status `from` to `to`.
+// closed_at is set when the PR gets CLOSED and cleared otherwise.
+// Returns:
+//   - model.ErrNotFound — if PR not found
+//   - preq.ErrInvalidTransition — if PR is not in status `from` anymore
+func (r *PGRepository) UpdateStatus(
+	ctx context.Context,
+	id string,
+	from, to preq.PRStatus,
+	at time.Time,
+) (*preq.PullRequest, error) {
 	q := pg.GetQuerierFromContext(ctx, r.pool)
 	const query = `
 		UPDATE pull_requests
-		SET status = 'MERGED', merged_at = NOW()
-		WHERE id = $1
-		RETURNING id, title, author_id, status, created_at, merged_at
+		SET status = $3::pr_status,
+		    closed_at = CASE WHEN $3 = 'CLOSED' THEN $4::timestamptz END
+		WHERE id = $1 AND status = $2::pr_status
+		RETURNING id, title, author_id, status, created_at, merged_at, closed_at
 	`
 
-	var newPR preq.PullRequest
-
-	err = q.QueryRow(ctx, query, id).Scan(
-		&newPR.ID, &newPR.Title, &newPR.AuthorID, &newPR.Status, &newPR.CreatedAt, &newPR.MergedAt,
+	var pr preq.PullRequest
+	err := q.QueryRow(ctx, query, id, string(from), string(to), at).Scan(
+		&pr.ID, &pr.Title, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt, &pr.ClosedAt,
 	)
 	if errors.Is(err, pgx.ErrNoRows) {
-		// маловероятно (мы только что читали), но формально — not found
-		return nil, model.ErrNotFound
+		// различаем «нет PR» и «статус уже другой»
+		if _, getErr := r.GetByID(ctx, id); getErr != nil {
+			return nil, getErr
+		}
+		return nil, preq.ErrInvalidTransition
 	}
 	if err != nil {
 		return nil, err
 	}
 
-	return &newPR, nil
+	return &pr, nil
+}
+
+// List returns PRs matching the filter ordered by (created_at, id).
+// At most filter.Limit rows are returned; callers request one extra row to detect the next page.
+func (r *PGRepository) List(ctx context.Context, filter preq.ListFilter) ([]*preq.PullRequest, error) {
+	q := pg.GetQuerierFromContext(ctx, r.pool)
+	const query = `
+		SELECT pr.id, pr.title, pr.author_id, pr.status, pr.created_at, pr.merged_at, pr.closed_at
+		FROM pull_requests pr
+		JOIN users au ON au.id = pr.author_id
+		WHERE (cardinality($1::text[]) = 0 OR pr.status::text = ANY($1))
+		  AND ($2 = '' OR pr.author_id = $2)
+		  AND ($3 = '' OR au.team_name = $3)
+		  AND ($4 = '' OR EXISTS (
+		      SELECT 1 FROM pull_request_reviewers prr
+		      WHERE prr.pr_id = pr.id AND prr.user_id = $4
+		  ))
+		  AND ($5::timestamptz IS NULL OR pr.created_at >= $5)
+		  AND ($6::timestamptz IS NULL OR pr.created_at < $6)
+		  AND ($7::timestamptz IS NULL OR pr.merged_at >= $7)
+		  AND ($8::timestamptz IS NULL OR pr.merged_at < $8)
+		  AND ($9::timestamptz IS NULL OR (pr.created_at, pr.id) > ($9, $10::text))
+		ORDER BY pr.created_at, pr.id
+		LIMIT $11
+	`
+
+	statuses := make([]string, 0, len(filter.Statuses))
+	for _, st := range filter.Statuses {
+		statuses = append(statuses, string(st))
+	}
+	var (
+		afterAt *time.Time
+		afterID string
+	)
+	if filter.After != nil {
+		afterAt, afterID = &filter.After.CreatedAt, filter.After.ID
+	}
+
+	rows, err := q.Query(ctx, query,
+		statuses, filter.AuthorID, filter.TeamName, filter.ReviewerID,
+		filter.CreatedFrom, filter.CreatedTo, filter.MergedFrom, filter.MergedTo,
+		afterAt, afterID, filter.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var prs []*preq.PullRequest
+	for rows.Next() {
+		var pr preq.PullRequest
+		if err := rows.Scan(
+			&pr.ID, &pr.Title, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt, &pr.ClosedAt,
+		); err != nil {
+			return nil, err
+		}
+		prs = append(prs, &pr)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return prs, nil
+}
+
+// ListByReviewer returns PRs the reviewer is assigned to together with assignment time and verdict,
+// ordered by (assigned_at, pr_id) descending. filter.Limit 0 means no limit.
+func (r *PGRepository) ListByReviewer(ctx context.Context, filter preq.ReviewFilter) ([]*preq.AssignedReview, error) {
+	q := pg.GetQuerierFromContext(ctx, r.pool)
+	const query = `
+		SELECT pr.id, pr.title, pr.author_id, pr.status, pr.created_at, pr.merged_at, pr.closed_at,
+		       prr.assigned_at, COALESCE(prr.verdict::text, ''), prr.verdict_at
+		FROM pull_request_reviewers prr
+		JOIN pull_requests pr ON pr.id = prr.pr_id
+		WHERE prr.user_id = $1
+		  AND (cardinality($2::text[]) = 0 OR pr.status::text = ANY($2))
+		  AND ($3::timestamptz IS NULL OR prr.assigned_at >= $3)
+		  AND ($4::timestamptz IS NULL OR (prr.assigned_at, prr.pr_id) < ($4, $5::text))
+		ORDER BY prr.assigned_at DESC, prr.pr_id DESC
+		LIMIT NULLIF($6, 0)
+	`
+
+	statuses := make([]string, 0, len(filter.Statuses))
+	for _, st := range filter.Statuses {
+		statuses = append(statuses, string(st))
+	}
+	var (
+		afterAt *time.Time
+		afterID string
+	)
+	if filter.After != nil {
+		afterAt, afterID = &filter.After.AssignedAt, filter.After.PRID
+	}
+
+	rows, err := q.Query(ctx, query, filter.ReviewerID, statuses, filter.Since, afterAt, afterID, filter.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []*preq.AssignedReview
+	for rows.Next() {
+		var (
+			pr preq.PullRequest
+			rv = preq.AssignedReview{PullRequest: &pr}
+		)
+		if err := rows.Scan(
+			&pr.ID, &pr.Title, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt, &pr.ClosedAt,
+			&rv.AssignedAt, &rv.Verdict, &rv.VerdictAt,
+		); err != nil {
+			return nil, err
+		}
+		res = append(res, &rv)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
 }
@@ -0,0 +1,272 @@
+package pull_request
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/zxchelik/avito-test-task/internal/infrastructure/sqlite"
+	"github.com/zxchelik/avito-test-task/internal/model"
+	preq "github.com/zxchelik/avito-test-task/internal/model/pull_request"
+)
+
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteRepository(db *sql.DB) *SQLiteRepository {
+	return &SQLiteRepository{db: db}
+}
+
+// Create inserts a new PR if not exists.
+// Returns:
+//   - preq.ErrPRExists (a model.ErrAlreadyExists) — if PR already exists (PK conflict)
+//   - model.ErrNotFound — if author does not exist (FK violation)
+func (r *SQLiteRepository) Create(ctx context.Context, pr *preq.PullRequest) (*preq.PullRequest, error) {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+	const query = `
+		INSERT INTO pull_requests (id, title, author_id, status, created_at)
+		VALUES (?1, ?2, ?3, ?4, ?5)
+		ON CONFLICT (id) DO NOTHING
+		RETURNING id, title, author_id, status, created_at, merged_at, closed_at
+	`
+
+	err := q.QueryRowContext(ctx, query,
+		pr.ID, pr.Title, pr.AuthorID, pr.Status, sqlite.Time(time.Now()),
+	).Scan(scanTargets(pr)...)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// ON CONFLICT с существующим PR
+			return nil, preq.ErrPRExists
+		}
+
+		// FK на author_id
+		if sqlite.IsForeignKeyViolation(err) {
+			// автор не найден
+			return nil, model.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return pr, nil
+}
+
+// GetByID returns a PR by pull_request_id.
+// Returns model.ErrNotFound when PR doesn't exist.
+func (r *SQLiteRepository) GetByID(ctx context.Context, id string) (*preq.PullRequest, error) {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+	const query = `
+		SELECT id, title, author_id, status, created_at, merged_at, closed_at
+		FROM pull_requests
+		WHERE id = ?1
+	`
+
+	var pr preq.PullRequest
+
+	err := q.QueryRowContext(ctx, query, id).Scan(scanTargets(&pr)...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &pr, nil
+}
+
+// GetByIDForUpdate is GetByID. SQLite has no row locks, but a transaction holds
+// the only connection until it ends, so concurrent changes of the PR are serialized anyway.
+// Returns model.ErrNotFound when PR doesn't exist.
+func (r *SQLiteRepository) GetByIDForUpdate(ctx context.Context, id string) (*preq.PullRequest, error) {
+	return r.GetByID(ctx, id)
+}
+
+// MarkMerged sets status to MERGED and updates merged_at timestamp.
+// Returns:
+//   - preq.ErrPRAlreadyMerged — if status is already MERGED
+//   - model.ErrNotFound — if PR not found
+func (r *SQLiteRepository) MarkMerged(ctx context.Context, id string) (*preq.PullRequest, error) {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+	const query = `
+		UPDATE pull_requests
+		SET status = 'MERGED', merged_at = ?2
+		WHERE id = ?1 AND status <> 'MERGED'
+		RETURNING id, title, author_id, status, created_at, merged_at, closed_at
+	`
+
+	var newPR preq.PullRequest
+
+	err := q.QueryRowContext(ctx, query, id, sqlite.Time(time.Now())).Scan(scanTargets(&newPR)...)
+	if errors.Is(err, sql.ErrNoRows) {
+		// различаем «нет PR» и «уже смержен»
+		pr, getErr := r.GetByID(ctx, id)
+		if getErr != nil {
+			return nil, getErr
+		}
+		return pr, preq.ErrPRAlreadyMerged
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &newPR, nil
+}
+
+// UpdateStatus moves a PR from status `from` to `to`.
+// closed_at is set when the PR gets CLOSED and cleared otherwise.
+// Returns:
+//   - model.ErrNotFound — if PR not found
+//   - preq.ErrInvalidTransition — if PR is not in status `from` anymore
+func (r *SQLiteRepository) UpdateStatus(
+	ctx context.Context,
+	id string,
+	from, to preq.PRStatus,
+	at time.Time,
+) (*preq.PullRequest, error) {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+	const query = `
+		UPDATE pull_requests
+		SET status = ?3,
+		    closed_at = CASE WHEN ?3 = 'CLOSED' THEN ?4 END
+		WHERE id = ?1 AND status = ?2
+		RETURNING id, title, author_id, status, created_at, merged_at, closed_at
+	`
+
+	var pr preq.PullRequest
+	err := q.QueryRowContext(ctx, query, id, string(from), string(to), sqlite.Time(at)).Scan(scanTargets(&pr)...)
+	if errors.Is(err, sql.ErrNoRows) {
+		// различаем «нет PR» и «статус уже другой»
+		if _, getErr := r.GetByID(ctx, id); getErr != nil {
+			return nil, getErr
+		}
+		return nil, preq.ErrInvalidTransition
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &pr, nil
+}
+
+// List returns PRs matching the filter ordered by (created_at, id).
+// At most filter.Limit rows are returned; callers request one extra row to detect the next page.
+func (r *SQLiteRepository) List(ctx context.Context, filter preq.ListFilter) ([]*preq.PullRequest, error) {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+	const query = `
+		SELECT pr.id, pr.title, pr.author_id, pr.status, pr.created_at, pr.merged_at, pr.closed_at
+		FROM pull_requests pr
+		JOIN users au ON au.id = pr.author_id
+		WHERE (json_array_length(?1) = 0 OR pr.status IN (SELECT value FROM json_each(?1)))
+		  AND (?2 = '' OR pr.author_id = ?2)
+		  AND (?3 = '' OR au.team_name = ?3)
+		  AND (?4 = '' OR EXISTS (
+		      SELECT 1 FROM pull_request_reviewers prr
+		      WHERE prr.pr_id = pr.id AND prr.user_id = ?4
+		  ))
+		  AND (?5 IS NULL OR pr.created_at >= ?5)
+		  AND (?6 IS NULL OR pr.created_at < ?6)
+		  AND (?7 IS NULL OR pr.merged_at >= ?7)
+		  AND (?8 IS NULL OR pr.merged_at < ?8)
+		  AND (?9 IS NULL OR (pr.created_at, pr.id) > (?9, ?10))
+		ORDER BY pr.created_at, pr.id
+		LIMIT ?11
+	`
+
+	var (
+		afterAt *time.Time
+		afterID string
+	)
+	if filter.After != nil {
+		afterAt, afterID = &filter.After.CreatedAt, filter.After.ID
+	}
+
+	rows, err := q.QueryContext(ctx, query,
+		sqlite.Array[preq.PRStatus](filter.Statuses), filter.AuthorID, filter.TeamName, filter.ReviewerID,
+		sqlite.NullTime(filter.CreatedFrom), sqlite.NullTime(filter.CreatedTo),
+		sqlite.NullTime(filter.MergedFrom), sqlite.NullTime(filter.MergedTo),
+		sqlite.NullTime(afterAt), afterID, filter.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var prs []*preq.PullRequest
+	for rows.Next() {
+		var pr preq.PullRequest
+		if err := rows.Scan(scanTargets(&pr)...); err != nil {
+			return nil, err
+		}
+		prs = append(prs, &pr)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return prs, nil
+}
+
+// ListByReviewer returns PRs the reviewer is assigned to together with assignment time and verdict,
+// ordered by (assigned_at, pr_id) descending. filter.Limit 0 means no limit.
+func (r *SQLiteRepository) ListByReviewer(ctx context.Context, filter preq.ReviewFilter) ([]*preq.AssignedReview, error) {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+	const query = `
+		SELECT pr.id, pr.title, pr.author_id, pr.status, pr.created_at, pr.merged_at, pr.closed_at,
+		       prr.assigned_at, COALESCE(prr.verdict, ''), prr.verdict_at
+		FROM pull_request_reviewers prr
+		JOIN pull_requests pr ON pr.id = prr.pr_id
+		WHERE prr.user_id = ?1
+		  AND (json_array_length(?2) = 0 OR pr.status IN (SELECT value FROM json_each(?2)))
+		  AND (?3 IS NULL OR prr.assigned_at >= ?3)
+		  AND (?4 IS NULL OR (prr.assigned_at, prr.pr_id) < (?4, ?5))
+		ORDER BY prr.assigned_at DESC, prr.pr_id DESC
+		LIMIT CASE WHEN ?6 > 0 THEN ?6 ELSE -1 END
+	`
+
+	var (
+		afterAt *time.Time
+		afterID string
+	)
+	if filter.After != nil {
+		afterAt, afterID = &filter.After.AssignedAt, filter.After.PRID
+	}
+
+	rows, err := q.QueryContext(ctx, query,
+		filter.ReviewerID, sqlite.Array[preq.PRStatus](filter.Statuses), sqlite.NullTime(filter.Since),
+		sqlite.NullTime(afterAt), afterID, filter.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []*preq.AssignedReview
+	for rows.Next() {
+		var (
+			pr preq.PullRequest
+			rv = preq.AssignedReview{PullRequest: &pr}
+		)
+		if err := rows.Scan(append(scanTargets(&pr),
+			sqlite.ScanTime(&rv.AssignedAt), &rv.Verdict, sqlite.ScanNullTime(&rv.VerdictAt),
+		)...); err != nil {
+			return nil, err
+		}
+		res = append(res, &rv)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// scanTargets returns scan destinations for
+// id, title, author_id, status, created_at, merged_at, closed_at.
+func scanTargets(pr *preq.PullRequest) []any {
+	return []any{
+		&pr.ID, &pr.Title, &pr.AuthorID, &pr.Status,
+		sqlite.ScanTime(&pr.CreatedAt), sqlite.ScanNullTime(&pr.MergedAt), sqlite.ScanNullTime(&pr.ClosedAt),
+	}
+}
@@ -0,0 +1,35 @@
+package repositorytest_test
+
+import (
+	"testing"
+
+	"github.com/zxchelik/avito-test-task/internal/infrastructure/memory"
+	absenceRep "github.com/zxchelik/avito-test-task/internal/repository/absence"
+	eventRep "github.com/zxchelik/avito-test-task/internal/repository/assignment_event"
+	prRep "github.com/zxchelik/avito-test-task/internal/repository/pull_request"
+	"github.com/zxchelik/avito-test-task/internal/repository/repositorytest"
+	raRep "github.com/zxchelik/avito-test-task/internal/repository/reviewer_assignment"
+	teamRep "github.com/zxchelik/avito-test-task/internal/repository/team"
+	userRep "github.com/zxchelik/avito-test-task/internal/repository/user"
+)
+
+func newMemoryStore() *repositorytest.Store {
+	db := memory.NewDB()
+	return &repositorytest.Store{
+		Tx:       memory.NewTxManager(db),
+		Teams:    teamRep.NewMemoryRepository(db),
+		Users:    userRep.NewMemoryRepository(db),
+		PRs:      prRep.NewMemoryRepository(db),
+		Reviews:  raRep.NewMemoryRepository(db),
+		Absences: absenceRep.NewMemoryRepository(db),
+		Events:   eventRep.NewMemoryRepository(db),
+	}
+}
+
+func TestMemory(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) *repositorytest.Store { return newMemoryStore() })
+}
+
+func BenchmarkMemoryReassignOpenFrom(b *testing.B) {
+	repositorytest.BenchReassignOpenFrom(b, newMemoryStore())
+}
@@ -0,0 +1,36 @@
+package repositorytest_test
+
+import (
+	"testing"
+
+	"github.com/zxchelik/avito-test-task/internal/infrastructure/pg"
+	"github.com/zxchelik/avito-test-task/internal/infrastructure/pg/pgtest"
+	absenceRep "github.com/zxchelik/avito-test-task/internal/repository/absence"
+	eventRep "github.com/zxchelik/avito-test-task/internal/repository/assignment_event"
+	prRep "github.com/zxchelik/avito-test-task/internal/repository/pull_request"
+	"github.com/zxchelik/avito-test-task/internal/repository/repositorytest"
+	raRep "github.com/zxchelik/avito-test-task/internal/repository/reviewer_assignment"
+	teamRep "github.com/zxchelik/avito-test-task/internal/repository/team"
+	userRep "github.com/zxchelik/avito-test-task/internal/repository/user"
+)
+
+func newPGStore(tb testing.TB) *repositorytest.Store {
+	pool := pgtest.NewPool(tb)
+	return &repositorytest.Store{
+		Tx:       pg.NewTxManager(pool),
+		Teams:    teamRep.NewPGRepository(pool),
+		Users:    userRep.NewPGRepository(pool),
+		PRs:      prRep.NewPGRepository(pool),
+		Reviews:  raRep.NewPGRepository(pool),
+		Absences: absenceRep.NewPGRepository(pool),
+		Events:   eventRep.NewPGRepository(pool),
+	}
+}
+
+func TestPostgres(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) *repositorytest.Store { return newPGStore(t) })
+}
+
+func BenchmarkPostgresReassignOpenFrom(b *testing.B) {
+	repositorytest.BenchReassignOpenFrom(b, newPGStore(b))
+}
@@ -0,0 +1,1177 @@
+// Package repositorytest is the behavioral contract shared by the storage backends:
+// every backend runs Run against its repositories and must pass it unchanged.
+package repositorytest
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/zxchelik/avito-test-task/internal/model"
+	modelabsence "github.com/zxchelik/avito-test-task/internal/model/absence"
+	modelpr "github.com/zxchelik/avito-test-task/internal/model/pull_request"
+	modelra "github.com/zxchelik/avito-test-task/internal/model/reviewer_assignment"
+	modelteam "github.com/zxchelik/avito-test-task/internal/model/team"
+	modeluser "github.com/zxchelik/avito-test-task/internal/model/user"
+	"github.com/zxchelik/avito-test-task/internal/service"
+)
+
+// Store is a backend under test: its repositories and transaction manager over one database.
+type Store struct {
+	Tx       service.TxManager
+	Teams    service.TeamRepository
+	Users    service.UserRepository
+	PRs      service.PRRepository
+	Reviews  service.ReviewerAssignmentRepository
+	Absences service.AbsenceRepository
+	Events   service.AssignmentEventRepository
+}
+
+// Factory returns a store over a new empty database.
+type Factory func(t *testing.T) *Store
+
+var errAbort = errors.New("abort")
+
+// t0 has no sub-microsecond part, so it survives a round-trip through every backend.
+var t0 = time.Date(2025, 3, 3, 10, 0, 0, 0, time.UTC)
+
+// Run runs the contract against stores made by newStore, each subtest on a fresh one.
+func Run(t *testing.T, newStore Factory) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, s *Store)
+	}{
+		{"TeamCreateAndGet", testTeamCreateAndGet},
+		{"TeamErrors", testTeamErrors},
+		{"UserUpsertAndGet", testUserUpsertAndGet},
+		{"UserErrors", testUserErrors},
+		{"UserDeactivateMembers", testUserDeactivateMembers},
+		{"PRLifecycle", testPRLifecycle},
+		{"PRErrors", testPRErrors},
+		{"PRList", testPRList},
+		{"PRListByReviewer", testPRListByReviewer},
+		{"ReviewerAssignments", testReviewerAssignments},
+		{"ReviewerErrors", testReviewerErrors},
+		{"ReviewerFallback", testReviewerFallback},
+		{"ReassignOpenFrom", testReassignOpenFrom},
+		{"Absences", testAbsences},
+		{"AbsenceErrors", testAbsenceErrors},
+		{"Events", testEvents},
+		{"TxCommit", testTxCommit},
+		{"TxRollback", testTxRollback},
+		{"TxSavepoint", testTxSavepoint},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.run(t, newStore(t))
+		})
+	}
+}
+
+func newTeam(name string, fallbacks ...string) *modelteam.Team {
+	return &modelteam.Team{
+		Name:             name,
+		ReviewerStrategy: modelteam.DefaultReviewerStrategy,
+		MinReviewers:     1,
+		MaxReviewers:     2,
+		FallbackTeams:    fallbacks,
+	}
+}
+
+// seed creates the team backend with active members author, r1 and r2 and an OPEN PR pr-1 of author.
+func seed(t *testing.T, s *Store) {
+	t.Helper()
+
+	ctx := context.Background()
+	if err := s.Teams.Create(ctx, newTeam("backend")); err != nil {
+		t.Fatalf("create team: %v", err)
+	}
+	for _, id := range []string{"author", "r1", "r2"} {
+		if err := s.Users.Upsert(ctx, &modeluser.User{ID: id, Username: id, TeamName: "backend", IsActive: true}); err != nil {
+			t.Fatalf("create user %s: %v", id, err)
+		}
+	}
+	createPR(t, s, "pr-1")
+}
+
+func createPR(t *testing.T, s *Store, id string) *modelpr.PullRequest {
+	t.Helper()
+
+	pr, err := s.PRs.Create(context.Background(), &modelpr.PullRequest{
+		ID: id, Title: "feature", AuthorID: "author", Status: modelpr.PROpen,
+	})
+	if err != nil {
+		t.Fatalf("create PR %s: %v", id, err)
+	}
+	return pr
+}
+
+func reviewerIDs(ctx context.Context, t *testing.T, s *Store, prID string) []string {
+	t.Helper()
+
+	assignments, err := s.Reviews.ListByPR(ctx, prID)
+	if err != nil {
+		t.Fatalf("list reviewers: %v", err)
+	}
+	ids := make([]string, len(assignments))
+	for i, a := range assignments {
+		ids[i] = a.UserId
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+func wantErr(t *testing.T, op string, got error, want ...error) {
+	t.Helper()
+
+	for _, w := range want {
+		if !errors.Is(got, w) {
+			t.Fatalf("%s: got %v, want %v", op, got, w)
+		}
+	}
+}
+
+func testTeamCreateAndGet(t *testing.T, s *Store) {
+	ctx := context.Background()
+	if err := s.Teams.Create(ctx, newTeam("platform")); err != nil {
+		t.Fatalf("create platform: %v", err)
+	}
+	backend := newTeam("backend", "platform")
+	backend.DefaultMaxOpenReviews = 3
+	backend.ReviewSLAWeekdayHours = 8
+	backend.SLAAutoReassign = true
+	if err := s.Teams.Create(ctx, backend); err != nil {
+		t.Fatalf("create backend: %v", err)
+	}
+
+	got, err := s.Teams.GetByName(ctx, "backend")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Name != backend.Name || got.ReviewerStrategy != backend.ReviewerStrategy ||
+		got.MinReviewers != 1 || got.MaxReviewers != 2 || got.DefaultMaxOpenReviews != 3 ||
+		got.ReviewSLAWeekdayHours != 8 || !got.SLAAutoReassign || !slices.Equal(got.FallbackTeams, []string{"platform"}) {
+		t.Fatalf("get = %+v, want %+v", got, backend)
+	}
+
+	got.MaxReviewers = 3
+	got.FallbackTeams = nil
+	updated, err := s.Teams.UpdateSettings(ctx, got)
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if updated.MaxReviewers != 3 || len(updated.FallbackTeams) != 0 {
+		t.Fatalf("update = %+v", updated)
+	}
+	if got, _ := s.Teams.GetByName(ctx, "backend"); got.MaxReviewers != 3 || len(got.FallbackTeams) != 0 {
+		t.Fatalf("get after update = %+v", got)
+	}
+}
+
+func testTeamErrors(t *testing.T, s *Store) {
+	ctx := context.Background()
+	if err := s.Teams.Create(ctx, newTeam("backend")); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	wantErr(t, "create duplicate", s.Teams.Create(ctx, newTeam("backend")), modelteam.ErrTeamExists, model.ErrAlreadyExists)
+
+	_, err := s.Teams.GetByName(ctx, "missing")
+	wantErr(t, "get missing", err, model.ErrNotFound)
+
+	_, err = s.Teams.UpdateSettings(ctx, newTeam("missing"))
+	wantErr(t, "update missing", err, model.ErrNotFound)
+
+	_, err = s.Teams.UpdateSettings(ctx, newTeam("backend", "missing"))
+	wantErr(t, "update with unknown fallback", err, modelteam.ErrFallbackTeamNotFound)
+
+	// the team and its fallbacks are written by separate statements, so callers create it in a transaction
+	err = s.Tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		return s.Teams.Create(ctx, newTeam("frontend", "missing"))
+	})
+	wantErr(t, "create with unknown fallback", err, modelteam.ErrFallbackTeamNotFound)
+	_, err = s.Teams.GetByName(ctx, "frontend")
+	wantErr(t, "get rolled back team", err, model.ErrNotFound)
+}
+
+func testUserUpsertAndGet(t *testing.T, s *Store) {
+	ctx := context.Background()
+	if err := s.Teams.Create(ctx, newTeam("backend")); err != nil {
+		t.Fatalf("create team: %v", err)
+	}
+
+	u := &modeluser.User{ID: "u1", Username: "alice", TeamName: "backend", IsActive: true, MaxOpenReviews: 2}
+	if err := s.Users.Upsert(ctx, u); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	created, err := s.Users.GetByID(ctx, "u1")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if created.Username != "alice" || created.TeamName != "backend" || !created.IsActive ||
+		created.MaxOpenReviews != 2 || created.CreatedAt.IsZero() {
+		t.Fatalf("get = %+v", created)
+	}
+
+	// upsert overwrites the profile but keeps the creation time and, when none is given,
+	// the personal limit
+	if err := s.Users.Upsert(ctx, &modeluser.User{ID: "u1", Username: "alice2", TeamName: "backend"}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	got, err := s.Users.GetByID(ctx, "u1")
+	if err != nil {
+		t.Fatalf("get after update: %v", err)
+	}
+	if got.Username != "alice2" || got.IsActive || got.MaxOpenReviews != 2 || !got.CreatedAt.Equal(created.CreatedAt) {
+		t.Fatalf("get after update = %+v, created at %s", got, created.CreatedAt)
+	}
+
+	// a given limit replaces the stored one
+	if err := s.Users.Upsert(ctx, &modeluser.User{ID: "u1", Username: "alice2", TeamName: "backend", MaxOpenReviews: 3}); err != nil {
+		t.Fatalf("update limit: %v", err)
+	}
+	if got, err := s.Users.GetByID(ctx, "u1"); err != nil || got.MaxOpenReviews != 3 {
+		t.Fatalf("get after limit update = %+v, %v", got, err)
+	}
+
+	if got, err := s.Users.SetIsActive(ctx, "u1", true); err != nil || !got.IsActive {
+		t.Fatalf("set active = %+v, %v", got, err)
+	}
+	if got, err := s.Users.SetMaxOpenReviews(ctx, "u1", 5); err != nil || got.MaxOpenReviews != 5 {
+		t.Fatalf("set max open reviews = %+v, %v", got, err)
+	}
+	if got, err := s.Users.SetMaxOpenReviews(ctx, "u1", 0); err != nil || got.MaxOpenReviews != 0 {
+		t.Fatalf("clear max open reviews = %+v, %v", got, err)
+	}
+
+	if err := s.Users.Upsert(ctx, &modeluser.User{ID: "u0", Username: "bob", TeamName: "backend"}); err != nil {
+		t.Fatalf("insert u0: %v", err)
+	}
+	listed, err := s.Users.ListByIDs(ctx, []string{"u1", "missing", "u0"})
+	if err != nil {
+		t.Fatalf("list by ids: %v", err)
+	}
+	if len(listed) != 2 || listed[0].ID != "u0" || listed[1].ID != "u1" {
+		t.Fatalf("list by ids = %+v, want [u0 u1]", listed)
+	}
+	members, err := s.Users.ListByTeam(ctx, "backend")
+	if err != nil {
+		t.Fatalf("list by team: %v", err)
+	}
+	if len(members) != 2 || members[0].ID != "u0" || members[1].ID != "u1" {
+		t.Fatalf("list by team = %+v, want [u0 u1]", members)
+	}
+}
+
+func testUserErrors(t *testing.T, s *Store) {
+	ctx := context.Background()
+
+	err := s.Users.Upsert(ctx, &modeluser.User{ID: "u1", Username: "alice", TeamName: "missing"})
+	wantErr(t, "upsert into missing team", err, modeluser.ErrTeamNotFound)
+
+	_, err = s.Users.GetByID(ctx, "u1")
+	wantErr(t, "get missing", err, model.ErrNotFound)
+	_, err = s.Users.SetIsActive(ctx, "u1", false)
+	wantErr(t, "set active of missing", err, model.ErrNotFound)
+	_, err = s.Users.SetMaxOpenReviews(ctx, "u1", 1)
+	wantErr(t, "set max open reviews of missing", err, model.ErrNotFound)
+}
+
+func testUserDeactivateMembers(t *testing.T, s *Store) {
+	seed(t, s)
+	ctx := context.Background()
+	if err := s.Teams.Create(ctx, newTeam("frontend")); err != nil {
+		t.Fatalf("create team: %v", err)
+	}
+	if err := s.Users.Upsert(ctx, &modeluser.User{ID: "f1", Username: "f1", TeamName: "frontend", IsActive: true}); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+
+	// users of other teams are not touched even when listed
+	got, err := s.Users.DeactivateMembers(ctx, "backend", []string{"r2", "r1", "f1"})
+	if err != nil {
+		t.Fatalf("deactivate listed: %v", err)
+	}
+	// the order of the matched users is not part of the contract
+	slices.SortFunc(got, func(a, b *modeluser.User) int { return cmp.Compare(a.ID, b.ID) })
+	if len(got) != 2 || got[0].ID != "r1" || got[1].ID != "r2" || got[0].IsActive || got[1].IsActive {
+		t.Fatalf("deactivate listed = %+v, want inactive [r1 r2]", got)
+	}
+	if f1, _ := s.Users.GetByID(ctx, "f1"); !f1.IsActive {
+		t.Fatal("member of another team deactivated")
+	}
+
+	all, err := s.Users.DeactivateMembers(ctx, "backend", nil)
+	if err != nil {
+		t.Fatalf("deactivate all: %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("deactivate all = %+v, want 3 members", all)
+	}
+	if author, _ := s.Users.GetByID(ctx, "author"); author.IsActive {
+		t.Fatal("author is still active")
+	}
+}
+
+func testPRLifecycle(t *testing.T, s *Store) {
+	seed(t, s)
+	ctx := context.Background()
+
+	pr, err := s.PRs.GetByID(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if pr.Title != "feature" || pr.AuthorID != "author" || pr.Status != modelpr.PROpen ||
+		pr.CreatedAt.IsZero() || pr.MergedAt != nil || pr.ClosedAt != nil {
+		t.Fatalf("get = %+v", pr)
+	}
+
+	closed, err := s.PRs.UpdateStatus(ctx, "pr-1", modelpr.PROpen, modelpr.PRClosed, t0)
+	if err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if closed.Status != modelpr.PRClosed || closed.ClosedAt == nil || !closed.ClosedAt.Equal(t0) {
+		t.Fatalf("close = %+v", closed)
+	}
+	reopened, err := s.PRs.UpdateStatus(ctx, "pr-1", modelpr.PRClosed, modelpr.PROpen, t0.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if reopened.Status != modelpr.PROpen || reopened.ClosedAt != nil {
+		t.Fatalf("reopen = %+v", reopened)
+	}
+
+	merged, err := s.PRs.MarkMerged(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	if merged.Status != modelpr.PRMerged || merged.MergedAt == nil {
+		t.Fatalf("merge = %+v", merged)
+	}
+
+	draft, err := s.PRs.Create(ctx, &modelpr.PullRequest{ID: "pr-draft", Title: "wip", AuthorID: "author", Status: modelpr.PRDraft})
+	if err != nil {
+		t.Fatalf("create draft: %v", err)
+	}
+	if draft.Status != modelpr.PRDraft {
+		t.Fatalf("create draft = %+v", draft)
+	}
+	if ready, err := s.PRs.UpdateStatus(ctx, "pr-draft", modelpr.PRDraft, modelpr.PROpen, t0); err != nil ||
+		ready.Status != modelpr.PROpen || ready.ClosedAt != nil {
+		t.Fatalf("ready = %+v, %v", ready, err)
+	}
+
+	// merging again keeps the first merge time
+	again, err := s.PRs.MarkMerged(ctx, "pr-1")
+	wantErr(t, "merge again", err, modelpr.ErrPRAlreadyMerged)
+	if again == nil || again.MergedAt == nil || !again.MergedAt.Equal(*merged.MergedAt) {
+		t.Fatalf("merge again = %+v, want merged at %s", again, merged.MergedAt)
+	}
+}
+
+func testPRErrors(t *testing.T, s *Store) {
+	seed(t, s)
+	ctx := context.Background()
+
+	_, err := s.PRs.Create(ctx, &modelpr.PullRequest{ID: "pr-1", Title: "again", AuthorID: "author", Status: modelpr.PROpen})
+	wantErr(t, "create duplicate", err, modelpr.ErrPRExists, model.ErrAlreadyExists)
+	_, err = s.PRs.Create(ctx, &modelpr.PullRequest{ID: "pr-2", Title: "orphan", AuthorID: "missing", Status: modelpr.PROpen})
+	wantErr(t, "create with missing author", err, model.ErrNotFound)
+
+	_, err = s.PRs.GetByID(ctx, "missing")
+	wantErr(t, "get missing", err, model.ErrNotFound)
+	_, err = s.PRs.GetByIDForUpdate(ctx, "missing")
+	wantErr(t, "get missing for update", err, model.ErrNotFound)
+	_, err = s.PRs.MarkMerged(ctx, "missing")
+	wantErr(t, "merge missing", err, model.ErrNotFound)
+	_, err = s.PRs.UpdateStatus(ctx, "missing", modelpr.PROpen, modelpr.PRClosed, t0)
+	wantErr(t, "update status of missing", err, model.ErrNotFound)
+	_, err = s.PRs.UpdateStatus(ctx, "pr-1", modelpr.PRDraft, modelpr.PROpen, t0)
+	wantErr(t, "update status from a stale status", err, modelpr.ErrInvalidTransition)
+}
+
+func prIDs(prs []*modelpr.PullRequest) []string {
+	ids := make([]string, len(prs))
+	for i, pr := range prs {
+		ids[i] = pr.ID
+	}
+	return ids
+}
+
+func testPRList(t *testing.T, s *Store) {
+	seed(t, s)
+	ctx := context.Background()
+	if err := s.Teams.Create(ctx, newTeam("frontend")); err != nil {
+		t.Fatalf("create team: %v", err)
+	}
+	if err := s.Users.Upsert(ctx, &modeluser.User{ID: "fe", Username: "fe", TeamName: "frontend", IsActive: true}); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	for _, pr := range []*modelpr.PullRequest{
+		{ID: "pr-2", Title: "closed", AuthorID: "author", Status: modelpr.PROpen},
+		{ID: "pr-3", Title: "merged", AuthorID: "author", Status: modelpr.PROpen},
+		{ID: "pr-draft", Title: "wip", AuthorID: "author", Status: modelpr.PRDraft},
+		{ID: "pr-fe", Title: "ui", AuthorID: "fe", Status: modelpr.PROpen},
+	} {
+		if _, err := s.PRs.Create(ctx, pr); err != nil {
+			t.Fatalf("create %s: %v", pr.ID, err)
+		}
+	}
+	if _, err := s.PRs.UpdateStatus(ctx, "pr-2", modelpr.PROpen, modelpr.PRClosed, t0); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	merged, err := s.PRs.MarkMerged(ctx, "pr-3")
+	if err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	for _, id := range []string{"pr-1", "pr-3"} {
+		if err := s.Reviews.Add(ctx, &modelra.ReviewerAssignment{PrId: id, UserId: "r1", AssignedAt: t0}); err != nil {
+			t.Fatalf("add reviewer to %s: %v", id, err)
+		}
+	}
+
+	// the full list is ordered by (created_at, id); created_at comes from the backend
+	all, err := s.PRs.List(ctx, modelpr.ListFilter{Limit: 100})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(all) != 5 || !slices.IsSortedFunc(all, func(a, b *modelpr.PullRequest) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	}) {
+		t.Fatalf("list = %v", prIDs(all))
+	}
+	first, last := all[0].CreatedAt, all[len(all)-1].CreatedAt
+	after := last.Add(time.Second)
+
+	tests := []struct {
+		name   string
+		filter modelpr.ListFilter
+		want   []string
+	}{
+		{"status", modelpr.ListFilter{Statuses: []modelpr.PRStatus{modelpr.PROpen}}, []string{"pr-1", "pr-fe"}},
+		{"several statuses", modelpr.ListFilter{Statuses: []modelpr.PRStatus{modelpr.PRMerged, modelpr.PRClosed}}, []string{"pr-2", "pr-3"}},
+		{"author", modelpr.ListFilter{AuthorID: "fe"}, []string{"pr-fe"}},
+		{"author team", modelpr.ListFilter{TeamName: "backend"}, []string{"pr-1", "pr-2", "pr-3", "pr-draft"}},
+		{"reviewer", modelpr.ListFilter{ReviewerID: "r1"}, []string{"pr-1", "pr-3"}},
+		{"reviewer and status", modelpr.ListFilter{ReviewerID: "r1", Statuses: []modelpr.PRStatus{modelpr.PROpen}}, []string{"pr-1"}},
+		{"created range with an inclusive start", modelpr.ListFilter{CreatedFrom: &first, CreatedTo: &after}, []string{"pr-1", "pr-2", "pr-3", "pr-draft", "pr-fe"}},
+		{"created after every PR", modelpr.ListFilter{CreatedFrom: &after}, nil},
+		{"created to is exclusive", modelpr.ListFilter{CreatedTo: &first}, nil},
+		{"merged range", modelpr.ListFilter{MergedFrom: merged.MergedAt}, []string{"pr-3"}},
+		{"unknown author", modelpr.ListFilter{AuthorID: "missing"}, nil},
+	}
+	for _, tt := range tests {
+		tt.filter.Limit = 100
+		got, err := s.PRs.List(ctx, tt.filter)
+		if err != nil {
+			t.Fatalf("list by %s: %v", tt.name, err)
+		}
+		ids := prIDs(got)
+		slices.Sort(ids)
+		if !slices.Equal(ids, tt.want) {
+			t.Fatalf("list by %s = %v, want %v", tt.name, ids, tt.want)
+		}
+	}
+
+	// pages of two joined together give the full list
+	var paged []*modelpr.PullRequest
+	filter := modelpr.ListFilter{Limit: 2}
+	for range len(all) {
+		page, err := s.PRs.List(ctx, filter)
+		if err != nil {
+			t.Fatalf("list page: %v", err)
+		}
+		if len(page) == 0 {
+			break
+		}
+		paged = append(paged, page...)
+		last := page[len(page)-1]
+		filter.After = &modelpr.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+	if !slices.Equal(prIDs(paged), prIDs(all)) {
+		t.Fatalf("pages = %v, want %v", prIDs(paged), prIDs(all))
+	}
+}
+
+func testPRListByReviewer(t *testing.T, s *Store) {
+	seed(t, s)
+	ctx := context.Background()
+	createPR(t, s, "pr-2")
+	createPR(t, s, "pr-3")
+	for _, a := range []*modelra.ReviewerAssignment{
+		{PrId: "pr-1", UserId: "r1", AssignedAt: t0},
+		{PrId: "pr-1", UserId: "r2", AssignedAt: t0},
+		{PrId: "pr-2", UserId: "r1", AssignedAt: t0.Add(time.Minute)},
+		{PrId: "pr-3", UserId: "r1", AssignedAt: t0.Add(time.Minute)},
+	} {
+		if err := s.Reviews.Add(ctx, a); err != nil {
+			t.Fatalf("add %s to %s: %v", a.UserId, a.PrId, err)
+		}
+	}
+	if _, err := s.PRs.UpdateStatus(ctx, "pr-2", modelpr.PROpen, modelpr.PRClosed, t0); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if _, err := s.Reviews.SetVerdict(ctx, "pr-1", "r1", modelra.VerdictApproved, t0.Add(time.Hour)); err != nil {
+		t.Fatalf("set verdict: %v", err)
+	}
+
+	// newest assignments first, ties broken by PR id descending
+	all, err := s.PRs.ListByReviewer(ctx, modelpr.ReviewFilter{ReviewerID: "r1"})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("list = %d reviews, want 3", len(all))
+	}
+	for i, want := range []struct {
+		id         string
+		status     modelpr.PRStatus
+		assignedAt time.Time
+		verdict    modelra.Verdict
+	}{
+		{"pr-3", modelpr.PROpen, t0.Add(time.Minute), modelra.VerdictNone},
+		{"pr-2", modelpr.PRClosed, t0.Add(time.Minute), modelra.VerdictNone},
+		{"pr-1", modelpr.PROpen, t0, modelra.VerdictApproved},
+	} {
+		r := all[i]
+		if r.PullRequest.ID != want.id || r.PullRequest.Status != want.status || r.PullRequest.AuthorID != "author" ||
+			!r.AssignedAt.Equal(want.assignedAt) || r.Verdict != want.verdict {
+			t.Fatalf("review %d = %+v (%+v), want %s", i, r, r.PullRequest, want.id)
+		}
+	}
+	if all[2].VerdictAt == nil || !all[2].VerdictAt.Equal(t0.Add(time.Hour)) || all[0].VerdictAt != nil {
+		t.Fatalf("verdict times = %v, %v", all[0].VerdictAt, all[2].VerdictAt)
+	}
+
+	since := t0.Add(time.Minute)
+	tests := []struct {
+		name   string
+		filter modelpr.ReviewFilter
+		want   []string
+	}{
+		{"status", modelpr.ReviewFilter{ReviewerID: "r1", Statuses: []modelpr.PRStatus{modelpr.PROpen}}, []string{"pr-3", "pr-1"}},
+		{"since is inclusive", modelpr.ReviewFilter{ReviewerID: "r1", Since: &since}, []string{"pr-3", "pr-2"}},
+		{"after cursor", modelpr.ReviewFilter{ReviewerID: "r1", After: &modelpr.ReviewCursor{AssignedAt: since, PRID: "pr-3"}}, []string{"pr-2", "pr-1"}},
+		{"limit", modelpr.ReviewFilter{ReviewerID: "r1", Limit: 1}, []string{"pr-3"}},
+		{"other reviewer", modelpr.ReviewFilter{ReviewerID: "r2"}, []string{"pr-1"}},
+		{"not a reviewer", modelpr.ReviewFilter{ReviewerID: "author"}, nil},
+	}
+	for _, tt := range tests {
+		got, err := s.PRs.ListByReviewer(ctx, tt.filter)
+		if err != nil {
+			t.Fatalf("list by %s: %v", tt.name, err)
+		}
+		var ids []string
+		for _, r := range got {
+			ids = append(ids, r.PullRequest.ID)
+		}
+		if !slices.Equal(ids, tt.want) {
+			t.Fatalf("list by %s = %v, want %v", tt.name, ids, tt.want)
+		}
+	}
+}
+
+func testReviewerAssignments(t *testing.T, s *Store) {
+	seed(t, s)
+	ctx := context.Background()
+	createPR(t, s, "pr-2")
+
+	for _, a := range []*modelra.ReviewerAssignment{
+		{PrId: "pr-1", UserId: "r1", AssignedAt: t0},
+		{PrId: "pr-2", UserId: "r1", AssignedAt: t0.Add(time.Minute)},
+		{PrId: "pr-2", UserId: "r2", AssignedAt: t0.Add(2 * time.Minute), Fallback: true},
+	} {
+		if err := s.Reviews.Add(ctx, a); err != nil {
+			t.Fatalf("add %s to %s: %v", a.UserId, a.PrId, err)
+		}
+	}
+
+	assignments, err := s.Reviews.ListByPR(ctx, "pr-2")
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(assignments) != 2 || assignments[0].UserId != "r1" || assignments[1].UserId != "r2" ||
+		!assignments[1].Fallback || !assignments[1].AssignedAt.Equal(t0.Add(2*time.Minute)) {
+		t.Fatalf("list = %+v", assignments)
+	}
+
+	load, err := s.Reviews.CountOpenByReviewers(ctx, []string{"r1", "r2", "author"})
+	if err != nil {
+		t.Fatalf("count open: %v", err)
+	}
+	if load["r1"] != 2 || load["r2"] != 1 || load["author"] != 0 {
+		t.Fatalf("open load = %v", load)
+	}
+
+	// only OPEN PRs count towards the load
+	if _, err := s.PRs.UpdateStatus(ctx, "pr-2", modelpr.PROpen, modelpr.PRClosed, t0); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	load, err = s.Reviews.CountOpenByReviewers(ctx, []string{"r1", "r2"})
+	if err != nil {
+		t.Fatalf("count open after close: %v", err)
+	}
+	if load["r1"] != 1 || load["r2"] != 0 {
+		t.Fatalf("open load after close = %v, want r1: 1, r2: 0", load)
+	}
+	if _, err := s.PRs.UpdateStatus(ctx, "pr-2", modelpr.PRClosed, modelpr.PROpen, t0); err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+
+	if err := s.Reviews.Replace(ctx, "r1", &modelra.ReviewerAssignment{PrId: "pr-1", UserId: "r2", AssignedAt: t0.Add(time.Hour)}); err != nil {
+		t.Fatalf("replace: %v", err)
+	}
+	if got := reviewerIDs(ctx, t, s, "pr-1"); !slices.Equal(got, []string{"r2"}) {
+		t.Fatalf("reviewers after replace = %v, want [r2]", got)
+	}
+
+	verdict, err := s.Reviews.SetVerdict(ctx, "pr-2", "r1", modelra.VerdictApproved, t0.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("set verdict: %v", err)
+	}
+	if verdict.Verdict != modelra.VerdictApproved || verdict.VerdictAt == nil || !verdict.VerdictAt.Equal(t0.Add(time.Hour)) {
+		t.Fatalf("set verdict = %+v", verdict)
+	}
+	// a new verdict replaces the previous one
+	if _, err := s.Reviews.SetVerdict(ctx, "pr-2", "r1", modelra.VerdictChangesRequested, t0.Add(2*time.Hour)); err != nil {
+		t.Fatalf("set verdict again: %v", err)
+	}
+	assignments, err = s.Reviews.ListByPR(ctx, "pr-2")
+	if err != nil {
+		t.Fatalf("list after verdict: %v", err)
+	}
+	if a := assignments[0]; a.UserId != "r1" || a.Verdict != modelra.VerdictChangesRequested ||
+		a.VerdictAt == nil || !a.VerdictAt.Equal(t0.Add(2*time.Hour)) {
+		t.Fatalf("r1 after second verdict = %+v", a)
+	}
+	if a := assignments[1]; a.Verdict != modelra.VerdictNone || a.VerdictAt != nil {
+		t.Fatalf("r2 without verdict = %+v", a)
+	}
+
+	if err := s.Reviews.Remove(ctx, "pr-2", "r1"); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if got := reviewerIDs(ctx, t, s, "pr-2"); !slices.Equal(got, []string{"r2"}) {
+		t.Fatalf("reviewers after remove = %v, want [r2]", got)
+	}
+}
+
+func testReviewerErrors(t *testing.T, s *Store) {
+	seed(t, s)
+	ctx := context.Background()
+	if err := s.Reviews.Add(ctx, &modelra.ReviewerAssignment{PrId: "pr-1", UserId: "r1", AssignedAt: t0}); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if err := s.Reviews.Add(ctx, &modelra.ReviewerAssignment{PrId: "pr-1", UserId: "r2", AssignedAt: t0}); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+
+	wantErr(t, "add duplicate",
+		s.Reviews.Add(ctx, &modelra.ReviewerAssignment{PrId: "pr-1", UserId: "r1", AssignedAt: t0}), model.ErrAlreadyExists)
+	wantErr(t, "add to missing PR",
+		s.Reviews.Add(ctx, &modelra.ReviewerAssignment{PrId: "missing", UserId: "r1", AssignedAt: t0}), model.ErrNotFound)
+	wantErr(t, "add missing user",
+		s.Reviews.Add(ctx, &modelra.ReviewerAssignment{PrId: "pr-1", UserId: "missing", AssignedAt: t0}), model.ErrNotFound)
+
+	wantErr(t, "remove unassigned", s.Reviews.Remove(ctx, "pr-1", "author"), modelra.ErrReviewerNotFoundInPR)
+
+	replace := func(old, next string) error {
+		return s.Reviews.Replace(ctx, old, &modelra.ReviewerAssignment{PrId: "pr-1", UserId: next, AssignedAt: t0})
+	}
+	wantErr(t, "replace with itself", replace("r1", "r1"), modelra.ErrReviewerSameAsOld)
+	wantErr(t, "replace unassigned", replace("author", "r1"), modelra.ErrReviewerNotFoundInPR)
+	wantErr(t, "replace with assigned", replace("r1", "r2"), modelra.ErrReviewerDuplication)
+	wantErr(t, "replace with missing user", replace("r1", "missing"), model.ErrNotFound)
+
+	_, err := s.Reviews.SetVerdict(ctx, "pr-1", "author", modelra.VerdictApproved, t0)
+	wantErr(t, "verdict of unassigned", err, modelra.ErrReviewerNotFoundInPR)
+
+	// failed calls leave the assignments as they were
+	if got := reviewerIDs(ctx, t, s, "pr-1"); !slices.Equal(got, []string{"r1", "r2"}) {
+		t.Fatalf("reviewers = %v, want [r1 r2]", got)
+	}
+}
+
+func testReviewerFallback(t *testing.T, s *Store) {
+	seed(t, s)
+	ctx := context.Background()
+	createPR(t, s, "pr-2")
+	if err := s.Reviews.Add(ctx, &modelra.ReviewerAssignment{PrId: "pr-1", UserId: "r1", AssignedAt: t0, Fallback: true}); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if err := s.Reviews.Add(ctx, &modelra.ReviewerAssignment{PrId: "pr-2", UserId: "r1", AssignedAt: t0}); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+
+	fallback := func(prID string) map[string]bool {
+		t.Helper()
+
+		assignments, err := s.Reviews.ListByPR(ctx, prID)
+		if err != nil {
+			t.Fatalf("list: %v", err)
+		}
+		res := make(map[string]bool, len(assignments))
+		for _, a := range assignments {
+			res[a.UserId] = a.Fallback
+		}
+		return res
+	}
+
+	// the flag belongs to the assignment: a replacement carries its own
+	if err := s.Reviews.Replace(ctx, "r1", &modelra.ReviewerAssignment{PrId: "pr-1", UserId: "r2", AssignedAt: t0}); err != nil {
+		t.Fatalf("replace with a home reviewer: %v", err)
+	}
+	if got := fallback("pr-1"); len(got) != 1 || got["r2"] {
+		t.Fatalf("pr-1 fallback flags = %v, want r2 not fallback", got)
+	}
+	if err := s.Reviews.Replace(ctx, "r1", &modelra.ReviewerAssignment{PrId: "pr-2", UserId: "r2", AssignedAt: t0, Fallback: true}); err != nil {
+		t.Fatalf("replace with a fallback reviewer: %v", err)
+	}
+	if got := fallback("pr-2"); len(got) != 1 || !got["r2"] {
+		t.Fatalf("pr-2 fallback flags = %v, want r2 fallback", got)
+	}
+
+	byPR, err := s.Reviews.ListByPRs(ctx, []string{"pr-1", "pr-2", "missing"})
+	if err != nil {
+		t.Fatalf("list by PRs: %v", err)
+	}
+	if len(byPR["pr-1"]) != 1 || byPR["pr-1"][0].Fallback || len(byPR["pr-2"]) != 1 || !byPR["pr-2"][0].Fallback ||
+		len(byPR["missing"]) != 0 {
+		t.Fatalf("list by PRs = %v", byPR)
+	}
+}
+
+// addUsers creates active members of team; a positive limit becomes their max_open_reviews.
+func addUsers(ctx context.Context, t testing.TB, s *Store, team string, limit int, ids ...string) {
+	t.Helper()
+
+	for _, id := range ids {
+		u := &modeluser.User{ID: id, Username: id, TeamName: team, IsActive: true, MaxOpenReviews: limit}
+		if err := s.Users.Upsert(ctx, u); err != nil {
+			t.Fatalf("create user %s: %v", id, err)
+		}
+	}
+}
+
+func testReassignOpenFrom(t *testing.T, s *Store) {
+	ctx := context.Background()
+	if err := s.Teams.Create(ctx, newTeam("backend")); err != nil {
+		t.Fatalf("create team: %v", err)
+	}
+	addUsers(ctx, t, s, "backend", 0, "author", "old", "b")
+	addUsers(ctx, t, s, "backend", 1, "a") // room for a single review
+	// away would be picked first on pr-2 but is absent at the reassignment time
+	addUsers(ctx, t, s, "backend", 0, "away")
+	if _, err := s.Absences.Create(ctx, &modelabsence.Absence{
+		UserID: "away", StartsAt: t0, EndsAt: t0.Add(2 * time.Hour), Reason: "vacation",
+	}); err != nil {
+		t.Fatalf("create absence: %v", err)
+	}
+	for _, id := range []string{"pr-1", "pr-2", "pr-3"} {
+		createPR(t, s, id)
+		if err := s.Reviews.Add(ctx, &modelra.ReviewerAssignment{PrId: id, UserId: "old", AssignedAt: t0}); err != nil {
+			t.Fatalf("assign old to %s: %v", id, err)
+		}
+	}
+	merged := createPR(t, s, "pr-merged")
+	if err := s.Reviews.Add(ctx, &modelra.ReviewerAssignment{PrId: merged.ID, UserId: "old", AssignedAt: t0}); err != nil {
+		t.Fatalf("assign old to merged PR: %v", err)
+	}
+	if _, err := s.PRs.MarkMerged(ctx, merged.ID); err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+
+	reassign := func(userIDs ...string) []*modelra.Reassignment {
+		t.Helper()
+
+		if _, err := s.Users.DeactivateMembers(ctx, "backend", userIDs); err != nil {
+			t.Fatalf("deactivate %v: %v", userIDs, err)
+		}
+		res, err := s.Reviews.ReassignOpenFrom(ctx, userIDs, t0.Add(time.Hour))
+		if err != nil {
+			t.Fatalf("reassign from %v: %v", userIDs, err)
+		}
+		return res
+	}
+	type result struct {
+		pr, old, next string
+		reason        error
+	}
+	check := func(got []*modelra.Reassignment, want ...result) {
+		t.Helper()
+
+		if len(got) != len(want) {
+			t.Fatalf("got %d reassignments, want %d: %+v", len(got), len(want), got)
+		}
+		for i, w := range want {
+			g := got[i]
+			if g.PrId != w.pr || g.OldUserId != w.old || g.NewUserId != w.next || !errors.Is(g.Reason, w.reason) ||
+				(w.reason == nil && g.Reason != nil) {
+				t.Fatalf("reassignment %d = %+v, want %+v", i, g, w)
+			}
+		}
+	}
+
+	// the round-robin starts pr-3 at a again, but a is full after pr-1, so pr-3 takes b;
+	// the merged PR is left alone
+	check(reassign("old"),
+		result{pr: "pr-1", old: "old", next: "a"},
+		result{pr: "pr-2", old: "old", next: "b"},
+		result{pr: "pr-3", old: "old", next: "b"},
+	)
+	for pr, want := range map[string]string{"pr-1": "a", "pr-2": "b", "pr-3": "b", "pr-merged": "old"} {
+		if got := reviewerIDs(ctx, t, s, pr); !slices.Equal(got, []string{want}) {
+			t.Fatalf("reviewers of %s = %v, want [%s]", pr, got, want)
+		}
+	}
+	assignments, err := s.Reviews.ListByPR(ctx, "pr-2")
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if !assignments[0].AssignedAt.Equal(t0.Add(time.Hour)) {
+		t.Fatalf("assigned at = %s, want %s", assignments[0].AssignedAt, t0.Add(time.Hour))
+	}
+
+	// a would fit on pr-2 and pr-3 but for the limit
+	check(reassign("b"),
+		result{pr: "pr-2", old: "b", reason: modelra.ErrReviewersAtCapacity},
+		result{pr: "pr-3", old: "b", reason: modelra.ErrReviewersAtCapacity},
+	)
+	// nobody is left to take them
+	check(reassign("a", "b"),
+		result{pr: "pr-1", old: "a", reason: modelra.ErrNoReviewerCandidatesLeft},
+		result{pr: "pr-2", old: "b", reason: modelra.ErrNoReviewerCandidatesLeft},
+		result{pr: "pr-3", old: "b", reason: modelra.ErrNoReviewerCandidatesLeft},
+	)
+	for pr, want := range map[string]string{"pr-1": "a", "pr-2": "b", "pr-3": "b"} {
+		if got := reviewerIDs(ctx, t, s, pr); !slices.Equal(got, []string{want}) {
+			t.Fatalf("uncovered %s reviewers = %v, want [%s]", pr, got, want)
+		}
+	}
+}
+
+func testAbsences(t *testing.T, s *Store) {
+	seed(t, s)
+	ctx := context.Background()
+
+	add := func(userID string, from, to time.Duration) *modelabsence.Absence {
+		t.Helper()
+
+		a, err := s.Absences.Create(ctx, &modelabsence.Absence{
+			UserID: userID, StartsAt: t0.Add(from), EndsAt: t0.Add(to), Reason: "vacation",
+		})
+		if err != nil {
+			t.Fatalf("create absence of %s: %v", userID, err)
+		}
+		return a
+	}
+	later := add("r1", 48*time.Hour, 72*time.Hour)
+	current := add("r1", 0, 24*time.Hour)
+	add("r2", -48*time.Hour, -24*time.Hour)
+
+	if current.ID == 0 || current.ID == later.ID || current.CreatedAt.IsZero() || current.CancelledAt != nil ||
+		!current.StartsAt.Equal(t0) || !current.EndsAt.Equal(t0.Add(24*time.Hour)) || current.Reason != "vacation" {
+		t.Fatalf("create = %+v", current)
+	}
+	if got, err := s.Absences.GetByID(ctx, current.ID); err != nil || got.UserID != "r1" || !got.StartsAt.Equal(t0) {
+		t.Fatalf("get = %+v, %v", got, err)
+	}
+
+	// [starts_at, ends_at): the start is covered, the end is not
+	for _, tt := range []struct {
+		at   time.Time
+		want []string
+	}{
+		{t0.Add(-time.Second), nil},
+		{t0, []string{"r1"}},
+		{t0.Add(24*time.Hour - time.Second), []string{"r1"}},
+		{t0.Add(24 * time.Hour), nil},
+		{t0.Add(-36 * time.Hour), []string{"r2"}},
+	} {
+		absent, err := s.Absences.ListAbsentUserIDs(ctx, []string{"author", "r1", "r2"}, tt.at)
+		if err != nil {
+			t.Fatalf("list absent at %s: %v", tt.at, err)
+		}
+		if len(absent) != len(tt.want) {
+			t.Fatalf("absent at %s = %v, want %v", tt.at, absent, tt.want)
+		}
+		for _, id := range tt.want {
+			if _, ok := absent[id]; !ok {
+				t.Fatalf("absent at %s = %v, want %v", tt.at, absent, tt.want)
+			}
+		}
+	}
+	// only the listed users are checked
+	if absent, err := s.Absences.ListAbsentUserIDs(ctx, []string{"r2"}, t0); err != nil || len(absent) != 0 {
+		t.Fatalf("absent among [r2] = %v, %v", absent, err)
+	}
+
+	cancelled, err := s.Absences.Cancel(ctx, current.ID, t0.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	if cancelled.CancelledAt == nil || !cancelled.CancelledAt.Equal(t0.Add(time.Hour)) {
+		t.Fatalf("cancel = %+v", cancelled)
+	}
+	if absent, err := s.Absences.ListAbsentUserIDs(ctx, []string{"r1"}, t0.Add(2*time.Hour)); err != nil || len(absent) != 0 {
+		t.Fatalf("absent after cancel = %v, %v", absent, err)
+	}
+
+	// ordered by start time; cancelled ones only on request
+	list := func(includeCancelled bool) []int64 {
+		t.Helper()
+
+		absences, err := s.Absences.ListByUser(ctx, "r1", includeCancelled)
+		if err != nil {
+			t.Fatalf("list: %v", err)
+		}
+		ids := make([]int64, len(absences))
+		for i, a := range absences {
+			ids[i] = a.ID
+		}
+		return ids
+	}
+	if got := list(false); !slices.Equal(got, []int64{later.ID}) {
+		t.Fatalf("list = %v, want [%d]", got, later.ID)
+	}
+	if got := list(true); !slices.Equal(got, []int64{current.ID, later.ID}) {
+		t.Fatalf("list with cancelled = %v, want [%d %d]", got, current.ID, later.ID)
+	}
+}
+
+func testAbsenceErrors(t *testing.T, s *Store) {
+	seed(t, s)
+	ctx := context.Background()
+
+	_, err := s.Absences.Create(ctx, &modelabsence.Absence{
+		UserID: "missing", StartsAt: t0, EndsAt: t0.Add(time.Hour), Reason: "vacation",
+	})
+	wantErr(t, "create for missing user", err, model.ErrNotFound)
+	_, err = s.Absences.GetByID(ctx, 42)
+	wantErr(t, "get missing", err, model.ErrNotFound)
+	_, err = s.Absences.Cancel(ctx, 42, t0)
+	wantErr(t, "cancel missing", err, model.ErrNotFound)
+
+	a, err := s.Absences.Create(ctx, &modelabsence.Absence{UserID: "r1", StartsAt: t0, EndsAt: t0.Add(time.Hour), Reason: "vacation"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := s.Absences.Cancel(ctx, a.ID, t0); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	_, err = s.Absences.Cancel(ctx, a.ID, t0.Add(time.Minute))
+	wantErr(t, "cancel twice", err, modelabsence.ErrAlreadyCancelled)
+	if got, _ := s.Absences.GetByID(ctx, a.ID); got.CancelledAt == nil || !got.CancelledAt.Equal(t0) {
+		t.Fatalf("second cancel changed the absence: %+v", got)
+	}
+}
+
+func testEvents(t *testing.T, s *Store) {
+	seed(t, s)
+	ctx := context.Background()
+	createPR(t, s, "pr-2")
+
+	timeline := []*modelra.Event{
+		{PrId: "pr-1", Type: modelra.EventStatusChanged, Status: string(modelpr.PROpen), Reason: modelra.ReasonPRCreated, CreatedAt: t0},
+		{PrId: "pr-1", Type: modelra.EventAssigned, UserId: "r1", Reason: modelra.ReasonPRCreated, CreatedAt: t0},
+		{PrId: "pr-1", Type: modelra.EventReplaced, UserId: "r2", PreviousUserId: "r1", ActorId: "author",
+			Reason: modelra.ReasonManual, CreatedAt: t0.Add(time.Hour)},
+		{PrId: "pr-1", Type: modelra.EventVerdict, UserId: "r2", Verdict: modelra.VerdictApproved, ActorId: "r2", CreatedAt: t0.Add(2 * time.Hour)},
+		{PrId: "pr-1", Type: modelra.EventMerged, Status: string(modelpr.PRMerged), ActorId: "author", CreatedAt: t0.Add(3 * time.Hour)},
+	}
+	// one batch per call, with another PR in between: the timeline keeps the write order
+	if err := s.Events.Append(ctx, timeline[:2]...); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	if err := s.Events.Append(ctx, &modelra.Event{PrId: "pr-2", Type: modelra.EventAssigned, UserId: "r1", CreatedAt: t0}); err != nil {
+		t.Fatalf("append to pr-2: %v", err)
+	}
+	if err := s.Events.Append(ctx, timeline[2:]...); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	if err := s.Events.Append(ctx); err != nil {
+		t.Fatalf("append nothing: %v", err)
+	}
+
+	got, err := s.Events.ListByPR(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(got) != len(timeline) {
+		t.Fatalf("got %d events, want %d: %+v", len(got), len(timeline), got)
+	}
+	for i, want := range timeline {
+		g := *got[i]
+		if g.ID == 0 || (i > 0 && g.ID <= got[i-1].ID) {
+			t.Fatalf("event %d id = %d, want increasing ids", i, g.ID)
+		}
+		if !g.CreatedAt.Equal(want.CreatedAt) {
+			t.Fatalf("event %d created at %s, want %s", i, g.CreatedAt, want.CreatedAt)
+		}
+		g.ID, g.CreatedAt = 0, want.CreatedAt
+		if g != *want {
+			t.Fatalf("event %d = %+v, want %+v", i, g, *want)
+		}
+	}
+
+	if got, err := s.Events.ListByPR(ctx, "missing"); err != nil || len(got) != 0 {
+		t.Fatalf("list of missing PR = %+v, %v", got, err)
+	}
+
+	// a batch with an unknown PR is rejected as a whole
+	err = s.Events.Append(ctx,
+		&modelra.Event{PrId: "pr-2", Type: modelra.EventUnassigned, UserId: "r1", CreatedAt: t0},
+		&modelra.Event{PrId: "missing", Type: modelra.EventAssigned, UserId: "r1", CreatedAt: t0},
+	)
+	wantErr(t, "append to missing PR", err, model.ErrNotFound)
+	if got, err := s.Events.ListByPR(ctx, "pr-2"); err != nil || len(got) != 1 {
+		t.Fatalf("pr-2 after rejected batch = %+v, %v", got, err)
+	}
+}
+
+// BenchReassignOpenFrom measures ReassignOpenFrom over a team with hundreds of open PRs:
+// 500 PRs with two reviewers each, a quarter of the 40 reviewers leaving. Every iteration
+// runs in a rolled back transaction, so all of them start from the same state.
+func BenchReassignOpenFrom(b *testing.B, s *Store) {
+	const prs, reviewers, leaving = 500, 40, 10
+
+	ctx := context.Background()
+	ids := make([]string, reviewers)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("r-%02d", i)
+	}
+	err := s.Tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.Teams.Create(ctx, newTeam("backend")); err != nil {
+			return err
+		}
+		addUsers(ctx, b, s, "backend", 0, "author")
+		addUsers(ctx, b, s, "backend", 40, ids...)
+		for i := range prs {
+			pr, err := s.PRs.Create(ctx, &modelpr.PullRequest{
+				ID: fmt.Sprintf("pr-%03d", i), Title: "feature", AuthorID: "author", Status: modelpr.PROpen,
+			})
+			if err != nil {
+				return err
+			}
+			for _, id := range []string{ids[2*i%reviewers], ids[(2*i+1)%reviewers]} {
+				if err := s.Reviews.Add(ctx, &modelra.ReviewerAssignment{PrId: pr.ID, UserId: id, AssignedAt: t0}); err != nil {
+					return err
+				}
+			}
+		}
+		_, err := s.Users.DeactivateMembers(ctx, "backend", ids[:leaving])
+		return err
+	})
+	if err != nil {
+		b.Fatalf("seed: %v", err)
+	}
+
+	for b.Loop() {
+		err := s.Tx.WithinTransaction(ctx, func(ctx context.Context) error {
+			res, err := s.Reviews.ReassignOpenFrom(ctx, ids[:leaving], t0.Add(time.Hour))
+			if err != nil {
+				return err
+			}
+			if want := prs * 2 * leaving / reviewers; len(res) != want {
+				b.Fatalf("got %d reassignments, want %d", len(res), want)
+			}
+			return errAbort
+		})
+		if !errors.Is(err, errAbort) {
+			b.Fatalf("reassign: %v", err)
+		}
+	}
+}
+
+func testTxCommit(t *testing.T, s *Store) {
+	ctx := context.Background()
+
+	err := s.Tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.Teams.Create(ctx, newTeam("backend")); err != nil {
+			return err
+		}
+		// the transaction sees its own writes: repositories pick it up from ctx
+		if _, err := s.Teams.GetByName(ctx, "backend"); err != nil {
+			return err
+		}
+		return s.Users.Upsert(ctx, &modeluser.User{ID: "u1", Username: "alice", TeamName: "backend", IsActive: true})
+	})
+	if err != nil {
+		t.Fatalf("transaction: %v", err)
+	}
+
+	if _, err := s.Users.GetByID(ctx, "u1"); err != nil {
+		t.Fatalf("get committed user: %v", err)
+	}
+}
+
+func testTxRollback(t *testing.T, s *Store) {
+	seed(t, s)
+	ctx := context.Background()
+
+	err := s.Tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.Teams.Create(ctx, newTeam("frontend")); err != nil {
+			return err
+		}
+		if err := s.Users.Upsert(ctx, &modeluser.User{ID: "f1", Username: "f1", TeamName: "frontend"}); err != nil {
+			return err
+		}
+		if _, err := s.PRs.MarkMerged(ctx, "pr-1"); err != nil {
+			return err
+		}
+		if err := s.Reviews.Add(ctx, &modelra.ReviewerAssignment{PrId: "pr-1", UserId: "r1", AssignedAt: t0}); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	wantErr(t, "transaction", err, errAbort)
+
+	_, err = s.Teams.GetByName(ctx, "frontend")
+	wantErr(t, "get rolled back team", err, model.ErrNotFound)
+	_, err = s.Users.GetByID(ctx, "f1")
+	wantErr(t, "get rolled back user", err, model.ErrNotFound)
+	if pr, err := s.PRs.GetByID(ctx, "pr-1"); err != nil || pr.Status != modelpr.PROpen || pr.MergedAt != nil {
+		t.Fatalf("PR after rollback = %+v, %v", pr, err)
+	}
+	if got := reviewerIDs(ctx, t, s, "pr-1"); len(got) != 0 {
+		t.Fatalf("reviewers after rollback = %v, want none", got)
+	}
+}
+
+func testTxSavepoint(t *testing.T, s *Store) {
+	seed(t, s)
+	ctx := context.Background()
+
+	err := s.Tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.Reviews.Add(ctx, &modelra.ReviewerAssignment{PrId: "pr-1", UserId: "r1", AssignedAt: t0}); err != nil {
+			return err
+		}
+		err := s.Tx.WithinTransaction(ctx, func(ctx context.Context) error {
+			if err := s.Reviews.Add(ctx, &modelra.ReviewerAssignment{PrId: "pr-1", UserId: "r2", AssignedAt: t0}); err != nil {
+				return err
+			}
+			// a failed statement inside the savepoint does not break the outer transaction
+			if err := s.Reviews.Add(ctx, &modelra.ReviewerAssignment{PrId: "pr-1", UserId: "r1", AssignedAt: t0}); err != nil {
+				return err
+			}
+			return nil
+		})
+		wantErr(t, "savepoint", err, model.ErrAlreadyExists)
+
+		if got := reviewerIDs(ctx, t, s, "pr-1"); !slices.Equal(got, []string{"r1"}) {
+			t.Fatalf("reviewers inside transaction = %v, want [r1]", got)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("transaction: %v", err)
+	}
+
+	if got := reviewerIDs(ctx, t, s, "pr-1"); !slices.Equal(got, []string{"r1"}) {
+		t.Fatalf("reviewers = %v, want [r1]", got)
+	}
+}
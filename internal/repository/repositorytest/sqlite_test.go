@@ -0,0 +1,41 @@
+package repositorytest_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/zxchelik/avito-test-task/internal/infrastructure/sqlite"
+	absenceRep "github.com/zxchelik/avito-test-task/internal/repository/absence"
+	eventRep "github.com/zxchelik/avito-test-task/internal/repository/assignment_event"
+	prRep "github.com/zxchelik/avito-test-task/internal/repository/pull_request"
+	"github.com/zxchelik/avito-test-task/internal/repository/repositorytest"
+	raRep "github.com/zxchelik/avito-test-task/internal/repository/reviewer_assignment"
+	teamRep "github.com/zxchelik/avito-test-task/internal/repository/team"
+	userRep "github.com/zxchelik/avito-test-task/internal/repository/user"
+)
+
+func newSQLiteStore(tb testing.TB) *repositorytest.Store {
+	db, err := sqlite.Open(context.Background(), filepath.Join(tb.TempDir(), "review.db"))
+	if err != nil {
+		tb.Fatalf("open: %v", err)
+	}
+	tb.Cleanup(func() { _ = db.Close() })
+	return &repositorytest.Store{
+		Tx:       sqlite.NewTxManager(db),
+		Teams:    teamRep.NewSQLiteRepository(db),
+		Users:    userRep.NewSQLiteRepository(db),
+		PRs:      prRep.NewSQLiteRepository(db),
+		Reviews:  raRep.NewSQLiteRepository(db),
+		Absences: absenceRep.NewSQLiteRepository(db),
+		Events:   eventRep.NewSQLiteRepository(db),
+	}
+}
+
+func TestSQLite(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) *repositorytest.Store { return newSQLiteStore(t) })
+}
+
+func BenchmarkSQLiteReassignOpenFrom(b *testing.B) {
+	repositorytest.BenchReassignOpenFrom(b, newSQLiteStore(b))
+}
@@ -0,0 +1,378 @@
+package reviewer_assignment
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/zxchelik/avito-test-task/internal/infrastructure/memory"
+	"github.com/zxchelik/avito-test-task/internal/model"
+	modelpr "github.com/zxchelik/avito-test-task/internal/model/pull_request"
+	reva "github.com/zxchelik/avito-test-task/internal/model/reviewer_assignment"
+)
+
+type MemoryRepository struct {
+	db *memory.DB
+}
+
+func NewMemoryRepository(db *memory.DB) *MemoryRepository {
+	return &MemoryRepository{db: db}
+}
+
+// ListByPR returns reviewers assigned to a pull request.
+func (r *MemoryRepository) ListByPR(ctx context.Context, prID string) ([]*reva.ReviewerAssignment, error) {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	return assignments(tables.Reviewers[prID]), nil
+}
+
+// ListByPRs returns reviewers of several PRs at once, grouped by PR id.
+func (r *MemoryRepository) ListByPRs(ctx context.Context, prIDs []string) (map[string][]*reva.ReviewerAssignment, error) {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	res := make(map[string][]*reva.ReviewerAssignment, len(prIDs))
+	for _, id := range prIDs {
+		if list := assignments(tables.Reviewers[id]); len(list) > 0 {
+			res[id] = list
+		}
+	}
+
+	return res, nil
+}
+
+// Add assigns a reviewer to a PR.
+// Returns:
+//   - model.ErrAlreadyExists — if the reviewer is already assigned
+//   - model.ErrNotFound — if PR or user does not exist
+func (r *MemoryRepository) Add(ctx context.Context, a *reva.ReviewerAssignment) error {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	if _, ok := tables.Reviewers[a.PrId][a.UserId]; ok {
+		return model.ErrAlreadyExists
+	}
+	if _, ok := tables.PullRequests[a.PrId]; !ok {
+		return model.ErrNotFound
+	}
+	if _, ok := tables.Users[a.UserId]; !ok {
+		return model.ErrNotFound
+	}
+
+	put(tables, &reva.ReviewerAssignment{
+		PrId:       a.PrId,
+		UserId:     a.UserId,
+		AssignedAt: memory.Timestamp(a.AssignedAt),
+		Fallback:   a.Fallback,
+	})
+	return nil
+}
+
+// Remove removes reviewer from PR.
+// Returns reva.ErrReviewerNotFoundInPR if reviewer not assigned.
+func (r *MemoryRepository) Remove(ctx context.Context, prID, userID string) error {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	if _, ok := tables.Reviewers[prID][userID]; !ok {
+		return reva.ErrReviewerNotFoundInPR
+	}
+	delete(tables.Reviewers[prID], userID)
+
+	return nil
+}
+
+// Replace atomically replaces reviewer oldUserID with next on the same PR.
+// Returns:
+//   - reva.ErrReviewerNotFoundInPR — old reviewer wasn't assigned
+//   - reva.ErrReviewerSameAsOld — new == old
+//   - reva.ErrReviewerDuplication — new reviewer is already assigned
+//   - model.ErrNotFound — new reviewer does not exist
+func (r *MemoryRepository) Replace(ctx context.Context, oldUserID string, next *reva.ReviewerAssignment) error {
+	if oldUserID == next.UserId {
+		return reva.ErrReviewerSameAsOld
+	}
+
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	byUser := tables.Reviewers[next.PrId]
+	if _, ok := byUser[oldUserID]; !ok {
+		return reva.ErrReviewerNotFoundInPR
+	}
+	if _, ok := byUser[next.UserId]; ok {
+		return reva.ErrReviewerDuplication
+	}
+	if _, ok := tables.Users[next.UserId]; !ok {
+		return model.ErrNotFound
+	}
+
+	delete(byUser, oldUserID)
+	put(tables, &reva.ReviewerAssignment{
+		PrId:       next.PrId,
+		UserId:     next.UserId,
+		AssignedAt: memory.Timestamp(next.AssignedAt),
+		Fallback:   next.Fallback,
+	})
+	return nil
+}
+
+// SetVerdict stores the reviewer's latest verdict on a PR.
+// Returns reva.ErrReviewerNotFoundInPR if reviewer is not assigned to the PR.
+func (r *MemoryRepository) SetVerdict(
+	ctx context.Context,
+	prID, userID string,
+	verdict reva.Verdict,
+	at time.Time,
+) (*reva.ReviewerAssignment, error) {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	ra, ok := tables.Reviewers[prID][userID]
+	if !ok {
+		return nil, reva.ErrReviewerNotFoundInPR
+	}
+	verdictAt := memory.Timestamp(at)
+	ra.Verdict = verdict
+	ra.VerdictAt = &verdictAt
+
+	c := *ra
+	return &c, nil
+}
+
+// ListSLAPending returns assignments on open PRs that have no verdict and no SLA
+// breach recorded yet, whose author team defines a review SLA.
+// Wall-clock time is used as a prefilter only: business time never exceeds it,
+// so the caller still has to check SLAPending.Breached.
+func (r *MemoryRepository) ListSLAPending(ctx context.Context, now time.Time) ([]*reva.SLAPending, error) {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	var res []*reva.SLAPending
+	for prID, byUser := range tables.Reviewers {
+		pr := tables.PullRequests[prID]
+		if pr.Status != modelpr.PROpen {
+			continue
+		}
+		team := tables.Teams[tables.Users[pr.AuthorID].TeamName]
+		if team.ReviewSLAWeekdayHours <= 0 {
+			continue
+		}
+		deadline := now.Add(-time.Duration(team.ReviewSLAWeekdayHours) * time.Hour)
+
+		for _, ra := range byUser {
+			if ra.Verdict != reva.VerdictNone || ra.SLABreachedAt != nil || ra.AssignedAt.After(deadline) {
+				continue
+			}
+			res = append(res, &reva.SLAPending{
+				ReviewerAssignment: reva.ReviewerAssignment{
+					PrId:       ra.PrId,
+					UserId:     ra.UserId,
+					AssignedAt: ra.AssignedAt,
+					Fallback:   ra.Fallback,
+				},
+				TeamName:     team.Name,
+				SLAHours:     team.ReviewSLAWeekdayHours,
+				AutoReassign: team.SLAAutoReassign,
+			})
+		}
+	}
+
+	slices.SortFunc(res, func(a, b *reva.SLAPending) int {
+		return cmp.Or(
+			a.AssignedAt.Compare(b.AssignedAt),
+			strings.Compare(a.PrId, b.PrId),
+			strings.Compare(a.UserId, b.UserId),
+		)
+	})
+
+	return res, nil
+}
+
+// MarkSLABreached records an SLA breach on the assignment made at assignedAt.
+// Returns false if the assignment has been replaced, got a verdict or was
+// already marked in the meantime.
+func (r *MemoryRepository) MarkSLABreached(
+	ctx context.Context,
+	prID, userID string,
+	assignedAt, at time.Time,
+) (bool, error) {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	ra, ok := tables.Reviewers[prID][userID]
+	if !ok || !ra.AssignedAt.Equal(assignedAt) || ra.Verdict != reva.VerdictNone || ra.SLABreachedAt != nil {
+		return false, nil
+	}
+	breachedAt := memory.Timestamp(at)
+	ra.SLABreachedAt = &breachedAt
+
+	return true, nil
+}
+
+// ListPRIDsByReviewer returns PR IDs where user is assigned as reviewer.
+func (r *MemoryRepository) ListPRIDsByReviewer(ctx context.Context, userID string) ([]string, error) {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	var list []*reva.ReviewerAssignment
+	for _, byUser := range tables.Reviewers {
+		if ra, ok := byUser[userID]; ok {
+			list = append(list, ra)
+		}
+	}
+	slices.SortFunc(list, func(a, b *reva.ReviewerAssignment) int {
+		return cmp.Or(b.AssignedAt.Compare(a.AssignedAt), strings.Compare(a.PrId, b.PrId))
+	})
+
+	var prIDs []string
+	for _, ra := range list {
+		prIDs = append(prIDs, ra.PrId)
+	}
+
+	return prIDs, nil
+}
+
+// CountOpenByReviewers returns the number of OPEN PRs each user is assigned to.
+// Users without open reviews are absent from the result map.
+func (r *MemoryRepository) CountOpenByReviewers(ctx context.Context, userIDs []string) (map[string]int, error) {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	all := openLoad(tables)
+	load := make(map[string]int, len(userIDs))
+	for _, id := range userIDs {
+		if n := all[id]; n > 0 {
+			load[id] = n
+		}
+	}
+
+	return load, nil
+}
+
+// LastAssignedAt returns the latest assigned_at of each user across all PRs.
+// Users that were never assigned are absent from the result map.
+func (r *MemoryRepository) LastAssignedAt(ctx context.Context, userIDs []string) (map[string]time.Time, error) {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	last := make(map[string]time.Time, len(userIDs))
+	for _, byUser := range tables.Reviewers {
+		for _, id := range userIDs {
+			ra, ok := byUser[id]
+			if !ok {
+				continue
+			}
+			if prev, seen := last[id]; !seen || ra.AssignedAt.After(prev) {
+				last[id] = ra.AssignedAt
+			}
+		}
+	}
+
+	return last, nil
+}
+
+// ReassignOpenFrom replaces the given users on every OPEN PR at once,
+// choosing the replacements with planReassignments like the SQL backends.
+//
+// Slots that could not be covered keep the old reviewer and carry
+// reva.ErrNoReviewerCandidatesLeft or reva.ErrReviewersAtCapacity in Reason.
+func (r *MemoryRepository) ReassignOpenFrom(ctx context.Context, userIDs []string, at time.Time) ([]*reva.Reassignment, error) {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	// reviews: all reviewers of the OPEN PRs the replaced users sit on.
+	var reviews []openReview
+	teams := make(map[string]bool)
+	for prID, byUser := range tables.Reviewers {
+		pr := tables.PullRequests[prID]
+		if pr.Status != modelpr.PROpen || !slices.ContainsFunc(userIDs, func(id string) bool {
+			_, ok := byUser[id]
+			return ok
+		}) {
+			continue
+		}
+		team := tables.Users[pr.AuthorID].TeamName
+		teams[team] = true
+		for id := range byUser {
+			reviews = append(reviews, openReview{prID: prID, userID: id, authorID: pr.AuthorID, teamName: team})
+		}
+	}
+
+	// candidates: active, present members of the author teams.
+	load := openLoad(tables)
+	now := memory.Timestamp(at)
+	var candidates []reassignCandidate
+	for _, u := range tables.Users {
+		if !teams[u.TeamName] || !u.IsActive || absentAt(tables, u.ID, now) {
+			continue
+		}
+		candidates = append(candidates, reassignCandidate{
+			id:       u.ID,
+			teamName: u.TeamName,
+			open:     load[u.ID],
+			cap:      u.Capacity(tables.Teams[u.TeamName].DefaultMaxOpenReviews),
+		})
+	}
+
+	// covered slots move to the new reviewer with a clean assignment.
+	res := planReassignments(userIDs, reviews, candidates)
+	for _, ra := range res {
+		if !ra.Replaced() {
+			continue
+		}
+		delete(tables.Reviewers[ra.PrId], ra.OldUserId)
+		put(tables, &reva.ReviewerAssignment{PrId: ra.PrId, UserId: ra.NewUserId, AssignedAt: now})
+	}
+
+	return res, nil
+}
+
+// openLoad counts OPEN PRs per assigned reviewer.
+func openLoad(tables *memory.Tables) map[string]int {
+	load := make(map[string]int)
+	for prID, byUser := range tables.Reviewers {
+		if tables.PullRequests[prID].Status != modelpr.PROpen {
+			continue
+		}
+		for id := range byUser {
+			load[id]++
+		}
+	}
+	return load
+}
+
+// absentAt reports whether the user has a non-cancelled absence covering at.
+func absentAt(tables *memory.Tables, userID string, at time.Time) bool {
+	for _, a := range tables.Absences {
+		if a.UserID == userID && a.Covers(at) {
+			return true
+		}
+	}
+	return false
+}
+
+// assignments returns copies of a PR's reviewers ordered by assignment time.
+func assignments(byUser map[string]*reva.ReviewerAssignment) []*reva.ReviewerAssignment {
+	var res []*reva.ReviewerAssignment
+	for _, ra := range byUser {
+		c := *ra
+		res = append(res, &c)
+	}
+	slices.SortFunc(res, func(a, b *reva.ReviewerAssignment) int {
+		return cmp.Or(a.AssignedAt.Compare(b.AssignedAt), strings.Compare(a.UserId, b.UserId))
+	})
+	return res
+}
+
+func put(tables *memory.Tables, ra *reva.ReviewerAssignment) {
+	byUser, ok := tables.Reviewers[ra.PrId]
+	if !ok {
+		byUser = make(map[string]*reva.ReviewerAssignment)
+		tables.Reviewers[ra.PrId] = byUser
+	}
+	byUser[ra.UserId] = ra
+}
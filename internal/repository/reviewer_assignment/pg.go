@@ -2,10 +2,14 @@ package reviewer_assignment
 
 import (
 	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/zxchelik/avito-test-task/internal/infrastructure/pg"
 	"github.com/zxchelik/avito-test-task/internal/model"
 	reva "github.com/zxchelik/avito-test-task/internal/model/reviewer_assignment"
+	"slices"
 	"time"
 )
 
@@ -22,7 +26,7 @@ func (r *PGRepository) ListByPR(ctx context.Context, prID string) ([]*reva.Revie
 	q := pg.GetQuerierFromContext(ctx, r.pool)
 
 	const query = `
-		SELECT pr_id, user_id, assigned_at
+		SELECT pr_id, user_id, assigned_at, is_fallback, COALESCE(verdict::text, ''), verdict_at, sla_breached_at
 		FROM pull_request_reviewers
 		WHERE pr_id = $1
 		ORDER BY assigned_at
@@ -37,7 +41,9 @@ func (r *PGRepository) ListByPR(ctx context.Context, prID string) ([]*reva.Revie
 	var res []*reva.ReviewerAssignment
 	for rows.Next() {
 		var ra reva.ReviewerAssignment
-		if err := rows.Scan(&ra.PrId, &ra.UserId, &ra.AssignedAt); err != nil {
+		if err := rows.Scan(
+			&ra.PrId, &ra.UserId, &ra.AssignedAt, &ra.Fallback, &ra.Verdict, &ra.VerdictAt, &ra.SLABreachedAt,
+		); err != nil {
 			return nil, err
 		}
 		res = append(res, &ra)
@@ -49,18 +55,60 @@ func (r *PGRepository) ListByPR(ctx context.Context, prID string) ([]*reva.Revie
 	return res, nil
 }
 
+// ListByPRs returns reviewers of several PRs at once, grouped by PR id.
+func (r *PGRepository) ListByPRs(ctx context.Context, prIDs []string) (map[string][]*reva.ReviewerAssignment, error) {
+	q := pg.GetQuerierFromContext(ctx, r.pool)
+
+	const query = `
+		SELECT pr_id, user_id, assigned_at, is_fallback, COALESCE(verdict::text, ''), verdict_at, sla_breached_at
+		FROM pull_request_reviewers
+		WHERE pr_id = ANY($1)
+		ORDER BY pr_id, assigned_at
+	`
+
+	rows, err := q.Query(ctx, query, prIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make(map[string][]*reva.ReviewerAssignment, len(prIDs))
+	for rows.Next() {
+		var ra reva.ReviewerAssignment
+		if err := rows.Scan(
+			&ra.PrId, &ra.UserId, &ra.AssignedAt, &ra.Fallback, &ra.Verdict, &ra.VerdictAt, &ra.SLABreachedAt,
+		); err != nil {
+			return nil, err
+		}
+		res[ra.PrId] = append(res[ra.PrId], &ra)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
 // Add assigns a reviewer to a PR.
-// Returns model.ErrAlreadyExists if conflict occurs (already assigned).
-func (r *PGRepository) Add(ctx context.Context, prID, userID string, assignedAt time.Time) error {
+// Returns:
+//   - model.ErrAlreadyExists — if conflict occurs (already assigned)
+//   - model.ErrNotFound — if PR or user does not exist (FK violation)
+func (r *PGRepository) Add(ctx context.Context, a *reva.ReviewerAssignment) error {
 	q := pg.GetQuerierFromContext(ctx, r.pool)
 
 	const query = `
-		INSERT INTO pull_request_reviewers (pr_id, user_id, assigned_at)
-		VALUES ($1, $2, $3)
+		INSERT INTO pull_request_reviewers (pr_id, user_id, assigned_at, is_fallback)
+		VALUES ($1, $2, $3, $4)
 	`
-	ct, err := q.Exec(ctx, query, prID, userID, assignedAt)
-	if ct.RowsAffected() == 0 {
-		return model.ErrAlreadyExists
+	_, err := q.Exec(ctx, query, a.PrId, a.UserId, a.AssignedAt, a.Fallback)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505": // unique_violation
+			return model.ErrAlreadyExists
+		case "23503": // foreign_key_violation
+			return model.ErrNotFound
+		}
 	}
 
 	return err
@@ -87,12 +135,14 @@ func (r *PGRepository) Remove(ctx context.Context, prID, userID string) error {
 	return nil
 }
 
-// Replace atomically replaces one reviewer with another in a transaction.
+// Replace atomically replaces reviewer oldUserID with next on the same PR.
 // Returns:
 //   - reva.ErrReviewerNotFoundInPR — old reviewer wasn't assigned
 //   - reva.ErrReviewerSameAsOld — new == old
-func (r *PGRepository) Replace(ctx context.Context, prID, oldUserID, newUserID string, assignedAt time.Time) error {
-	if oldUserID == newUserID {
+//   - reva.ErrReviewerDuplication — new reviewer is already assigned
+//   - model.ErrNotFound — new reviewer does not exist
+func (r *PGRepository) Replace(ctx context.Context, oldUserID string, next *reva.ReviewerAssignment) error {
+	if oldUserID == next.UserId {
 		return reva.ErrReviewerSameAsOld
 	}
 
@@ -104,12 +154,21 @@ WITH deleted AS (
     WHERE pr_id = $1 AND user_id = $2
     RETURNING 1
 )
-INSERT INTO pull_request_reviewers (pr_id, user_id, assigned_at)
-SELECT $1, $3, $4
+INSERT INTO pull_request_reviewers (pr_id, user_id, assigned_at, is_fallback)
+SELECT $1, $3, $4, $5
 WHERE EXISTS (SELECT 1 FROM deleted)
 `
 
-	res, err := q.Exec(ctx, query, prID, oldUserID, newUserID, assignedAt)
+	res, err := q.Exec(ctx, query, next.PrId, oldUserID, next.UserId, next.AssignedAt, next.Fallback)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505": // unique_violation
+			return reva.ErrReviewerDuplication
+		case "23503": // foreign_key_violation
+			return model.ErrNotFound
+		}
+	}
 	if err != nil {
 		return err
 	}
@@ -122,6 +181,106 @@ WHERE EXISTS (SELECT 1 FROM deleted)
 	return nil
 }
 
+// SetVerdict stores the reviewer's latest verdict on a PR.
+// Returns reva.ErrReviewerNotFoundInPR if reviewer is not assigned to the PR.
+func (r *PGRepository) SetVerdict(
+	ctx context.Context,
+	prID, userID string,
+	verdict reva.Verdict,
+	at time.Time,
+) (*reva.ReviewerAssignment, error) {
+	q := pg.GetQuerierFromContext(ctx, r.pool)
+	const query = `
+		UPDATE pull_request_reviewers
+		SET verdict = $3::review_verdict, verdict_at = $4
+		WHERE pr_id = $1 AND user_id = $2
+		RETURNING pr_id, user_id, assigned_at, is_fallback, verdict::text, verdict_at, sla_breached_at
+	`
+
+	var ra reva.ReviewerAssignment
+	err := q.QueryRow(ctx, query, prID, userID, string(verdict), at).Scan(
+		&ra.PrId, &ra.UserId, &ra.AssignedAt, &ra.Fallback, &ra.Verdict, &ra.VerdictAt, &ra.SLABreachedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, reva.ErrReviewerNotFoundInPR
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &ra, nil
+}
+
+// ListSLAPending returns assignments on open PRs that have no verdict and no SLA
+// breach recorded yet, whose author team defines a review SLA.
+// Wall-clock time is used as a prefilter only: business time never exceeds it,
+// so the caller still has to check SLAPending.Breached.
+func (r *PGRepository) ListSLAPending(ctx context.Context, now time.Time) ([]*reva.SLAPending, error) {
+	q := pg.GetQuerierFromContext(ctx, r.pool)
+
+	const query = `
+		SELECT prr.pr_id, prr.user_id, prr.assigned_at, prr.is_fallback,
+		       t.name, t.review_sla_weekday_hours, t.sla_auto_reassign
+		FROM pull_request_reviewers prr
+		JOIN pull_requests pr ON pr.id = prr.pr_id
+		JOIN users a ON a.id = pr.author_id
+		JOIN teams t ON t.name = a.team_name
+		WHERE pr.status = 'OPEN'
+		  AND prr.verdict IS NULL
+		  AND prr.sla_breached_at IS NULL
+		  AND t.review_sla_weekday_hours IS NOT NULL
+		  AND prr.assigned_at <= $1 - make_interval(hours => t.review_sla_weekday_hours)
+		ORDER BY prr.assigned_at, prr.pr_id, prr.user_id
+	`
+
+	rows, err := q.Query(ctx, query, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []*reva.SLAPending
+	for rows.Next() {
+		var p reva.SLAPending
+		if err := rows.Scan(
+			&p.PrId, &p.UserId, &p.AssignedAt, &p.Fallback,
+			&p.TeamName, &p.SLAHours, &p.AutoReassign,
+		); err != nil {
+			return nil, err
+		}
+		res = append(res, &p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// MarkSLABreached records an SLA breach on the assignment made at assignedAt.
+// Returns false if the assignment has been replaced, got a verdict or was
+// already marked in the meantime.
+func (r *PGRepository) MarkSLABreached(
+	ctx context.Context,
+	prID, userID string,
+	assignedAt, at time.Time,
+) (bool, error) {
+	q := pg.GetQuerierFromContext(ctx, r.pool)
+	const query = `
+		UPDATE pull_request_reviewers
+		SET sla_breached_at = $4
+		WHERE pr_id = $1 AND user_id = $2 AND assigned_at = $3
+		  AND verdict IS NULL AND sla_breached_at IS NULL
+	`
+
+	ct, err := q.Exec(ctx, query, prID, userID, assignedAt, at)
+	if err != nil {
+		return false, err
+	}
+
+	return ct.RowsAffected() > 0, nil
+}
+
 // ListPRIDsByReviewer returns PR IDs where user is assigned as reviewer.
 func (r *PGRepository) ListPRIDsByReviewer(ctx context.Context, userID string) ([]string, error) {
 	q := pg.GetQuerierFromContext(ctx, r.pool)
@@ -152,3 +311,205 @@ func (r *PGRepository) ListPRIDsByReviewer(ctx context.Context, userID string) (
 
 	return prIDs, nil
 }
+
+// CountOpenByReviewers returns the number of OPEN PRs each user is assigned to.
+// Users without open reviews are absent from the result map.
+func (r *PGRepository) CountOpenByReviewers(ctx context.Context, userIDs []string) (map[string]int, error) {
+	q := pg.GetQuerierFromContext(ctx, r.pool)
+	const query = `
+		SELECT prr.user_id, COUNT(*)
+		FROM pull_request_reviewers prr
+		JOIN pull_requests pr ON pr.id = prr.pr_id
+		WHERE prr.user_id = ANY($1) AND pr.status = 'OPEN'
+		GROUP BY prr.user_id
+	`
+
+	rows, err := q.Query(ctx, query, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	load := make(map[string]int, len(userIDs))
+	for rows.Next() {
+		var (
+			id    string
+			count int
+		)
+		if err := rows.Scan(&id, &count); err != nil {
+			return nil, err
+		}
+		load[id] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return load, nil
+}
+
+// LastAssignedAt returns the latest assigned_at of each user across all PRs.
+// Users that were never assigned are absent from the result map.
+func (r *PGRepository) LastAssignedAt(ctx context.Context, userIDs []string) (map[string]time.Time, error) {
+	q := pg.GetQuerierFromContext(ctx, r.pool)
+	const query = `
+		SELECT user_id, MAX(assigned_at)
+		FROM pull_request_reviewers
+		WHERE user_id = ANY($1)
+		GROUP BY user_id
+	`
+
+	rows, err := q.Query(ctx, query, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	last := make(map[string]time.Time, len(userIDs))
+	for rows.Next() {
+		var (
+			id string
+			at time.Time
+		)
+		if err := rows.Scan(&id, &at); err != nil {
+			return nil, err
+		}
+		last[id] = at
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return last, nil
+}
+
+// ReassignOpenFrom replaces the given users on every OPEN PR,
+// choosing the replacements with planReassignments like the other backends.
+//
+// The affected PR rows are locked first, in id order, the same way GetByIDForUpdate locks
+// them for Merge and Reassign: a concurrent merge either finishes before and its PR is
+// skipped, or waits until the transaction commits. Call it inside a transaction so the
+// locks are held until the end.
+//
+// Slots that could not be covered keep the old reviewer and carry
+// reva.ErrNoReviewerCandidatesLeft or reva.ErrReviewersAtCapacity in Reason.
+func (r *PGRepository) ReassignOpenFrom(ctx context.Context, userIDs []string, at time.Time) ([]*reva.Reassignment, error) {
+	q := pg.GetQuerierFromContext(ctx, r.pool)
+
+	// A separate statement: under READ COMMITTED the next one takes a fresh snapshot
+	// and sees the status a merge committed while we were waiting for its lock.
+	const lock = `
+		SELECT id
+		FROM pull_requests
+		WHERE status = 'OPEN'
+		  AND id IN (SELECT pr_id FROM pull_request_reviewers WHERE user_id = ANY($1))
+		ORDER BY id
+		FOR UPDATE
+	`
+	if _, err := q.Exec(ctx, lock, userIDs); err != nil {
+		return nil, err
+	}
+
+	const reviewsQuery = `
+		SELECT prr.pr_id, prr.user_id, pr.author_id, au.team_name
+		FROM pull_request_reviewers prr
+		JOIN pull_requests pr ON pr.id = prr.pr_id
+		JOIN users au ON au.id = pr.author_id
+		WHERE pr.status = 'OPEN'
+		  AND prr.pr_id IN (SELECT pr_id FROM pull_request_reviewers WHERE user_id = ANY($1))
+	`
+	rows, err := q.Query(ctx, reviewsQuery, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		reviews []openReview
+		teams   []string
+	)
+	for rows.Next() {
+		var rv openReview
+		if err := rows.Scan(&rv.prID, &rv.userID, &rv.authorID, &rv.teamName); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		reviews = append(reviews, rv)
+		if !slices.Contains(teams, rv.teamName) {
+			teams = append(teams, rv.teamName)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(reviews) == 0 {
+		return nil, nil
+	}
+
+	const candidatesQuery = `
+		WITH load AS (
+		    SELECT prr.user_id, COUNT(*) AS open
+		    FROM pull_request_reviewers prr
+		    JOIN pull_requests pr ON pr.id = prr.pr_id
+		    WHERE pr.status = 'OPEN'
+		    GROUP BY prr.user_id
+		)
+		SELECT u.id,
+		       u.team_name,
+		       COALESCE(l.open, 0),
+		       COALESCE(u.max_open_reviews, t.default_max_open_reviews, 0)
+		FROM users u
+		JOIN teams t ON t.name = u.team_name
+		LEFT JOIN load l ON l.user_id = u.id
+		WHERE u.is_active
+		  AND u.team_name = ANY($1)
+		  AND NOT EXISTS (
+		      SELECT 1 FROM user_absences a
+		      WHERE a.user_id = u.id
+		        AND a.cancelled_at IS NULL
+		        AND a.starts_at <= $2 AND a.ends_at > $2
+		  )
+	`
+	rows, err = q.Query(ctx, candidatesQuery, teams, at)
+	if err != nil {
+		return nil, err
+	}
+	var candidates []reassignCandidate
+	for rows.Next() {
+		var c reassignCandidate
+		if err := rows.Scan(&c.id, &c.teamName, &c.open, &c.cap); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		candidates = append(candidates, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	res := planReassignments(userIDs, reviews, candidates)
+	var prIDs, oldIDs, newIDs []string
+	for _, ra := range res {
+		if ra.Replaced() {
+			prIDs = append(prIDs, ra.PrId)
+			oldIDs = append(oldIDs, ra.OldUserId)
+			newIDs = append(newIDs, ra.NewUserId)
+		}
+	}
+	if len(prIDs) == 0 {
+		return res, nil
+	}
+
+	const updateQuery = `
+		UPDATE pull_request_reviewers prr
+		SET user_id = n.new_user_id, assigned_at = $4, is_fallback = FALSE,
+		    verdict = NULL, verdict_at = NULL, sla_breached_at = NULL
+		FROM unnest($1::text[], $2::text[], $3::text[]) AS n(pr_id, old_user_id, new_user_id)
+		WHERE prr.pr_id = n.pr_id AND prr.user_id = n.old_user_id
+	`
+	if _, err := q.Exec(ctx, updateQuery, prIDs, oldIDs, newIDs, at); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
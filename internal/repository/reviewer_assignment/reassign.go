@@ -0,0 +1,119 @@
+package reviewer_assignment
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	reva "github.com/zxchelik/avito-test-task/internal/model/reviewer_assignment"
+)
+
+// openReview — a reviewer of an OPEN PR on which at least one of the replaced users sits.
+type openReview struct {
+	prID     string
+	userID   string
+	authorID string
+	teamName string
+}
+
+// reassignCandidate — an active, present member of an author team.
+type reassignCandidate struct {
+	id       string
+	teamName string
+	open     int
+	cap      int // 0 — no limit
+}
+
+// hasRoom reports whether the candidate can take one more review after taking taken.
+func (c *reassignCandidate) hasRoom(taken int) bool {
+	return c.cap == 0 || c.open+taken < c.cap
+}
+
+// planReassignments picks a replacement for every review of userIDs in reviews;
+// the same plan is used by every backend, so they hand out the same replacements.
+//
+// Replacements are candidates of the PR author's team who are not the author and not yet
+// assigned to the PR. Candidates with room are ordered by current load and handed out
+// round-robin across the team's PRs, so the load spreads instead of landing on the single
+// least loaded member: the rotation of the team's n-th PR starts at its n-th candidate.
+// Each slot takes the first candidate of the rotation that still has room, so a full
+// candidate is skipped in favour of the next one instead of failing the slot.
+//
+// Slots that could not be covered have an empty NewUserId and carry
+// reva.ErrReviewersAtCapacity if some candidate would fit but for the limit, or
+// reva.ErrNoReviewerCandidatesLeft otherwise. The result is ordered by PR and old user id.
+func planReassignments(userIDs []string, reviews []openReview, candidates []reassignCandidate) []*reva.Reassignment {
+	slices.SortFunc(reviews, func(a, b openReview) int {
+		return cmp.Or(strings.Compare(a.prID, b.prID), strings.Compare(a.userID, b.userID))
+	})
+	replaced := make(map[string]bool, len(userIDs))
+	for _, id := range userIDs {
+		replaced[id] = true
+	}
+
+	onPR := make(map[string]map[string]bool)
+	for _, r := range reviews {
+		if onPR[r.prID] == nil {
+			onPR[r.prID] = make(map[string]bool)
+		}
+		onPR[r.prID][r.userID] = true
+	}
+
+	// rotation: the team's candidates with room, by (open, id); members: all of them.
+	rotation := make(map[string][]*reassignCandidate)
+	members := make(map[string][]*reassignCandidate)
+	for i := range candidates {
+		c := &candidates[i]
+		members[c.teamName] = append(members[c.teamName], c)
+		if c.hasRoom(0) {
+			rotation[c.teamName] = append(rotation[c.teamName], c)
+		}
+	}
+	for _, team := range rotation {
+		slices.SortFunc(team, func(a, b *reassignCandidate) int {
+			return cmp.Or(cmp.Compare(a.open, b.open), strings.Compare(a.id, b.id))
+		})
+	}
+
+	var (
+		res     []*reva.Reassignment
+		taken   = make(map[string]int)
+		prNo    = make(map[string]int) // PRs of the team seen so far
+		prevPR  string
+		rotFrom int
+	)
+	for _, r := range reviews {
+		if !replaced[r.userID] {
+			continue
+		}
+		if r.prID != prevPR {
+			prevPR = r.prID
+			rotFrom = prNo[r.teamName]
+			prNo[r.teamName]++
+		}
+
+		eligible := func(c *reassignCandidate) bool {
+			return c.id != r.authorID && !onPR[r.prID][c.id]
+		}
+		ra := &reva.Reassignment{PrId: r.prID, OldUserId: r.userID}
+		team := rotation[r.teamName]
+		for k := range team {
+			c := team[(rotFrom+k)%len(team)]
+			if eligible(c) && c.hasRoom(taken[c.id]) {
+				ra.NewUserId = c.id
+				taken[c.id]++
+				onPR[r.prID][c.id] = true
+				break
+			}
+		}
+		if !ra.Replaced() {
+			ra.Reason = reva.ErrNoReviewerCandidatesLeft
+			if slices.ContainsFunc(members[r.teamName], eligible) {
+				ra.Reason = reva.ErrReviewersAtCapacity
+			}
+		}
+		res = append(res, ra)
+	}
+
+	return res
+}
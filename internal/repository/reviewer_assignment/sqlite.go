@@ -0,0 +1,497 @@
+package reviewer_assignment
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/zxchelik/avito-test-task/internal/infrastructure/sqlite"
+	"github.com/zxchelik/avito-test-task/internal/model"
+	reva "github.com/zxchelik/avito-test-task/internal/model/reviewer_assignment"
+)
+
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteRepository(db *sql.DB) *SQLiteRepository {
+	return &SQLiteRepository{db: db}
+}
+
+// ListByPR returns reviewers assigned to a pull request.
+func (r *SQLiteRepository) ListByPR(ctx context.Context, prID string) ([]*reva.ReviewerAssignment, error) {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+
+	const query = `
+		SELECT pr_id, user_id, assigned_at, is_fallback, COALESCE(verdict, ''), verdict_at, sla_breached_at
+		FROM pull_request_reviewers
+		WHERE pr_id = ?1
+		ORDER BY assigned_at
+	`
+
+	rows, err := q.QueryContext(ctx, query, prID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []*reva.ReviewerAssignment
+	for rows.Next() {
+		var ra reva.ReviewerAssignment
+		if err := rows.Scan(scanTargets(&ra)...); err != nil {
+			return nil, err
+		}
+		res = append(res, &ra)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ListByPRs returns reviewers of several PRs at once, grouped by PR id.
+func (r *SQLiteRepository) ListByPRs(ctx context.Context, prIDs []string) (map[string][]*reva.ReviewerAssignment, error) {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+
+	const query = `
+		SELECT pr_id, user_id, assigned_at, is_fallback, COALESCE(verdict, ''), verdict_at, sla_breached_at
+		FROM pull_request_reviewers
+		WHERE pr_id IN (SELECT value FROM json_each(?1))
+		ORDER BY pr_id, assigned_at
+	`
+
+	rows, err := q.QueryContext(ctx, query, sqlite.Array[string](prIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make(map[string][]*reva.ReviewerAssignment, len(prIDs))
+	for rows.Next() {
+		var ra reva.ReviewerAssignment
+		if err := rows.Scan(scanTargets(&ra)...); err != nil {
+			return nil, err
+		}
+		res[ra.PrId] = append(res[ra.PrId], &ra)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Add assigns a reviewer to a PR.
+// Returns:
+//   - model.ErrAlreadyExists — if conflict occurs (already assigned)
+//   - model.ErrNotFound — if PR or user does not exist (FK violation)
+func (r *SQLiteRepository) Add(ctx context.Context, a *reva.ReviewerAssignment) error {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+
+	const query = `
+		INSERT INTO pull_request_reviewers (pr_id, user_id, assigned_at, is_fallback)
+		VALUES (?1, ?2, ?3, ?4)
+	`
+	_, err := q.ExecContext(ctx, query, a.PrId, a.UserId, sqlite.Time(a.AssignedAt), a.Fallback)
+	switch {
+	case sqlite.IsUniqueViolation(err):
+		return model.ErrAlreadyExists
+	case sqlite.IsForeignKeyViolation(err):
+		return model.ErrNotFound
+	}
+
+	return err
+}
+
+// Remove removes reviewer from PR.
+// Returns reva.ErrReviewerNotFoundInPR if reviewer not assigned.
+func (r *SQLiteRepository) Remove(ctx context.Context, prID, userID string) error {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+	const query = `
+		DELETE FROM pull_request_reviewers
+		WHERE pr_id = ?1 AND user_id = ?2
+	`
+
+	res, err := q.ExecContext(ctx, query, prID, userID)
+	if err != nil {
+		return err
+	}
+
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return reva.ErrReviewerNotFoundInPR
+	}
+
+	return nil
+}
+
+// Replace atomically replaces reviewer oldUserID with next on the same PR.
+// The row is rewritten in place, which is the same as deleting the old assignment
+// and inserting a fresh one: verdict and SLA marks are reset.
+// Returns:
+//   - reva.ErrReviewerNotFoundInPR — old reviewer wasn't assigned
+//   - reva.ErrReviewerSameAsOld — new == old
+//   - reva.ErrReviewerDuplication — new reviewer is already assigned
+func (r *SQLiteRepository) Replace(ctx context.Context, oldUserID string, next *reva.ReviewerAssignment) error {
+	if oldUserID == next.UserId {
+		return reva.ErrReviewerSameAsOld
+	}
+
+	q := sqlite.GetQuerierFromContext(ctx, r.db) // достаём либо tx, либо db
+
+	const query = `
+		UPDATE pull_request_reviewers
+		SET user_id = ?3, assigned_at = ?4, is_fallback = ?5,
+		    verdict = NULL, verdict_at = NULL, sla_breached_at = NULL
+		WHERE pr_id = ?1 AND user_id = ?2
+	`
+
+	res, err := q.ExecContext(ctx, query, next.PrId, oldUserID, next.UserId, sqlite.Time(next.AssignedAt), next.Fallback)
+	switch {
+	case sqlite.IsUniqueViolation(err):
+		return reva.ErrReviewerDuplication
+	case sqlite.IsForeignKeyViolation(err):
+		return model.ErrNotFound
+	case err != nil:
+		return err
+	}
+
+	// Если ничего не затронули — значит старого ревьювера не было.
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return reva.ErrReviewerNotFoundInPR
+	}
+
+	return nil
+}
+
+// SetVerdict stores the reviewer's latest verdict on a PR.
+// Returns reva.ErrReviewerNotFoundInPR if reviewer is not assigned to the PR.
+func (r *SQLiteRepository) SetVerdict(
+	ctx context.Context,
+	prID, userID string,
+	verdict reva.Verdict,
+	at time.Time,
+) (*reva.ReviewerAssignment, error) {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+	const query = `
+		UPDATE pull_request_reviewers
+		SET verdict = ?3, verdict_at = ?4
+		WHERE pr_id = ?1 AND user_id = ?2
+		RETURNING pr_id, user_id, assigned_at, is_fallback, verdict, verdict_at, sla_breached_at
+	`
+
+	var ra reva.ReviewerAssignment
+	err := q.QueryRowContext(ctx, query, prID, userID, string(verdict), sqlite.Time(at)).Scan(scanTargets(&ra)...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, reva.ErrReviewerNotFoundInPR
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &ra, nil
+}
+
+// ListSLAPending returns assignments on open PRs that have no verdict and no SLA
+// breach recorded yet, whose author team defines a review SLA.
+// Wall-clock time is used as a prefilter only: business time never exceeds it,
+// so the caller still has to check SLAPending.Breached.
+func (r *SQLiteRepository) ListSLAPending(ctx context.Context, now time.Time) ([]*reva.SLAPending, error) {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+
+	const query = `
+		SELECT prr.pr_id, prr.user_id, prr.assigned_at, prr.is_fallback,
+		       t.name, t.review_sla_weekday_hours, t.sla_auto_reassign
+		FROM pull_request_reviewers prr
+		JOIN pull_requests pr ON pr.id = prr.pr_id
+		JOIN users a ON a.id = pr.author_id
+		JOIN teams t ON t.name = a.team_name
+		WHERE pr.status = 'OPEN'
+		  AND prr.verdict IS NULL
+		  AND prr.sla_breached_at IS NULL
+		  AND t.review_sla_weekday_hours IS NOT NULL
+		  AND julianday(prr.assigned_at) <= julianday(?1) - t.review_sla_weekday_hours / 24.0
+		ORDER BY prr.assigned_at, prr.pr_id, prr.user_id
+	`
+
+	rows, err := q.QueryContext(ctx, query, sqlite.Time(now))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []*reva.SLAPending
+	for rows.Next() {
+		var p reva.SLAPending
+		if err := rows.Scan(
+			&p.PrId, &p.UserId, sqlite.ScanTime(&p.AssignedAt), &p.Fallback,
+			&p.TeamName, &p.SLAHours, &p.AutoReassign,
+		); err != nil {
+			return nil, err
+		}
+		res = append(res, &p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// MarkSLABreached records an SLA breach on the assignment made at assignedAt.
+// Returns false if the assignment has been replaced, got a verdict or was
+// already marked in the meantime.
+func (r *SQLiteRepository) MarkSLABreached(
+	ctx context.Context,
+	prID, userID string,
+	assignedAt, at time.Time,
+) (bool, error) {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+	const query = `
+		UPDATE pull_request_reviewers
+		SET sla_breached_at = ?4
+		WHERE pr_id = ?1 AND user_id = ?2 AND assigned_at = ?3
+		  AND verdict IS NULL AND sla_breached_at IS NULL
+	`
+
+	res, err := q.ExecContext(ctx, query, prID, userID, sqlite.Time(assignedAt), sqlite.Time(at))
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return n > 0, nil
+}
+
+// ListPRIDsByReviewer returns PR IDs where user is assigned as reviewer.
+func (r *SQLiteRepository) ListPRIDsByReviewer(ctx context.Context, userID string) ([]string, error) {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+	const query = `
+		SELECT pr_id
+		FROM pull_request_reviewers
+		WHERE user_id = ?1
+		ORDER BY assigned_at DESC
+	`
+
+	rows, err := q.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var prIDs []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		prIDs = append(prIDs, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return prIDs, nil
+}
+
+// CountOpenByReviewers returns the number of OPEN PRs each user is assigned to.
+// Users without open reviews are absent from the result map.
+func (r *SQLiteRepository) CountOpenByReviewers(ctx context.Context, userIDs []string) (map[string]int, error) {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+	const query = `
+		SELECT prr.user_id, COUNT(*)
+		FROM pull_request_reviewers prr
+		JOIN pull_requests pr ON pr.id = prr.pr_id
+		WHERE prr.user_id IN (SELECT value FROM json_each(?1)) AND pr.status = 'OPEN'
+		GROUP BY prr.user_id
+	`
+
+	rows, err := q.QueryContext(ctx, query, sqlite.Array[string](userIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	load := make(map[string]int, len(userIDs))
+	for rows.Next() {
+		var (
+			id    string
+			count int
+		)
+		if err := rows.Scan(&id, &count); err != nil {
+			return nil, err
+		}
+		load[id] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return load, nil
+}
+
+// LastAssignedAt returns the latest assigned_at of each user across all PRs.
+// Users that were never assigned are absent from the result map.
+func (r *SQLiteRepository) LastAssignedAt(ctx context.Context, userIDs []string) (map[string]time.Time, error) {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+	const query = `
+		SELECT user_id, MAX(assigned_at)
+		FROM pull_request_reviewers
+		WHERE user_id IN (SELECT value FROM json_each(?1))
+		GROUP BY user_id
+	`
+
+	rows, err := q.QueryContext(ctx, query, sqlite.Array[string](userIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	last := make(map[string]time.Time, len(userIDs))
+	for rows.Next() {
+		var (
+			id string
+			at time.Time
+		)
+		if err := rows.Scan(&id, sqlite.ScanTime(&at)); err != nil {
+			return nil, err
+		}
+		last[id] = at
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return last, nil
+}
+
+// ReassignOpenFrom replaces the given users on every OPEN PR,
+// choosing the replacements with planReassignments like the other backends.
+//
+// The reviews and candidates are read first and the replacements are written one by one;
+// run it in a transaction to keep them atomic.
+//
+// Slots that could not be covered keep the old reviewer and carry
+// reva.ErrNoReviewerCandidatesLeft or reva.ErrReviewersAtCapacity in Reason.
+func (r *SQLiteRepository) ReassignOpenFrom(ctx context.Context, userIDs []string, at time.Time) ([]*reva.Reassignment, error) {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+
+	const reviewsQuery = `
+		SELECT prr.pr_id, prr.user_id, pr.author_id, au.team_name
+		FROM pull_request_reviewers prr
+		JOIN pull_requests pr ON pr.id = prr.pr_id
+		JOIN users au ON au.id = pr.author_id
+		WHERE pr.status = 'OPEN'
+		  AND prr.pr_id IN (
+		      SELECT pr_id FROM pull_request_reviewers
+		      WHERE user_id IN (SELECT value FROM json_each(?1))
+		  )
+	`
+	rows, err := q.QueryContext(ctx, reviewsQuery, sqlite.Array[string](userIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		reviews []openReview
+		teams   []string
+	)
+	for rows.Next() {
+		var rv openReview
+		if err := rows.Scan(&rv.prID, &rv.userID, &rv.authorID, &rv.teamName); err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, rv)
+		if !slices.Contains(teams, rv.teamName) {
+			teams = append(teams, rv.teamName)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+	if len(reviews) == 0 {
+		return nil, nil
+	}
+
+	const candidatesQuery = `
+		WITH load AS (
+		    SELECT prr.user_id, COUNT(*) AS open
+		    FROM pull_request_reviewers prr
+		    JOIN pull_requests pr ON pr.id = prr.pr_id
+		    WHERE pr.status = 'OPEN'
+		    GROUP BY prr.user_id
+		)
+		SELECT u.id,
+		       u.team_name,
+		       COALESCE(l.open, 0),
+		       COALESCE(u.max_open_reviews, t.default_max_open_reviews, 0)
+		FROM users u
+		JOIN teams t ON t.name = u.team_name
+		LEFT JOIN load l ON l.user_id = u.id
+		WHERE u.is_active
+		  AND u.team_name IN (SELECT value FROM json_each(?1))
+		  AND NOT EXISTS (
+		      SELECT 1 FROM user_absences a
+		      WHERE a.user_id = u.id
+		        AND a.cancelled_at IS NULL
+		        AND a.starts_at <= ?2 AND a.ends_at > ?2
+		  )
+	`
+	rows, err = q.QueryContext(ctx, candidatesQuery, sqlite.Array[string](teams), sqlite.Time(at))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var candidates []reassignCandidate
+	for rows.Next() {
+		var c reassignCandidate
+		if err := rows.Scan(&c.id, &c.teamName, &c.open, &c.cap); err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	const updateQuery = `
+		UPDATE pull_request_reviewers
+		SET user_id = ?3, assigned_at = ?4, is_fallback = FALSE,
+		    verdict = NULL, verdict_at = NULL, sla_breached_at = NULL
+		WHERE pr_id = ?1 AND user_id = ?2
+	`
+	res := planReassignments(userIDs, reviews, candidates)
+	for _, ra := range res {
+		if !ra.Replaced() {
+			continue
+		}
+		if _, err := q.ExecContext(ctx, updateQuery, ra.PrId, ra.OldUserId, ra.NewUserId, sqlite.Time(at)); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// scanTargets returns scan destinations for
+// pr_id, user_id, assigned_at, is_fallback, verdict, verdict_at, sla_breached_at.
+func scanTargets(ra *reva.ReviewerAssignment) []any {
+	return []any{
+		&ra.PrId, &ra.UserId, sqlite.ScanTime(&ra.AssignedAt), &ra.Fallback, &ra.Verdict,
+		sqlite.ScanNullTime(&ra.VerdictAt), sqlite.ScanNullTime(&ra.SLABreachedAt),
+	}
+}
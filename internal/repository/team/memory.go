@@ -0,0 +1,89 @@
+package team
+
+import (
+	"context"
+	"slices"
+
+	"github.com/zxchelik/avito-test-task/internal/infrastructure/memory"
+	"github.com/zxchelik/avito-test-task/internal/model"
+	"github.com/zxchelik/avito-test-task/internal/model/team"
+)
+
+type MemoryRepository struct {
+	db *memory.DB
+}
+
+func NewMemoryRepository(db *memory.DB) *MemoryRepository {
+	return &MemoryRepository{db: db}
+}
+
+// Create inserts a new team.
+// Returns:
+//   - team.ErrTeamExists (a model.ErrAlreadyExists) — if team already exists
+//   - team.ErrFallbackTeamNotFound — if one of fallback teams does not exist
+func (r *MemoryRepository) Create(ctx context.Context, t *team.Team) error {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	if _, ok := tables.Teams[t.Name]; ok {
+		return team.ErrTeamExists
+	}
+	if err := checkFallbacks(tables, t.FallbackTeams); err != nil {
+		return err
+	}
+
+	tables.Teams[t.Name] = clone(t)
+	return nil
+}
+
+// GetByName returns a team by team_name.
+// Returns model.ErrNotFound if not found.
+func (r *MemoryRepository) GetByName(ctx context.Context, name string) (*team.Team, error) {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	t, ok := tables.Teams[name]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+
+	return clone(t), nil
+}
+
+// UpdateSettings overwrites reviewer settings (including fallback teams) of an existing team.
+// Returns:
+//   - model.ErrNotFound — if team does not exist
+//   - team.ErrFallbackTeamNotFound — if one of fallback teams does not exist
+func (r *MemoryRepository) UpdateSettings(ctx context.Context, t *team.Team) (*team.Team, error) {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	if _, ok := tables.Teams[t.Name]; !ok {
+		return nil, model.ErrNotFound
+	}
+	if err := checkFallbacks(tables, t.FallbackTeams); err != nil {
+		return nil, err
+	}
+
+	tables.Teams[t.Name] = clone(t)
+	return clone(t), nil
+}
+
+// checkFallbacks mirrors the foreign keys of team_fallbacks.
+func checkFallbacks(tables *memory.Tables, fallbacks []string) error {
+	for _, f := range fallbacks {
+		if _, ok := tables.Teams[f]; !ok {
+			return team.ErrFallbackTeamNotFound
+		}
+	}
+	return nil
+}
+
+func clone(t *team.Team) *team.Team {
+	c := *t
+	c.FallbackTeams = slices.Clone(t.FallbackTeams)
+	if c.FallbackTeams == nil {
+		c.FallbackTeams = []string{}
+	}
+	return &c
+}
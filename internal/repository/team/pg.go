@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/zxchelik/avito-test-task/internal/infrastructure/pg"
 	"github.com/zxchelik/avito-test-task/internal/model"
@@ -18,38 +19,57 @@ func NewPGRepository(pool *pgxpool.Pool) *PGRepository {
 	return &PGRepository{pool: pool}
 }
 
-// Create inserts a new team by name.
-// Returns model.ErrAlreadyExists if team already exists (PK conflict).
-func (r *PGRepository) Create(ctx context.Context, name string) error {
+// Create inserts a new team.
+// Returns team.ErrTeamExists (a model.ErrAlreadyExists) if team already exists (PK conflict).
+func (r *PGRepository) Create(ctx context.Context, t *team.Team) error {
 	q := pg.GetQuerierFromContext(ctx, r.pool)
 
 	const query = `
-        INSERT INTO teams (name)
-        VALUES ($1)
+        INSERT INTO teams (name, reviewer_strategy, min_reviewers, max_reviewers, default_max_open_reviews,
+                           review_sla_weekday_hours, sla_auto_reassign)
+        VALUES ($1, $2, $3, $4, NULLIF($5, 0), NULLIF($6, 0), $7)
         ON CONFLICT (name) DO NOTHING
     `
 
-	ct, err := q.Exec(ctx, query, name)
+	ct, err := q.Exec(ctx, query,
+		t.Name, t.ReviewerStrategy, t.MinReviewers, t.MaxReviewers, t.DefaultMaxOpenReviews,
+		t.ReviewSLAWeekdayHours, t.SLAAutoReassign,
+	)
 	if err != nil {
 		return err
 	}
 
 	if ct.RowsAffected() == 0 {
 		// строка не вставилась => такая команда уже есть
-		return model.ErrAlreadyExists
+		return team.ErrTeamExists
 	}
 
-	return nil
+	return r.setFallbacks(ctx, q, t.Name, t.FallbackTeams)
 }
 
 // GetByName returns a team by team_name.
 // Returns model.ErrNotFound if not found.
 func (r *PGRepository) GetByName(ctx context.Context, name string) (*team.Team, error) {
 	q := pg.GetQuerierFromContext(ctx, r.pool)
-	const query = `SELECT name FROM teams WHERE name = $1`
+	const query = `
+		SELECT t.name, t.reviewer_strategy, t.min_reviewers, t.max_reviewers,
+		       COALESCE(t.default_max_open_reviews, 0),
+		       COALESCE(t.review_sla_weekday_hours, 0), t.sla_auto_reassign,
+		       COALESCE(
+		           array_agg(f.fallback_team ORDER BY f.position) FILTER (WHERE f.fallback_team IS NOT NULL),
+		           '{}'
+		       )
+		FROM teams t
+		LEFT JOIN team_fallbacks f ON f.team_name = t.name
+		WHERE t.name = $1
+		GROUP BY t.name
+	`
 
 	var t team.Team
-	err := q.QueryRow(ctx, query, name).Scan(&t.Name)
+	err := q.QueryRow(ctx, query, name).Scan(
+		&t.Name, &t.ReviewerStrategy, &t.MinReviewers, &t.MaxReviewers, &t.DefaultMaxOpenReviews,
+		&t.ReviewSLAWeekdayHours, &t.SLAAutoReassign, &t.FallbackTeams,
+	)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, model.ErrNotFound
 	}
@@ -59,3 +79,73 @@ func (r *PGRepository) GetByName(ctx context.Context, name string) (*team.Team,
 
 	return &t, nil
 }
+
+// UpdateSettings overwrites reviewer settings (including fallback teams) of an existing team.
+// Returns:
+//   - model.ErrNotFound — if team does not exist
+//   - team.ErrFallbackTeamNotFound — if one of fallback teams does not exist
+func (r *PGRepository) UpdateSettings(ctx context.Context, t *team.Team) (*team.Team, error) {
+	q := pg.GetQuerierFromContext(ctx, r.pool)
+	const query = `
+		UPDATE teams
+		SET reviewer_strategy = $2,
+		    min_reviewers = $3,
+		    max_reviewers = $4,
+		    default_max_open_reviews = NULLIF($5, 0),
+		    review_sla_weekday_hours = NULLIF($6, 0),
+		    sla_auto_reassign = $7
+		WHERE name = $1
+		RETURNING name, reviewer_strategy, min_reviewers, max_reviewers, COALESCE(default_max_open_reviews, 0),
+		          COALESCE(review_sla_weekday_hours, 0), sla_auto_reassign
+	`
+
+	var updated team.Team
+	err := q.QueryRow(ctx, query,
+		t.Name, t.ReviewerStrategy, t.MinReviewers, t.MaxReviewers, t.DefaultMaxOpenReviews,
+		t.ReviewSLAWeekdayHours, t.SLAAutoReassign,
+	).Scan(
+		&updated.Name, &updated.ReviewerStrategy, &updated.MinReviewers, &updated.MaxReviewers, &updated.DefaultMaxOpenReviews,
+		&updated.ReviewSLAWeekdayHours, &updated.SLAAutoReassign,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.setFallbacks(ctx, q, t.Name, t.FallbackTeams); err != nil {
+		return nil, err
+	}
+	updated.FallbackTeams = t.FallbackTeams
+
+	return &updated, nil
+}
+
+// setFallbacks replaces the ordered list of fallback teams.
+// Returns team.ErrFallbackTeamNotFound on FK violation.
+func (r *PGRepository) setFallbacks(ctx context.Context, q pg.Querier, name string, fallbacks []string) error {
+	const deleteQuery = `DELETE FROM team_fallbacks WHERE team_name = $1`
+	if _, err := q.Exec(ctx, deleteQuery, name); err != nil {
+		return err
+	}
+
+	if len(fallbacks) == 0 {
+		return nil
+	}
+
+	const insertQuery = `
+		INSERT INTO team_fallbacks (team_name, fallback_team, position)
+		SELECT $1, f.name, f.position
+		FROM unnest($2::text[]) WITH ORDINALITY AS f(name, position)
+	`
+	if _, err := q.Exec(ctx, insertQuery, name, fallbacks); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return team.ErrFallbackTeamNotFound
+		}
+		return err
+	}
+
+	return nil
+}
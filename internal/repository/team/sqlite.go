@@ -0,0 +1,149 @@
+package team
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/zxchelik/avito-test-task/internal/infrastructure/sqlite"
+	"github.com/zxchelik/avito-test-task/internal/model"
+	"github.com/zxchelik/avito-test-task/internal/model/team"
+)
+
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteRepository(db *sql.DB) *SQLiteRepository {
+	return &SQLiteRepository{db: db}
+}
+
+// Create inserts a new team.
+// Returns team.ErrTeamExists (a model.ErrAlreadyExists) if team already exists (PK conflict).
+func (r *SQLiteRepository) Create(ctx context.Context, t *team.Team) error {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+
+	const query = `
+        INSERT INTO teams (name, reviewer_strategy, min_reviewers, max_reviewers, default_max_open_reviews,
+                           review_sla_weekday_hours, sla_auto_reassign)
+        VALUES (?1, ?2, ?3, ?4, NULLIF(?5, 0), NULLIF(?6, 0), ?7)
+        ON CONFLICT (name) DO NOTHING
+    `
+
+	res, err := q.ExecContext(ctx, query,
+		t.Name, t.ReviewerStrategy, t.MinReviewers, t.MaxReviewers, t.DefaultMaxOpenReviews,
+		t.ReviewSLAWeekdayHours, t.SLAAutoReassign,
+	)
+	if err != nil {
+		return err
+	}
+
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		// строка не вставилась => такая команда уже есть
+		return team.ErrTeamExists
+	}
+
+	return r.setFallbacks(ctx, q, t.Name, t.FallbackTeams)
+}
+
+// GetByName returns a team by team_name.
+// Returns model.ErrNotFound if not found.
+func (r *SQLiteRepository) GetByName(ctx context.Context, name string) (*team.Team, error) {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+	const query = `
+		SELECT t.name, t.reviewer_strategy, t.min_reviewers, t.max_reviewers,
+		       COALESCE(t.default_max_open_reviews, 0),
+		       COALESCE(t.review_sla_weekday_hours, 0), t.sla_auto_reassign,
+		       (
+		           SELECT json_group_array(f.fallback_team)
+		           FROM (SELECT fallback_team FROM team_fallbacks WHERE team_name = t.name ORDER BY position) f
+		       )
+		FROM teams t
+		WHERE t.name = ?1
+	`
+
+	var t team.Team
+	err := q.QueryRowContext(ctx, query, name).Scan(
+		&t.Name, &t.ReviewerStrategy, &t.MinReviewers, &t.MaxReviewers, &t.DefaultMaxOpenReviews,
+		&t.ReviewSLAWeekdayHours, &t.SLAAutoReassign, (*sqlite.Array[string])(&t.FallbackTeams),
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+// UpdateSettings overwrites reviewer settings (including fallback teams) of an existing team.
+// Returns:
+//   - model.ErrNotFound — if team does not exist
+//   - team.ErrFallbackTeamNotFound — if one of fallback teams does not exist
+func (r *SQLiteRepository) UpdateSettings(ctx context.Context, t *team.Team) (*team.Team, error) {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+	const query = `
+		UPDATE teams
+		SET reviewer_strategy = ?2,
+		    min_reviewers = ?3,
+		    max_reviewers = ?4,
+		    default_max_open_reviews = NULLIF(?5, 0),
+		    review_sla_weekday_hours = NULLIF(?6, 0),
+		    sla_auto_reassign = ?7
+		WHERE name = ?1
+		RETURNING name, reviewer_strategy, min_reviewers, max_reviewers, COALESCE(default_max_open_reviews, 0),
+		          COALESCE(review_sla_weekday_hours, 0), sla_auto_reassign
+	`
+
+	var updated team.Team
+	err := q.QueryRowContext(ctx, query,
+		t.Name, t.ReviewerStrategy, t.MinReviewers, t.MaxReviewers, t.DefaultMaxOpenReviews,
+		t.ReviewSLAWeekdayHours, t.SLAAutoReassign,
+	).Scan(
+		&updated.Name, &updated.ReviewerStrategy, &updated.MinReviewers, &updated.MaxReviewers, &updated.DefaultMaxOpenReviews,
+		&updated.ReviewSLAWeekdayHours, &updated.SLAAutoReassign,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.setFallbacks(ctx, q, t.Name, t.FallbackTeams); err != nil {
+		return nil, err
+	}
+	updated.FallbackTeams = t.FallbackTeams
+
+	return &updated, nil
+}
+
+// setFallbacks replaces the ordered list of fallback teams.
+// Returns team.ErrFallbackTeamNotFound on FK violation.
+func (r *SQLiteRepository) setFallbacks(ctx context.Context, q sqlite.Querier, name string, fallbacks []string) error {
+	const deleteQuery = `DELETE FROM team_fallbacks WHERE team_name = ?1`
+	if _, err := q.ExecContext(ctx, deleteQuery, name); err != nil {
+		return err
+	}
+
+	if len(fallbacks) == 0 {
+		return nil
+	}
+
+	const insertQuery = `
+		INSERT INTO team_fallbacks (team_name, fallback_team, position)
+		SELECT ?1, f.value, f.key + 1
+		FROM json_each(?2) AS f
+	`
+	if _, err := q.ExecContext(ctx, insertQuery, name, sqlite.Array[string](fallbacks)); err != nil {
+		if sqlite.IsForeignKeyViolation(err) {
+			return team.ErrFallbackTeamNotFound
+		}
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package user
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/zxchelik/avito-test-task/internal/infrastructure/memory"
+	"github.com/zxchelik/avito-test-task/internal/model"
+	"github.com/zxchelik/avito-test-task/internal/model/user"
+)
+
+type MemoryRepository struct {
+	db *memory.DB
+}
+
+func NewMemoryRepository(db *memory.DB) *MemoryRepository {
+	return &MemoryRepository{db: db}
+}
+
+// Upsert inserts a new user or updates existing one by ID.
+// A zero MaxOpenReviews keeps the stored personal limit; SetMaxOpenReviews clears it.
+// Returns user.ErrTeamNotFound if related team does not exist.
+func (r *MemoryRepository) Upsert(ctx context.Context, u *user.User) error {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	if _, ok := tables.Teams[u.TeamName]; !ok {
+		return user.ErrTeamNotFound
+	}
+
+	stored := *u
+	stored.CreatedAt = r.db.Now()
+	if existing, ok := tables.Users[u.ID]; ok {
+		stored.CreatedAt = existing.CreatedAt
+		if stored.MaxOpenReviews == 0 {
+			stored.MaxOpenReviews = existing.MaxOpenReviews
+		}
+	}
+	tables.Users[u.ID] = &stored
+
+	*u = stored
+	return nil
+}
+
+// GetByID returns a user by user_id.
+// Returns model.ErrNotFound if user not found.
+func (r *MemoryRepository) GetByID(ctx context.Context, id string) (*user.User, error) {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	u, ok := tables.Users[id]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+
+	c := *u
+	return &c, nil
+}
+
+// ListByIDs returns users with the given ids ordered by id.
+// Unknown ids are silently skipped.
+func (r *MemoryRepository) ListByIDs(ctx context.Context, ids []string) ([]*user.User, error) {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	return collect(tables, func(u *user.User) bool {
+		return slices.Contains(ids, u.ID)
+	}), nil
+}
+
+// ListByTeam returns all users belonging to a given team.
+func (r *MemoryRepository) ListByTeam(ctx context.Context, teamName string) ([]*user.User, error) {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	return collect(tables, func(u *user.User) bool {
+		return u.TeamName == teamName
+	}), nil
+}
+
+// SetIsActive updates is_active flag for user.
+// Returns updated user or model.ErrNotFound if user doesn't exist.
+func (r *MemoryRepository) SetIsActive(ctx context.Context, id string, isActive bool) (*user.User, error) {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	u, ok := tables.Users[id]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+	u.IsActive = isActive
+
+	c := *u
+	return &c, nil
+}
+
+// SetMaxOpenReviews updates personal open reviews limit (0 removes the limit).
+// Returns updated user or model.ErrNotFound if user doesn't exist.
+func (r *MemoryRepository) SetMaxOpenReviews(ctx context.Context, id string, maxOpenReviews int) (*user.User, error) {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	u, ok := tables.Users[id]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+	u.MaxOpenReviews = maxOpenReviews
+
+	c := *u
+	return &c, nil
+}
+
+// DeactivateMembers sets is_active = false for members of a team.
+// Empty ids means every member; otherwise only listed users that belong to the team are touched.
+// Returns the matched users (already inactive ones included) ordered by id.
+func (r *MemoryRepository) DeactivateMembers(ctx context.Context, teamName string, ids []string) ([]*user.User, error) {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	matched := collect(tables, func(u *user.User) bool {
+		return u.TeamName == teamName && (len(ids) == 0 || slices.Contains(ids, u.ID))
+	})
+	for _, u := range matched {
+		tables.Users[u.ID].IsActive = false
+		u.IsActive = false
+	}
+
+	return matched, nil
+}
+
+// collect returns copies of the users matching keep, ordered by id.
+func collect(tables *memory.Tables, keep func(u *user.User) bool) []*user.User {
+	var users []*user.User
+	for _, u := range tables.Users {
+		if keep(u) {
+			c := *u
+			users = append(users, &c)
+		}
+	}
+	slices.SortFunc(users, func(a, b *user.User) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	return users
+}
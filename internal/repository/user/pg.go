@@ -20,6 +20,7 @@ func NewPGRepository(pool *pgxpool.Pool) *PGRepository {
 }
 
 // Upsert inserts a new user or updates existing one by ID.
+// A zero MaxOpenReviews keeps the stored personal limit; SetMaxOpenReviews clears it.
 // Returns:
 //   - user.ErrTeamNotFound — if related team does not exist (FK violation)
 //   - other DB errors
@@ -27,18 +28,19 @@ func (r *PGRepository) Upsert(ctx context.Context, u *user.User) error {
 	q := pg.GetQuerierFromContext(ctx, r.pool)
 
 	const query = `
-		INSERT INTO users (id, name, team_name, is_active)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO users (id, name, team_name, is_active, max_open_reviews)
+		VALUES ($1, $2, $3, $4, NULLIF($5, 0))
 		ON CONFLICT (id) DO UPDATE
 		SET name = EXCLUDED.name,
 		    team_name = EXCLUDED.team_name,
-		    is_active = EXCLUDED.is_active
-		RETURNING id, name, team_name, is_active, created_at
+		    is_active = EXCLUDED.is_active,
+		    max_open_reviews = COALESCE(EXCLUDED.max_open_reviews, users.max_open_reviews)
+		RETURNING id, name, team_name, is_active, COALESCE(max_open_reviews, 0), created_at
 	`
 
 	err := q.QueryRow(ctx, query,
-		u.ID, u.Username, u.TeamName, u.IsActive,
-	).Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive, &u.CreatedAt)
+		u.ID, u.Username, u.TeamName, u.IsActive, u.MaxOpenReviews,
+	).Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive, &u.MaxOpenReviews, &u.CreatedAt)
 	if err != nil {
 		// если упали по FK — команды нет
 		var pgErr *pgconn.PgError
@@ -58,14 +60,14 @@ func (r *PGRepository) GetByID(ctx context.Context, id string) (*user.User, erro
 	q := pg.GetQuerierFromContext(ctx, r.pool)
 
 	const query = `
-		SELECT id, name, team_name, is_active, created_at
+		SELECT id, name, team_name, is_active, COALESCE(max_open_reviews, 0), created_at
 		FROM users
 		WHERE id = $1
 	`
 
 	var u user.User
 	err := q.QueryRow(ctx, query, id).Scan(
-		&u.ID, &u.Username, &u.TeamName, &u.IsActive, &u.CreatedAt,
+		&u.ID, &u.Username, &u.TeamName, &u.IsActive, &u.MaxOpenReviews, &u.CreatedAt,
 	)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, model.ErrNotFound
@@ -77,12 +79,47 @@ func (r *PGRepository) GetByID(ctx context.Context, id string) (*user.User, erro
 	return &u, nil
 }
 
+// ListByIDs returns users with the given ids ordered by id.
+// Unknown ids are silently skipped.
+func (r *PGRepository) ListByIDs(ctx context.Context, ids []string) ([]*user.User, error) {
+	q := pg.GetQuerierFromContext(ctx, r.pool)
+
+	const query = `
+		SELECT id, name, team_name, is_active, COALESCE(max_open_reviews, 0), created_at
+		FROM users
+		WHERE id = ANY($1)
+		ORDER BY id
+	`
+
+	rows, err := q.Query(ctx, query, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*user.User
+
+	for rows.Next() {
+		var u user.User
+		if err := rows.Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive, &u.MaxOpenReviews, &u.CreatedAt); err != nil {
+			return nil, err
+		}
+		users = append(users, &u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
 // ListByTeam returns all users belonging to a given team.
 func (r *PGRepository) ListByTeam(ctx context.Context, teamName string) ([]*user.User, error) {
 	q := pg.GetQuerierFromContext(ctx, r.pool)
 
 	const query = `
-		SELECT id, name, team_name, is_active, created_at
+		SELECT id, name, team_name, is_active, COALESCE(max_open_reviews, 0), created_at
 		FROM users
 		WHERE team_name = $1
 		ORDER BY id
@@ -98,7 +135,7 @@ func (r *PGRepository) ListByTeam(ctx context.Context, teamName string) ([]*user
 
 	for rows.Next() {
 		var u user.User
-		if err := rows.Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive, &u.CreatedAt); err != nil {
+		if err := rows.Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive, &u.MaxOpenReviews, &u.CreatedAt); err != nil {
 			return nil, err
 		}
 		users = append(users, &u)
@@ -120,12 +157,38 @@ func (r *PGRepository) SetIsActive(ctx context.Context, id string, isActive bool
 		UPDATE users
 		SET is_active = $2
 		WHERE id = $1
-		RETURNING id, name, team_name, is_active, created_at
+		RETURNING id, name, team_name, is_active, COALESCE(max_open_reviews, 0), created_at
 	`
 
 	var u user.User
 	err := q.QueryRow(ctx, query, id, isActive).Scan(
-		&u.ID, &u.Username, &u.TeamName, &u.IsActive, &u.CreatedAt,
+		&u.ID, &u.Username, &u.TeamName, &u.IsActive, &u.MaxOpenReviews, &u.CreatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &u, nil
+}
+
+// SetMaxOpenReviews updates personal open reviews limit (0 removes the limit).
+// Returns updated user or model.ErrNotFound if no rows affected.
+func (r *PGRepository) SetMaxOpenReviews(ctx context.Context, id string, maxOpenReviews int) (*user.User, error) {
+	q := pg.GetQuerierFromContext(ctx, r.pool)
+
+	const query = `
+		UPDATE users
+		SET max_open_reviews = NULLIF($2, 0)
+		WHERE id = $1
+		RETURNING id, name, team_name, is_active, COALESCE(max_open_reviews, 0), created_at
+	`
+
+	var u user.User
+	err := q.QueryRow(ctx, query, id, maxOpenReviews).Scan(
+		&u.ID, &u.Username, &u.TeamName, &u.IsActive, &u.MaxOpenReviews, &u.CreatedAt,
 	)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, model.ErrNotFound
@@ -136,3 +199,42 @@ func (r *PGRepository) SetIsActive(ctx context.Context, id string, isActive bool
 
 	return &u, nil
 }
+
+// DeactivateMembers sets is_active = false for members of a team.
+// Empty ids means every member; otherwise only listed users that belong to the team are touched.
+// Returns the matched users (already inactive ones included).
+func (r *PGRepository) DeactivateMembers(ctx context.Context, teamName string, ids []string) ([]*user.User, error) {
+	q := pg.GetQuerierFromContext(ctx, r.pool)
+
+	const query = `
+		UPDATE users
+		SET is_active = FALSE
+		WHERE team_name = $1 AND (cardinality($2::text[]) = 0 OR id = ANY($2))
+		RETURNING id, name, team_name, is_active, COALESCE(max_open_reviews, 0), created_at
+	`
+
+	if ids == nil {
+		ids = []string{}
+	}
+	rows, err := q.Query(ctx, query, teamName, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*user.User
+	for rows.Next() {
+		var u user.User
+		if err := rows.Scan(
+			&u.ID, &u.Username, &u.TeamName, &u.IsActive, &u.MaxOpenReviews, &u.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		users = append(users, &u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
@@ -0,0 +1,211 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/zxchelik/avito-test-task/internal/infrastructure/sqlite"
+	"github.com/zxchelik/avito-test-task/internal/model"
+	"github.com/zxchelik/avito-test-task/internal/model/user"
+)
+
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteRepository(db *sql.DB) *SQLiteRepository {
+	return &SQLiteRepository{db: db}
+}
+
+// Upsert inserts a new user or updates existing one by ID.
+// A zero MaxOpenReviews keeps the stored personal limit; SetMaxOpenReviews clears it.
+// Returns:
+//   - user.ErrTeamNotFound — if related team does not exist (FK violation)
+//   - other DB errors
+func (r *SQLiteRepository) Upsert(ctx context.Context, u *user.User) error {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+
+	const query = `
+		INSERT INTO users (id, name, team_name, is_active, max_open_reviews, created_at)
+		VALUES (?1, ?2, ?3, ?4, NULLIF(?5, 0), ?6)
+		ON CONFLICT (id) DO UPDATE
+		SET name = excluded.name,
+		    team_name = excluded.team_name,
+		    is_active = excluded.is_active,
+		    max_open_reviews = COALESCE(excluded.max_open_reviews, users.max_open_reviews)
+		RETURNING id, name, team_name, is_active, COALESCE(max_open_reviews, 0), created_at
+	`
+
+	err := q.QueryRowContext(ctx, query,
+		u.ID, u.Username, u.TeamName, u.IsActive, u.MaxOpenReviews, sqlite.Time(time.Now()),
+	).Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive, &u.MaxOpenReviews, sqlite.ScanTime(&u.CreatedAt))
+	if err != nil {
+		// если упали по FK — команды нет
+		if sqlite.IsForeignKeyViolation(err) {
+			return user.ErrTeamNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GetByID returns a user by user_id.
+// Returns:
+//   - model.ErrNotFound — if user not found
+func (r *SQLiteRepository) GetByID(ctx context.Context, id string) (*user.User, error) {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+
+	const query = `
+		SELECT id, name, team_name, is_active, COALESCE(max_open_reviews, 0), created_at
+		FROM users
+		WHERE id = ?1
+	`
+
+	var u user.User
+	err := q.QueryRowContext(ctx, query, id).Scan(
+		&u.ID, &u.Username, &u.TeamName, &u.IsActive, &u.MaxOpenReviews, sqlite.ScanTime(&u.CreatedAt),
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &u, nil
+}
+
+// ListByIDs returns users with the given ids ordered by id.
+// Unknown ids are silently skipped.
+func (r *SQLiteRepository) ListByIDs(ctx context.Context, ids []string) ([]*user.User, error) {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+
+	const query = `
+		SELECT id, name, team_name, is_active, COALESCE(max_open_reviews, 0), created_at
+		FROM users
+		WHERE id IN (SELECT value FROM json_each(?1))
+		ORDER BY id
+	`
+
+	rows, err := q.QueryContext(ctx, query, sqlite.Array[string](ids))
+	if err != nil {
+		return nil, err
+	}
+
+	return scanUsers(rows)
+}
+
+// ListByTeam returns all users belonging to a given team.
+func (r *SQLiteRepository) ListByTeam(ctx context.Context, teamName string) ([]*user.User, error) {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+
+	const query = `
+		SELECT id, name, team_name, is_active, COALESCE(max_open_reviews, 0), created_at
+		FROM users
+		WHERE team_name = ?1
+		ORDER BY id
+	`
+
+	rows, err := q.QueryContext(ctx, query, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanUsers(rows)
+}
+
+// SetIsActive updates is_active flag for user.
+// Returns updated user or model.ErrNotFound if no rows affected.
+func (r *SQLiteRepository) SetIsActive(ctx context.Context, id string, isActive bool) (*user.User, error) {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+
+	const query = `
+		UPDATE users
+		SET is_active = ?2
+		WHERE id = ?1
+		RETURNING id, name, team_name, is_active, COALESCE(max_open_reviews, 0), created_at
+	`
+
+	var u user.User
+	err := q.QueryRowContext(ctx, query, id, isActive).Scan(
+		&u.ID, &u.Username, &u.TeamName, &u.IsActive, &u.MaxOpenReviews, sqlite.ScanTime(&u.CreatedAt),
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &u, nil
+}
+
+// SetMaxOpenReviews updates personal open reviews limit (0 removes the limit).
+// Returns updated user or model.ErrNotFound if no rows affected.
+func (r *SQLiteRepository) SetMaxOpenReviews(ctx context.Context, id string, maxOpenReviews int) (*user.User, error) {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+
+	const query = `
+		UPDATE users
+		SET max_open_reviews = NULLIF(?2, 0)
+		WHERE id = ?1
+		RETURNING id, name, team_name, is_active, COALESCE(max_open_reviews, 0), created_at
+	`
+
+	var u user.User
+	err := q.QueryRowContext(ctx, query, id, maxOpenReviews).Scan(
+		&u.ID, &u.Username, &u.TeamName, &u.IsActive, &u.MaxOpenReviews, sqlite.ScanTime(&u.CreatedAt),
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &u, nil
+}
+
+// DeactivateMembers sets is_active = false for members of a team.
+// Empty ids means every member; otherwise only listed users that belong to the team are touched.
+// Returns the matched users (already inactive ones included).
+func (r *SQLiteRepository) DeactivateMembers(ctx context.Context, teamName string, ids []string) ([]*user.User, error) {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+
+	const query = `
+		UPDATE users
+		SET is_active = FALSE
+		WHERE team_name = ?1 AND (json_array_length(?2) = 0 OR id IN (SELECT value FROM json_each(?2)))
+		RETURNING id, name, team_name, is_active, COALESCE(max_open_reviews, 0), created_at
+	`
+
+	rows, err := q.QueryContext(ctx, query, teamName, sqlite.Array[string](ids))
+	if err != nil {
+		return nil, err
+	}
+
+	return scanUsers(rows)
+}
+
+func scanUsers(rows *sql.Rows) ([]*user.User, error) {
+	defer rows.Close()
+
+	var users []*user.User
+	for rows.Next() {
+		var u user.User
+		if err := rows.Scan(
+			&u.ID, &u.Username, &u.TeamName, &u.IsActive, &u.MaxOpenReviews, sqlite.ScanTime(&u.CreatedAt),
+		); err != nil {
+			return nil, err
+		}
+		users = append(users, &u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
@@ -0,0 +1,231 @@
+package webhook
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/zxchelik/avito-test-task/internal/infrastructure/memory"
+	"github.com/zxchelik/avito-test-task/internal/model"
+	wh "github.com/zxchelik/avito-test-task/internal/model/webhook"
+)
+
+type MemoryRepository struct {
+	db *memory.DB
+}
+
+func NewMemoryRepository(db *memory.DB) *MemoryRepository {
+	return &MemoryRepository{db: db}
+}
+
+// CreateSubscription stores a new webhook subscription.
+func (r *MemoryRepository) CreateSubscription(ctx context.Context, s *wh.Subscription) (*wh.Subscription, error) {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	stored := &wh.Subscription{
+		ID:         tables.NextID("webhook_subscriptions"),
+		URL:        s.URL,
+		Secret:     s.Secret,
+		EventTypes: slices.Clone(s.EventTypes),
+		CreatedAt:  r.db.Now(),
+	}
+	tables.Subscriptions[stored.ID] = stored
+
+	return cloneSubscription(stored), nil
+}
+
+// ListSubscriptions returns all subscriptions ordered by id.
+func (r *MemoryRepository) ListSubscriptions(ctx context.Context) ([]*wh.Subscription, error) {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	return subscriptions(tables), nil
+}
+
+// DeleteSubscription removes a subscription together with its undelivered messages.
+// Returns model.ErrNotFound if subscription doesn't exist.
+func (r *MemoryRepository) DeleteSubscription(ctx context.Context, id int64) error {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	if _, ok := tables.Subscriptions[id]; !ok {
+		return model.ErrNotFound
+	}
+	delete(tables.Subscriptions, id)
+	for did, d := range tables.Deliveries {
+		if d.SubscriptionID == id {
+			delete(tables.Deliveries, did)
+		}
+	}
+
+	return nil
+}
+
+// Enqueue fans messages out to every subscription interested in their type.
+// Messages nobody subscribed to are dropped.
+func (r *MemoryRepository) Enqueue(ctx context.Context, msgs ...*wh.Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	subs := subscriptions(tables)
+	for _, m := range msgs {
+		createdAt := memory.Timestamp(m.CreatedAt)
+		for _, s := range subs {
+			if !slices.Contains(s.EventTypes, m.Type) {
+				continue
+			}
+			d := &wh.Delivery{
+				ID:             tables.NextID("webhook_deliveries"),
+				SubscriptionID: s.ID,
+				EventType:      m.Type,
+				Payload:        slices.Clone(m.Payload),
+				Status:         wh.DeliveryPending,
+				NextAttemptAt:  createdAt,
+				CreatedAt:      createdAt,
+			}
+			tables.Deliveries[d.ID] = d
+		}
+	}
+
+	return nil
+}
+
+// ClaimDue picks up to limit pending deliveries due at now and leases them
+// until leaseUntil, so concurrent workers don't send the same message twice.
+// A worker that dies mid-delivery releases the message when the lease expires.
+func (r *MemoryRepository) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*wh.Delivery, error) {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	var due []*wh.Delivery
+	for _, d := range tables.Deliveries {
+		if d.Status == wh.DeliveryPending && !d.NextAttemptAt.After(now) {
+			due = append(due, d)
+		}
+	}
+	slices.SortFunc(due, func(a, b *wh.Delivery) int {
+		return cmp.Or(a.NextAttemptAt.Compare(b.NextAttemptAt), cmp.Compare(a.ID, b.ID))
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	res := make([]*wh.Delivery, 0, len(due))
+	for _, d := range due {
+		d.NextAttemptAt = memory.Timestamp(leaseUntil)
+		c := withSubscription(tables, d)
+		c.Secret = tables.Subscriptions[d.SubscriptionID].Secret
+		res = append(res, c)
+	}
+
+	return res, nil
+}
+
+// MarkDelivered records a successful delivery.
+func (r *MemoryRepository) MarkDelivered(ctx context.Context, id int64, at time.Time) error {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	if d, ok := tables.Deliveries[id]; ok {
+		deliveredAt := memory.Timestamp(at)
+		d.Status = wh.DeliveryDelivered
+		d.Attempts++
+		d.DeliveredAt = &deliveredAt
+		d.LastError = ""
+	}
+
+	return nil
+}
+
+// MarkFailed records a failed attempt. The delivery is retried at next,
+// or moved to the dead-letter state if dead is set.
+func (r *MemoryRepository) MarkFailed(ctx context.Context, id int64, lastErr string, next time.Time, dead bool) error {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	if d, ok := tables.Deliveries[id]; ok {
+		d.Status = wh.DeliveryPending
+		if dead {
+			d.Status = wh.DeliveryDead
+		}
+		d.Attempts++
+		d.LastError = lastErr
+		d.NextAttemptAt = memory.Timestamp(next)
+	}
+
+	return nil
+}
+
+// ListDead returns dead-lettered deliveries, newest first.
+// Secrets are not loaded.
+func (r *MemoryRepository) ListDead(ctx context.Context, limit int) ([]*wh.Delivery, error) {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	var res []*wh.Delivery
+	for _, d := range tables.Deliveries {
+		if d.Status == wh.DeliveryDead {
+			res = append(res, withSubscription(tables, d))
+		}
+	}
+	slices.SortFunc(res, func(a, b *wh.Delivery) int {
+		return cmp.Compare(b.ID, a.ID)
+	})
+	if len(res) > limit {
+		res = res[:limit]
+	}
+
+	return res, nil
+}
+
+// Redeliver moves a dead-lettered delivery back to the queue with a fresh attempt budget.
+// Returns model.ErrNotFound if delivery doesn't exist and wh.ErrNotDead if it isn't dead.
+func (r *MemoryRepository) Redeliver(ctx context.Context, id int64, at time.Time) error {
+	tables, release := r.db.Acquire(ctx)
+	defer release()
+
+	d, ok := tables.Deliveries[id]
+	if !ok {
+		return model.ErrNotFound
+	}
+	if d.Status != wh.DeliveryDead {
+		return wh.ErrNotDead
+	}
+	d.Status = wh.DeliveryPending
+	d.Attempts = 0
+	d.NextAttemptAt = memory.Timestamp(at)
+
+	return nil
+}
+
+// subscriptions returns copies of all subscriptions ordered by id.
+func subscriptions(tables *memory.Tables) []*wh.Subscription {
+	res := make([]*wh.Subscription, 0, len(tables.Subscriptions))
+	for _, s := range tables.Subscriptions {
+		res = append(res, cloneSubscription(s))
+	}
+	slices.SortFunc(res, func(a, b *wh.Subscription) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return res
+}
+
+// withSubscription copies a delivery and fills in the subscription url.
+func withSubscription(tables *memory.Tables, d *wh.Delivery) *wh.Delivery {
+	c := *d
+	c.Payload = slices.Clone(d.Payload)
+	c.URL = tables.Subscriptions[d.SubscriptionID].URL
+	return &c
+}
+
+func cloneSubscription(s *wh.Subscription) *wh.Subscription {
+	c := *s
+	c.EventTypes = slices.Clone(s.EventTypes)
+	return &c
+}
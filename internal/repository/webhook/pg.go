@@ -0,0 +1,270 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/zxchelik/avito-test-task/internal/infrastructure/pg"
+	"github.com/zxchelik/avito-test-task/internal/model"
+	wh "github.com/zxchelik/avito-test-task/internal/model/webhook"
+	"time"
+)
+
+type PGRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPGRepository(pool *pgxpool.Pool) *PGRepository {
+	return &PGRepository{pool: pool}
+}
+
+// CreateSubscription stores a new webhook subscription.
+func (r *PGRepository) CreateSubscription(ctx context.Context, s *wh.Subscription) (*wh.Subscription, error) {
+	q := pg.GetQuerierFromContext(ctx, r.pool)
+	const query = `
+		INSERT INTO webhook_subscriptions (url, secret, event_types)
+		VALUES ($1, $2, $3)
+		RETURNING id, url, secret, event_types, created_at
+	`
+
+	var created wh.Subscription
+	err := q.QueryRow(ctx, query, s.URL, s.Secret, eventTypes(s.EventTypes)).Scan(
+		&created.ID, &created.URL, &created.Secret, &created.EventTypes, &created.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// ListSubscriptions returns all subscriptions ordered by id.
+func (r *PGRepository) ListSubscriptions(ctx context.Context) ([]*wh.Subscription, error) {
+	q := pg.GetQuerierFromContext(ctx, r.pool)
+	const query = `
+		SELECT id, url, secret, event_types, created_at
+		FROM webhook_subscriptions
+		ORDER BY id
+	`
+
+	rows, err := q.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []*wh.Subscription
+	for rows.Next() {
+		var s wh.Subscription
+		if err := rows.Scan(&s.ID, &s.URL, &s.Secret, &s.EventTypes, &s.CreatedAt); err != nil {
+			return nil, err
+		}
+		res = append(res, &s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// DeleteSubscription removes a subscription together with its undelivered messages.
+// Returns model.ErrNotFound if subscription doesn't exist.
+func (r *PGRepository) DeleteSubscription(ctx context.Context, id int64) error {
+	q := pg.GetQuerierFromContext(ctx, r.pool)
+	const query = `DELETE FROM webhook_subscriptions WHERE id = $1`
+
+	ct, err := q.Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() == 0 {
+		return model.ErrNotFound
+	}
+
+	return nil
+}
+
+// Enqueue fans messages out to every subscription interested in their type.
+// Messages nobody subscribed to are dropped.
+func (r *PGRepository) Enqueue(ctx context.Context, msgs ...*wh.Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	q := pg.GetQuerierFromContext(ctx, r.pool)
+	const query = `
+		INSERT INTO webhook_deliveries (subscription_id, event_type, payload, next_attempt_at, created_at)
+		SELECT s.id, m.event_type, m.payload, m.created_at, m.created_at
+		FROM unnest($1::text[], $2::jsonb[], $3::timestamptz[])
+		     WITH ORDINALITY AS m(event_type, payload, created_at, ord)
+		JOIN webhook_subscriptions s ON m.event_type = ANY(s.event_types)
+		ORDER BY m.ord, s.id
+	`
+
+	var (
+		types     = make([]string, len(msgs))
+		payloads  = make([]string, len(msgs))
+		createdAt = make([]time.Time, len(msgs))
+	)
+	for i, m := range msgs {
+		types[i] = string(m.Type)
+		payloads[i] = string(m.Payload)
+		createdAt[i] = m.CreatedAt
+	}
+
+	_, err := q.Exec(ctx, query, types, payloads, createdAt)
+	return err
+}
+
+// ClaimDue picks up to limit pending deliveries due at now and leases them
+// until leaseUntil, so concurrent workers don't send the same message twice.
+// A worker that dies mid-delivery releases the message when the lease expires.
+func (r *PGRepository) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*wh.Delivery, error) {
+	q := pg.GetQuerierFromContext(ctx, r.pool)
+	const query = `
+		WITH due AS (
+		    SELECT id
+		    FROM webhook_deliveries
+		    WHERE status = 'PENDING' AND next_attempt_at <= $1
+		    ORDER BY next_attempt_at, id
+		    LIMIT $3
+		    FOR UPDATE SKIP LOCKED
+		)
+		UPDATE webhook_deliveries d
+		SET next_attempt_at = $2
+		FROM due, webhook_subscriptions s
+		WHERE d.id = due.id AND s.id = d.subscription_id
+		RETURNING d.id, d.subscription_id, s.url, s.secret, d.event_type, d.payload::text,
+		          d.status::text, d.attempts, d.next_attempt_at, d.last_error, d.created_at, d.delivered_at
+	`
+
+	rows, err := q.Query(ctx, query, now, leaseUntil, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanDeliveries(rows)
+}
+
+// MarkDelivered records a successful delivery.
+func (r *PGRepository) MarkDelivered(ctx context.Context, id int64, at time.Time) error {
+	q := pg.GetQuerierFromContext(ctx, r.pool)
+	const query = `
+		UPDATE webhook_deliveries
+		SET status = 'DELIVERED', attempts = attempts + 1, delivered_at = $2, last_error = ''
+		WHERE id = $1
+	`
+
+	_, err := q.Exec(ctx, query, id, at)
+	return err
+}
+
+// MarkFailed records a failed attempt. The delivery is retried at next,
+// or moved to the dead-letter state if dead is set.
+func (r *PGRepository) MarkFailed(ctx context.Context, id int64, lastErr string, next time.Time, dead bool) error {
+	q := pg.GetQuerierFromContext(ctx, r.pool)
+	const query = `
+		UPDATE webhook_deliveries
+		SET status = CASE WHEN $4 THEN 'DEAD' ELSE 'PENDING' END::webhook_delivery_status,
+		    attempts = attempts + 1,
+		    last_error = $2,
+		    next_attempt_at = $3
+		WHERE id = $1
+	`
+
+	_, err := q.Exec(ctx, query, id, lastErr, next, dead)
+	return err
+}
+
+// ListDead returns dead-lettered deliveries, newest first.
+// Secrets are not loaded.
+func (r *PGRepository) ListDead(ctx context.Context, limit int) ([]*wh.Delivery, error) {
+	q := pg.GetQuerierFromContext(ctx, r.pool)
+	const query = `
+		SELECT d.id, d.subscription_id, s.url, '', d.event_type, d.payload::text,
+		       d.status::text, d.attempts, d.next_attempt_at, d.last_error, d.created_at, d.delivered_at
+		FROM webhook_deliveries d
+		JOIN webhook_subscriptions s ON s.id = d.subscription_id
+		WHERE d.status = 'DEAD'
+		ORDER BY d.id DESC
+		LIMIT $1
+	`
+
+	rows, err := q.Query(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanDeliveries(rows)
+}
+
+// Redeliver moves a dead-lettered delivery back to the queue with a fresh attempt budget.
+// Returns model.ErrNotFound if delivery doesn't exist and wh.ErrNotDead if it isn't dead.
+func (r *PGRepository) Redeliver(ctx context.Context, id int64, at time.Time) error {
+	q := pg.GetQuerierFromContext(ctx, r.pool)
+	const query = `
+		WITH target AS (
+		    SELECT id, status FROM webhook_deliveries WHERE id = $1
+		), updated AS (
+		    UPDATE webhook_deliveries d
+		    SET status = 'PENDING', attempts = 0, next_attempt_at = $2
+		    FROM target t
+		    WHERE d.id = t.id AND t.status = 'DEAD'
+		    RETURNING d.id
+		)
+		SELECT t.status::text, EXISTS (SELECT 1 FROM updated)
+		FROM target t
+	`
+
+	var (
+		status  string
+		updated bool
+	)
+	err := q.QueryRow(ctx, query, id, at).Scan(&status, &updated)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return wh.ErrNotDead
+	}
+
+	return nil
+}
+
+func scanDeliveries(rows pgx.Rows) ([]*wh.Delivery, error) {
+	var res []*wh.Delivery
+	for rows.Next() {
+		var (
+			d       wh.Delivery
+			payload string
+		)
+		if err := rows.Scan(
+			&d.ID, &d.SubscriptionID, &d.URL, &d.Secret, &d.EventType, &payload,
+			&d.Status, &d.Attempts, &d.NextAttemptAt, &d.LastError, &d.CreatedAt, &d.DeliveredAt,
+		); err != nil {
+			return nil, err
+		}
+		d.Payload = []byte(payload)
+		res = append(res, &d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func eventTypes(types []wh.EventType) []string {
+	res := make([]string, len(types))
+	for i, t := range types {
+		res[i] = string(t)
+	}
+	return res
+}
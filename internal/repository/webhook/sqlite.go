@@ -0,0 +1,265 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/zxchelik/avito-test-task/internal/infrastructure/sqlite"
+	"github.com/zxchelik/avito-test-task/internal/model"
+	wh "github.com/zxchelik/avito-test-task/internal/model/webhook"
+)
+
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteRepository(db *sql.DB) *SQLiteRepository {
+	return &SQLiteRepository{db: db}
+}
+
+// CreateSubscription stores a new webhook subscription.
+func (r *SQLiteRepository) CreateSubscription(ctx context.Context, s *wh.Subscription) (*wh.Subscription, error) {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+	const query = `
+		INSERT INTO webhook_subscriptions (url, secret, event_types, created_at)
+		VALUES (?1, ?2, ?3, ?4)
+		RETURNING id, url, secret, event_types, created_at
+	`
+
+	var created wh.Subscription
+	err := q.QueryRowContext(ctx, query,
+		s.URL, s.Secret, sqlite.Array[wh.EventType](s.EventTypes), sqlite.Time(time.Now()),
+	).Scan(
+		&created.ID, &created.URL, &created.Secret, (*sqlite.Array[wh.EventType])(&created.EventTypes),
+		sqlite.ScanTime(&created.CreatedAt),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// ListSubscriptions returns all subscriptions ordered by id.
+func (r *SQLiteRepository) ListSubscriptions(ctx context.Context) ([]*wh.Subscription, error) {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+	const query = `
+		SELECT id, url, secret, event_types, created_at
+		FROM webhook_subscriptions
+		ORDER BY id
+	`
+
+	rows, err := q.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []*wh.Subscription
+	for rows.Next() {
+		var s wh.Subscription
+		if err := rows.Scan(
+			&s.ID, &s.URL, &s.Secret, (*sqlite.Array[wh.EventType])(&s.EventTypes), sqlite.ScanTime(&s.CreatedAt),
+		); err != nil {
+			return nil, err
+		}
+		res = append(res, &s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// DeleteSubscription removes a subscription together with its undelivered messages.
+// Returns model.ErrNotFound if subscription doesn't exist.
+func (r *SQLiteRepository) DeleteSubscription(ctx context.Context, id int64) error {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+	const query = `DELETE FROM webhook_subscriptions WHERE id = ?1`
+
+	res, err := q.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return model.ErrNotFound
+	}
+
+	return nil
+}
+
+// Enqueue fans messages out to every subscription interested in their type.
+// Messages nobody subscribed to are dropped.
+func (r *SQLiteRepository) Enqueue(ctx context.Context, msgs ...*wh.Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+	query := `
+		WITH m(ord, event_type, payload, created_at) AS (
+		    VALUES ` + sqlite.Placeholders(len(msgs), 4) + `
+		)
+		INSERT INTO webhook_deliveries (subscription_id, event_type, payload, next_attempt_at, created_at)
+		SELECT s.id, m.event_type, m.payload, m.created_at, m.created_at
+		FROM m
+		JOIN webhook_subscriptions s ON m.event_type IN (SELECT value FROM json_each(s.event_types))
+		ORDER BY m.ord, s.id
+	`
+
+	args := make([]any, 0, len(msgs)*4)
+	for i, m := range msgs {
+		args = append(args, i, string(m.Type), string(m.Payload), sqlite.Time(m.CreatedAt))
+	}
+
+	_, err := q.ExecContext(ctx, query, args...)
+	return err
+}
+
+// ClaimDue picks up to limit pending deliveries due at now and leases them
+// until leaseUntil, so concurrent workers don't send the same message twice.
+// A worker that dies mid-delivery releases the message when the lease expires.
+func (r *SQLiteRepository) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*wh.Delivery, error) {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+	// одна инструкция: выбор и продление аренды не разделяются другими запросами
+	const query = `
+		UPDATE webhook_deliveries
+		SET next_attempt_at = ?2
+		WHERE id IN (
+		    SELECT id
+		    FROM webhook_deliveries
+		    WHERE status = 'PENDING' AND next_attempt_at <= ?1
+		    ORDER BY next_attempt_at, id
+		    LIMIT ?3
+		)
+		RETURNING id, subscription_id,
+		          (SELECT s.url FROM webhook_subscriptions s WHERE s.id = webhook_deliveries.subscription_id),
+		          (SELECT s.secret FROM webhook_subscriptions s WHERE s.id = webhook_deliveries.subscription_id),
+		          event_type, payload, status, attempts, next_attempt_at, last_error,
+		          created_at, delivered_at
+	`
+
+	rows, err := q.QueryContext(ctx, query, sqlite.Time(now), sqlite.Time(leaseUntil), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanSQLiteDeliveries(rows)
+}
+
+// MarkDelivered records a successful delivery.
+func (r *SQLiteRepository) MarkDelivered(ctx context.Context, id int64, at time.Time) error {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+	const query = `
+		UPDATE webhook_deliveries
+		SET status = 'DELIVERED', attempts = attempts + 1, delivered_at = ?2, last_error = ''
+		WHERE id = ?1
+	`
+
+	_, err := q.ExecContext(ctx, query, id, sqlite.Time(at))
+	return err
+}
+
+// MarkFailed records a failed attempt. The delivery is retried at next,
+// or moved to the dead-letter state if dead is set.
+func (r *SQLiteRepository) MarkFailed(ctx context.Context, id int64, lastErr string, next time.Time, dead bool) error {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+	const query = `
+		UPDATE webhook_deliveries
+		SET status = CASE WHEN ?4 THEN 'DEAD' ELSE 'PENDING' END,
+		    attempts = attempts + 1,
+		    last_error = ?2,
+		    next_attempt_at = ?3
+		WHERE id = ?1
+	`
+
+	_, err := q.ExecContext(ctx, query, id, lastErr, sqlite.Time(next), dead)
+	return err
+}
+
+// ListDead returns dead-lettered deliveries, newest first.
+// Secrets are not loaded.
+func (r *SQLiteRepository) ListDead(ctx context.Context, limit int) ([]*wh.Delivery, error) {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+	const query = `
+		SELECT d.id, d.subscription_id, s.url, '', d.event_type, d.payload,
+		       d.status, d.attempts, d.next_attempt_at, d.last_error, d.created_at, d.delivered_at
+		FROM webhook_deliveries d
+		JOIN webhook_subscriptions s ON s.id = d.subscription_id
+		WHERE d.status = 'DEAD'
+		ORDER BY d.id DESC
+		LIMIT ?1
+	`
+
+	rows, err := q.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanSQLiteDeliveries(rows)
+}
+
+// Redeliver moves a dead-lettered delivery back to the queue with a fresh attempt budget.
+// Returns model.ErrNotFound if delivery doesn't exist and wh.ErrNotDead if it isn't dead.
+func (r *SQLiteRepository) Redeliver(ctx context.Context, id int64, at time.Time) error {
+	q := sqlite.GetQuerierFromContext(ctx, r.db)
+	const query = `
+		UPDATE webhook_deliveries
+		SET status = 'PENDING', attempts = 0, next_attempt_at = ?2
+		WHERE id = ?1 AND status = 'DEAD'
+	`
+
+	res, err := q.ExecContext(ctx, query, id, sqlite.Time(at))
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n > 0 {
+		return nil
+	}
+
+	// различаем «нет такой доставки» и «доставка не в dead letter»
+	const existsQuery = `SELECT 1 FROM webhook_deliveries WHERE id = ?1`
+	var one int
+	err = q.QueryRowContext(ctx, existsQuery, id).Scan(&one)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	return wh.ErrNotDead
+}
+
+func scanSQLiteDeliveries(rows *sql.Rows) ([]*wh.Delivery, error) {
+	defer rows.Close()
+
+	var res []*wh.Delivery
+	for rows.Next() {
+		var (
+			d       wh.Delivery
+			payload string
+		)
+		if err := rows.Scan(
+			&d.ID, &d.SubscriptionID, &d.URL, &d.Secret, &d.EventType, &payload,
+			&d.Status, &d.Attempts, sqlite.ScanTime(&d.NextAttemptAt), &d.LastError,
+			sqlite.ScanTime(&d.CreatedAt), sqlite.ScanNullTime(&d.DeliveredAt),
+		); err != nil {
+			return nil, err
+		}
+		d.Payload = []byte(payload)
+		res = append(res, &d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,97 @@
+package absence
+
+import (
+	"context"
+	"strings"
+
+	modelabsence "github.com/zxchelik/avito-test-task/internal/model/absence"
+	modelauth "github.com/zxchelik/avito-test-task/internal/model/auth"
+	"github.com/zxchelik/avito-test-task/internal/service"
+)
+
+type Service struct {
+	absences service.AbsenceRepository
+	users    service.UserRepository
+	clock    service.Clock
+}
+
+func NewService(absences service.AbsenceRepository, users service.UserRepository) *Service {
+	return &Service{
+		absences: absences,
+		users:    users,
+		clock:    service.DefaultClock,
+	}
+}
+
+// WithClock позволяет подменять время в тестах.
+func (s *Service) WithClock(clock service.Clock) *Service {
+	s.clock = clock
+	return s
+}
+
+// Add планирует отсутствие пользователя.
+// Ошибки:
+//   - ErrNotFound                 — если пользователя нет
+//   - absence.ErrReasonRequired   — пустая причина
+//   - absence.ErrInvalidPeriod    — конец не позже начала
+//   - absence.ErrAlreadyEnded     — период целиком в прошлом
+//   - auth.ErrUnauthorized, auth.ErrForbidden — как в authorize
+func (s *Service) Add(ctx context.Context, a *modelabsence.Absence) (*modelabsence.Absence, error) {
+	if err := authorize(ctx, a.UserID); err != nil {
+		return nil, err
+	}
+	a.Reason = strings.TrimSpace(a.Reason)
+	if a.Reason == "" {
+		return nil, modelabsence.ErrReasonRequired
+	}
+	if !a.EndsAt.After(a.StartsAt) {
+		return nil, modelabsence.ErrInvalidPeriod
+	}
+	if !a.EndsAt.After(s.clock()) {
+		return nil, modelabsence.ErrAlreadyEnded
+	}
+
+	return s.absences.Create(ctx, a)
+}
+
+// List возвращает отсутствия пользователя.
+// Ошибки:
+//   - ErrNotFound — если пользователя нет
+func (s *Service) List(
+	ctx context.Context,
+	userID string,
+	includeCancelled bool,
+) ([]*modelabsence.Absence, error) {
+	if _, err := s.users.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.absences.ListByUser(ctx, userID, includeCancelled)
+}
+
+// Cancel отменяет отсутствие; пользователь сразу снова доступен для ревью.
+// Ошибки:
+//   - ErrNotFound                   — если записи нет
+//   - absence.ErrAlreadyCancelled   — уже отменено
+//   - auth.ErrUnauthorized, auth.ErrForbidden — как в authorize
+func (s *Service) Cancel(ctx context.Context, id int64) (*modelabsence.Absence, error) {
+	a, err := s.absences.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(ctx, a.UserID); err != nil {
+		return nil, err
+	}
+	return s.absences.Cancel(ctx, id, s.clock())
+}
+
+// authorize проверяет, что отсутствием распоряжается сам пользователь, админ или система.
+func authorize(ctx context.Context, userID string) error {
+	p, err := service.RequirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	if p.Privileged() || p.Subject == userID {
+		return nil
+	}
+	return modelauth.ErrForbidden
+}
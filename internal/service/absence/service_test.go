@@ -0,0 +1,106 @@
+package absence_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/zxchelik/avito-test-task/internal/model"
+	modelabsence "github.com/zxchelik/avito-test-task/internal/model/absence"
+	modelauth "github.com/zxchelik/avito-test-task/internal/model/auth"
+	srvabsence "github.com/zxchelik/avito-test-task/internal/service/absence"
+	"github.com/zxchelik/avito-test-task/internal/service/servicetest"
+)
+
+var now = time.Date(2025, 3, 3, 10, 0, 0, 0, time.UTC)
+
+func newService(t *testing.T) *srvabsence.Service {
+	t.Helper()
+
+	store := servicetest.NewStore()
+	store.AddTeam(t, servicetest.Team("backend", 1), "u1")
+	return srvabsence.NewService(store.Absences, store.Users).WithClock(func() time.Time { return now })
+}
+
+func TestAddValidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		absence    modelabsence.Absence
+		wantErr    error
+		wantReason string
+	}{
+		{name: "future", absence: modelabsence.Absence{StartsAt: now.Add(24 * time.Hour), EndsAt: now.Add(48 * time.Hour), Reason: "vacation"}, wantReason: "vacation"},
+		// уже начавшееся отсутствие можно завести задним числом
+		{name: "current", absence: modelabsence.Absence{StartsAt: now.Add(-24 * time.Hour), EndsAt: now.Add(time.Hour), Reason: " sick leave "}, wantReason: "sick leave"},
+		{name: "blank reason", absence: modelabsence.Absence{StartsAt: now, EndsAt: now.Add(time.Hour), Reason: "  "}, wantErr: modelabsence.ErrReasonRequired},
+		{name: "empty period", absence: modelabsence.Absence{StartsAt: now.Add(time.Hour), EndsAt: now.Add(time.Hour), Reason: "vacation"}, wantErr: modelabsence.ErrInvalidPeriod},
+		{name: "reversed period", absence: modelabsence.Absence{StartsAt: now.Add(2 * time.Hour), EndsAt: now.Add(time.Hour), Reason: "vacation"}, wantErr: modelabsence.ErrInvalidPeriod},
+		// конец по часам сервиса уже наступил
+		{name: "ended", absence: modelabsence.Absence{StartsAt: now.Add(-2 * time.Hour), EndsAt: now, Reason: "vacation"}, wantErr: modelabsence.ErrAlreadyEnded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newService(t)
+
+			a := tt.absence
+			a.UserID = "u1"
+			got, err := svc.Add(servicetest.As("u1", modelauth.RoleUser), &a)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("got %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("add: %v", err)
+			}
+			if got.ID == 0 || got.Reason != tt.wantReason {
+				t.Fatalf("added = %+v, want reason %q", got, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestAddForMissingUser(t *testing.T) {
+	svc := newService(t)
+
+	_, err := svc.Add(servicetest.System(), &modelabsence.Absence{
+		UserID: "missing", StartsAt: now, EndsAt: now.Add(time.Hour), Reason: "vacation",
+	})
+	if !errors.Is(err, model.ErrNotFound) {
+		t.Fatalf("got %v, want ErrNotFound", err)
+	}
+}
+
+func TestListAndCancel(t *testing.T) {
+	svc := newService(t)
+	ctx := servicetest.As("u1", modelauth.RoleUser)
+
+	a, err := svc.Add(ctx, &modelabsence.Absence{UserID: "u1", StartsAt: now, EndsAt: now.Add(time.Hour), Reason: "vacation"})
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+
+	cancelled, err := svc.Cancel(ctx, a.ID)
+	if err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	// время отмены берётся из часов сервиса
+	if cancelled.CancelledAt == nil || !cancelled.CancelledAt.Equal(now) {
+		t.Fatalf("cancelled = %+v, want cancelled at %s", cancelled, now)
+	}
+	if _, err := svc.Cancel(ctx, a.ID); !errors.Is(err, modelabsence.ErrAlreadyCancelled) {
+		t.Fatalf("second cancel: got %v, want ErrAlreadyCancelled", err)
+	}
+
+	if list, err := svc.List(context.Background(), "u1", false); err != nil || len(list) != 0 {
+		t.Fatalf("list = %+v, %v, want none", list, err)
+	}
+	if list, err := svc.List(context.Background(), "u1", true); err != nil || len(list) != 1 {
+		t.Fatalf("list with cancelled = %+v, %v, want one", list, err)
+	}
+	if _, err := svc.List(context.Background(), "missing", false); !errors.Is(err, model.ErrNotFound) {
+		t.Fatalf("list of missing user: got %v, want ErrNotFound", err)
+	}
+}
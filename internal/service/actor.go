@@ -0,0 +1,16 @@
+package service
+
+import "context"
+
+type actorKey struct{}
+
+// WithActor кладёт в контекст идентификатор того, кто выполняет действие.
+func WithActor(ctx context.Context, actorID string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actorID)
+}
+
+// ActorFrom возвращает исполнителя действия; пустая строка — система.
+func ActorFrom(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
@@ -0,0 +1,9 @@
+package service
+
+import "time"
+
+// Clock — абстракция времени для тестов.
+type Clock func() time.Time
+
+// DefaultClock возвращает текущее время в UTC.
+func DefaultClock() time.Time { return time.Now().UTC() }
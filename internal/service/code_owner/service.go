@@ -0,0 +1,145 @@
+package code_owner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	modelco "github.com/zxchelik/avito-test-task/internal/model/code_owner"
+	"github.com/zxchelik/avito-test-task/internal/service"
+	"github.com/zxchelik/avito-test-task/pkg/codeowners"
+)
+
+type Service struct {
+	rules service.CodeOwnerRepository
+	tx    service.TxManager
+}
+
+func NewService(rules service.CodeOwnerRepository, tx service.TxManager) *Service {
+	return &Service{
+		rules: rules,
+		tx:    tx,
+	}
+}
+
+// Add создаёт правило владения. Position 0 — добавить в конец.
+// Ошибки:
+//   - code_owner.ErrInvalidPattern — некорректный шаблон
+func (s *Service) Add(ctx context.Context, rule *modelco.Rule) (*modelco.Rule, error) {
+	if err := validate(rule); err != nil {
+		return nil, err
+	}
+	return s.rules.Create(ctx, rule)
+}
+
+// List возвращает правила в порядке применения.
+func (s *Service) List(ctx context.Context) ([]*modelco.Rule, error) {
+	return s.rules.List(ctx)
+}
+
+// Update перезаписывает правило.
+// Ошибки:
+//   - ErrNotFound                  — если правила нет
+//   - code_owner.ErrInvalidPattern — некорректный шаблон
+func (s *Service) Update(ctx context.Context, rule *modelco.Rule) (*modelco.Rule, error) {
+	if err := validate(rule); err != nil {
+		return nil, err
+	}
+
+	var updated *modelco.Rule
+
+	err := s.tx.WithinTransaction(ctx, func(txCtx context.Context) error {
+		current, err := s.rules.GetByID(txCtx, rule.ID)
+		if err != nil {
+			return err
+		}
+		if rule.Position == 0 {
+			rule.Position = current.Position
+		}
+
+		updated, err = s.rules.Update(txCtx, rule)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// Delete удаляет правило.
+// Ошибки:
+//   - ErrNotFound — если правила нет
+func (s *Service) Delete(ctx context.Context, id int64) error {
+	return s.rules.Delete(ctx, id)
+}
+
+// Import разбирает файл CODEOWNERS и добавляет его правила в конец списка
+// (или заменяет все существующие правила при replace) одной транзакцией.
+// Владельцы вида @org/team становятся командами (team), @user — пользователями,
+// прочие значения (например, email) трактуются как user_id как есть.
+// Ошибки:
+//   - code_owner.ErrInvalidPattern — строка файла с некорректным шаблоном
+//   - ошибки чтения r (например, bufio.ErrTooLong) — как есть
+func (s *Service) Import(ctx context.Context, r io.Reader, replace bool) ([]*modelco.Rule, error) {
+	parsed, err := codeowners.Parse(r)
+	var perr *codeowners.ParseError
+	switch {
+	case errors.As(err, &perr):
+		return nil, fmt.Errorf("%w: %s", modelco.ErrInvalidPattern, perr.Error())
+	case err != nil:
+		return nil, err
+	}
+
+	var imported []*modelco.Rule
+
+	err = s.tx.WithinTransaction(ctx, func(txCtx context.Context) error {
+		if replace {
+			if err := s.rules.DeleteAll(txCtx); err != nil {
+				return err
+			}
+		}
+
+		imported = make([]*modelco.Rule, 0, len(parsed))
+		for _, p := range parsed {
+			users, teams := splitOwners(p.Owners)
+			created, err := s.rules.Create(txCtx, &modelco.Rule{
+				Pattern: p.Pattern,
+				Users:   users,
+				Teams:   teams,
+			})
+			if err != nil {
+				return err
+			}
+			imported = append(imported, created)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return imported, nil
+}
+
+func validate(rule *modelco.Rule) error {
+	if _, err := codeowners.Compile(rule.Pattern); err != nil {
+		return fmt.Errorf("%w: %s", modelco.ErrInvalidPattern, err.Error())
+	}
+	return nil
+}
+
+// splitOwners раскладывает владельцев CODEOWNERS на пользователей и команды.
+func splitOwners(owners []string) (users, teams []string) {
+	for _, o := range owners {
+		o = strings.TrimPrefix(o, "@")
+		if i := strings.LastIndex(o, "/"); i >= 0 {
+			teams = append(teams, o[i+1:])
+			continue
+		}
+		users = append(users, o)
+	}
+	return users, teams
+}
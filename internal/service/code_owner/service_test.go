@@ -0,0 +1,54 @@
+package code_owner_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	modelco "github.com/zxchelik/avito-test-task/internal/model/code_owner"
+	coSvc "github.com/zxchelik/avito-test-task/internal/service/code_owner"
+	"github.com/zxchelik/avito-test-task/internal/service/servicetest"
+)
+
+func newService() *coSvc.Service {
+	store := servicetest.NewStore()
+	return coSvc.NewService(store.Owners, store.Tx)
+}
+
+func TestImport(t *testing.T) {
+	svc := newService()
+	ctx := context.Background()
+
+	rules, err := svc.Import(ctx, strings.NewReader("*  @alice\n/docs/  @bob\n"), false)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if len(rules) != 2 || rules[0].Pattern != "*" || rules[1].Pattern != "/docs/" {
+		t.Fatalf("rules = %+v", rules)
+	}
+}
+
+func TestImportInvalidPattern(t *testing.T) {
+	svc := newService()
+
+	_, err := svc.Import(context.Background(), strings.NewReader("*  @alice\n!vendor/  @bob\n"), false)
+	if !errors.Is(err, modelco.ErrInvalidPattern) {
+		t.Fatalf("got %v, want ErrInvalidPattern", err)
+	}
+}
+
+func TestImportReadError(t *testing.T) {
+	svc := newService()
+
+	// ошибка чтения — не ошибка клиента в шаблоне и не должна выдавать себя за неё
+	long := strings.Repeat("a", bufio.MaxScanTokenSize) + "  @bob\n"
+	_, err := svc.Import(context.Background(), strings.NewReader(long), false)
+	if err == nil || errors.Is(err, modelco.ErrInvalidPattern) {
+		t.Fatalf("got %v, want a read error", err)
+	}
+	if !errors.Is(err, bufio.ErrTooLong) {
+		t.Fatalf("got %v, want bufio.ErrTooLong", err)
+	}
+}
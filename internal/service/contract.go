@@ -2,35 +2,101 @@ package service
 
 import (
 	"context"
+	modelabsence "github.com/zxchelik/avito-test-task/internal/model/absence"
+	modelco "github.com/zxchelik/avito-test-task/internal/model/code_owner"
+	modelforge "github.com/zxchelik/avito-test-task/internal/model/forge"
 	modelpr "github.com/zxchelik/avito-test-task/internal/model/pull_request"
 	modelra "github.com/zxchelik/avito-test-task/internal/model/reviewer_assignment"
 	modelteam "github.com/zxchelik/avito-test-task/internal/model/team"
 	modeluser "github.com/zxchelik/avito-test-task/internal/model/user"
+	modelwh "github.com/zxchelik/avito-test-task/internal/model/webhook"
 	"time"
 )
 
 type TeamRepository interface {
-	Create(ctx context.Context, name string) error
+	Create(ctx context.Context, team *modelteam.Team) error
 	GetByName(ctx context.Context, name string) (*modelteam.Team, error)
+	UpdateSettings(ctx context.Context, team *modelteam.Team) (*modelteam.Team, error)
 }
 
 type UserRepository interface {
 	Upsert(ctx context.Context, u *modeluser.User) error
 	GetByID(ctx context.Context, id string) (*modeluser.User, error)
+	ListByIDs(ctx context.Context, ids []string) ([]*modeluser.User, error)
 	ListByTeam(ctx context.Context, teamName string) ([]*modeluser.User, error)
 	SetIsActive(ctx context.Context, id string, isActive bool) (*modeluser.User, error)
+	SetMaxOpenReviews(ctx context.Context, id string, maxOpenReviews int) (*modeluser.User, error)
+	DeactivateMembers(ctx context.Context, teamName string, ids []string) ([]*modeluser.User, error)
 }
 
 type PRRepository interface {
 	Create(ctx context.Context, pr *modelpr.PullRequest) (*modelpr.PullRequest, error)
 	GetByID(ctx context.Context, id string) (*modelpr.PullRequest, error)
+	GetByIDForUpdate(ctx context.Context, id string) (*modelpr.PullRequest, error)
 	MarkMerged(ctx context.Context, id string) (*modelpr.PullRequest, error)
+	UpdateStatus(ctx context.Context, id string, from, to modelpr.PRStatus, at time.Time) (*modelpr.PullRequest, error)
+	List(ctx context.Context, filter modelpr.ListFilter) ([]*modelpr.PullRequest, error)
+	ListByReviewer(ctx context.Context, filter modelpr.ReviewFilter) ([]*modelpr.AssignedReview, error)
 }
 
 type ReviewerAssignmentRepository interface {
 	ListByPR(ctx context.Context, prID string) ([]*modelra.ReviewerAssignment, error)
-	Add(ctx context.Context, prID, userID string, assignedAt time.Time) error
+	ListByPRs(ctx context.Context, prIDs []string) (map[string][]*modelra.ReviewerAssignment, error)
+	Add(ctx context.Context, a *modelra.ReviewerAssignment) error
 	Remove(ctx context.Context, prID, userID string) error
-	Replace(ctx context.Context, prID, oldUserID, newUserID string, assignedAt time.Time) error
+	Replace(ctx context.Context, oldUserID string, next *modelra.ReviewerAssignment) error
+	SetVerdict(ctx context.Context, prID, userID string, verdict modelra.Verdict, at time.Time) (*modelra.ReviewerAssignment, error)
 	ListPRIDsByReviewer(ctx context.Context, userID string) ([]string, error)
+	CountOpenByReviewers(ctx context.Context, userIDs []string) (map[string]int, error)
+	LastAssignedAt(ctx context.Context, userIDs []string) (map[string]time.Time, error)
+	ReassignOpenFrom(ctx context.Context, userIDs []string, at time.Time) ([]*modelra.Reassignment, error)
+	ListSLAPending(ctx context.Context, now time.Time) ([]*modelra.SLAPending, error)
+	MarkSLABreached(ctx context.Context, prID, userID string, assignedAt, at time.Time) (bool, error)
+}
+
+type AssignmentEventRepository interface {
+	Append(ctx context.Context, events ...*modelra.Event) error
+	ListByPR(ctx context.Context, prID string) ([]*modelra.Event, error)
+}
+
+type CodeOwnerRepository interface {
+	Create(ctx context.Context, rule *modelco.Rule) (*modelco.Rule, error)
+	GetByID(ctx context.Context, id int64) (*modelco.Rule, error)
+	List(ctx context.Context) ([]*modelco.Rule, error)
+	Update(ctx context.Context, rule *modelco.Rule) (*modelco.Rule, error)
+	Delete(ctx context.Context, id int64) error
+	DeleteAll(ctx context.Context) error
+}
+
+type AbsenceRepository interface {
+	Create(ctx context.Context, a *modelabsence.Absence) (*modelabsence.Absence, error)
+	GetByID(ctx context.Context, id int64) (*modelabsence.Absence, error)
+	ListByUser(ctx context.Context, userID string, includeCancelled bool) ([]*modelabsence.Absence, error)
+	Cancel(ctx context.Context, id int64, at time.Time) (*modelabsence.Absence, error)
+	ListAbsentUserIDs(ctx context.Context, userIDs []string, at time.Time) (map[string]struct{}, error)
+}
+
+// WebhookOutbox кладёт исходящие события в outbox в транзакции изменения.
+type WebhookOutbox interface {
+	Enqueue(ctx context.Context, msgs ...*modelwh.Message) error
+}
+
+type WebhookRepository interface {
+	WebhookOutbox
+	CreateSubscription(ctx context.Context, s *modelwh.Subscription) (*modelwh.Subscription, error)
+	ListSubscriptions(ctx context.Context) ([]*modelwh.Subscription, error)
+	DeleteSubscription(ctx context.Context, id int64) error
+	ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*modelwh.Delivery, error)
+	MarkDelivered(ctx context.Context, id int64, at time.Time) error
+	MarkFailed(ctx context.Context, id int64, lastErr string, next time.Time, dead bool) error
+	ListDead(ctx context.Context, limit int) ([]*modelwh.Delivery, error)
+	Redeliver(ctx context.Context, id int64, at time.Time) error
+}
+
+type ForgeRepository interface {
+	UpsertIdentity(ctx context.Context, id *modelforge.Identity) (*modelforge.Identity, error)
+	ResolveUser(ctx context.Context, forge modelforge.Forge, username string) (string, error)
+	ListIdentities(ctx context.Context, userID string) ([]*modelforge.Identity, error)
+	DeleteIdentity(ctx context.Context, forge modelforge.Forge, username string) error
+	RegisterDelivery(ctx context.Context, forge modelforge.Forge, deliveryID string, at time.Time) error
 }
@@ -0,0 +1,155 @@
+package forge
+
+import (
+	"context"
+	"errors"
+
+	"github.com/zxchelik/avito-test-task/internal/model"
+	modelforge "github.com/zxchelik/avito-test-task/internal/model/forge"
+	modelpr "github.com/zxchelik/avito-test-task/internal/model/pull_request"
+	modelra "github.com/zxchelik/avito-test-task/internal/model/reviewer_assignment"
+	"github.com/zxchelik/avito-test-task/internal/service"
+	srvpr "github.com/zxchelik/avito-test-task/internal/service/pull_request"
+)
+
+// PRLifecycle — операции над PR, которые вызывают события с хостинга.
+type PRLifecycle interface {
+	Create(ctx context.Context, pr *modelpr.PullRequest, opts srvpr.CreateOptions) (*modelpr.PullRequest, []*modelra.ReviewerAssignment, error)
+	Ready(ctx context.Context, prID string, opts srvpr.CreateOptions) (*modelpr.PullRequest, []*modelra.ReviewerAssignment, error)
+	Close(ctx context.Context, prID string) (*modelpr.PullRequest, error)
+	Reopen(ctx context.Context, prID string) (*modelpr.PullRequest, []*modelra.ReviewerAssignment, error)
+	MarkMergedExternal(ctx context.Context, prID string) (*modelpr.PullRequest, error)
+}
+
+type Service struct {
+	repo  service.ForgeRepository
+	prs   PRLifecycle
+	tx    service.TxManager
+	clock service.Clock
+}
+
+func NewService(repo service.ForgeRepository, prs PRLifecycle, tx service.TxManager) *Service {
+	return &Service{
+		repo:  repo,
+		prs:   prs,
+		tx:    tx,
+		clock: service.DefaultClock,
+	}
+}
+
+// WithClock позволяет подменять время в тестах.
+func (s *Service) WithClock(clock service.Clock) *Service {
+	s.clock = clock
+	return s
+}
+
+// MapIdentity связывает логин на хостинге с пользователем.
+// Ошибки:
+//   - ErrNotFound           — если пользователя нет
+//   - forge.ErrUnknownForge — неизвестный хостинг
+func (s *Service) MapIdentity(ctx context.Context, id *modelforge.Identity) (*modelforge.Identity, error) {
+	if !id.Forge.Valid() {
+		return nil, modelforge.ErrUnknownForge
+	}
+	return s.repo.UpsertIdentity(ctx, id)
+}
+
+// Identities возвращает привязки логинов; пустой userID — все.
+func (s *Service) Identities(ctx context.Context, userID string) ([]*modelforge.Identity, error) {
+	return s.repo.ListIdentities(ctx, userID)
+}
+
+// UnmapIdentity удаляет привязку логина.
+// Ошибки:
+//   - ErrNotFound — если привязки нет
+func (s *Service) UnmapIdentity(ctx context.Context, f modelforge.Forge, username string) error {
+	return s.repo.DeleteIdentity(ctx, f, username)
+}
+
+// Handle применяет событие PR с хостинга от имени системы (auth.System).
+// Доставка запоминается в той же транзакции, что и изменение PR:
+// повтор уже обработанной доставки возвращает ErrDuplicateDelivery,
+// а при ошибке доставка не запоминается и хостинг может прислать её снова.
+// Возвращает PR после изменения; nil — событие не затрагивает PR.
+// Ошибки:
+//   - forge.ErrDuplicateDelivery — доставка уже обработана
+//   - forge.ErrIdentityNotMapped — автор PR не привязан к пользователю
+//   - ошибки pull_request.Service для соответствующего действия
+func (s *Service) Handle(ctx context.Context, ev *modelforge.Event) (*modelpr.PullRequest, error) {
+	// подпись хостинга уже проверена: изменения PR выполняет система
+	ctx = service.AsSystem(ctx)
+
+	var pr *modelpr.PullRequest
+
+	err := s.tx.WithinTransaction(ctx, func(txCtx context.Context) error {
+		if err := s.repo.RegisterDelivery(txCtx, ev.Forge, ev.DeliveryID, s.clock()); err != nil {
+			return err
+		}
+		if ev.Action == "" {
+			return nil
+		}
+
+		// Исполнитель — привязанный отправитель; иначе действие считается системным.
+		if ev.SenderUsername != "" {
+			actor, err := s.repo.ResolveUser(txCtx, ev.Forge, ev.SenderUsername)
+			switch {
+			case err == nil:
+				txCtx = service.WithActor(txCtx, actor)
+			case !errors.Is(err, modelforge.ErrIdentityNotMapped):
+				return err
+			}
+		}
+
+		var err error
+		pr, err = s.apply(txCtx, ev)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pr, nil
+}
+
+func (s *Service) apply(ctx context.Context, ev *modelforge.Event) (*modelpr.PullRequest, error) {
+	prID := ev.PullRequestID()
+
+	switch ev.Action {
+	case modelforge.ActionOpened:
+		authorID, err := s.repo.ResolveUser(ctx, ev.Forge, ev.AuthorUsername)
+		if err != nil {
+			return nil, err
+		}
+		status := modelpr.PROpen
+		if ev.Draft {
+			status = modelpr.PRDraft
+		}
+		created, _, err := s.prs.Create(ctx, &modelpr.PullRequest{
+			ID:       prID,
+			Title:    ev.Title,
+			AuthorID: authorID,
+			Status:   status,
+		}, srvpr.CreateOptions{})
+		if errors.Is(err, model.ErrAlreadyExists) {
+			return nil, nil // PR уже заведён через API
+		}
+		return created, err
+	case modelforge.ActionReady:
+		pr, _, err := s.prs.Ready(ctx, prID, srvpr.CreateOptions{})
+		return pr, err
+	case modelforge.ActionClosed:
+		return s.prs.Close(ctx, prID)
+	case modelforge.ActionReopened:
+		pr, _, err := s.prs.Reopen(ctx, prID)
+		return pr, err
+	case modelforge.ActionMerged:
+		// PR уже смержен на хостинге: правило merge не проверяется
+		merged, err := s.prs.MarkMergedExternal(ctx, prID)
+		if errors.Is(err, modelpr.ErrPRAlreadyMerged) {
+			return nil, nil // PR уже смержен через API
+		}
+		return merged, err
+	default:
+		return nil, nil
+	}
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+
+	modelauth "github.com/zxchelik/avito-test-task/internal/model/auth"
+)
+
+type principalKey struct{}
+
+// WithPrincipal кладёт в контекст вызывающего: аутентифицированного пользователя,
+// auth.Anonymous при выключенной аутентификации или auth.System для действий самой системы.
+func WithPrincipal(ctx context.Context, p *modelauth.Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFrom возвращает вызывающего; false — вызывающего в контексте нет.
+func PrincipalFrom(ctx context.Context) (*modelauth.Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*modelauth.Principal)
+	return p, ok && p != nil
+}
+
+// RequirePrincipal возвращает вызывающего или auth.ErrUnauthorized, если его нет:
+// вызов без вызывающего не получает прав молча.
+func RequirePrincipal(ctx context.Context) (*modelauth.Principal, error) {
+	p, ok := PrincipalFrom(ctx)
+	if !ok {
+		return nil, modelauth.ErrUnauthorized
+	}
+	return p, nil
+}
+
+// AsSystem помечает действия в ctx как выполняемые самой системой.
+func AsSystem(ctx context.Context) context.Context {
+	return WithPrincipal(ctx, modelauth.System)
+}
@@ -0,0 +1,113 @@
+package pull_request_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	modelabsence "github.com/zxchelik/avito-test-task/internal/model/absence"
+	modelpr "github.com/zxchelik/avito-test-task/internal/model/pull_request"
+	modelra "github.com/zxchelik/avito-test-task/internal/model/reviewer_assignment"
+	prSvc "github.com/zxchelik/avito-test-task/internal/service/pull_request"
+	"github.com/zxchelik/avito-test-task/internal/service/servicetest"
+)
+
+var now = time.Date(2025, 3, 3, 10, 0, 0, 0, time.UTC)
+
+// newAbsenceFixture — команда backend с одним ревьювером на PR; r1 в отпуске [now, now+24h).
+// Часы сервиса показывают *clock.
+func newAbsenceFixture(t *testing.T, clock *time.Time) (*servicetest.Store, *prSvc.Service, *modelabsence.Absence) {
+	t.Helper()
+
+	store := servicetest.NewStore()
+	store.AddTeam(t, servicetest.Team("backend", 1), "author", "r1", "r2")
+	a, err := store.Absences.Create(context.Background(), &modelabsence.Absence{
+		UserID: "r1", StartsAt: now, EndsAt: now.Add(24 * time.Hour), Reason: "vacation",
+	})
+	if err != nil {
+		t.Fatalf("create absence: %v", err)
+	}
+	return store, store.PRService().WithClock(func() time.Time { return *clock }), a
+}
+
+func TestCreateSkipsAbsentReviewers(t *testing.T) {
+	tests := []struct {
+		name  string
+		clock time.Time
+		want  string
+	}{
+		{"before absence", now.Add(-time.Minute), ""},
+		{"absence starts", now, "r2"},
+		{"during absence", now.Add(23 * time.Hour), "r2"},
+		{"absence ended", now.Add(24 * time.Hour), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := tt.clock
+			_, svc, _ := newAbsenceFixture(t, &clock)
+
+			// без отсутствия r1 и r2 выбираются поровну: создаём два PR
+			got := map[string]int{}
+			for _, id := range []string{"pr-1", "pr-2"} {
+				pr := &modelpr.PullRequest{ID: id, Title: "feature", AuthorID: "author", Status: modelpr.PROpen}
+				_, reviewers, err := svc.Create(servicetest.System(), pr, prSvc.CreateOptions{})
+				if err != nil {
+					t.Fatalf("create %s: %v", id, err)
+				}
+				got[reviewers[0].UserId]++
+			}
+			if tt.want != "" && got[tt.want] != 2 {
+				t.Fatalf("reviewers = %v, want only %s", got, tt.want)
+			}
+			if tt.want == "" && got["r1"] == 0 {
+				t.Fatalf("reviewers = %v, want r1 available", got)
+			}
+		})
+	}
+}
+
+func TestCreateWhenEveryoneIsAbsent(t *testing.T) {
+	clock := now
+	store, svc, _ := newAbsenceFixture(t, &clock)
+	if _, err := store.Absences.Create(context.Background(), &modelabsence.Absence{
+		UserID: "r2", StartsAt: now, EndsAt: now.Add(time.Hour), Reason: "sick leave",
+	}); err != nil {
+		t.Fatalf("create absence: %v", err)
+	}
+
+	pr := &modelpr.PullRequest{ID: "pr-1", Title: "feature", AuthorID: "author", Status: modelpr.PROpen}
+	if _, _, err := svc.Create(servicetest.System(), pr, prSvc.CreateOptions{}); !errors.Is(err, modelra.ErrNoReviewerCandidatesLeft) {
+		t.Fatalf("got %v, want ErrNoReviewerCandidatesLeft", err)
+	}
+}
+
+func TestReassignSkipsAbsentReviewers(t *testing.T) {
+	clock := now
+	store, svc, a := newAbsenceFixture(t, &clock)
+	if _, err := store.PRs.Create(context.Background(), &modelpr.PullRequest{
+		ID: "pr-1", Title: "feature", AuthorID: "author", Status: modelpr.PROpen,
+	}); err != nil {
+		t.Fatalf("create PR: %v", err)
+	}
+	if err := store.Reviews.Add(context.Background(), &modelra.ReviewerAssignment{PrId: "pr-1", UserId: "r2", AssignedAt: now}); err != nil {
+		t.Fatalf("assign r2: %v", err)
+	}
+
+	// замена для r2 — только r1, а он в отпуске
+	if _, err := svc.Reassign(servicetest.System(), "pr-1", "r2"); !errors.Is(err, modelra.ErrNoReviewerCandidatesLeft) {
+		t.Fatalf("reassign during absence: got %v, want ErrNoReviewerCandidatesLeft", err)
+	}
+
+	// после отмены отпуска r1 сразу доступен
+	if _, err := store.Absences.Cancel(context.Background(), a.ID, now); err != nil {
+		t.Fatalf("cancel absence: %v", err)
+	}
+	next, err := svc.Reassign(servicetest.System(), "pr-1", "r2")
+	if err != nil {
+		t.Fatalf("reassign after cancel: %v", err)
+	}
+	if next.UserId != "r1" {
+		t.Fatalf("replacement = %s, want r1", next.UserId)
+	}
+}
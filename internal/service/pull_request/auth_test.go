@@ -0,0 +1,211 @@
+package pull_request_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	modelauth "github.com/zxchelik/avito-test-task/internal/model/auth"
+	modelpr "github.com/zxchelik/avito-test-task/internal/model/pull_request"
+	modelra "github.com/zxchelik/avito-test-task/internal/model/reviewer_assignment"
+	prSvc "github.com/zxchelik/avito-test-task/internal/service/pull_request"
+	"github.com/zxchelik/avito-test-task/internal/service/servicetest"
+)
+
+// newAuthFixture — команда backend (автор, тимлид и два ревьювера) и команда frontend.
+func newAuthFixture(t *testing.T) (*servicetest.Store, *prSvc.Service) {
+	t.Helper()
+
+	store := servicetest.NewStore()
+	store.AddTeam(t, servicetest.Team("backend", 1), "author", "lead", "r1", "r2")
+	store.AddTeam(t, servicetest.Team("frontend", 1), "f1", "flead")
+	return store, store.PRService()
+}
+
+// createPR создаёт PR автора author от имени системы и возвращает его ревьюверов.
+func createPR(t *testing.T, svc *prSvc.Service, status modelpr.PRStatus) []*modelra.ReviewerAssignment {
+	t.Helper()
+
+	pr := &modelpr.PullRequest{ID: "pr-1", Title: "feature", AuthorID: "author", Status: status}
+	_, reviewers, err := svc.Create(servicetest.System(), pr, prSvc.CreateOptions{})
+	if err != nil {
+		t.Fatalf("create PR: %v", err)
+	}
+	return reviewers
+}
+
+func TestChangeAuthorization(t *testing.T) {
+	actions := map[string]func(ctx context.Context, t *testing.T, svc *prSvc.Service) error{
+		"reassign": func(ctx context.Context, t *testing.T, svc *prSvc.Service) error {
+			reviewers := createPR(t, svc, modelpr.PROpen)
+			_, err := svc.Reassign(ctx, "pr-1", reviewers[0].UserId)
+			return err
+		},
+		"ready": func(ctx context.Context, t *testing.T, svc *prSvc.Service) error {
+			createPR(t, svc, modelpr.PRDraft)
+			_, _, err := svc.Ready(ctx, "pr-1", prSvc.CreateOptions{})
+			return err
+		},
+		"close": func(ctx context.Context, t *testing.T, svc *prSvc.Service) error {
+			createPR(t, svc, modelpr.PROpen)
+			_, err := svc.Close(ctx, "pr-1")
+			return err
+		},
+		"reopen": func(ctx context.Context, t *testing.T, svc *prSvc.Service) error {
+			createPR(t, svc, modelpr.PROpen)
+			if _, err := svc.Close(servicetest.System(), "pr-1"); err != nil {
+				t.Fatalf("close PR: %v", err)
+			}
+			_, _, err := svc.Reopen(ctx, "pr-1")
+			return err
+		},
+	}
+
+	callers := []struct {
+		name string
+		ctx  context.Context
+		want error
+	}{
+		{"author", servicetest.As("author", modelauth.RoleUser), nil},
+		{"team lead", servicetest.As("lead", modelauth.RoleTeamLead), nil},
+		{"admin", servicetest.As("admin", modelauth.RoleAdmin), nil},
+		{"system", servicetest.System(), nil},
+		{"other user", servicetest.As("f1", modelauth.RoleUser), modelauth.ErrForbidden},
+		{"teammate", servicetest.As("r2", modelauth.RoleUser), modelauth.ErrForbidden},
+		{"lead of other team", servicetest.As("flead", modelauth.RoleTeamLead), modelauth.ErrForbidden},
+		{"no principal", context.Background(), modelauth.ErrUnauthorized},
+	}
+
+	for action, run := range actions {
+		for _, c := range callers {
+			t.Run(action+"/"+c.name, func(t *testing.T) {
+				_, svc := newAuthFixture(t)
+
+				err := run(c.ctx, t, svc)
+				if !errors.Is(err, c.want) || (c.want == nil && err != nil) {
+					t.Fatalf("got %v, want %v", err, c.want)
+				}
+			})
+		}
+	}
+}
+
+func TestChangeForbiddenLeavesPRUntouched(t *testing.T) {
+	store, svc := newAuthFixture(t)
+	reviewers := createPR(t, svc, modelpr.PROpen)
+
+	if _, err := svc.Close(servicetest.As("f1", modelauth.RoleUser), "pr-1"); !errors.Is(err, modelauth.ErrForbidden) {
+		t.Fatalf("close: got %v, want ErrForbidden", err)
+	}
+	if _, err := svc.Reassign(servicetest.As("f1", modelauth.RoleUser), "pr-1", reviewers[0].UserId); !errors.Is(err, modelauth.ErrForbidden) {
+		t.Fatalf("reassign: got %v, want ErrForbidden", err)
+	}
+
+	ctx := context.Background()
+	pr, err := store.PRs.GetByID(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("get PR: %v", err)
+	}
+	if pr.Status != modelpr.PROpen {
+		t.Fatalf("status = %s, want OPEN", pr.Status)
+	}
+	current, err := store.Reviews.ListByPR(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("list reviewers: %v", err)
+	}
+	if len(current) != 1 || current[0].UserId != reviewers[0].UserId {
+		t.Fatalf("reviewers changed: %+v", current)
+	}
+}
+
+func TestReviewAuthorization(t *testing.T) {
+	_, svc := newAuthFixture(t)
+	reviewer := createPR(t, svc, modelpr.PROpen)[0].UserId
+	// ревьювер выбирается из команды автора, поэтому тимлидом берётся тот, кого не выбрали
+	lead := "lead"
+	if reviewer == lead {
+		lead = "r1"
+	}
+
+	cases := []struct {
+		name string
+		ctx  context.Context
+		want error
+	}{
+		{"reviewer", servicetest.As(reviewer, modelauth.RoleUser), nil},
+		{"admin", servicetest.As("admin", modelauth.RoleAdmin), nil},
+		{"system", servicetest.System(), nil},
+		{"author", servicetest.As("author", modelauth.RoleUser), modelauth.ErrForbidden},
+		{"team lead", servicetest.As(lead, modelauth.RoleTeamLead), modelauth.ErrForbidden},
+		{"no principal", context.Background(), modelauth.ErrUnauthorized},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, _, err := svc.Review(c.ctx, "pr-1", reviewer, modelra.VerdictApproved)
+			if !errors.Is(err, c.want) || (c.want == nil && err != nil) {
+				t.Fatalf("got %v, want %v", err, c.want)
+			}
+		})
+	}
+}
+
+func TestMergeAuthorization(t *testing.T) {
+	cases := []struct {
+		name string
+		ctx  context.Context
+		want error
+	}{
+		{"team lead", servicetest.As("lead", modelauth.RoleTeamLead), nil},
+		{"admin", servicetest.As("admin", modelauth.RoleAdmin), nil},
+		{"system", servicetest.System(), nil},
+		{"author", servicetest.As("author", modelauth.RoleUser), modelauth.ErrForbidden},
+		{"lead of other team", servicetest.As("flead", modelauth.RoleTeamLead), modelauth.ErrForbidden},
+		{"no principal", context.Background(), modelauth.ErrUnauthorized},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, svc := newAuthFixture(t)
+			createPR(t, svc, modelpr.PROpen)
+
+			_, err := svc.Merge(c.ctx, "pr-1")
+			if !errors.Is(err, c.want) || (c.want == nil && err != nil) {
+				t.Fatalf("got %v, want %v", err, c.want)
+			}
+		})
+	}
+}
+
+func TestCreateAuthorization(t *testing.T) {
+	cases := []struct {
+		name string
+		ctx  context.Context
+		want error
+	}{
+		{"author", servicetest.As("author", modelauth.RoleUser), nil},
+		{"admin", servicetest.As("admin", modelauth.RoleAdmin), nil},
+		{"system", servicetest.System(), nil},
+		{"other user", servicetest.As("r1", modelauth.RoleUser), modelauth.ErrForbidden},
+		{"team lead", servicetest.As("lead", modelauth.RoleTeamLead), modelauth.ErrForbidden},
+		{"no principal", context.Background(), modelauth.ErrUnauthorized},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			store, svc := newAuthFixture(t)
+
+			pr := &modelpr.PullRequest{ID: "pr-1", Title: "feature", AuthorID: "author", Status: modelpr.PROpen}
+			_, _, err := svc.Create(c.ctx, pr, prSvc.CreateOptions{})
+			if !errors.Is(err, c.want) || (c.want == nil && err != nil) {
+				t.Fatalf("got %v, want %v", err, c.want)
+			}
+			// отказ не оставляет ни PR, ни событий в outbox
+			if c.want != nil {
+				if _, err := store.PRs.GetByID(context.Background(), "pr-1"); err == nil {
+					t.Fatal("PR created despite the refusal")
+				}
+				if types := store.OutboxTypes(); len(types) != 0 {
+					t.Fatalf("outbox = %v, want empty", types)
+				}
+			}
+		})
+	}
+}
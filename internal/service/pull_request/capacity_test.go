@@ -0,0 +1,151 @@
+package pull_request_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	modelpr "github.com/zxchelik/avito-test-task/internal/model/pull_request"
+	modelra "github.com/zxchelik/avito-test-task/internal/model/reviewer_assignment"
+	prSvc "github.com/zxchelik/avito-test-task/internal/service/pull_request"
+	"github.com/zxchelik/avito-test-task/internal/service/servicetest"
+)
+
+// newCapacityFixture — команда backend с одним ревьювером на PR и лимитом по умолчанию teamDefault.
+func newCapacityFixture(t *testing.T, teamDefault int, members ...string) (*servicetest.Store, *prSvc.Service) {
+	t.Helper()
+
+	store := servicetest.NewStore()
+	team := servicetest.Team("backend", 1)
+	team.DefaultMaxOpenReviews = teamDefault
+	store.AddTeam(t, team, append([]string{"author"}, members...)...)
+	return store, store.PRService()
+}
+
+func setLimit(t *testing.T, store *servicetest.Store, userID string, limit int) {
+	t.Helper()
+
+	if _, err := store.Users.SetMaxOpenReviews(context.Background(), userID, limit); err != nil {
+		t.Fatalf("set limit of %s: %v", userID, err)
+	}
+}
+
+func createOpenPR(svc *prSvc.Service, id string) ([]*modelra.ReviewerAssignment, error) {
+	pr := &modelpr.PullRequest{ID: id, Title: "feature", AuthorID: "author", Status: modelpr.PROpen}
+	_, reviewers, err := svc.Create(servicetest.System(), pr, prSvc.CreateOptions{})
+	return reviewers, err
+}
+
+func TestCreateSkipsReviewersAtCapacity(t *testing.T) {
+	store, svc := newCapacityFixture(t, 0, "r1", "r2")
+	setLimit(t, store, "r1", 1)
+
+	// r1 берёт одно ревью, дальше всё достаётся r2 без лимита
+	load := map[string]int{}
+	for i := range 4 {
+		reviewers, err := createOpenPR(svc, fmt.Sprintf("pr-%d", i))
+		if err != nil {
+			t.Fatalf("create pr-%d: %v", i, err)
+		}
+		load[reviewers[0].UserId]++
+	}
+	if load["r1"] != 1 || load["r2"] != 3 {
+		t.Fatalf("load = %v, want r1: 1, r2: 3", load)
+	}
+}
+
+func TestCreateAtCapacity(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(t *testing.T, store *servicetest.Store)
+		teamCap int
+		prs     int // сколько PR помещается
+	}{
+		{name: "team default", teamCap: 1, prs: 2},
+		{name: "personal limits", setup: func(t *testing.T, store *servicetest.Store) {
+			setLimit(t, store, "r1", 2)
+			setLimit(t, store, "r2", 1)
+		}, prs: 3},
+		// личный лимит важнее лимита команды в обе стороны
+		{name: "personal over team default", teamCap: 5, setup: func(t *testing.T, store *servicetest.Store) {
+			setLimit(t, store, "r1", 1)
+			setLimit(t, store, "r2", 1)
+		}, prs: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, svc := newCapacityFixture(t, tt.teamCap, "r1", "r2")
+			if tt.setup != nil {
+				tt.setup(t, store)
+			}
+
+			for i := range tt.prs {
+				if _, err := createOpenPR(svc, fmt.Sprintf("pr-%d", i)); err != nil {
+					t.Fatalf("create pr-%d: %v", i, err)
+				}
+			}
+			_, err := createOpenPR(svc, "pr-full")
+			if !errors.Is(err, modelra.ErrReviewersAtCapacity) || errors.Is(err, modelra.ErrNoReviewerCandidatesLeft) {
+				t.Fatalf("got %v, want only ErrReviewersAtCapacity", err)
+			}
+		})
+	}
+}
+
+func TestCreateAtCapacityReleasedByMerge(t *testing.T) {
+	_, svc := newCapacityFixture(t, 1, "r1")
+
+	if _, err := createOpenPR(svc, "pr-1"); err != nil {
+		t.Fatalf("create pr-1: %v", err)
+	}
+	if _, err := createOpenPR(svc, "pr-2"); !errors.Is(err, modelra.ErrReviewersAtCapacity) {
+		t.Fatalf("create pr-2: got %v, want ErrReviewersAtCapacity", err)
+	}
+
+	// нагрузка — только OPEN PR
+	if _, err := svc.Merge(servicetest.System(), "pr-1"); err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	if _, err := createOpenPR(svc, "pr-2"); err != nil {
+		t.Fatalf("create pr-2 after merge: %v", err)
+	}
+}
+
+func TestReassignSkipsReviewersAtCapacity(t *testing.T) {
+	store, svc := newCapacityFixture(t, 0, "r1", "r2", "r3")
+	setLimit(t, store, "r2", 1)
+	setLimit(t, store, "r3", 1)
+
+	assign := func(prID string, reviewers ...string) {
+		t.Helper()
+
+		pr := &modelpr.PullRequest{ID: prID, Title: "feature", AuthorID: "author", Status: modelpr.PROpen}
+		if _, err := store.PRs.Create(context.Background(), pr); err != nil {
+			t.Fatalf("create %s: %v", prID, err)
+		}
+		for _, id := range reviewers {
+			if err := store.Reviews.Add(context.Background(), &modelra.ReviewerAssignment{PrId: prID, UserId: id}); err != nil {
+				t.Fatalf("assign %s to %s: %v", id, prID, err)
+			}
+		}
+	}
+	// r1 — ревьювер pr-1, r2 занят своим PR
+	assign("pr-1", "r1")
+	assign("pr-2", "r2")
+
+	// r2 на пределе, свободен только r3
+	next, err := svc.Reassign(servicetest.System(), "pr-1", "r1")
+	if err != nil {
+		t.Fatalf("reassign: %v", err)
+	}
+	if next.UserId != "r3" {
+		t.Fatalf("replacement = %s, want r3", next.UserId)
+	}
+
+	// теперь на пределе и r3: r1 подошёл бы, но он заменяемый ревьювер
+	assign("pr-3", "r1")
+	if _, err := svc.Reassign(servicetest.System(), "pr-3", "r1"); !errors.Is(err, modelra.ErrReviewersAtCapacity) {
+		t.Fatalf("reassign with everyone full: got %v, want ErrReviewersAtCapacity", err)
+	}
+}
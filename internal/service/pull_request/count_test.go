@@ -0,0 +1,67 @@
+package pull_request_test
+
+import (
+	"errors"
+	"testing"
+
+	modelpr "github.com/zxchelik/avito-test-task/internal/model/pull_request"
+	modelra "github.com/zxchelik/avito-test-task/internal/model/reviewer_assignment"
+	prSvc "github.com/zxchelik/avito-test-task/internal/service/pull_request"
+	"github.com/zxchelik/avito-test-task/internal/service/servicetest"
+)
+
+func TestCreateReviewersCount(t *testing.T) {
+	count := func(n int) *int { return &n }
+	tests := []struct {
+		name     string
+		min, max int
+		members  []string
+		override *int
+		want     int
+		wantErr  error
+	}{
+		{name: "team max by default", min: 1, max: 3, members: []string{"r1", "r2", "r3", "r4"}, want: 3},
+		{name: "single reviewer team", min: 1, max: 1, members: []string{"r1", "r2"}, want: 1},
+		{name: "override within bounds", min: 1, max: 3, members: []string{"r1", "r2", "r3"}, override: count(2), want: 2},
+		{name: "override at min", min: 1, max: 3, members: []string{"r1", "r2", "r3"}, override: count(1), want: 1},
+		{name: "override below min", min: 2, max: 3, members: []string{"r1", "r2", "r3"}, override: count(1), wantErr: modelpr.ErrReviewersCountOutOfBounds},
+		{name: "override above max", min: 1, max: 2, members: []string{"r1", "r2", "r3"}, override: count(3), wantErr: modelpr.ErrReviewersCountOutOfBounds},
+		{name: "no reviewers required", min: 0, max: 2, members: nil, want: 0},
+		// кандидатов меньше max, но не меньше min — PR создаётся с тем, что есть
+		{name: "fewer candidates than max", min: 1, max: 3, members: []string{"r1"}, want: 1},
+		{name: "fewer candidates than min", min: 2, max: 3, members: []string{"r1"}, wantErr: modelra.ErrNoReviewerCandidatesLeft},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := servicetest.NewStore()
+			team := servicetest.Team("backend", tt.max)
+			team.MinReviewers = tt.min
+			store.AddTeam(t, team, append([]string{"author"}, tt.members...)...)
+			svc := store.PRService()
+
+			pr := &modelpr.PullRequest{ID: "pr-1", Title: "feature", AuthorID: "author", Status: modelpr.PROpen}
+			_, reviewers, err := svc.Create(servicetest.System(), pr, prSvc.CreateOptions{ReviewersCount: tt.override})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("got %v, want %v", err, tt.wantErr)
+				}
+				// PR не создан: подбор ревьюверов идёт в той же транзакции
+				if _, err := svc.GetByID(servicetest.System(), "pr-1"); err == nil {
+					t.Fatal("PR created despite the error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("create: %v", err)
+			}
+			if len(reviewers) != tt.want {
+				t.Fatalf("got %d reviewers, want %d: %+v", len(reviewers), tt.want, reviewers)
+			}
+			for _, r := range reviewers {
+				if r.UserId == "author" {
+					t.Fatal("author assigned to review their own PR")
+				}
+			}
+		})
+	}
+}
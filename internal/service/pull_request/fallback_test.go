@@ -0,0 +1,151 @@
+package pull_request_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	modelpr "github.com/zxchelik/avito-test-task/internal/model/pull_request"
+	modelra "github.com/zxchelik/avito-test-task/internal/model/reviewer_assignment"
+	prSvc "github.com/zxchelik/avito-test-task/internal/service/pull_request"
+	"github.com/zxchelik/avito-test-task/internal/service/servicetest"
+)
+
+// newFallbackFixture — в команде автора один ревьювер, резервные команды platform и sre по порядку.
+func newFallbackFixture(t *testing.T, maxReviewers int) (*servicetest.Store, *prSvc.Service) {
+	t.Helper()
+
+	store := servicetest.NewStore()
+	store.AddTeam(t, servicetest.Team("platform", 1), "p1", "p2")
+	store.AddTeam(t, servicetest.Team("sre", 1), "s1")
+	backend := servicetest.Team("backend", maxReviewers)
+	backend.FallbackTeams = []string{"platform", "sre"}
+	store.AddTeam(t, backend, "author", "b1")
+	return store, store.PRService()
+}
+
+func reviewersByID(reviewers []*modelra.ReviewerAssignment) map[string]*modelra.ReviewerAssignment {
+	res := make(map[string]*modelra.ReviewerAssignment, len(reviewers))
+	for _, r := range reviewers {
+		res[r.UserId] = r
+	}
+	return res
+}
+
+func TestCreateSpillsOverToFallbackTeams(t *testing.T) {
+	_, svc := newFallbackFixture(t, 4)
+
+	pr := &modelpr.PullRequest{ID: "pr-1", Title: "feature", AuthorID: "author", Status: modelpr.PROpen}
+	_, reviewers, err := svc.Create(servicetest.System(), pr, prSvc.CreateOptions{})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	// команда автора, затем platform целиком, затем sre
+	got := reviewersByID(reviewers)
+	if len(got) != 4 || got["b1"] == nil || got["p1"] == nil || got["p2"] == nil || got["s1"] == nil {
+		t.Fatalf("reviewers = %+v, want b1, p1, p2, s1", reviewers)
+	}
+	if got["b1"].Fallback {
+		t.Fatal("member of the author's team marked as fallback")
+	}
+	for _, id := range []string{"p1", "p2", "s1"} {
+		if !got[id].Fallback {
+			t.Fatalf("%s is not marked as fallback", id)
+		}
+	}
+
+	// пометка сохраняется вместе с назначением
+	_, stored, err := svc.Get(context.Background(), "pr-1")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	for _, r := range stored {
+		if r.Fallback != got[r.UserId].Fallback {
+			t.Fatalf("stored %s fallback = %v, want %v", r.UserId, r.Fallback, got[r.UserId].Fallback)
+		}
+	}
+}
+
+func TestCreateFallbackTeamsInOrder(t *testing.T) {
+	_, svc := newFallbackFixture(t, 2)
+
+	pr := &modelpr.PullRequest{ID: "pr-1", Title: "feature", AuthorID: "author", Status: modelpr.PROpen}
+	_, reviewers, err := svc.Create(servicetest.System(), pr, prSvc.CreateOptions{})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	// одного места хватает platform: до sre очередь не доходит
+	got := reviewersByID(reviewers)
+	if len(got) != 2 || got["b1"] == nil || got["s1"] != nil {
+		t.Fatalf("reviewers = %+v, want b1 and a member of platform", reviewers)
+	}
+}
+
+func TestCreateSkipsInactiveFallbackMembers(t *testing.T) {
+	store, svc := newFallbackFixture(t, 3)
+	ctx := context.Background()
+	for _, id := range []string{"b1", "p1"} {
+		if _, err := store.Users.SetIsActive(ctx, id, false); err != nil {
+			t.Fatalf("deactivate %s: %v", id, err)
+		}
+	}
+
+	pr := &modelpr.PullRequest{ID: "pr-1", Title: "feature", AuthorID: "author", Status: modelpr.PROpen}
+	_, reviewers, err := svc.Create(servicetest.System(), pr, prSvc.CreateOptions{})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	ids := make([]string, 0, len(reviewers))
+	for _, r := range reviewers {
+		ids = append(ids, r.UserId)
+	}
+	slices.Sort(ids)
+	if !slices.Equal(ids, []string{"p2", "s1"}) {
+		t.Fatalf("reviewers = %v, want [p2 s1]", ids)
+	}
+}
+
+func TestCreateWithoutFallbackTeams(t *testing.T) {
+	store := servicetest.NewStore()
+	store.AddTeam(t, servicetest.Team("backend", 2), "author")
+	svc := store.PRService()
+
+	pr := &modelpr.PullRequest{ID: "pr-1", Title: "feature", AuthorID: "author", Status: modelpr.PROpen}
+	if _, _, err := svc.Create(servicetest.System(), pr, prSvc.CreateOptions{}); !errors.Is(err, modelra.ErrNoReviewerCandidatesLeft) {
+		t.Fatalf("got %v, want ErrNoReviewerCandidatesLeft", err)
+	}
+}
+
+func TestReassignSpillsOverToFallbackTeams(t *testing.T) {
+	_, svc := newFallbackFixture(t, 1)
+
+	pr := &modelpr.PullRequest{ID: "pr-1", Title: "feature", AuthorID: "author", Status: modelpr.PROpen}
+	_, reviewers, err := svc.Create(servicetest.System(), pr, prSvc.CreateOptions{})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if len(reviewers) != 1 || reviewers[0].UserId != "b1" || reviewers[0].Fallback {
+		t.Fatalf("reviewers = %+v, want b1 from the author's team", reviewers)
+	}
+
+	// в команде автора замены для b1 нет
+	next, err := svc.Reassign(servicetest.System(), "pr-1", "b1")
+	if err != nil {
+		t.Fatalf("reassign: %v", err)
+	}
+	if (next.UserId != "p1" && next.UserId != "p2") || !next.Fallback {
+		t.Fatalf("replacement = %+v, want a fallback member of platform", next)
+	}
+
+	// замена из резервной команды может вернуть ревью в команду автора
+	back, err := svc.Reassign(servicetest.System(), "pr-1", next.UserId)
+	if err != nil {
+		t.Fatalf("reassign back: %v", err)
+	}
+	if back.UserId != "b1" || back.Fallback {
+		t.Fatalf("replacement = %+v, want b1 from the author's team", back)
+	}
+}
@@ -0,0 +1,96 @@
+package pull_request_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/zxchelik/avito-test-task/internal/model"
+	modelauth "github.com/zxchelik/avito-test-task/internal/model/auth"
+	modelpr "github.com/zxchelik/avito-test-task/internal/model/pull_request"
+	modelra "github.com/zxchelik/avito-test-task/internal/model/reviewer_assignment"
+	"github.com/zxchelik/avito-test-task/internal/service"
+	prSvc "github.com/zxchelik/avito-test-task/internal/service/pull_request"
+	"github.com/zxchelik/avito-test-task/internal/service/servicetest"
+)
+
+// as — контекст вызывающего, как его собирает HTTP-слой: с исполнителем для истории.
+func as(subject string, role modelauth.Role) context.Context {
+	return service.WithActor(servicetest.As(subject, role), subject)
+}
+
+func TestHistoryRecordsEveryChange(t *testing.T) {
+	store := servicetest.NewStore()
+	store.AddTeam(t, servicetest.Team("backend", 1), "author", "lead", "r1")
+	svc := store.PRService()
+
+	pr := &modelpr.PullRequest{ID: "pr-1", Title: "feature", AuthorID: "author", Status: modelpr.PROpen}
+	_, reviewers, err := svc.Create(as("author", modelauth.RoleUser), pr, prSvc.CreateOptions{})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	first := reviewers[0].UserId
+	next, err := svc.Reassign(as("author", modelauth.RoleUser), "pr-1", first)
+	if err != nil {
+		t.Fatalf("reassign: %v", err)
+	}
+	if _, _, err := svc.Review(as(next.UserId, modelauth.RoleUser), "pr-1", next.UserId, modelra.VerdictApproved); err != nil {
+		t.Fatalf("review: %v", err)
+	}
+	if _, err := svc.Merge(as("lead", modelauth.RoleTeamLead), "pr-1"); err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+
+	events, err := svc.History(context.Background(), "pr-1")
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	want := []modelra.Event{
+		{Type: modelra.EventStatusChanged, Status: "OPEN", ActorId: "author", Reason: modelra.ReasonPRCreated},
+		{Type: modelra.EventAssigned, UserId: first, ActorId: "author", Reason: modelra.ReasonPRCreated},
+		// прежний ревьювер остаётся в истории после замены
+		{Type: modelra.EventReplaced, UserId: next.UserId, PreviousUserId: first, ActorId: "author", Reason: modelra.ReasonManual},
+		{Type: modelra.EventVerdict, UserId: next.UserId, Verdict: modelra.VerdictApproved, ActorId: next.UserId},
+		{Type: modelra.EventMerged, Status: "MERGED", ActorId: "lead"},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("history = %+v, want %d events", events, len(want))
+	}
+	for i, w := range want {
+		g := *events[i]
+		if g.CreatedAt.IsZero() || g.PrId != "pr-1" {
+			t.Fatalf("event %d = %+v", i, g)
+		}
+		g.ID, g.PrId, g.CreatedAt = 0, "", w.CreatedAt
+		if g != w {
+			t.Fatalf("event %d = %+v, want %+v", i, g, w)
+		}
+	}
+}
+
+func TestHistoryRolledBackWithChange(t *testing.T) {
+	store := servicetest.NewStore()
+	store.AddTeam(t, servicetest.Team("backend", 1), "author", "r1")
+	svc := store.PRService()
+
+	pr := &modelpr.PullRequest{ID: "pr-1", Title: "feature", AuthorID: "author", Status: modelpr.PROpen}
+	if _, _, err := svc.Create(servicetest.System(), pr, prSvc.CreateOptions{}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	// замены для r1 нет: неудачная операция не оставляет следов в истории
+	if _, err := svc.Reassign(servicetest.System(), "pr-1", "r1"); !errors.Is(err, modelra.ErrNoReviewerCandidatesLeft) {
+		t.Fatalf("reassign: got %v, want ErrNoReviewerCandidatesLeft", err)
+	}
+	events, err := svc.History(context.Background(), "pr-1")
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("history = %+v, want creation and assignment only", events)
+	}
+
+	if _, err := svc.History(context.Background(), "missing"); !errors.Is(err, model.ErrNotFound) {
+		t.Fatalf("history of missing PR: got %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,210 @@
+package pull_request_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	modelpr "github.com/zxchelik/avito-test-task/internal/model/pull_request"
+	modelra "github.com/zxchelik/avito-test-task/internal/model/reviewer_assignment"
+	prSvc "github.com/zxchelik/avito-test-task/internal/service/pull_request"
+	"github.com/zxchelik/avito-test-task/internal/service/servicetest"
+)
+
+// newLifecycleFixture — команда backend с одним ревьювером на PR и r1 как единственным кандидатом.
+func newLifecycleFixture(t *testing.T) (*servicetest.Store, *prSvc.Service) {
+	t.Helper()
+
+	store := servicetest.NewStore()
+	store.AddTeam(t, servicetest.Team("backend", 1), "author", "r1")
+	return store, store.PRService()
+}
+
+func createWithStatus(t *testing.T, svc *prSvc.Service, id string, status modelpr.PRStatus) []*modelra.ReviewerAssignment {
+	t.Helper()
+
+	pr := &modelpr.PullRequest{ID: id, Title: "feature", AuthorID: "author", Status: status}
+	_, reviewers, err := svc.Create(servicetest.System(), pr, prSvc.CreateOptions{})
+	if err != nil {
+		t.Fatalf("create %s: %v", id, err)
+	}
+	return reviewers
+}
+
+func TestDraftGetsReviewersWhenReady(t *testing.T) {
+	store, svc := newLifecycleFixture(t)
+
+	if reviewers := createWithStatus(t, svc, "pr-1", modelpr.PRDraft); len(reviewers) != 0 {
+		t.Fatalf("draft reviewers = %+v, want none", reviewers)
+	}
+	if load, _ := store.Reviews.CountOpenByReviewers(context.Background(), []string{"r1"}); load["r1"] != 0 {
+		t.Fatalf("draft counted in load: %v", load)
+	}
+
+	opened, reviewers, err := svc.Ready(servicetest.System(), "pr-1", prSvc.CreateOptions{})
+	if err != nil {
+		t.Fatalf("ready: %v", err)
+	}
+	if opened.Status != modelpr.PROpen || len(reviewers) != 1 || reviewers[0].UserId != "r1" {
+		t.Fatalf("ready = %+v, %+v", opened, reviewers)
+	}
+
+	if _, _, err := svc.Ready(servicetest.System(), "pr-1", prSvc.CreateOptions{}); !errors.Is(err, modelpr.ErrInvalidTransition) {
+		t.Fatalf("ready twice: got %v, want ErrInvalidTransition", err)
+	}
+}
+
+func TestReadyFailureKeepsDraft(t *testing.T) {
+	store, svc := newLifecycleFixture(t)
+	createWithStatus(t, svc, "pr-1", modelpr.PRDraft)
+	if _, err := store.Users.SetIsActive(context.Background(), "r1", false); err != nil {
+		t.Fatalf("deactivate: %v", err)
+	}
+
+	if _, _, err := svc.Ready(servicetest.System(), "pr-1", prSvc.CreateOptions{}); !errors.Is(err, modelra.ErrNoReviewerCandidatesLeft) {
+		t.Fatalf("got %v, want ErrNoReviewerCandidatesLeft", err)
+	}
+	if pr, _ := store.PRs.GetByID(context.Background(), "pr-1"); pr.Status != modelpr.PRDraft {
+		t.Fatalf("status = %s, want DRAFT", pr.Status)
+	}
+}
+
+func TestCloseReleasesLoadAndReopenRestoresReviewers(t *testing.T) {
+	store, svc := newLifecycleFixture(t)
+	if _, err := store.Users.SetMaxOpenReviews(context.Background(), "r1", 1); err != nil {
+		t.Fatalf("set limit: %v", err)
+	}
+	createWithStatus(t, svc, "pr-1", modelpr.PROpen)
+
+	closed, err := svc.Close(servicetest.System(), "pr-1")
+	if err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if closed.Status != modelpr.PRClosed || closed.ClosedAt == nil {
+		t.Fatalf("closed = %+v", closed)
+	}
+
+	// закрытый PR не держит место r1
+	createWithStatus(t, svc, "pr-2", modelpr.PROpen)
+
+	// при переоткрытии ревьюверы прежние, даже сверх лимита
+	reopened, reviewers, err := svc.Reopen(servicetest.System(), "pr-1")
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if reopened.Status != modelpr.PROpen || reopened.ClosedAt != nil || len(reviewers) != 1 || reviewers[0].UserId != "r1" {
+		t.Fatalf("reopen = %+v, %+v", reopened, reviewers)
+	}
+}
+
+func TestReopenClosedDraftAssignsReviewers(t *testing.T) {
+	_, svc := newLifecycleFixture(t)
+	createWithStatus(t, svc, "pr-1", modelpr.PRDraft)
+	if _, err := svc.Close(servicetest.System(), "pr-1"); err != nil {
+		t.Fatalf("close draft: %v", err)
+	}
+
+	_, reviewers, err := svc.Reopen(servicetest.System(), "pr-1")
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if len(reviewers) != 1 || reviewers[0].UserId != "r1" {
+		t.Fatalf("reviewers = %+v, want r1", reviewers)
+	}
+}
+
+func TestInvalidTransitions(t *testing.T) {
+	type step func(svc *prSvc.Service) error
+	var (
+		ready = func(svc *prSvc.Service) error {
+			_, _, err := svc.Ready(servicetest.System(), "pr-1", prSvc.CreateOptions{})
+			return err
+		}
+		closePR = func(svc *prSvc.Service) error {
+			_, err := svc.Close(servicetest.System(), "pr-1")
+			return err
+		}
+		reopen = func(svc *prSvc.Service) error {
+			_, _, err := svc.Reopen(servicetest.System(), "pr-1")
+			return err
+		}
+		merge = func(svc *prSvc.Service) error {
+			_, err := svc.Merge(servicetest.System(), "pr-1")
+			return err
+		}
+		reassign = func(svc *prSvc.Service) error {
+			_, err := svc.Reassign(servicetest.System(), "pr-1", "r1")
+			return err
+		}
+	)
+
+	cases := []struct {
+		name    string
+		status  modelpr.PRStatus
+		prepare []step
+		action  step
+		want    error
+	}{
+		{"reopen open", modelpr.PROpen, nil, reopen, modelpr.ErrInvalidTransition},
+		{"reopen draft", modelpr.PRDraft, nil, reopen, modelpr.ErrInvalidTransition},
+		{"ready open", modelpr.PROpen, nil, ready, modelpr.ErrInvalidTransition},
+		{"ready closed", modelpr.PROpen, []step{closePR}, ready, modelpr.ErrInvalidTransition},
+		{"close closed", modelpr.PROpen, []step{closePR}, closePR, modelpr.ErrInvalidTransition},
+		{"close merged", modelpr.PROpen, []step{merge}, closePR, modelpr.ErrInvalidTransition},
+		{"reopen merged", modelpr.PROpen, []step{merge}, reopen, modelpr.ErrInvalidTransition},
+		{"merge draft", modelpr.PRDraft, nil, merge, modelpr.ErrInvalidTransition},
+		{"reassign on closed", modelpr.PROpen, []step{closePR}, reassign, modelpr.ErrPRNotOpen},
+		{"reassign on merged", modelpr.PROpen, []step{merge}, reassign, modelpr.ErrPRAlreadyMerged},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			store, svc := newLifecycleFixture(t)
+			createWithStatus(t, svc, "pr-1", c.status)
+			for _, p := range c.prepare {
+				if err := p(svc); err != nil {
+					t.Fatalf("prepare: %v", err)
+				}
+			}
+			before, err := store.PRs.GetByID(context.Background(), "pr-1")
+			if err != nil {
+				t.Fatalf("get: %v", err)
+			}
+
+			if err := c.action(svc); !errors.Is(err, c.want) {
+				t.Fatalf("got %v, want %v", err, c.want)
+			}
+			if after, _ := store.PRs.GetByID(context.Background(), "pr-1"); after.Status != before.Status {
+				t.Fatalf("status changed from %s to %s", before.Status, after.Status)
+			}
+		})
+	}
+}
+
+func TestLifecycleHistory(t *testing.T) {
+	store, svc := newLifecycleFixture(t)
+	createWithStatus(t, svc, "pr-1", modelpr.PRDraft)
+	for _, step := range []func() error{
+		func() error { _, _, err := svc.Ready(servicetest.System(), "pr-1", prSvc.CreateOptions{}); return err },
+		func() error { _, err := svc.Close(servicetest.System(), "pr-1"); return err },
+		func() error { _, _, err := svc.Reopen(servicetest.System(), "pr-1"); return err },
+	} {
+		if err := step(); err != nil {
+			t.Fatalf("transition: %v", err)
+		}
+	}
+
+	events, err := store.Events.ListByPR(context.Background(), "pr-1")
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	var statuses []string
+	for _, e := range events {
+		if e.Type == modelra.EventStatusChanged {
+			statuses = append(statuses, e.Status)
+		}
+	}
+	if want := []string{"DRAFT", "OPEN", "CLOSED", "OPEN"}; !slices.Equal(statuses, want) {
+		t.Fatalf("statuses = %v, want %v", statuses, want)
+	}
+}
@@ -0,0 +1,104 @@
+package pull_request_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/zxchelik/avito-test-task/internal/model"
+	modelpr "github.com/zxchelik/avito-test-task/internal/model/pull_request"
+	prSvc "github.com/zxchelik/avito-test-task/internal/service/pull_request"
+	"github.com/zxchelik/avito-test-task/internal/service/servicetest"
+)
+
+// newListFixture — команда backend и три открытых PR автора с одним ревьювером каждый.
+func newListFixture(t *testing.T) *prSvc.Service {
+	t.Helper()
+
+	store := servicetest.NewStore()
+	store.AddTeam(t, servicetest.Team("backend", 1), "author", "r1", "r2")
+	svc := store.PRService()
+	for i := range 3 {
+		pr := &modelpr.PullRequest{ID: fmt.Sprintf("pr-%d", i+1), Title: "feature", AuthorID: "author", Status: modelpr.PROpen}
+		if _, _, err := svc.Create(servicetest.System(), pr, prSvc.CreateOptions{}); err != nil {
+			t.Fatalf("create %s: %v", pr.ID, err)
+		}
+	}
+	return svc
+}
+
+func TestGetReturnsReviewers(t *testing.T) {
+	svc := newListFixture(t)
+
+	pr, reviewers, err := svc.Get(context.Background(), "pr-2")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if pr.ID != "pr-2" || pr.Status != modelpr.PROpen || pr.CreatedAt.IsZero() {
+		t.Fatalf("pr = %+v", pr)
+	}
+	if len(reviewers) != 1 || reviewers[0].PrId != "pr-2" || reviewers[0].AssignedAt.IsZero() {
+		t.Fatalf("reviewers = %+v", reviewers)
+	}
+
+	if _, _, err := svc.Get(context.Background(), "missing"); !errors.Is(err, model.ErrNotFound) {
+		t.Fatalf("get missing: got %v, want ErrNotFound", err)
+	}
+}
+
+func TestListPages(t *testing.T) {
+	svc := newListFixture(t)
+	ctx := context.Background()
+
+	var ids []string
+	filter := modelpr.ListFilter{Limit: 2}
+	for {
+		page, err := svc.List(ctx, filter)
+		if err != nil {
+			t.Fatalf("list: %v", err)
+		}
+		for _, pr := range page.PullRequests {
+			ids = append(ids, pr.ID)
+			if len(page.Reviewers[pr.ID]) != 1 {
+				t.Fatalf("reviewers of %s = %+v", pr.ID, page.Reviewers[pr.ID])
+			}
+		}
+		if page.Next == nil {
+			break
+		}
+		// курсор указывает на последний PR страницы
+		last := page.PullRequests[len(page.PullRequests)-1]
+		if page.Next.ID != last.ID || !page.Next.CreatedAt.Equal(last.CreatedAt) {
+			t.Fatalf("next = %+v, want the last PR %s", page.Next, last.ID)
+		}
+		filter.After = page.Next
+	}
+	if !slices.Equal(ids, []string{"pr-1", "pr-2", "pr-3"}) {
+		t.Fatalf("pages = %v", ids)
+	}
+
+	// ровно заполненная страница — последняя
+	page, err := svc.List(ctx, modelpr.ListFilter{Limit: 3})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(page.PullRequests) != 3 || page.Next != nil {
+		t.Fatalf("page = %d PRs, next %+v", len(page.PullRequests), page.Next)
+	}
+}
+
+func TestListInvalidFilter(t *testing.T) {
+	svc := newListFixture(t)
+
+	for name, filter := range map[string]modelpr.ListFilter{
+		"negative limit": {Limit: -1},
+		"limit too big":  {Limit: modelpr.MaxListLimit + 1},
+		"unknown status": {Statuses: []modelpr.PRStatus{"REVIEWED"}},
+	} {
+		if _, err := svc.List(context.Background(), filter); !errors.Is(err, modelpr.ErrInvalidListFilter) {
+			t.Fatalf("%s: got %v, want ErrInvalidListFilter", name, err)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package pull_request_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	modelauth "github.com/zxchelik/avito-test-task/internal/model/auth"
+	modelpr "github.com/zxchelik/avito-test-task/internal/model/pull_request"
+	prSvc "github.com/zxchelik/avito-test-task/internal/service/pull_request"
+	"github.com/zxchelik/avito-test-task/internal/service/servicetest"
+)
+
+func TestMarkMergedExternalSkipsRuleAndRole(t *testing.T) {
+	store, _ := newAuthFixture(t)
+	svc := store.PRService().WithMergeRule(modelpr.MergeRule{Kind: modelpr.MergeRuleAllApproved})
+	createPR(t, svc, modelpr.PROpen)
+
+	var notApproved *modelpr.NotApprovedError
+	if _, err := svc.Merge(servicetest.System(), "pr-1"); !errors.As(err, &notApproved) {
+		t.Fatalf("merge: got %v, want NotApprovedError", err)
+	}
+
+	// без вызывающего и без одобрений: merge уже произошёл на хостинге
+	merged, err := svc.MarkMergedExternal(context.Background(), "pr-1")
+	if err != nil {
+		t.Fatalf("mark merged: %v", err)
+	}
+	if merged.Status != modelpr.PRMerged || merged.MergedAt == nil {
+		t.Fatalf("PR not merged: %+v", merged)
+	}
+
+	if _, err := svc.MarkMergedExternal(context.Background(), "pr-1"); !errors.Is(err, modelpr.ErrPRAlreadyMerged) {
+		t.Fatalf("second mark: got %v, want ErrPRAlreadyMerged", err)
+	}
+}
+
+func TestMarkMergedExternalClosedPR(t *testing.T) {
+	_, svc := newAuthFixture(t)
+	createPR(t, svc, modelpr.PROpen)
+	if _, err := svc.Close(servicetest.As("author", modelauth.RoleUser), "pr-1"); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	merged, err := svc.MarkMergedExternal(context.Background(), "pr-1")
+	if err != nil {
+		t.Fatalf("mark merged: %v", err)
+	}
+	if merged.Status != modelpr.PRMerged {
+		t.Fatalf("status = %s, want MERGED", merged.Status)
+	}
+}
+
+func TestMergeRejectsNotOpenPR(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(t *testing.T, svc *prSvc.Service)
+		want    error
+	}{
+		{"merged", func(t *testing.T, svc *prSvc.Service) {
+			createPR(t, svc, modelpr.PROpen)
+			if _, err := svc.MarkMergedExternal(context.Background(), "pr-1"); err != nil {
+				t.Fatalf("mark merged: %v", err)
+			}
+		}, modelpr.ErrPRAlreadyMerged},
+		{"draft", func(t *testing.T, svc *prSvc.Service) {
+			createPR(t, svc, modelpr.PRDraft)
+		}, modelpr.ErrInvalidTransition},
+		{"closed", func(t *testing.T, svc *prSvc.Service) {
+			createPR(t, svc, modelpr.PROpen)
+			if _, err := svc.Close(servicetest.System(), "pr-1"); err != nil {
+				t.Fatalf("close: %v", err)
+			}
+		}, modelpr.ErrInvalidTransition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, _ := newAuthFixture(t)
+			// правило, которое не выполнить: статус должен отказать раньше правила
+			svc := store.PRService().WithMergeRule(modelpr.MergeRule{Kind: modelpr.MergeRuleAllApproved})
+			tt.prepare(t, svc)
+			before := len(store.OutboxTypes())
+
+			for _, ctx := range []context.Context{
+				servicetest.System(),
+				servicetest.As("author", modelauth.RoleUser),
+			} {
+				if _, err := svc.Merge(ctx, "pr-1"); !errors.Is(err, tt.want) {
+					t.Fatalf("got %v, want %v", err, tt.want)
+				}
+			}
+			if after := len(store.OutboxTypes()); after != before {
+				t.Fatalf("rejected merge recorded %d events", after-before)
+			}
+		})
+	}
+}
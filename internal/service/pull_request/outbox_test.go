@@ -0,0 +1,130 @@
+package pull_request_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	modelpr "github.com/zxchelik/avito-test-task/internal/model/pull_request"
+	modelwh "github.com/zxchelik/avito-test-task/internal/model/webhook"
+	prSvc "github.com/zxchelik/avito-test-task/internal/service/pull_request"
+	"github.com/zxchelik/avito-test-task/internal/service/servicetest"
+)
+
+var errAbort = errors.New("abort")
+
+// newOutboxFixture — команда из автора и двух ревьюверов, подписка на все события.
+func newOutboxFixture(t *testing.T) (*servicetest.Store, *prSvc.Service) {
+	t.Helper()
+
+	store := servicetest.NewStore()
+	store.AddTeam(t, servicetest.Team("backend", 1), "author", "r1", "r2")
+	store.Subscribe(t, "https://hooks.example.com/review", "secret")
+	return store, store.PRService()
+}
+
+// aborted выполняет fn во внешней транзакции, которая затем откатывается.
+func aborted(t *testing.T, store *servicetest.Store, fn func(ctx context.Context) error) {
+	t.Helper()
+
+	err := store.Tx.WithinTransaction(servicetest.System(), func(txCtx context.Context) error {
+		if err := fn(txCtx); err != nil {
+			t.Fatalf("inside transaction: %v", err)
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("transaction: got %v, want errAbort", err)
+	}
+}
+
+func assertOutbox(t *testing.T, store *servicetest.Store, want ...modelwh.EventType) {
+	t.Helper()
+
+	if got := store.OutboxTypes(); !slices.Equal(got, want) {
+		t.Fatalf("outbox = %v, want %v", got, want)
+	}
+}
+
+func TestOutboxCreate(t *testing.T) {
+	t.Run("commit", func(t *testing.T) {
+		store, svc := newOutboxFixture(t)
+		createPR(t, svc, modelpr.PROpen)
+
+		assertOutbox(t, store, modelwh.EventPRCreated, modelwh.EventReviewerAssigned)
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		store, svc := newOutboxFixture(t)
+		aborted(t, store, func(ctx context.Context) error {
+			_, _, err := svc.Create(ctx, &modelpr.PullRequest{
+				ID: "pr-1", Title: "feature", AuthorID: "author", Status: modelpr.PROpen,
+			}, prSvc.CreateOptions{})
+			return err
+		})
+
+		assertOutbox(t, store)
+		if _, err := store.PRs.GetByID(context.Background(), "pr-1"); err == nil {
+			t.Fatal("PR survived rollback")
+		}
+	})
+}
+
+func TestOutboxReassign(t *testing.T) {
+	t.Run("commit", func(t *testing.T) {
+		store, svc := newOutboxFixture(t)
+		reviewer := createPR(t, svc, modelpr.PROpen)[0].UserId
+
+		if _, err := svc.Reassign(servicetest.System(), "pr-1", reviewer); err != nil {
+			t.Fatalf("reassign: %v", err)
+		}
+		assertOutbox(t, store, modelwh.EventPRCreated, modelwh.EventReviewerAssigned, modelwh.EventReviewerReplaced)
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		store, svc := newOutboxFixture(t)
+		reviewer := createPR(t, svc, modelpr.PROpen)[0].UserId
+
+		aborted(t, store, func(ctx context.Context) error {
+			_, err := svc.Reassign(ctx, "pr-1", reviewer)
+			return err
+		})
+		assertOutbox(t, store, modelwh.EventPRCreated, modelwh.EventReviewerAssigned)
+	})
+}
+
+func TestOutboxMerge(t *testing.T) {
+	t.Run("commit", func(t *testing.T) {
+		store, svc := newOutboxFixture(t)
+		createPR(t, svc, modelpr.PROpen)
+
+		if _, err := svc.Merge(servicetest.System(), "pr-1"); err != nil {
+			t.Fatalf("merge: %v", err)
+		}
+		assertOutbox(t, store, modelwh.EventPRCreated, modelwh.EventReviewerAssigned, modelwh.EventPRMerged)
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		store, svc := newOutboxFixture(t)
+		createPR(t, svc, modelpr.PROpen)
+
+		aborted(t, store, func(ctx context.Context) error {
+			_, err := svc.Merge(ctx, "pr-1")
+			return err
+		})
+		assertOutbox(t, store, modelwh.EventPRCreated, modelwh.EventReviewerAssigned)
+	})
+
+	t.Run("rule not met", func(t *testing.T) {
+		store, svc := newOutboxFixture(t)
+		svc.WithMergeRule(modelpr.MergeRule{Kind: modelpr.MergeRuleAllApproved})
+		createPR(t, svc, modelpr.PROpen)
+
+		var notApproved *modelpr.NotApprovedError
+		if _, err := svc.Merge(servicetest.System(), "pr-1"); !errors.As(err, &notApproved) {
+			t.Fatalf("merge: got %v, want NotApprovedError", err)
+		}
+		assertOutbox(t, store, modelwh.EventPRCreated, modelwh.EventReviewerAssigned)
+	})
+}
@@ -0,0 +1,81 @@
+package pull_request
+
+import (
+	"context"
+
+	modeluser "github.com/zxchelik/avito-test-task/internal/model/user"
+	"github.com/zxchelik/avito-test-task/pkg/codeowners"
+)
+
+// resolveOwners возвращает владельцев затронутых путей: пользователей из правил
+// и участников команд из правил. Для каждого пути действует последнее совпавшее правило.
+func (s *Service) resolveOwners(ctx context.Context, paths []string) ([]*modeluser.User, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	rules, err := s.owners.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	patterns := make([]*codeowners.Pattern, len(rules))
+	for i, r := range rules {
+		// Шаблоны валидируются при сохранении; сломанное правило просто не совпадает.
+		patterns[i], _ = codeowners.Compile(r.Pattern)
+	}
+
+	var (
+		userIDs   []string
+		teamNames []string
+		seenUser  = make(map[string]struct{})
+		seenTeam  = make(map[string]struct{})
+	)
+	for _, path := range paths {
+		for i := len(rules) - 1; i >= 0; i-- {
+			if patterns[i] == nil || !patterns[i].Match(path) {
+				continue
+			}
+			for _, id := range rules[i].Users {
+				if _, ok := seenUser[id]; !ok {
+					seenUser[id] = struct{}{}
+					userIDs = append(userIDs, id)
+				}
+			}
+			for _, t := range rules[i].Teams {
+				if _, ok := seenTeam[t]; !ok {
+					seenTeam[t] = struct{}{}
+					teamNames = append(teamNames, t)
+				}
+			}
+			break
+		}
+	}
+
+	owners := make([]*modeluser.User, 0, len(userIDs))
+	if len(userIDs) > 0 {
+		users, err := s.users.ListByIDs(ctx, userIDs)
+		if err != nil {
+			return nil, err
+		}
+		owners = append(owners, users...)
+	}
+	for _, t := range teamNames {
+		members, err := s.users.ListByTeam(ctx, t)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range members {
+			if _, ok := seenUser[m.ID]; ok {
+				continue
+			}
+			seenUser[m.ID] = struct{}{}
+			owners = append(owners, m)
+		}
+	}
+
+	return owners, nil
+}
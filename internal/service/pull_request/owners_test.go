@@ -0,0 +1,102 @@
+package pull_request_test
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	modelco "github.com/zxchelik/avito-test-task/internal/model/code_owner"
+	modelpr "github.com/zxchelik/avito-test-task/internal/model/pull_request"
+	modelra "github.com/zxchelik/avito-test-task/internal/model/reviewer_assignment"
+	prSvc "github.com/zxchelik/avito-test-task/internal/service/pull_request"
+	"github.com/zxchelik/avito-test-task/internal/service/servicetest"
+)
+
+// newOwnersFixture — команда автора backend с владельцами кода и непричастными участниками,
+// команда dba, правила в порядке файла CODEOWNERS.
+func newOwnersFixture(t *testing.T) *prSvc.Service {
+	t.Helper()
+
+	store := servicetest.NewStore()
+	store.AddTeam(t, servicetest.Team("backend", 3), "author", "o-any", "o-docs", "o-go", "o-legacy", "o-scripts", "m1", "m2", "m3")
+	store.AddTeam(t, servicetest.Team("dba", 1), "d1", "d2")
+
+	ctx := context.Background()
+	for i, r := range []*modelco.Rule{
+		{Pattern: "*", Users: []string{"o-any"}},
+		{Pattern: "/docs/", Users: []string{"o-docs"}},
+		{Pattern: "*.go", Users: []string{"o-go"}},
+		{Pattern: "/internal/**/pg.go", Teams: []string{"dba"}},
+		{Pattern: "/internal/legacy/", Users: []string{"o-legacy", "o-go"}},
+		{Pattern: "/scripts/*", Users: []string{"o-scripts"}},
+		{Pattern: "[broken", Users: []string{"m1"}}, // сохранённое сломанное правило ни с чем не совпадает
+	} {
+		r.Position = i
+		if _, err := store.Owners.Create(ctx, r); err != nil {
+			t.Fatalf("create rule %s: %v", r.Pattern, err)
+		}
+	}
+	return store.PRService()
+}
+
+func TestCreateAssignsCodeOwners(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []string
+		want  []string
+	}{
+		{"catch-all", []string{"README.md"}, []string{"o-any"}},
+		{"directory", []string{"docs/guide.md"}, []string{"o-docs"}},
+		{"last match wins", []string{"docs/gen.go"}, []string{"o-go"}},
+		{"team owner", []string{"internal/repository/user/pg.go"}, []string{"d1", "d2"}},
+		{"double star matches no directories", []string{"internal/pg.go"}, []string{"d1", "d2"}},
+		{"later directory rule wins", []string{"internal/legacy/sub/pg.go"}, []string{"o-go", "o-legacy"}},
+		{"star", []string{"scripts/run.sh"}, []string{"o-scripts"}},
+		{"star does not cross slash", []string{"scripts/ci/run.sh"}, []string{"o-any"}},
+		{"owners of several paths", []string{"README.md", "cmd/main.go"}, []string{"o-any", "o-go"}},
+		{"duplicate owners", []string{"internal/legacy/a.go", "internal/legacy/b.go", "cmd/main.go"}, []string{"o-go", "o-legacy"}},
+		{"normalized path", []string{"./docs/guide.md"}, []string{"o-docs"}},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newOwnersFixture(t)
+
+			count := len(tt.want)
+			pr := &modelpr.PullRequest{ID: fmt.Sprintf("pr-%d", i), Title: "feature", AuthorID: "author", Status: modelpr.PROpen}
+			_, reviewers, err := svc.Create(servicetest.System(), pr, prSvc.CreateOptions{
+				ReviewersCount: &count,
+				ChangedFiles:   tt.files,
+			})
+			if err != nil {
+				t.Fatalf("create: %v", err)
+			}
+
+			got := make([]string, len(reviewers))
+			for i, r := range reviewers {
+				got[i] = r.UserId
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("reviewers = %v, want owners %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateFillsUpAfterOwners(t *testing.T) {
+	svc := newOwnersFixture(t)
+
+	count := 3
+	pr := &modelpr.PullRequest{ID: "pr-1", Title: "feature", AuthorID: "author", Status: modelpr.PROpen}
+	_, reviewers, err := svc.Create(servicetest.System(), pr, prSvc.CreateOptions{
+		ReviewersCount: &count,
+		ChangedFiles:   []string{"docs/guide.md"},
+	})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if len(reviewers) != 3 || !slices.ContainsFunc(reviewers, func(r *modelra.ReviewerAssignment) bool { return r.UserId == "o-docs" }) {
+		t.Fatalf("reviewers = %+v, want o-docs and two members of backend", reviewers)
+	}
+}
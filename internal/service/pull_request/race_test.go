@@ -0,0 +1,289 @@
+package pull_request_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/zxchelik/avito-test-task/internal/infrastructure/pg"
+	"github.com/zxchelik/avito-test-task/internal/infrastructure/pg/pgtest"
+	modelpr "github.com/zxchelik/avito-test-task/internal/model/pull_request"
+	modelra "github.com/zxchelik/avito-test-task/internal/model/reviewer_assignment"
+	modeluser "github.com/zxchelik/avito-test-task/internal/model/user"
+	absenceRep "github.com/zxchelik/avito-test-task/internal/repository/absence"
+	eventRep "github.com/zxchelik/avito-test-task/internal/repository/assignment_event"
+	coRep "github.com/zxchelik/avito-test-task/internal/repository/code_owner"
+	prRep "github.com/zxchelik/avito-test-task/internal/repository/pull_request"
+	raRep "github.com/zxchelik/avito-test-task/internal/repository/reviewer_assignment"
+	teamRep "github.com/zxchelik/avito-test-task/internal/repository/team"
+	userRep "github.com/zxchelik/avito-test-task/internal/repository/user"
+	webhookRep "github.com/zxchelik/avito-test-task/internal/repository/webhook"
+	"github.com/zxchelik/avito-test-task/internal/service"
+	prSvc "github.com/zxchelik/avito-test-task/internal/service/pull_request"
+	"github.com/zxchelik/avito-test-task/internal/service/servicetest"
+	teamSvc "github.com/zxchelik/avito-test-task/internal/service/team"
+)
+
+// pgFixture — сервисы PR и команд поверх Postgres: команда backend из автора и восьми ревьюверов.
+type pgFixture struct {
+	tx      *pg.TxManager
+	prs     *prRep.PGRepository
+	reviews *raRep.PGRepository
+	svc     *prSvc.Service
+	teams   *teamSvc.Service
+}
+
+func newPGFixture(t *testing.T, pool *pgxpool.Pool, opts service.TxOptions) *pgFixture {
+	t.Helper()
+
+	f := &pgFixture{
+		tx:      pg.NewTxManager(pool).WithOptions(opts),
+		prs:     prRep.NewPGRepository(pool),
+		reviews: raRep.NewPGRepository(pool),
+	}
+	users, teams := userRep.NewPGRepository(pool), teamRep.NewPGRepository(pool)
+	events, outbox := eventRep.NewPGRepository(pool), webhookRep.NewPGRepository(pool)
+	f.svc = prSvc.NewService(f.prs, users, teams, f.reviews, coRep.NewPGRepository(pool),
+		absenceRep.NewPGRepository(pool), events, outbox, f.tx)
+	f.teams = teamSvc.NewService(teams, users, f.reviews, events, outbox, f.tx)
+
+	ctx := context.Background()
+	if err := teams.Create(ctx, servicetest.Team("backend", 2)); err != nil {
+		t.Fatalf("create team: %v", err)
+	}
+	for _, id := range []string{"author", "r1", "r2", "r3", "r4", "r5", "r6", "r7", "r8"} {
+		if err := users.Upsert(ctx, &modeluser.User{ID: id, Username: id, TeamName: "backend", IsActive: true}); err != nil {
+			t.Fatalf("create user %s: %v", id, err)
+		}
+	}
+	return f
+}
+
+func (f *pgFixture) reviewers(ctx context.Context, t *testing.T, prID string) []string {
+	t.Helper()
+
+	assignments, err := f.reviews.ListByPR(ctx, prID)
+	if err != nil {
+		t.Errorf("list reviewers of %s: %v", prID, err)
+		return nil
+	}
+	ids := make([]string, len(assignments))
+	for i, a := range assignments {
+		ids[i] = a.UserId
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// TestConcurrentReassignAndMerge гоняет замены ревьюверов параллельно с merge тех же PR.
+// Замена, дошедшая до PR после merge, должна получить ErrPRAlreadyMerged,
+// а состав ревьюверов — остаться таким, каким его зафиксировал merge.
+func TestConcurrentReassignAndMerge(t *testing.T) {
+	isolations := map[string]service.TxOptions{
+		"read committed": {},
+		// конфликты сериализации здесь ожидаемы: их повторяет TxManager
+		"serializable": {Isolation: service.IsolationSerializable, MaxRetries: 50},
+	}
+	for name, opts := range isolations {
+		t.Run(name, func(t *testing.T) {
+			f := newPGFixture(t, pgtest.NewPool(t), opts)
+			ctx := servicetest.System()
+
+			const prs, reassigners, rounds = 8, 4, 10
+			for i := range prs {
+				pr := &modelpr.PullRequest{ID: fmt.Sprintf("pr-%d", i), Title: "feature", AuthorID: "author", Status: modelpr.PROpen}
+				if _, _, err := f.svc.Create(ctx, pr, prSvc.CreateOptions{}); err != nil {
+					t.Fatalf("create %s: %v", pr.ID, err)
+				}
+			}
+
+			var wg sync.WaitGroup
+			atMerge := make([][]string, prs)
+			for i := range prs {
+				prID := fmt.Sprintf("pr-%d", i)
+
+				for range reassigners {
+					wg.Go(func() {
+						for range rounds {
+							current := f.reviewers(ctx, t, prID)
+							if len(current) == 0 {
+								return
+							}
+							_, err := f.svc.Reassign(ctx, prID, current[0])
+							switch {
+							case err == nil, errors.Is(err, modelra.ErrReviewerNotFoundInPR):
+								// заменён или уже заменён соседней горутиной
+							case errors.Is(err, modelpr.ErrPRAlreadyMerged):
+								return
+							default:
+								t.Errorf("reassign %s: %v", prID, err)
+								return
+							}
+						}
+					})
+				}
+
+				wg.Go(func() {
+					time.Sleep(time.Duration(i) * time.Millisecond)
+					// состав ревьюверов читается в транзакции merge, пока строка PR заблокирована
+					err := f.tx.WithinTransaction(ctx, func(txCtx context.Context) error {
+						if _, err := f.svc.Merge(txCtx, prID); err != nil {
+							return err
+						}
+						atMerge[i] = f.reviewers(txCtx, t, prID)
+						return nil
+					})
+					if err != nil {
+						t.Errorf("merge %s: %v", prID, err)
+					}
+				})
+			}
+			wg.Wait()
+
+			for i := range prs {
+				prID := fmt.Sprintf("pr-%d", i)
+				pr, err := f.prs.GetByID(ctx, prID)
+				if err != nil {
+					t.Fatalf("get %s: %v", prID, err)
+				}
+				if pr.Status != modelpr.PRMerged {
+					t.Fatalf("%s status = %s, want MERGED", prID, pr.Status)
+				}
+
+				got := f.reviewers(ctx, t, prID)
+				if !slices.Equal(got, atMerge[i]) {
+					t.Fatalf("%s reviewers changed after merge: %v, at merge %v", prID, got, atMerge[i])
+				}
+				if len(got) != 2 || slices.Contains(got, "author") || len(slices.Compact(slices.Clone(got))) != len(got) {
+					t.Fatalf("%s reviewers = %v, want two distinct non-authors", prID, got)
+				}
+			}
+		})
+	}
+}
+
+// TestReassignWaitsForPRLock проверяет, что замена ждёт блокировку строки PR,
+// взятую GetByIDForUpdate в чужой транзакции, и видит её результат.
+func TestReassignWaitsForPRLock(t *testing.T) {
+	f := newPGFixture(t, pgtest.NewPool(t), service.TxOptions{})
+	ctx := servicetest.System()
+	reviewer := createPR(t, f.svc, modelpr.PROpen)[0].UserId
+	before := f.reviewers(ctx, t, "pr-1")
+
+	locked := make(chan struct{})
+	release := make(chan struct{})
+	holder := make(chan error, 1)
+	go func() {
+		holder <- f.tx.WithinTransaction(ctx, func(txCtx context.Context) error {
+			if _, err := f.prs.GetByIDForUpdate(txCtx, "pr-1"); err != nil {
+				return err
+			}
+			close(locked)
+			<-release
+			_, err := f.prs.MarkMerged(txCtx, "pr-1")
+			return err
+		})
+	}()
+	<-locked
+
+	reassigned := make(chan error, 1)
+	go func() {
+		_, err := f.svc.Reassign(ctx, "pr-1", reviewer)
+		reassigned <- err
+	}()
+
+	select {
+	case err := <-reassigned:
+		t.Fatalf("reassign did not wait for the PR lock: %v", err)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	close(release)
+	if err := <-holder; err != nil {
+		t.Fatalf("merge under lock: %v", err)
+	}
+	if err := <-reassigned; !errors.Is(err, modelpr.ErrPRAlreadyMerged) {
+		t.Fatalf("reassign: got %v, want ErrPRAlreadyMerged", err)
+	}
+	if got := f.reviewers(ctx, t, "pr-1"); !slices.Equal(got, before) {
+		t.Fatalf("reviewers = %v, want %v", got, before)
+	}
+}
+
+// TestConcurrentDeactivateAndMerge гоняет деактивацию части команды параллельно с merge её PR.
+// Деактивация не должна менять ревьюверов PR, смерженного раньше неё, а каждая
+// объявленная ею замена — оказаться в PR.
+func TestConcurrentDeactivateAndMerge(t *testing.T) {
+	isolations := map[string]service.TxOptions{
+		"read committed": {},
+		"serializable":   {Isolation: service.IsolationSerializable, MaxRetries: 50},
+	}
+	for name, opts := range isolations {
+		t.Run(name, func(t *testing.T) {
+			f := newPGFixture(t, pgtest.NewPool(t), opts)
+			ctx := servicetest.System()
+
+			const prs = 16
+			for i := range prs {
+				pr := &modelpr.PullRequest{ID: fmt.Sprintf("pr-%02d", i), Title: "feature", AuthorID: "author", Status: modelpr.PROpen}
+				if _, _, err := f.svc.Create(ctx, pr, prSvc.CreateOptions{}); err != nil {
+					t.Fatalf("create %s: %v", pr.ID, err)
+				}
+			}
+
+			var (
+				wg      sync.WaitGroup
+				results []*modelra.Reassignment
+			)
+			atMerge := make([][]string, prs)
+			wg.Go(func() {
+				time.Sleep(prs / 2 * time.Millisecond)
+				var err error
+				_, results, err = f.teams.Deactivate(ctx, "backend", []string{"r1", "r2", "r3", "r4"})
+				if err != nil {
+					t.Errorf("deactivate: %v", err)
+				}
+			})
+			for i := range prs {
+				prID := fmt.Sprintf("pr-%02d", i)
+				wg.Go(func() {
+					time.Sleep(time.Duration(i) * time.Millisecond)
+					err := f.tx.WithinTransaction(ctx, func(txCtx context.Context) error {
+						if _, err := f.svc.Merge(txCtx, prID); err != nil {
+							return err
+						}
+						atMerge[i] = f.reviewers(txCtx, t, prID)
+						return nil
+					})
+					if err != nil {
+						t.Errorf("merge %s: %v", prID, err)
+					}
+				})
+			}
+			wg.Wait()
+
+			final := make(map[string][]string, prs)
+			for i := range prs {
+				prID := fmt.Sprintf("pr-%02d", i)
+				final[prID] = f.reviewers(ctx, t, prID)
+				if !slices.Equal(final[prID], atMerge[i]) {
+					t.Fatalf("%s reviewers changed after merge: %v, at merge %v", prID, final[prID], atMerge[i])
+				}
+			}
+			for _, r := range results {
+				if !r.Replaced() {
+					continue
+				}
+				got := final[r.PrId]
+				if !slices.Contains(got, r.NewUserId) || slices.Contains(got, r.OldUserId) {
+					t.Fatalf("%s: replacement %s -> %s is not in reviewers %v", r.PrId, r.OldUserId, r.NewUserId, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package pull_request_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	modelauth "github.com/zxchelik/avito-test-task/internal/model/auth"
+	modelpr "github.com/zxchelik/avito-test-task/internal/model/pull_request"
+	modelra "github.com/zxchelik/avito-test-task/internal/model/reviewer_assignment"
+	prSvc "github.com/zxchelik/avito-test-task/internal/service/pull_request"
+	"github.com/zxchelik/avito-test-task/internal/service/servicetest"
+)
+
+// newReviewFixture — OPEN PR pr-1 с ревьюверами r1 и r2 и правилом merge rule.
+func newReviewFixture(t *testing.T, rule modelpr.MergeRule) (*servicetest.Store, *prSvc.Service) {
+	t.Helper()
+
+	store := servicetest.NewStore()
+	store.AddTeam(t, servicetest.Team("backend", 2), "author", "r1", "r2")
+	svc := store.PRService().WithMergeRule(rule)
+	pr := &modelpr.PullRequest{ID: "pr-1", Title: "feature", AuthorID: "author", Status: modelpr.PROpen}
+	if _, _, err := svc.Create(servicetest.System(), pr, prSvc.CreateOptions{}); err != nil {
+		t.Fatalf("create PR: %v", err)
+	}
+	return store, svc
+}
+
+func review(t *testing.T, svc *prSvc.Service, reviewer string, verdict modelra.Verdict) []*modelra.ReviewerAssignment {
+	t.Helper()
+
+	_, reviews, err := svc.Review(servicetest.As(reviewer, modelauth.RoleUser), "pr-1", reviewer, verdict)
+	if err != nil {
+		t.Fatalf("%s %s: %v", reviewer, verdict, err)
+	}
+	return reviews
+}
+
+func verdictOf(reviews []*modelra.ReviewerAssignment, userID string) modelra.Verdict {
+	for _, r := range reviews {
+		if r.UserId == userID {
+			return r.Verdict
+		}
+	}
+	return modelra.VerdictNone
+}
+
+func TestReviewStoresLatestVerdict(t *testing.T) {
+	store, svc := newReviewFixture(t, modelpr.MergeRule{Kind: modelpr.MergeRuleNone})
+
+	reviews := review(t, svc, "r1", modelra.VerdictChangesRequested)
+	if verdictOf(reviews, "r1") != modelra.VerdictChangesRequested || verdictOf(reviews, "r2") != modelra.VerdictNone {
+		t.Fatalf("reviews = %+v", reviews)
+	}
+	// повторная отправка заменяет вердикт
+	reviews = review(t, svc, "r1", modelra.VerdictApproved)
+	if verdictOf(reviews, "r1") != modelra.VerdictApproved {
+		t.Fatalf("reviews = %+v", reviews)
+	}
+	for _, r := range reviews {
+		if r.UserId == "r1" && r.VerdictAt == nil {
+			t.Fatal("verdict has no time")
+		}
+	}
+
+	// каждый вердикт — отдельное событие истории
+	events, err := store.Events.ListByPR(context.Background(), "pr-1")
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	var verdicts []modelra.Verdict
+	for _, e := range events {
+		if e.Type == modelra.EventVerdict {
+			verdicts = append(verdicts, e.Verdict)
+		}
+	}
+	if !slices.Equal(verdicts, []modelra.Verdict{modelra.VerdictChangesRequested, modelra.VerdictApproved}) {
+		t.Fatalf("verdict events = %v", verdicts)
+	}
+}
+
+func TestReviewErrors(t *testing.T) {
+	_, svc := newReviewFixture(t, modelpr.MergeRule{Kind: modelpr.MergeRuleNone})
+	as := servicetest.As
+
+	cases := []struct {
+		name     string
+		ctx      context.Context
+		reviewer string
+		verdict  modelra.Verdict
+		want     error
+	}{
+		{"unknown verdict", as("r1", modelauth.RoleUser), "r1", "LGTM", modelra.ErrUnknownVerdict},
+		{"empty verdict", as("r1", modelauth.RoleUser), "r1", modelra.VerdictNone, modelra.ErrUnknownVerdict},
+		{"for another reviewer", as("r2", modelauth.RoleUser), "r1", modelra.VerdictApproved, modelauth.ErrForbidden},
+		{"no principal", context.Background(), "r1", modelra.VerdictApproved, modelauth.ErrUnauthorized},
+		{"not assigned", as("author", modelauth.RoleUser), "author", modelra.VerdictApproved, modelra.ErrReviewerNotFoundInPR},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if _, _, err := svc.Review(c.ctx, "pr-1", c.reviewer, c.verdict); !errors.Is(err, c.want) {
+				t.Fatalf("got %v, want %v", err, c.want)
+			}
+		})
+	}
+
+	// за ревьювера вердикт может отправить админ
+	if _, _, err := svc.Review(as("admin", modelauth.RoleAdmin), "pr-1", "r1", modelra.VerdictApproved); err != nil {
+		t.Fatalf("verdict by admin: %v", err)
+	}
+
+	if _, err := svc.Merge(servicetest.System(), "pr-1"); err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	if _, _, err := svc.Review(as("r2", modelauth.RoleUser), "pr-1", "r2", modelra.VerdictApproved); !errors.Is(err, modelpr.ErrPRAlreadyMerged) {
+		t.Fatalf("verdict on merged PR: got %v, want ErrPRAlreadyMerged", err)
+	}
+}
+
+func TestMergeRules(t *testing.T) {
+	cases := []struct {
+		name     string
+		rule     modelpr.MergeRule
+		verdicts map[string]modelra.Verdict
+		blocking []string // nil — merge проходит
+	}{
+		{"all approved", modelpr.MergeRule{Kind: modelpr.MergeRuleAllApproved},
+			map[string]modelra.Verdict{"r1": modelra.VerdictApproved, "r2": modelra.VerdictApproved}, nil},
+		{"all approved, one pending", modelpr.MergeRule{Kind: modelpr.MergeRuleAllApproved},
+			map[string]modelra.Verdict{"r1": modelra.VerdictApproved}, []string{"r2"}},
+		{"all approved, one commented", modelpr.MergeRule{Kind: modelpr.MergeRuleAllApproved},
+			map[string]modelra.Verdict{"r1": modelra.VerdictApproved, "r2": modelra.VerdictCommented}, []string{"r2"}},
+		{"one approval", modelpr.MergeRule{Kind: modelpr.MergeRuleMinApprovals, MinApprovals: 1},
+			map[string]modelra.Verdict{"r1": modelra.VerdictApproved}, nil},
+		{"approval and changes requested", modelpr.MergeRule{Kind: modelpr.MergeRuleMinApprovals, MinApprovals: 1},
+			map[string]modelra.Verdict{"r1": modelra.VerdictApproved, "r2": modelra.VerdictChangesRequested}, []string{"r2"}},
+		{"no approvals", modelpr.MergeRule{Kind: modelpr.MergeRuleMinApprovals, MinApprovals: 1},
+			nil, []string{"r1", "r2"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			store, svc := newReviewFixture(t, c.rule)
+			for reviewer, verdict := range c.verdicts {
+				review(t, svc, reviewer, verdict)
+			}
+
+			merged, err := svc.Merge(servicetest.System(), "pr-1")
+			if c.blocking == nil {
+				if err != nil || merged.Status != modelpr.PRMerged {
+					t.Fatalf("merge = %+v, %v", merged, err)
+				}
+				return
+			}
+
+			var notApproved *modelpr.NotApprovedError
+			if !errors.As(err, &notApproved) {
+				t.Fatalf("got %v, want NotApprovedError", err)
+			}
+			blocking := slices.Sorted(slices.Values(notApproved.Blocking))
+			if !slices.Equal(blocking, c.blocking) {
+				t.Fatalf("blocking = %v, want %v", blocking, c.blocking)
+			}
+			// отказ ничего не меняет
+			if pr, err := store.PRs.GetByID(context.Background(), "pr-1"); err != nil || pr.Status != modelpr.PROpen {
+				t.Fatalf("PR after refused merge = %+v, %v", pr, err)
+			}
+		})
+	}
+}
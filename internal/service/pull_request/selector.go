@@ -0,0 +1,170 @@
+package pull_request
+
+import (
+	"context"
+	"encoding/binary"
+	"hash/fnv"
+	"math/rand/v2"
+	"sort"
+
+	modelteam "github.com/zxchelik/avito-test-task/internal/model/team"
+	modeluser "github.com/zxchelik/avito-test-task/internal/model/user"
+	"github.com/zxchelik/avito-test-task/internal/service"
+)
+
+// Selection — входные данные для стратегии выбора ревьюверов.
+type Selection struct {
+	PRID       string
+	TeamName   string
+	Candidates []*modeluser.User // уже отфильтрованные кандидаты
+	Count      int               // сколько ревьюверов нужно
+}
+
+// ReviewerSelector выбирает до sel.Count ревьюверов из sel.Candidates.
+// Фильтрация (активность, автор, уже назначенные) выполняется до вызова.
+type ReviewerSelector interface {
+	Select(ctx context.Context, sel Selection) ([]*modeluser.User, error)
+}
+
+// defaultSelectors возвращает встроенные стратегии; seed стратегии seeded
+// по умолчанию 0, сервер подменяет её через WithSelector с seed из конфига.
+func defaultSelectors(reviews service.ReviewerAssignmentRepository) map[modelteam.ReviewerStrategy]ReviewerSelector {
+	return map[modelteam.ReviewerStrategy]ReviewerSelector{
+		modelteam.StrategyRandom:      NewRandomSelector(),
+		modelteam.StrategyRoundRobin:  NewRoundRobinSelector(reviews),
+		modelteam.StrategyLeastLoaded: NewLeastLoadedSelector(reviews),
+		modelteam.StrategySeeded:      NewSeededSelector(0),
+	}
+}
+
+// RandomSelector выбирает кандидатов случайно.
+type RandomSelector struct{}
+
+func NewRandomSelector() *RandomSelector {
+	return &RandomSelector{}
+}
+
+func (RandomSelector) Select(_ context.Context, sel Selection) ([]*modeluser.User, error) {
+	picked := shuffled(sel.Candidates)
+	return limit(picked, sel.Count), nil
+}
+
+// RoundRobinSelector выбирает тех, кому ревью назначали давнее всего.
+// Очередь восстанавливается по assigned_at, поэтому не зависит от рестартов и реплик.
+type RoundRobinSelector struct {
+	reviews service.ReviewerAssignmentRepository
+}
+
+func NewRoundRobinSelector(reviews service.ReviewerAssignmentRepository) *RoundRobinSelector {
+	return &RoundRobinSelector{reviews: reviews}
+}
+
+func (s *RoundRobinSelector) Select(ctx context.Context, sel Selection) ([]*modeluser.User, error) {
+	last, err := s.reviews.LastAssignedAt(ctx, userIDs(sel.Candidates))
+	if err != nil {
+		return nil, err
+	}
+
+	picked := make([]*modeluser.User, len(sel.Candidates))
+	copy(picked, sel.Candidates)
+	sort.SliceStable(picked, func(i, j int) bool {
+		ti, iok := last[picked[i].ID]
+		tj, jok := last[picked[j].ID]
+		switch {
+		case iok != jok:
+			return !iok // ни разу не назначенные — первыми
+		case !ti.Equal(tj):
+			return ti.Before(tj)
+		default:
+			return picked[i].ID < picked[j].ID
+		}
+	})
+
+	return limit(picked, sel.Count), nil
+}
+
+// LeastLoadedSelector выбирает кандидатов с наименьшим числом OPEN ревью.
+// При равной нагрузке порядок случайный.
+type LeastLoadedSelector struct {
+	reviews service.ReviewerAssignmentRepository
+}
+
+func NewLeastLoadedSelector(reviews service.ReviewerAssignmentRepository) *LeastLoadedSelector {
+	return &LeastLoadedSelector{reviews: reviews}
+}
+
+func (s *LeastLoadedSelector) Select(ctx context.Context, sel Selection) ([]*modeluser.User, error) {
+	load, err := s.reviews.CountOpenByReviewers(ctx, userIDs(sel.Candidates))
+	if err != nil {
+		return nil, err
+	}
+
+	picked := shuffled(sel.Candidates)
+	sort.SliceStable(picked, func(i, j int) bool {
+		return load[picked[i].ID] < load[picked[j].ID]
+	})
+
+	return limit(picked, sel.Count), nil
+}
+
+// SeededSelector детерминированно перемешивает кандидатов по хешу (seed, PR, user).
+// Для одного и того же PR и набора кандидатов результат всегда одинаков.
+type SeededSelector struct {
+	seed uint64
+}
+
+func NewSeededSelector(seed uint64) *SeededSelector {
+	return &SeededSelector{seed: seed}
+}
+
+func (s *SeededSelector) Select(_ context.Context, sel Selection) ([]*modeluser.User, error) {
+	weights := make(map[string]uint64, len(sel.Candidates))
+	for _, c := range sel.Candidates {
+		weights[c.ID] = s.weight(sel.PRID, c.ID)
+	}
+
+	picked := make([]*modeluser.User, len(sel.Candidates))
+	copy(picked, sel.Candidates)
+	sort.SliceStable(picked, func(i, j int) bool {
+		wi, wj := weights[picked[i].ID], weights[picked[j].ID]
+		if wi != wj {
+			return wi < wj
+		}
+		return picked[i].ID < picked[j].ID
+	})
+
+	return limit(picked, sel.Count), nil
+}
+
+func (s *SeededSelector) weight(prID, userID string) uint64 {
+	h := fnv.New64a()
+	var seed [8]byte
+	binary.BigEndian.PutUint64(seed[:], s.seed)
+	_, _ = h.Write(seed[:])
+	_, _ = h.Write([]byte(prID))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(userID))
+	return h.Sum64()
+}
+
+func userIDs(users []*modeluser.User) []string {
+	ids := make([]string, len(users))
+	for i, u := range users {
+		ids[i] = u.ID
+	}
+	return ids
+}
+
+func shuffled(users []*modeluser.User) []*modeluser.User {
+	out := make([]*modeluser.User, len(users))
+	copy(out, users)
+	rand.Shuffle(len(out), func(i, j int) { out[i], out[j] = out[j], out[i] })
+	return out
+}
+
+func limit(users []*modeluser.User, n int) []*modeluser.User {
+	if len(users) > n {
+		return users[:n]
+	}
+	return users
+}
@@ -0,0 +1,123 @@
+package pull_request_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	modeluser "github.com/zxchelik/avito-test-task/internal/model/user"
+	"github.com/zxchelik/avito-test-task/internal/service"
+	prSvc "github.com/zxchelik/avito-test-task/internal/service/pull_request"
+)
+
+// reviewsStub отдаёт селекторам заданную историю назначений и нагрузку.
+type reviewsStub struct {
+	service.ReviewerAssignmentRepository
+	last map[string]time.Time
+	open map[string]int
+}
+
+func (s reviewsStub) LastAssignedAt(_ context.Context, _ []string) (map[string]time.Time, error) {
+	return s.last, nil
+}
+
+func (s reviewsStub) CountOpenByReviewers(_ context.Context, _ []string) (map[string]int, error) {
+	return s.open, nil
+}
+
+func candidates(ids ...string) []*modeluser.User {
+	users := make([]*modeluser.User, len(ids))
+	for i, id := range ids {
+		users[i] = &modeluser.User{ID: id, TeamName: "backend", IsActive: true}
+	}
+	return users
+}
+
+func selectIDs(t *testing.T, sel prSvc.ReviewerSelector, prID string, count int, ids ...string) []string {
+	t.Helper()
+
+	picked, err := sel.Select(context.Background(), prSvc.Selection{
+		PRID: prID, TeamName: "backend", Candidates: candidates(ids...), Count: count,
+	})
+	if err != nil {
+		t.Fatalf("select: %v", err)
+	}
+	res := make([]string, len(picked))
+	for i, u := range picked {
+		res[i] = u.ID
+	}
+	return res
+}
+
+func TestRoundRobinSelector(t *testing.T) {
+	at := time.Date(2025, 3, 3, 10, 0, 0, 0, time.UTC)
+	sel := prSvc.NewRoundRobinSelector(reviewsStub{last: map[string]time.Time{
+		"u1": at.Add(time.Hour),
+		"u2": at,
+		"u3": at, // одновременно с u2: порядок по id
+	}})
+
+	// ни разу не назначенные первыми (между собой по id), затем давнее всего назначенные
+	got := selectIDs(t, sel, "pr-1", 5, "u1", "u5", "u3", "u2", "u4")
+	if want := []string{"u4", "u5", "u2", "u3", "u1"}; !slices.Equal(got, want) {
+		t.Fatalf("order = %v, want %v", got, want)
+	}
+	if got := selectIDs(t, sel, "pr-1", 1, "u3", "u2", "u1"); !slices.Equal(got, []string{"u2"}) {
+		t.Fatalf("tie-break = %v, want [u2]", got)
+	}
+}
+
+func TestLeastLoadedSelector(t *testing.T) {
+	sel := prSvc.NewLeastLoadedSelector(reviewsStub{open: map[string]int{"u1": 3, "u2": 1, "u4": 2}})
+
+	// u3 без открытых ревью, затем u2; равных по нагрузке нет, поэтому порядок стабилен
+	for range 20 {
+		if got := selectIDs(t, sel, "pr-1", 2, "u1", "u2", "u3", "u4"); !slices.Equal(got, []string{"u3", "u2"}) {
+			t.Fatalf("picked %v, want [u3 u2]", got)
+		}
+	}
+
+	// при равной нагрузке выбирается любой из наименее загруженных
+	tied := prSvc.NewLeastLoadedSelector(reviewsStub{open: map[string]int{"u1": 1, "u2": 1, "u3": 5}})
+	seen := map[string]bool{}
+	for range 200 {
+		got := selectIDs(t, tied, "pr-1", 1, "u1", "u2", "u3")
+		if got[0] == "u3" {
+			t.Fatalf("picked the most loaded candidate")
+		}
+		seen[got[0]] = true
+	}
+	if !seen["u1"] || !seen["u2"] {
+		t.Fatalf("ties are not shuffled: %v", seen)
+	}
+}
+
+func TestSeededSelector(t *testing.T) {
+	ids := []string{"u1", "u2", "u3", "u4", "u5", "u6"}
+	sel := prSvc.NewSeededSelector(42)
+
+	// выбор зафиксирован: смена хеша перераспределит ревью на всех инстансах
+	first := selectIDs(t, sel, "pr-1", 3, ids...)
+	if want := []string{"u4", "u5", "u6"}; !slices.Equal(first, want) {
+		t.Fatalf("picked %v, want %v", first, want)
+	}
+
+	// тот же seed и PR: тот же выбор при любом порядке кандидатов и в новом экземпляре
+	reversed := slices.Clone(ids)
+	slices.Reverse(reversed)
+	if got := selectIDs(t, prSvc.NewSeededSelector(42), "pr-1", 3, reversed...); !slices.Equal(got, first) {
+		t.Fatalf("same seed: picked %v, then %v", first, got)
+	}
+
+	// другой seed или другой PR меняют порядок хотя бы для одного из вариантов
+	changed := func(sel prSvc.ReviewerSelector, prID string) bool {
+		return !slices.Equal(selectIDs(t, sel, prID, len(ids), ids...), selectIDs(t, prSvc.NewSeededSelector(42), "pr-1", len(ids), ids...))
+	}
+	if !changed(prSvc.NewSeededSelector(7), "pr-1") {
+		t.Fatal("seed does not affect the order")
+	}
+	if !changed(sel, "pr-2") {
+		t.Fatal("PR id does not affect the order")
+	}
+}
@@ -5,38 +5,57 @@ import (
 	"errors"
 	"github.com/zxchelik/avito-test-task/internal/model"
 	"github.com/zxchelik/avito-test-task/internal/service"
-	"time"
 
+	modelauth "github.com/zxchelik/avito-test-task/internal/model/auth"
 	modelpr "github.com/zxchelik/avito-test-task/internal/model/pull_request"
 	modelra "github.com/zxchelik/avito-test-task/internal/model/reviewer_assignment"
+	modelteam "github.com/zxchelik/avito-test-task/internal/model/team"
 	modeluser "github.com/zxchelik/avito-test-task/internal/model/user"
+	modelwh "github.com/zxchelik/avito-test-task/internal/model/webhook"
 )
 
 // Clock — абстракция времени для тестов.
-type Clock func() time.Time
-
-func defaultClock() time.Time { return time.Now().UTC() }
+type Clock = service.Clock
 
 type Service struct {
-	prs     service.PRRepository
-	users   service.UserRepository
-	reviews service.ReviewerAssignmentRepository
-	clock   Clock
-	tx      service.TxManager
+	prs       service.PRRepository
+	users     service.UserRepository
+	teams     service.TeamRepository
+	reviews   service.ReviewerAssignmentRepository
+	owners    service.CodeOwnerRepository
+	absences  service.AbsenceRepository
+	events    service.AssignmentEventRepository
+	outbox    service.WebhookOutbox
+	selectors map[modelteam.ReviewerStrategy]ReviewerSelector
+	mergeRule modelpr.MergeRule
+	clock     Clock
+	tx        service.TxManager
 }
 
 func NewService(
 	prs service.PRRepository,
 	users service.UserRepository,
+	teams service.TeamRepository,
 	reviews service.ReviewerAssignmentRepository,
+	owners service.CodeOwnerRepository,
+	absences service.AbsenceRepository,
+	events service.AssignmentEventRepository,
+	outbox service.WebhookOutbox,
 	tx service.TxManager,
 ) *Service {
 	return &Service{
-		prs:     prs,
-		users:   users,
-		reviews: reviews,
-		clock:   defaultClock,
-		tx:      tx,
+		prs:       prs,
+		users:     users,
+		teams:     teams,
+		reviews:   reviews,
+		owners:    owners,
+		absences:  absences,
+		events:    events,
+		outbox:    outbox,
+		selectors: defaultSelectors(reviews),
+		mergeRule: modelpr.MergeRule{Kind: modelpr.MergeRuleNone},
+		clock:     service.DefaultClock,
+		tx:        tx,
 	}
 }
 
@@ -46,194 +65,963 @@ func (s *Service) WithClock(clock Clock) *Service {
 	return s
 }
 
-// Create создаёт новый PR и назначает до двух ревьюверов из команды автора.
+// WithSelector регистрирует (или подменяет) стратегию выбора ревьюверов.
+func (s *Service) WithSelector(strategy modelteam.ReviewerStrategy, selector ReviewerSelector) *Service {
+	s.selectors[strategy] = selector
+	return s
+}
+
+// WithMergeRule задаёт правило, по которому PR допускается к merge.
+func (s *Service) WithMergeRule(rule modelpr.MergeRule) *Service {
+	s.mergeRule = rule
+	return s
+}
+
+// CreateOptions — необязательные параметры создания PR.
+type CreateOptions struct {
+	// ReviewersCount переопределяет число ревьюверов для PR;
+	// должно лежать в границах min_reviewers..max_reviewers команды автора.
+	ReviewersCount *int
+	// ChangedFiles — пути, затронутые PR; владельцы этих путей назначаются в первую очередь.
+	ChangedFiles []string
+}
+
+// Create создаёт новый PR и назначает ревьюверов (по умолчанию max_reviewers команды автора):
+// сначала владельцев затронутых файлов, затем участников команды автора,
+// а если кандидатов не хватает — участников резервных команд по порядку.
+// PR в статусе DRAFT создаётся без ревьюверов — они назначаются в Ready.
+// Ревьюверы подбираются в той же транзакции, что создаёт PR.
+// Создать PR от чужого имени могут только админ и система.
 // Ошибки:
+//   - auth.ErrUnauthorized        — в контексте нет вызывающего
+//   - auth.ErrForbidden           — PR от имени другого автора (FORBIDDEN, 403)
 //   - ErrNotFound                 — если автор не найден
 //   - ErrUserInactive             — если автор неактивен
 //   - ErrAlreadyExists            — если PR с таким id уже есть
-//   - reviewer_assignment.ErrNoReviewerCandidatesLeft — нет подходящих ревьюверов
+//   - pull_request.ErrReviewersCountOutOfBounds       — override вне границ команды
+//   - reviewer_assignment.ErrNoReviewerCandidatesLeft — кандидатов меньше min_reviewers
+//   - reviewer_assignment.ErrReviewersAtCapacity      — кандидаты есть, но все на пределе нагрузки
 func (s *Service) Create(
 	ctx context.Context,
 	pr *modelpr.PullRequest,
-) (*modelpr.PullRequest, []*modeluser.User, error) {
-	// 1. Автор существует?
-	author, err := s.users.GetByID(ctx, pr.AuthorID)
-	if err != nil {
-		return nil, nil, err // ErrNotFound → 404
+	opts CreateOptions,
+) (*modelpr.PullRequest, []*modelra.ReviewerAssignment, error) {
+	if err := authorizeSelf(ctx, pr.AuthorID); err != nil {
+		return nil, nil, err
 	}
-	if !author.IsActive {
-		return nil, nil, modeluser.ErrUserInactive
+
+	var (
+		created   *modelpr.PullRequest
+		reviewers []*modelra.ReviewerAssignment
+	)
+
+	err := s.tx.WithinTransaction(ctx, func(txCtx context.Context) error {
+		// 1. Автор существует и активен?
+		author, err := s.activeAuthor(txCtx, pr.AuthorID)
+		if err != nil {
+			return err // ErrNotFound → 404
+		}
+
+		// 2. Создаём PR: параллельный Create с тем же id упрётся в первичный ключ.
+		created, err = s.prs.Create(txCtx, pr)
+		if err != nil {
+			// ErrAlreadyExists → PR_EXISTS (409)
+			return err
+		}
+		if err := s.record(txCtx, statusEvent(created, modelra.ReasonPRCreated)); err != nil {
+			return err
+		}
+
+		// 3. Выбираем ревьюверов: владельцы кода, команда автора, резервные команды.
+		reviewers = nil
+		if created.Status == modelpr.PRDraft {
+			return nil
+		}
+		reviewers, err = s.initialReviewers(txCtx, created.ID, author, opts)
+		if err != nil {
+			return err
+		}
+
+		// 4. Назначаем ревьюверов.
+		return s.addReviewers(txCtx, reviewers, modelra.ReasonPRCreated)
+	})
+	if err != nil {
+		return nil, nil, err
 	}
 
-	// 2. Выбираем ревьюверов из команды автора.
-	reviewers, err := s.pickInitialReviewers(ctx, author)
+	return created, reviewers, nil
+}
+
+// Ready переводит DRAFT в OPEN и назначает ревьюверов так же, как Create.
+// Менять статус и ревьюверов PR могут автор, тимлид команды автора, админ и система.
+// Ошибки:
+//   - ErrNotFound                       — если PR или автор не найдены
+//   - auth.ErrUnauthorized, auth.ErrForbidden — как в authorizeChange
+//   - ErrUserInactive                   — если автор неактивен
+//   - pull_request.ErrInvalidTransition — PR не в статусе DRAFT (INVALID_TRANSITION, 409)
+//   - ошибки подбора ревьюверов, как в Create
+func (s *Service) Ready(
+	ctx context.Context,
+	prID string,
+	opts CreateOptions,
+) (*modelpr.PullRequest, []*modelra.ReviewerAssignment, error) {
+	var (
+		opened    *modelpr.PullRequest
+		reviewers []*modelra.ReviewerAssignment
+	)
+
+	err := s.tx.WithinTransaction(ctx, func(txCtx context.Context) error {
+		// Блокировка строки PR сериализует Ready с параллельными изменениями PR.
+		pr, err := s.prs.GetByIDForUpdate(txCtx, prID)
+		if err != nil {
+			return err
+		}
+		if err := s.authorizeChange(txCtx, pr); err != nil {
+			return err
+		}
+		if pr.Status != modelpr.PRDraft {
+			return modelpr.ErrInvalidTransition
+		}
+
+		author, err := s.activeAuthor(txCtx, pr.AuthorID)
+		if err != nil {
+			return err
+		}
+		reviewers, err = s.initialReviewers(txCtx, pr.ID, author, opts)
+		if err != nil {
+			return err
+		}
+
+		opened, err = s.prs.UpdateStatus(txCtx, prID, modelpr.PRDraft, modelpr.PROpen, s.clock())
+		if err != nil {
+			return err
+		}
+		if err := s.record(txCtx, statusEvent(opened, modelra.ReasonReady)); err != nil {
+			return err
+		}
+		return s.addReviewers(txCtx, reviewers, modelra.ReasonReady)
+	})
 	if err != nil {
 		return nil, nil, err
 	}
 
-	var created *modelpr.PullRequest
+	return opened, reviewers, nil
+}
 
-	err = s.tx.WithinTransaction(ctx, func(txCtx context.Context) error {
-		//3. Создаём PR.
-		created, err = s.prs.Create(txCtx, pr)
+// Close закрывает DRAFT или OPEN PR без merge.
+// Назначения сохраняются, но перестают учитываться в нагрузке ревьюверов (считаются только OPEN PR).
+// Ошибки:
+//   - ErrNotFound                       — если PR нет
+//   - auth.ErrUnauthorized, auth.ErrForbidden — как в authorizeChange
+//   - pull_request.ErrInvalidTransition — PR уже MERGED или CLOSED (INVALID_TRANSITION, 409)
+func (s *Service) Close(ctx context.Context, prID string) (*modelpr.PullRequest, error) {
+	var closed *modelpr.PullRequest
+
+	err := s.tx.WithinTransaction(ctx, func(txCtx context.Context) error {
+		pr, err := s.prs.GetByIDForUpdate(txCtx, prID)
 		if err != nil {
-			// ErrAlreadyExists → PR_EXISTS (409)
 			return err
 		}
-		// 4. Назначаем ревьюверов.
-		now := s.clock()
-		for _, rv := range reviewers {
-			if err := s.reviews.Add(txCtx, created.ID, rv.ID, now); err != nil {
-				// Если уже назначен — пропускаем.
-				if errors.Is(err, model.ErrAlreadyExists) || errors.Is(err, modelra.ErrReviewerDuplication) {
-					continue
-				}
+		if err := s.authorizeChange(txCtx, pr); err != nil {
+			return err
+		}
+		if !pr.Status.CanTransitionTo(modelpr.PRClosed) {
+			return modelpr.ErrInvalidTransition
+		}
+
+		closed, err = s.prs.UpdateStatus(txCtx, prID, pr.Status, modelpr.PRClosed, s.clock())
+		if err != nil {
+			return err
+		}
+		return s.record(txCtx, statusEvent(closed, ""))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return closed, nil
+}
+
+// Reopen возвращает CLOSED PR в OPEN с прежними ревьюверами.
+// Если ревьюверов у PR нет (например, он был закрыт черновиком), они назначаются как в Create.
+// Ошибки:
+//   - ErrNotFound                       — если PR нет
+//   - auth.ErrUnauthorized, auth.ErrForbidden — как в authorizeChange
+//   - pull_request.ErrInvalidTransition — PR не в статусе CLOSED (INVALID_TRANSITION, 409)
+//   - ErrUserInactive и ошибки подбора ревьюверов — если ревьюверов нужно назначить
+func (s *Service) Reopen(
+	ctx context.Context,
+	prID string,
+) (*modelpr.PullRequest, []*modelra.ReviewerAssignment, error) {
+	var (
+		reopened  *modelpr.PullRequest
+		reviewers []*modelra.ReviewerAssignment
+		added     []*modelra.ReviewerAssignment
+	)
+
+	err := s.tx.WithinTransaction(ctx, func(txCtx context.Context) error {
+		pr, err := s.prs.GetByIDForUpdate(txCtx, prID)
+		if err != nil {
+			return err
+		}
+		if err := s.authorizeChange(txCtx, pr); err != nil {
+			return err
+		}
+		if pr.Status != modelpr.PRClosed {
+			return modelpr.ErrInvalidTransition
+		}
+
+		reviewers, err = s.reviews.ListByPR(txCtx, prID)
+		if err != nil {
+			return err
+		}
+		added = nil
+		if len(reviewers) == 0 {
+			author, err := s.activeAuthor(txCtx, pr.AuthorID)
+			if err != nil {
+				return err
+			}
+			added, err = s.initialReviewers(txCtx, pr.ID, author, CreateOptions{})
+			if err != nil {
 				return err
 			}
 		}
-		return nil
+
+		reopened, err = s.prs.UpdateStatus(txCtx, prID, modelpr.PRClosed, modelpr.PROpen, s.clock())
+		if err != nil {
+			return err
+		}
+		if err := s.record(txCtx, statusEvent(reopened, modelra.ReasonReopened)); err != nil {
+			return err
+		}
+		return s.addReviewers(txCtx, added, modelra.ReasonReopened)
 	})
 	if err != nil {
 		return nil, nil, err
 	}
 
-	return created, reviewers, nil
+	return reopened, append(reviewers, added...), nil
 }
 
-// pickInitialReviewers выбирает до двух ревьюверов из команды автора.
-func (s *Service) pickInitialReviewers(
+// activeAuthor возвращает автора PR, если он активен.
+func (s *Service) activeAuthor(ctx context.Context, authorID string) (*modeluser.User, error) {
+	author, err := s.users.GetByID(ctx, authorID)
+	if err != nil {
+		return nil, err
+	}
+	if !author.IsActive {
+		return nil, modeluser.ErrUserInactive
+	}
+	return author, nil
+}
+
+// initialReviewers подбирает ревьюверов для PR, у которого их ещё нет.
+func (s *Service) initialReviewers(
 	ctx context.Context,
+	prID string,
 	author *modeluser.User,
+	opts CreateOptions,
+) ([]*modelra.ReviewerAssignment, error) {
+	team, err := s.teams.GetByName(ctx, author.TeamName)
+	if err != nil {
+		return nil, err
+	}
+	count, err := reviewersCount(team, opts.ReviewersCount)
+	if err != nil {
+		return nil, err
+	}
+
+	owners, err := s.resolveOwners(ctx, opts.ChangedFiles)
+	if err != nil {
+		return nil, err
+	}
+	return s.pickReviewers(ctx, pickRequest{
+		prID:   prID,
+		author: author,
+		team:   team,
+		owners: owners,
+		count:  count,
+		min:    team.MinReviewers,
+	})
+}
+
+// addReviewers сохраняет выбранных ревьюверов с текущим временем назначения
+// и записывает их назначение в историю.
+func (s *Service) addReviewers(ctx context.Context, reviewers []*modelra.ReviewerAssignment, reason string) error {
+	now := s.clock()
+	events := make([]*modelra.Event, 0, len(reviewers))
+	for _, rv := range reviewers {
+		rv.AssignedAt = now
+		// Точка сохранения: конфликт ключа не обрывает внешнюю транзакцию.
+		err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+			return s.reviews.Add(ctx, rv)
+		})
+		if err != nil {
+			// Если уже назначен — пропускаем.
+			if errors.Is(err, model.ErrAlreadyExists) || errors.Is(err, modelra.ErrReviewerDuplication) {
+				continue
+			}
+			return err
+		}
+		events = append(events, &modelra.Event{
+			PrId:   rv.PrId,
+			Type:   modelra.EventAssigned,
+			UserId: rv.UserId,
+			Reason: reason,
+		})
+	}
+	return s.record(ctx, events...)
+}
+
+// record дописывает события в историю PR от имени исполнителя из контекста
+// и в той же транзакции кладёт публикуемые из них вебхуки в outbox.
+func (s *Service) record(ctx context.Context, events ...*modelra.Event) error {
+	actor := service.ActorFrom(ctx)
+	now := s.clock()
+	for _, e := range events {
+		e.ActorId = actor
+		e.CreatedAt = now
+	}
+	if err := s.events.Append(ctx, events...); err != nil {
+		return err
+	}
+	return s.publish(ctx, events)
+}
+
+// publish кладёт в outbox вебхуки по событиям, которые публикуются наружу.
+func (s *Service) publish(ctx context.Context, events []*modelra.Event) error {
+	msgs, err := modelwh.MessagesFor(events)
+	if err != nil {
+		return err
+	}
+	return s.outbox.Enqueue(ctx, msgs...)
+}
+
+func statusEvent(pr *modelpr.PullRequest, reason string) *modelra.Event {
+	typ := modelra.EventStatusChanged
+	if pr.Status == modelpr.PRMerged {
+		typ = modelra.EventMerged
+	}
+	return &modelra.Event{
+		PrId:   pr.ID,
+		Type:   typ,
+		Status: string(pr.Status),
+		Reason: reason,
+	}
+}
+
+// reviewersCount возвращает число ревьюверов для PR с учётом override.
+func reviewersCount(team *modelteam.Team, override *int) (int, error) {
+	if override == nil {
+		return team.MaxReviewers, nil
+	}
+	if *override < team.MinReviewers || *override > team.MaxReviewers {
+		return 0, modelpr.ErrReviewersCountOutOfBounds
+	}
+	return *override, nil
+}
+
+// candidatePool — источник кандидатов в ревьюверы; members == nil — участники команды team.
+type candidatePool struct {
+	team     string
+	members  []*modeluser.User
+	fallback bool
+}
+
+// pickRequest — параметры подбора ревьюверов.
+type pickRequest struct {
+	prID    string
+	author  *modeluser.User
+	team    *modelteam.Team     // команда автора: стратегия, резервные команды
+	owners  []*modeluser.User   // владельцы кода, рассматриваются первыми
+	exclude map[string]struct{} // уже назначенные и заменяемые ревьюверы
+	count   int                 // сколько нужно
+	min     int                 // меньше — ошибка
+}
+
+// pickReviewers выбирает до req.count ревьюверов стратегией команды автора:
+// сначала из владельцев кода, затем из команды автора, затем из резервных команд
+// в порядке приоритета. Отсутствующие в данный момент кандидаты и кандидаты,
+// достигшие лимита открытых ревью, пропускаются.
+// Если выбрано меньше req.min — ErrReviewersAtCapacity (когда мешают только лимиты)
+// или ErrNoReviewerCandidatesLeft.
+func (s *Service) pickReviewers(
+	ctx context.Context,
+	req pickRequest,
+) ([]*modelra.ReviewerAssignment, error) {
+	skip := make(map[string]struct{}, len(req.exclude)+req.count)
+	for id := range req.exclude {
+		skip[id] = struct{}{}
+	}
+
+	pools := make([]candidatePool, 0, len(req.team.FallbackTeams)+2)
+	if len(req.owners) > 0 {
+		pools = append(pools, candidatePool{team: req.team.Name, members: req.owners})
+	}
+	pools = append(pools, candidatePool{team: req.team.Name})
+	for _, f := range req.team.FallbackTeams {
+		pools = append(pools, candidatePool{team: f, fallback: true})
+	}
+
+	teams := map[string]*modelteam.Team{req.team.Name: req.team}
+	picked := make([]*modelra.ReviewerAssignment, 0, req.count)
+	saturated := false
+
+	for _, pool := range pools {
+		if len(picked) >= req.count {
+			break
+		}
+
+		members := pool.members
+		if members == nil {
+			var err error
+			members, err = s.users.ListByTeam(ctx, pool.team)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		candidates, err := s.withoutAbsent(ctx, eligibleCandidates(members, req.author.ID, skip))
+		if err != nil {
+			return nil, err
+		}
+		available, err := s.withinCapacity(ctx, candidates, teams)
+		if err != nil {
+			return nil, err
+		}
+		if len(available) < len(candidates) {
+			saturated = true
+		}
+		if len(available) == 0 {
+			continue
+		}
+
+		users, err := s.selectReviewers(ctx, req.team, Selection{
+			PRID:       req.prID,
+			TeamName:   pool.team,
+			Candidates: available,
+			Count:      req.count - len(picked),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, u := range users {
+			skip[u.ID] = struct{}{}
+			picked = append(picked, &modelra.ReviewerAssignment{
+				PrId:     req.prID,
+				UserId:   u.ID,
+				Fallback: pool.fallback,
+			})
+		}
+	}
+
+	if len(picked) < req.min {
+		if saturated {
+			return nil, modelra.ErrReviewersAtCapacity
+		}
+		return nil, modelra.ErrNoReviewerCandidatesLeft
+	}
+
+	return picked, nil
+}
+
+// withoutAbsent убирает кандидатов, у которых на текущий момент (по s.clock) идёт отсутствие.
+func (s *Service) withoutAbsent(
+	ctx context.Context,
+	candidates []*modeluser.User,
 ) ([]*modeluser.User, error) {
-	members, err := s.users.ListByTeam(ctx, author.TeamName)
+	if len(candidates) == 0 {
+		return candidates, nil
+	}
+
+	absent, err := s.absences.ListAbsentUserIDs(ctx, userIDs(candidates), s.clock())
 	if err != nil {
 		return nil, err
 	}
+	if len(absent) == 0 {
+		return candidates, nil
+	}
+
+	available := make([]*modeluser.User, 0, len(candidates))
+	for _, c := range candidates {
+		if _, ok := absent[c.ID]; ok {
+			continue
+		}
+		available = append(available, c)
+	}
+
+	return available, nil
+}
 
-	candidates := make([]*modeluser.User, 0)
+// withinCapacity убирает кандидатов, достигших лимита открытых ревью
+// (личного или лимита по умолчанию их команды). teams — кеш команд по имени.
+func (s *Service) withinCapacity(
+	ctx context.Context,
+	candidates []*modeluser.User,
+	teams map[string]*modelteam.Team,
+) ([]*modeluser.User, error) {
+	limited := make([]*modeluser.User, 0, len(candidates))
+	capacity := make(map[string]int, len(candidates))
+	for _, c := range candidates {
+		team, ok := teams[c.TeamName]
+		if !ok {
+			var err error
+			team, err = s.teams.GetByName(ctx, c.TeamName)
+			if err != nil {
+				return nil, err
+			}
+			teams[c.TeamName] = team
+		}
+		if limit := c.Capacity(team.DefaultMaxOpenReviews); limit > 0 {
+			capacity[c.ID] = limit
+			limited = append(limited, c)
+		}
+	}
+	if len(limited) == 0 {
+		return candidates, nil
+	}
+
+	load, err := s.reviews.CountOpenByReviewers(ctx, userIDs(limited))
+	if err != nil {
+		return nil, err
+	}
+
+	available := make([]*modeluser.User, 0, len(candidates))
+	for _, c := range candidates {
+		l := modeluser.ReviewLoad{Open: load[c.ID], Capacity: capacity[c.ID]}
+		if l.AtCapacity() {
+			continue
+		}
+		available = append(available, c)
+	}
+
+	return available, nil
+}
+
+// eligibleCandidates отбирает активных участников команды, кроме автора и уже назначенных.
+func eligibleCandidates(
+	members []*modeluser.User,
+	authorID string,
+	exclude map[string]struct{},
+) []*modeluser.User {
+	candidates := make([]*modeluser.User, 0, len(members))
 	for _, m := range members {
 		if !m.IsActive {
 			continue
 		}
-		if m.ID == author.ID {
+		if m.ID == authorID {
+			continue
+		}
+		if _, skip := exclude[m.ID]; skip {
 			continue
 		}
 		candidates = append(candidates, m)
 	}
+	return candidates
+}
 
-	if len(candidates) == 0 {
-		return nil, modelra.ErrNoReviewerCandidatesLeft
-	}
-
-	if len(candidates) > 2 {
-		candidates = candidates[:2]
+// selectReviewers применяет стратегию, настроенную для команды.
+// Неизвестная стратегия заменяется стратегией по умолчанию.
+func (s *Service) selectReviewers(
+	ctx context.Context,
+	team *modelteam.Team,
+	sel Selection,
+) ([]*modeluser.User, error) {
+	selector, ok := s.selectors[team.ReviewerStrategy]
+	if !ok {
+		selector = s.selectors[modelteam.DefaultReviewerStrategy]
 	}
 
-	return candidates, nil
+	return selector.Select(ctx, sel)
 }
 
-// Merge помечает PR как MERGED.
+// Merge помечает PR как MERGED, если ревью удовлетворяют правилу merge.
+// Строка PR блокируется до конца транзакции, поэтому параллельная замена
+// ревьювера не изменит набор ревью между проверкой правила и merge.
+// Смержить PR может админ, система или тимлид команды автора.
 // Ошибки:
-//   - ErrNotFound — если PR нет
+//   - ErrNotFound                       — если PR нет
+//   - pull_request.ErrPRAlreadyMerged   — PR уже смержен (PR_MERGED, 409); проверяется до роли и правила
+//   - pull_request.ErrInvalidTransition — PR в статусе DRAFT или CLOSED (INVALID_TRANSITION, 409)
+//   - auth.ErrUnauthorized              — в контексте нет вызывающего
+//   - auth.ErrForbidden                 — вызывающий не админ и не тимлид команды автора (FORBIDDEN, 403)
+//   - *pull_request.NotApprovedError     — правило не выполнено (NOT_APPROVED, 409)
 //   - остальные ошибки — из репозитория PR
 func (s *Service) Merge(
 	ctx context.Context,
 	prID string,
 ) (*modelpr.PullRequest, error) {
-	pr, err := s.prs.MarkMerged(ctx, prID)
+	var merged *modelpr.PullRequest
+
+	err := s.tx.WithinTransaction(ctx, func(txCtx context.Context) error {
+		pr, err := s.prs.GetByIDForUpdate(txCtx, prID)
+		if err != nil {
+			return err
+		}
+		switch pr.Status {
+		case modelpr.PROpen:
+		case modelpr.PRMerged:
+			return modelpr.ErrPRAlreadyMerged
+		default:
+			return modelpr.ErrInvalidTransition
+		}
+		if err := s.authorizeMerge(txCtx, pr); err != nil {
+			return err
+		}
+
+		reviews, err := s.reviews.ListByPR(txCtx, prID)
+		if err != nil {
+			return err
+		}
+		if err := s.mergeRule.Check(reviews); err != nil {
+			return err
+		}
+
+		merged, err = s.prs.MarkMerged(txCtx, prID)
+		if err != nil {
+			return err
+		}
+		return s.record(txCtx, statusEvent(merged, ""))
+	})
 	if err != nil {
 		return nil, err
 	}
-	return pr, nil
+	return merged, nil
 }
 
-func (s *Service) GetByID(ctx context.Context, prID string) (*modelpr.PullRequest, error) {
-	return s.prs.GetByID(ctx, prID)
+// MarkMergedExternal фиксирует merge, уже выполненный на хостинге.
+// Правило merge и роль вызывающего не проверяются: PR смержен вне сервиса,
+// и отказ только заставил бы хостинг бесконечно повторять доставку.
+// Статус DRAFT или CLOSED тоже не мешает — хостинг здесь источник истины.
+// Ошибки:
+//   - ErrNotFound                     — если PR нет
+//   - pull_request.ErrPRAlreadyMerged — PR уже смержен
+func (s *Service) MarkMergedExternal(ctx context.Context, prID string) (*modelpr.PullRequest, error) {
+	var merged *modelpr.PullRequest
+
+	err := s.tx.WithinTransaction(ctx, func(txCtx context.Context) error {
+		if _, err := s.prs.GetByIDForUpdate(txCtx, prID); err != nil {
+			return err
+		}
+
+		var err error
+		merged, err = s.prs.MarkMerged(txCtx, prID)
+		if err != nil {
+			return err
+		}
+		return s.record(txCtx, statusEvent(merged, ""))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return merged, nil
 }
 
-// Reassign переназначает одного ревьювера на другого и возвращает нового ревьювера.
+// authorizeMerge проверяет, что вызывающий — админ, система или тимлид команды автора PR.
+func (s *Service) authorizeMerge(ctx context.Context, pr *modelpr.PullRequest) error {
+	p, err := service.RequirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	if p.Privileged() {
+		return nil
+	}
+	if !p.HasRole(modelauth.RoleTeamLead) {
+		return modelauth.ErrForbidden
+	}
+	return s.requireLeadOf(ctx, p, pr)
+}
+
+// authorizeChange проверяет, что вызывающий может менять PR (ревьюверов и статус):
+// это автор PR, тимлид команды автора, админ или система.
+func (s *Service) authorizeChange(ctx context.Context, pr *modelpr.PullRequest) error {
+	p, err := service.RequirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	if p.Privileged() || p.Subject == pr.AuthorID {
+		return nil
+	}
+	if !p.HasRole(modelauth.RoleTeamLead) {
+		return modelauth.ErrForbidden
+	}
+	return s.requireLeadOf(ctx, p, pr)
+}
+
+// authorizeSelf проверяет, что действует сам пользователь userID (автор PR или ревьювер),
+// админ или система.
+func authorizeSelf(ctx context.Context, userID string) error {
+	p, err := service.RequirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	if p.Privileged() || p.Subject == userID {
+		return nil
+	}
+	return modelauth.ErrForbidden
+}
+
+// requireLeadOf проверяет, что тимлид p состоит в команде автора PR.
+func (s *Service) requireLeadOf(ctx context.Context, p *modelauth.Principal, pr *modelpr.PullRequest) error {
+	users, err := s.users.ListByIDs(ctx, []string{p.Subject, pr.AuthorID})
+	if err != nil {
+		return err
+	}
+	teams := make(map[string]string, len(users))
+	for _, u := range users {
+		teams[u.ID] = u.TeamName
+	}
+	lead, ok := teams[p.Subject]
+	if !ok || lead != teams[pr.AuthorID] {
+		return modelauth.ErrForbidden
+	}
+	return nil
+}
+
+// Review сохраняет вердикт ревьювера; повторная отправка заменяет прежний вердикт.
+// Вердикт отправляет сам ревьювер; за другого — только админ или система.
 // Ошибки:
-//   - ErrNotFound                      — если PR / автор не найдены
-//   - ErrUserInactive                  — если автор неактивен
-//   - reviewer_assignment.ErrReviewerNotFoundInPR     — oldUserID не был ревьювером (NOT_ASSIGNED, 409)
-//   - reviewer_assignment.ErrNoReviewerCandidatesLeft — нет кандидатов (NO_CANDIDATE, 409)
-func (s *Service) Reassign(
+//   - ErrNotFound                                  — если PR нет
+//   - auth.ErrUnauthorized                         — в контексте нет вызывающего
+//   - auth.ErrForbidden                            — вердикт за другого ревьювера (FORBIDDEN, 403)
+//   - pull_request.ErrPRAlreadyMerged              — PR уже смержен (PR_MERGED, 409)
+//   - pull_request.ErrPRNotOpen                    — PR в статусе DRAFT или CLOSED (PR_NOT_OPEN, 409)
+//   - reviewer_assignment.ErrUnknownVerdict        — неизвестный вердикт
+//   - reviewer_assignment.ErrReviewerNotFoundInPR  — пользователь не ревьювер PR (NOT_ASSIGNED, 409)
+func (s *Service) Review(
 	ctx context.Context,
 	prID string,
-	oldUserID string,
-) (*modeluser.User, error) {
-	// 1. PR существует.
-	pr, err := s.prs.GetByID(ctx, prID)
-	if err != nil {
-		return nil, err
+	reviewerID string,
+	verdict modelra.Verdict,
+) (*modelpr.PullRequest, []*modelra.ReviewerAssignment, error) {
+	if !verdict.Valid() {
+		return nil, nil, modelra.ErrUnknownVerdict
 	}
-	if pr.Status == modelpr.PRMerged {
-		return nil, modelpr.ErrPRAlreadyMerged
+	if err := authorizeSelf(ctx, reviewerID); err != nil {
+		return nil, nil, err
 	}
 
-	// 2. Автор.
-	author, err := s.users.GetByID(ctx, pr.AuthorID)
+	var (
+		pr      *modelpr.PullRequest
+		reviews []*modelra.ReviewerAssignment
+	)
+
+	err := s.tx.WithinTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		pr, err = s.prs.GetByIDForUpdate(txCtx, prID)
+		if err != nil {
+			return err
+		}
+		if pr.Status == modelpr.PRMerged {
+			return modelpr.ErrPRAlreadyMerged
+		}
+		if pr.Status != modelpr.PROpen {
+			return modelpr.ErrPRNotOpen
+		}
+
+		if _, err := s.reviews.SetVerdict(txCtx, prID, reviewerID, verdict, s.clock()); err != nil {
+			return err
+		}
+		if err := s.record(txCtx, &modelra.Event{
+			PrId:    prID,
+			Type:    modelra.EventVerdict,
+			UserId:  reviewerID,
+			Verdict: verdict,
+		}); err != nil {
+			return err
+		}
+
+		reviews, err = s.reviews.ListByPR(txCtx, prID)
+		return err
+	})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	if !author.IsActive {
-		return nil, modeluser.ErrUserInactive
+
+	return pr, reviews, nil
+}
+
+func (s *Service) GetByID(ctx context.Context, prID string) (*modelpr.PullRequest, error) {
+	return s.prs.GetByID(ctx, prID)
+}
+
+// Get возвращает PR вместе с ревьюверами.
+// Ошибки:
+//   - ErrNotFound — если PR нет
+func (s *Service) Get(ctx context.Context, prID string) (*modelpr.PullRequest, []*modelra.ReviewerAssignment, error) {
+	pr, err := s.prs.GetByID(ctx, prID)
+	if err != nil {
+		return nil, nil, err
+	}
+	reviewers, err := s.reviews.ListByPR(ctx, prID)
+	if err != nil {
+		return nil, nil, err
 	}
+	return pr, reviewers, nil
+}
 
-	// 2.5. Проверяем, что oldUserID вообще существует.
-	if _, err := s.users.GetByID(ctx, oldUserID); err != nil {
+// ListPage — страница списка PR; Next == nil — страница последняя.
+type ListPage struct {
+	PullRequests []*modelpr.PullRequest
+	Reviewers    map[string][]*modelra.ReviewerAssignment // по id PR
+	Next         *modelpr.Cursor
+}
+
+// List возвращает страницу PR по фильтру в порядке (created_at, id).
+// Ошибки:
+//   - pull_request.ErrInvalidListFilter — неизвестный статус или лимит вне 1..MaxListLimit
+func (s *Service) List(ctx context.Context, filter modelpr.ListFilter) (*ListPage, error) {
+	if err := filter.Normalize(); err != nil {
 		return nil, err
 	}
 
-	// 3. Текущие ревьюверы PR.
-	assignments, err := s.reviews.ListByPR(ctx, pr.ID)
+	limit := filter.Limit
+	filter.Limit++ // лишняя строка показывает, есть ли следующая страница
+	prs, err := s.prs.List(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
 
-	assigned := make(map[string]struct{}, len(assignments))
-	oldAssigned := false
-	for _, a := range assignments {
-		assigned[a.UserId] = struct{}{}
-		if a.UserId == oldUserID {
-			oldAssigned = true
-		}
+	page := &ListPage{}
+	if len(prs) > limit {
+		prs = prs[:limit]
+		last := prs[limit-1]
+		page.Next = &modelpr.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
 	}
+	page.PullRequests = prs
 
-	if !oldAssigned {
-		return nil, modelra.ErrReviewerNotFoundInPR
+	ids := make([]string, 0, len(prs))
+	for _, pr := range prs {
+		ids = append(ids, pr.ID)
 	}
-
-	// 4. Кандидаты из команды автора.
-	members, err := s.users.ListByTeam(ctx, author.TeamName)
+	page.Reviewers, err = s.reviews.ListByPRs(ctx, ids)
 	if err != nil {
 		return nil, err
 	}
 
-	var newReviewer *modeluser.User
+	return page, nil
+}
+
+// History возвращает историю ревью PR в хронологическом порядке.
+// Ошибки:
+//   - ErrNotFound — если PR нет
+func (s *Service) History(ctx context.Context, prID string) ([]*modelra.Event, error) {
+	if _, err := s.prs.GetByID(ctx, prID); err != nil {
+		return nil, err
+	}
+	return s.events.ListByPR(ctx, prID)
+}
 
-	for _, m := range members {
-		if !m.IsActive {
-			continue
+// Reassign переназначает одного ревьювера на другого и возвращает новое назначение.
+// Замена ищется в команде автора, затем в резервных командах.
+// Ошибки:
+//   - ErrNotFound                      — если PR / автор не найдены
+//   - auth.ErrUnauthorized, auth.ErrForbidden — как в authorizeChange
+//   - ErrUserInactive                  — если автор неактивен
+//   - pull_request.ErrPRNotOpen        — PR в статусе DRAFT или CLOSED (PR_NOT_OPEN, 409)
+//   - reviewer_assignment.ErrReviewerNotFoundInPR     — oldUserID не был ревьювером (NOT_ASSIGNED, 409)
+//   - reviewer_assignment.ErrNoReviewerCandidatesLeft — нет кандидатов (NO_CANDIDATE, 409)
+//   - reviewer_assignment.ErrReviewersAtCapacity      — все кандидаты на пределе (REVIEWERS_AT_CAPACITY, 409)
+func (s *Service) Reassign(
+	ctx context.Context,
+	prID string,
+	oldUserID string,
+) (*modelra.ReviewerAssignment, error) {
+	return s.ReplaceReviewer(ctx, prID, oldUserID, modelra.ReasonManual)
+}
+
+// ReplaceReviewer — Reassign с указанием причины замены для истории PR.
+// Проверки и подбор замены идут в одной транзакции под блокировкой строки PR,
+// поэтому параллельные замены и merge того же PR выполняются по очереди.
+func (s *Service) ReplaceReviewer(
+	ctx context.Context,
+	prID string,
+	oldUserID string,
+	reason string,
+) (*modelra.ReviewerAssignment, error) {
+	var newReviewer *modelra.ReviewerAssignment
+
+	err := s.tx.WithinTransaction(ctx, func(txCtx context.Context) error {
+		// 1. PR существует и открыт.
+		pr, err := s.prs.GetByIDForUpdate(txCtx, prID)
+		if err != nil {
+			return err
 		}
-		if m.ID == author.ID {
-			continue
+		if err := s.authorizeChange(txCtx, pr); err != nil {
+			return err
 		}
-		if m.ID == oldUserID {
-			continue
+		if pr.Status == modelpr.PRMerged {
+			return modelpr.ErrPRAlreadyMerged
 		}
-		if _, alreadyAssigned := assigned[m.ID]; alreadyAssigned {
-			continue
+		if pr.Status != modelpr.PROpen {
+			return modelpr.ErrPRNotOpen
 		}
-		newReviewer = m
-		break
-	}
 
-	if newReviewer == nil {
-		return nil, modelra.ErrNoReviewerCandidatesLeft
-	}
+		// 2. Автор.
+		author, err := s.activeAuthor(txCtx, pr.AuthorID)
+		if err != nil {
+			return err
+		}
 
-	if err := s.reviews.Replace(ctx, pr.ID, oldUserID, newReviewer.ID, s.clock()); err != nil {
+		// 2.5. Проверяем, что oldUserID вообще существует.
+		if _, err := s.users.GetByID(txCtx, oldUserID); err != nil {
+			return err
+		}
+
+		// 3. Текущие ревьюверы PR.
+		assignments, err := s.reviews.ListByPR(txCtx, pr.ID)
+		if err != nil {
+			return err
+		}
+
+		assigned := make(map[string]struct{}, len(assignments))
+		oldAssigned := false
+		for _, a := range assignments {
+			assigned[a.UserId] = struct{}{}
+			if a.UserId == oldUserID {
+				oldAssigned = true
+			}
+		}
+
+		if !oldAssigned {
+			return modelra.ErrReviewerNotFoundInPR
+		}
+
+		// 4. Кандидат из команды автора, а при её исчерпании — из резервных команд.
+		team, err := s.teams.GetByName(txCtx, author.TeamName)
+		if err != nil {
+			return err
+		}
+
+		assigned[oldUserID] = struct{}{}
+		picked, err := s.pickReviewers(txCtx, pickRequest{
+			prID:    pr.ID,
+			author:  author,
+			team:    team,
+			exclude: assigned,
+			count:   1,
+			min:     1,
+		})
+		if err != nil {
+			return err
+		}
+
+		newReviewer = picked[0]
+		newReviewer.AssignedAt = s.clock()
+
+		if err := s.reviews.Replace(txCtx, oldUserID, newReviewer); err != nil {
+			return err
+		}
+		return s.record(txCtx, &modelra.Event{
+			PrId:           pr.ID,
+			Type:           modelra.EventReplaced,
+			UserId:         newReviewer.UserId,
+			PreviousUserId: oldUserID,
+			Reason:         reason,
+		})
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,141 @@
+// Package servicetest собирает сервисы поверх хранилища в памяти для тестов.
+package servicetest
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/zxchelik/avito-test-task/internal/infrastructure/memory"
+	modelauth "github.com/zxchelik/avito-test-task/internal/model/auth"
+	modelteam "github.com/zxchelik/avito-test-task/internal/model/team"
+	modeluser "github.com/zxchelik/avito-test-task/internal/model/user"
+	modelwh "github.com/zxchelik/avito-test-task/internal/model/webhook"
+	absenceRep "github.com/zxchelik/avito-test-task/internal/repository/absence"
+	eventRep "github.com/zxchelik/avito-test-task/internal/repository/assignment_event"
+	coRep "github.com/zxchelik/avito-test-task/internal/repository/code_owner"
+	forgeRep "github.com/zxchelik/avito-test-task/internal/repository/forge"
+	prRep "github.com/zxchelik/avito-test-task/internal/repository/pull_request"
+	raRep "github.com/zxchelik/avito-test-task/internal/repository/reviewer_assignment"
+	teamRep "github.com/zxchelik/avito-test-task/internal/repository/team"
+	userRep "github.com/zxchelik/avito-test-task/internal/repository/user"
+	webhookRep "github.com/zxchelik/avito-test-task/internal/repository/webhook"
+	"github.com/zxchelik/avito-test-task/internal/service"
+	prSvc "github.com/zxchelik/avito-test-task/internal/service/pull_request"
+)
+
+// Store — репозитории и менеджер транзакций одного хранилища в памяти.
+type Store struct {
+	DB       *memory.DB
+	Tx       *memory.TxManager
+	Users    *userRep.MemoryRepository
+	Teams    *teamRep.MemoryRepository
+	PRs      *prRep.MemoryRepository
+	Reviews  *raRep.MemoryRepository
+	Owners   *coRep.MemoryRepository
+	Absences *absenceRep.MemoryRepository
+	Events   *eventRep.MemoryRepository
+	Webhooks *webhookRep.MemoryRepository
+	Forge    *forgeRep.MemoryRepository
+}
+
+// NewStore создаёт пустое хранилище.
+func NewStore() *Store {
+	db := memory.NewDB()
+	return &Store{
+		DB:       db,
+		Tx:       memory.NewTxManager(db),
+		Users:    userRep.NewMemoryRepository(db),
+		Teams:    teamRep.NewMemoryRepository(db),
+		PRs:      prRep.NewMemoryRepository(db),
+		Reviews:  raRep.NewMemoryRepository(db),
+		Owners:   coRep.NewMemoryRepository(db),
+		Absences: absenceRep.NewMemoryRepository(db),
+		Events:   eventRep.NewMemoryRepository(db),
+		Webhooks: webhookRep.NewMemoryRepository(db),
+		Forge:    forgeRep.NewMemoryRepository(db),
+	}
+}
+
+// PRService — сервис PR поверх хранилища.
+func (s *Store) PRService() *prSvc.Service {
+	return prSvc.NewService(s.PRs, s.Users, s.Teams, s.Reviews, s.Owners, s.Absences, s.Events, s.Webhooks, s.Tx)
+}
+
+// Team — команда по умолчанию: least_loaded, от 1 до max ревьюверов.
+func Team(name string, maxReviewers int) *modelteam.Team {
+	return &modelteam.Team{
+		Name:             name,
+		ReviewerStrategy: modelteam.DefaultReviewerStrategy,
+		MinReviewers:     1,
+		MaxReviewers:     maxReviewers,
+	}
+}
+
+// AddTeam создаёт команду и активных участников с id memberIDs.
+func (s *Store) AddTeam(t testing.TB, team *modelteam.Team, memberIDs ...string) {
+	t.Helper()
+
+	ctx := context.Background()
+	if err := s.Teams.Create(ctx, team); err != nil {
+		t.Fatalf("create team %s: %v", team.Name, err)
+	}
+	for _, id := range memberIDs {
+		u := &modeluser.User{ID: id, Username: id, TeamName: team.Name, IsActive: true}
+		if err := s.Users.Upsert(ctx, u); err != nil {
+			t.Fatalf("create user %s: %v", id, err)
+		}
+	}
+}
+
+// Subscribe подписывает url на все события.
+func (s *Store) Subscribe(t testing.TB, url, secret string) *modelwh.Subscription {
+	t.Helper()
+
+	sub, err := s.Webhooks.CreateSubscription(context.Background(), &modelwh.Subscription{
+		URL:    url,
+		Secret: secret,
+		EventTypes: []modelwh.EventType{
+			modelwh.EventPRCreated, modelwh.EventPRMerged, modelwh.EventReviewerAssigned,
+			modelwh.EventReviewerReplaced, modelwh.EventReviewSLABreached,
+		},
+	})
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	return sub
+}
+
+// Outbox возвращает все сообщения outbox в порядке id.
+func (s *Store) Outbox() []*modelwh.Delivery {
+	tables, release := s.DB.Acquire(context.Background())
+	defer release()
+
+	res := make([]*modelwh.Delivery, 0, len(tables.Deliveries))
+	for _, d := range tables.Deliveries {
+		c := *d
+		res = append(res, &c)
+	}
+	slices.SortFunc(res, func(a, b *modelwh.Delivery) int { return cmp.Compare(a.ID, b.ID) })
+	return res
+}
+
+// OutboxTypes возвращает типы сообщений outbox в порядке id.
+func (s *Store) OutboxTypes() []modelwh.EventType {
+	var res []modelwh.EventType
+	for _, d := range s.Outbox() {
+		res = append(res, d.EventType)
+	}
+	return res
+}
+
+// As возвращает контекст с вызывающим subject в роли role.
+func As(subject string, role modelauth.Role) context.Context {
+	return service.WithPrincipal(context.Background(), &modelauth.Principal{Subject: subject, Role: role})
+}
+
+// System возвращает контекст, в котором действует сама система.
+func System() context.Context {
+	return service.AsSystem(context.Background())
+}
@@ -0,0 +1,148 @@
+package sla
+
+import (
+	"context"
+	"errors"
+
+	modelra "github.com/zxchelik/avito-test-task/internal/model/reviewer_assignment"
+	modeluser "github.com/zxchelik/avito-test-task/internal/model/user"
+	modelwh "github.com/zxchelik/avito-test-task/internal/model/webhook"
+	"github.com/zxchelik/avito-test-task/internal/service"
+)
+
+// ReviewReassigner заменяет ревьювера на PR по тем же правилам, что и /pullRequest/reassign.
+type ReviewReassigner interface {
+	ReplaceReviewer(ctx context.Context, prID, oldUserID, reason string) (*modelra.ReviewerAssignment, error)
+}
+
+// Escalation — зафиксированное нарушение SLA.
+type Escalation struct {
+	Assignment   *modelra.SLAPending
+	Reassignment *modelra.Reassignment // nil, если у команды выключено автопереназначение
+	// Err — обработка нарушения не удалась и откатилась; назначение попадёт в следующий проход.
+	Err error
+}
+
+type Service struct {
+	reviews    service.ReviewerAssignmentRepository
+	events     service.AssignmentEventRepository
+	outbox     service.WebhookOutbox
+	reassigner ReviewReassigner
+	tx         service.TxManager
+	clock      service.Clock
+}
+
+func NewService(
+	reviews service.ReviewerAssignmentRepository,
+	events service.AssignmentEventRepository,
+	outbox service.WebhookOutbox,
+	reassigner ReviewReassigner,
+	tx service.TxManager,
+) *Service {
+	return &Service{
+		reviews:    reviews,
+		events:     events,
+		outbox:     outbox,
+		reassigner: reassigner,
+		tx:         tx,
+		clock:      service.DefaultClock,
+	}
+}
+
+// WithClock позволяет подменять время в тестах.
+func (s *Service) WithClock(clock service.Clock) *Service {
+	s.clock = clock
+	return s
+}
+
+// Escalate находит назначения, нарушившие SLA команды, отмечает их, пишет
+// событие SLA_BREACHED (и вебхук reviewer.sla_breached в outbox) и,
+// если команда это разрешает, переназначает ревью.
+// Каждое нарушение обрабатывается в своей транзакции: ошибка одного не откатывает остальные
+// и не останавливает проход, а возвращается в Escalation.Err.
+// Ошибка возвращается, только если не удалось выбрать назначения или ctx отменён.
+func (s *Service) Escalate(ctx context.Context) ([]*Escalation, error) {
+	ctx = service.AsSystem(ctx) // переназначает сама система, а не пользователь
+	now := s.clock()
+
+	pending, err := s.reviews.ListSLAPending(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []*Escalation
+	for _, p := range pending {
+		if !p.Breached(now) {
+			continue
+		}
+		esc, err := s.escalate(ctx, p)
+		switch {
+		case ctx.Err() != nil:
+			return res, ctx.Err()
+		case err != nil:
+			res = append(res, &Escalation{Assignment: p, Err: err})
+		case esc != nil:
+			res = append(res, esc)
+		}
+	}
+
+	return res, nil
+}
+
+// escalate обрабатывает одно нарушение; nil без ошибки — назначение изменилось
+// с момента выборки и нарушение уже неактуально.
+func (s *Service) escalate(ctx context.Context, p *modelra.SLAPending) (*Escalation, error) {
+	var esc *Escalation
+	err := s.tx.WithinTransaction(ctx, func(txCtx context.Context) error {
+		now := s.clock()
+		marked, err := s.reviews.MarkSLABreached(txCtx, p.PrId, p.UserId, p.AssignedAt, now)
+		if err != nil || !marked {
+			return err
+		}
+		breached := &modelra.Event{
+			PrId:      p.PrId,
+			Type:      modelra.EventSLABreached,
+			UserId:    p.UserId,
+			ActorId:   service.ActorFrom(txCtx),
+			Reason:    modelra.ReasonSLABreached,
+			CreatedAt: now,
+		}
+		if err := s.events.Append(txCtx, breached); err != nil {
+			return err
+		}
+		msg, err := modelwh.MessageFor(breached)
+		if err != nil {
+			return err
+		}
+		if err := s.outbox.Enqueue(txCtx, msg); err != nil {
+			return err
+		}
+
+		esc = &Escalation{Assignment: p}
+		if !p.AutoReassign {
+			return nil
+		}
+
+		res := &modelra.Reassignment{PrId: p.PrId, OldUserId: p.UserId}
+		next, err := s.reassigner.ReplaceReviewer(txCtx, p.PrId, p.UserId, modelra.ReasonSLABreached)
+		switch {
+		case err == nil:
+			res.NewUserId = next.UserId
+			res.Fallback = next.Fallback
+		case errors.Is(err, modelra.ErrNoReviewerCandidatesLeft),
+			errors.Is(err, modelra.ErrReviewersAtCapacity),
+			errors.Is(err, modeluser.ErrUserInactive):
+			// замены нет — нарушение остаётся отмеченным за текущим ревьювером
+			res.Reason = err
+		default:
+			return err
+		}
+		esc.Reassignment = res
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return esc, nil
+}
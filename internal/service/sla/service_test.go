@@ -0,0 +1,257 @@
+package sla_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	modelpr "github.com/zxchelik/avito-test-task/internal/model/pull_request"
+	modelra "github.com/zxchelik/avito-test-task/internal/model/reviewer_assignment"
+	modelteam "github.com/zxchelik/avito-test-task/internal/model/team"
+	modelwh "github.com/zxchelik/avito-test-task/internal/model/webhook"
+	prSvc "github.com/zxchelik/avito-test-task/internal/service/pull_request"
+	"github.com/zxchelik/avito-test-task/internal/service/servicetest"
+	slaSvc "github.com/zxchelik/avito-test-task/internal/service/sla"
+)
+
+// monday — понедельник, 10:00 UTC.
+var monday = time.Date(2025, 3, 3, 10, 0, 0, 0, time.UTC)
+
+// fixture — команда backend с SLA, PR и SLA-сервис с подменяемым временем.
+type fixture struct {
+	store *servicetest.Store
+	prs   *prSvc.Service
+	svc   *slaSvc.Service
+	now   time.Time
+}
+
+func newFixture(t *testing.T, team *modelteam.Team, members ...string) *fixture {
+	t.Helper()
+
+	f := &fixture{store: servicetest.NewStore(), now: monday}
+	f.store.AddTeam(t, team, members...)
+	f.store.Subscribe(t, "https://hooks.example.com/review", "secret")
+
+	f.prs = f.store.PRService().WithClock(f.clock)
+	f.withReassigner(f.prs)
+	return f
+}
+
+func (f *fixture) clock() time.Time { return f.now }
+
+// withReassigner пересобирает SLA-сервис с другим исполнителем переназначений.
+func (f *fixture) withReassigner(r slaSvc.ReviewReassigner) {
+	f.svc = slaSvc.NewService(f.store.Reviews, f.store.Events, f.store.Webhooks, r, f.store.Tx).WithClock(f.clock)
+}
+
+func slaTeam(hours int, autoReassign bool) *modelteam.Team {
+	team := servicetest.Team("backend", 1)
+	team.ReviewSLAWeekdayHours = hours
+	team.SLAAutoReassign = autoReassign
+	return team
+}
+
+// createPR создаёт PR в момент f.now и возвращает id ревьювера.
+func (f *fixture) createPR(t *testing.T, id string) string {
+	t.Helper()
+
+	_, reviewers, err := f.prs.Create(servicetest.System(), &modelpr.PullRequest{
+		ID: id, Title: "feature", AuthorID: "author", Status: modelpr.PROpen,
+	}, prSvc.CreateOptions{})
+	if err != nil {
+		t.Fatalf("create PR %s: %v", id, err)
+	}
+	return reviewers[0].UserId
+}
+
+func (f *fixture) escalate(t *testing.T) []*slaSvc.Escalation {
+	t.Helper()
+
+	res, err := f.svc.Escalate(context.Background())
+	if err != nil {
+		t.Fatalf("escalate: %v", err)
+	}
+	return res
+}
+
+func TestEscalatePublishesBreach(t *testing.T) {
+	f := newFixture(t, slaTeam(4, true), "author", "r1", "r2")
+	f.createPR(t, "pr-1")
+
+	f.now = monday.Add(4 * time.Hour)
+	if res := f.escalate(t); len(res) != 1 || res[0].Reassignment == nil || !res[0].Reassignment.Replaced() {
+		t.Fatalf("escalations: %+v", res)
+	}
+
+	want := []modelwh.EventType{
+		modelwh.EventPRCreated, modelwh.EventReviewerAssigned,
+		modelwh.EventReviewSLABreached, modelwh.EventReviewerReplaced,
+	}
+	if got := f.store.OutboxTypes(); !slices.Equal(got, want) {
+		t.Fatalf("outbox = %v, want %v", got, want)
+	}
+}
+
+// breachedAt возвращает отметку о нарушении SLA у ревьювера PR.
+func (f *fixture) breachedAt(t *testing.T, prID, userID string) *time.Time {
+	t.Helper()
+
+	reviewers, err := f.store.Reviews.ListByPR(context.Background(), prID)
+	if err != nil {
+		t.Fatalf("list reviewers: %v", err)
+	}
+	for _, r := range reviewers {
+		if r.UserId == userID {
+			return r.SLABreachedAt
+		}
+	}
+	t.Fatalf("%s is not a reviewer of %s", userID, prID)
+	return nil
+}
+
+func TestEscalateDetectsBreach(t *testing.T) {
+	f := newFixture(t, slaTeam(4, false), "author", "r1")
+	reviewer := f.createPR(t, "pr-1")
+
+	f.now = monday.Add(4*time.Hour - time.Second)
+	if res := f.escalate(t); len(res) != 0 {
+		t.Fatalf("escalated before deadline: %+v", res)
+	}
+
+	f.now = monday.Add(4 * time.Hour)
+	res := f.escalate(t)
+	if len(res) != 1 || res[0].Assignment.UserId != reviewer || res[0].Reassignment != nil || res[0].Err != nil {
+		t.Fatalf("escalations: %+v", res)
+	}
+	if at := f.breachedAt(t, "pr-1", reviewer); at == nil || !at.Equal(f.now) {
+		t.Fatalf("sla_breached_at = %v, want %s", at, f.now)
+	}
+
+	history, err := f.store.Events.ListByPR(context.Background(), "pr-1")
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	if last := history[len(history)-1]; last.Type != modelra.EventSLABreached || last.UserId != reviewer {
+		t.Fatalf("last event: %+v", last)
+	}
+
+	// одно назначение эскалируется один раз
+	f.now = f.now.Add(24 * time.Hour)
+	if res := f.escalate(t); len(res) != 0 {
+		t.Fatalf("escalated twice: %+v", res)
+	}
+}
+
+func TestEscalateSkipsWeekend(t *testing.T) {
+	f := newFixture(t, slaTeam(4, false), "author", "r1")
+
+	// пятница 22:00: до конца пятницы 2 часа, остальные 2 — в понедельник
+	f.now = time.Date(2025, 3, 7, 22, 0, 0, 0, time.UTC)
+	f.createPR(t, "pr-1")
+
+	for _, now := range []time.Time{
+		time.Date(2025, 3, 8, 12, 0, 0, 0, time.UTC),  // суббота
+		time.Date(2025, 3, 9, 23, 59, 0, 0, time.UTC), // воскресенье
+		time.Date(2025, 3, 10, 1, 59, 0, 0, time.UTC), // понедельник, 3:59 по будням
+	} {
+		f.now = now
+		if res := f.escalate(t); len(res) != 0 {
+			t.Fatalf("escalated at %s: %+v", now, res)
+		}
+	}
+
+	f.now = time.Date(2025, 3, 10, 2, 0, 0, 0, time.UTC)
+	if res := f.escalate(t); len(res) != 1 {
+		t.Fatalf("escalations at %s: %+v", f.now, res)
+	}
+}
+
+func TestEscalateAutoReassigns(t *testing.T) {
+	f := newFixture(t, slaTeam(4, true), "author", "r1", "r2")
+	reviewer := f.createPR(t, "pr-1")
+
+	f.now = monday.Add(5 * time.Hour)
+	res := f.escalate(t)
+	if len(res) != 1 || res[0].Reassignment == nil || !res[0].Reassignment.Replaced() {
+		t.Fatalf("escalations: %+v", res)
+	}
+	next := res[0].Reassignment.NewUserId
+	if next == reviewer || next == "author" {
+		t.Fatalf("replaced %s by %s", reviewer, next)
+	}
+
+	history, err := f.store.Events.ListByPR(context.Background(), "pr-1")
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	last := history[len(history)-1]
+	if last.Type != modelra.EventReplaced || last.PreviousUserId != reviewer || last.Reason != modelra.ReasonSLABreached {
+		t.Fatalf("last event: %+v", last)
+	}
+
+	// новый ревьювер получает SLA заново с момента назначения
+	f.now = f.now.Add(4*time.Hour - time.Second)
+	if res := f.escalate(t); len(res) != 0 {
+		t.Fatalf("new reviewer escalated early: %+v", res)
+	}
+}
+
+func TestEscalateWithoutCandidateKeepsBreach(t *testing.T) {
+	f := newFixture(t, slaTeam(4, true), "author", "r1")
+	reviewer := f.createPR(t, "pr-1")
+
+	f.now = monday.Add(4 * time.Hour)
+	res := f.escalate(t)
+	if len(res) != 1 || res[0].Reassignment == nil || !errors.Is(res[0].Reassignment.Reason, modelra.ErrNoReviewerCandidatesLeft) {
+		t.Fatalf("escalations: %+v", res)
+	}
+	if f.breachedAt(t, "pr-1", reviewer) == nil {
+		t.Fatal("breach not recorded on the current reviewer")
+	}
+}
+
+var errReassign = errors.New("reassign failed")
+
+// failingReassigner ломает переназначение на одном PR.
+type failingReassigner struct {
+	slaSvc.ReviewReassigner
+	prID string
+}
+
+func (r failingReassigner) ReplaceReviewer(ctx context.Context, prID, oldUserID, reason string) (*modelra.ReviewerAssignment, error) {
+	if prID == r.prID {
+		return nil, errReassign
+	}
+	return r.ReviewReassigner.ReplaceReviewer(ctx, prID, oldUserID, reason)
+}
+
+func TestEscalateContinuesAfterFailure(t *testing.T) {
+	f := newFixture(t, slaTeam(4, true), "author", "r1", "r2", "r3")
+	broken := f.createPR(t, "pr-1")
+	f.now = f.now.Add(time.Minute)
+	f.createPR(t, "pr-2")
+	f.withReassigner(failingReassigner{ReviewReassigner: f.prs, prID: "pr-1"})
+
+	f.now = monday.Add(5 * time.Hour)
+	res := f.escalate(t)
+	if len(res) != 2 {
+		t.Fatalf("escalations: %+v", res)
+	}
+	if res[0].Assignment.PrId != "pr-1" || !errors.Is(res[0].Err, errReassign) {
+		t.Fatalf("failed escalation: %+v", res[0])
+	}
+	if res[1].Assignment.PrId != "pr-2" || res[1].Err != nil || !res[1].Reassignment.Replaced() {
+		t.Fatalf("next escalation: %+v", res[1])
+	}
+
+	// сбой откатил только своё нарушение: оно повторится в следующем проходе
+	if f.breachedAt(t, "pr-1", broken) != nil {
+		t.Fatal("failed escalation left the breach mark")
+	}
+	f.withReassigner(f.prs)
+	if res := f.escalate(t); len(res) != 1 || res[0].Assignment.PrId != "pr-1" || res[0].Err != nil {
+		t.Fatalf("retry: %+v", res)
+	}
+}
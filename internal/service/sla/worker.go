@@ -0,0 +1,63 @@
+package sla
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Worker периодически запускает Escalate до отмены контекста.
+type Worker struct {
+	svc      *Service
+	interval time.Duration
+	log      *slog.Logger
+}
+
+func NewWorker(svc *Service, interval time.Duration, log *slog.Logger) *Worker {
+	return &Worker{svc: svc, interval: interval, log: log}
+}
+
+// Run блокируется до отмены ctx; проход, начавшийся до отмены, доводится до конца
+// либо прерывается вместе с запросами к БД.
+func (w *Worker) Run(ctx context.Context) {
+	w.log.Info("sla worker started", slog.Duration("interval", w.interval))
+	defer w.log.Info("sla worker stopped")
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.tick(ctx)
+		}
+	}
+}
+
+func (w *Worker) tick(ctx context.Context) {
+	escalations, err := w.svc.Escalate(ctx)
+	if err != nil && ctx.Err() == nil {
+		w.log.Error("sla escalation failed", slog.String("error", err.Error()))
+	}
+	for _, e := range escalations {
+		attrs := []any{
+			slog.String("pull_request_id", e.Assignment.PrId),
+			slog.String("user_id", e.Assignment.UserId),
+			slog.String("team_name", e.Assignment.TeamName),
+		}
+		if e.Err != nil {
+			w.log.Error("sla escalation of assignment failed", append(attrs, slog.String("error", e.Err.Error()))...)
+			continue
+		}
+		if r := e.Reassignment; r != nil {
+			if r.Replaced() {
+				attrs = append(attrs, slog.String("replaced_by", r.NewUserId))
+			} else {
+				attrs = append(attrs, slog.String("not_reassigned", r.Reason.Error()))
+			}
+		}
+		w.log.Warn("review sla breached", attrs...)
+	}
+}
@@ -2,35 +2,72 @@ package team
 
 import (
 	"context"
+	"time"
+
+	modelra "github.com/zxchelik/avito-test-task/internal/model/reviewer_assignment"
 	modelteam "github.com/zxchelik/avito-test-task/internal/model/team"
 	modeluser "github.com/zxchelik/avito-test-task/internal/model/user"
+	modelwh "github.com/zxchelik/avito-test-task/internal/model/webhook"
 	"github.com/zxchelik/avito-test-task/internal/service"
 )
 
 type Service struct {
-	teams service.TeamRepository
-	users service.UserRepository
-	tx    service.TxManager
+	teams   service.TeamRepository
+	users   service.UserRepository
+	reviews service.ReviewerAssignmentRepository
+	events  service.AssignmentEventRepository
+	outbox  service.WebhookOutbox
+	tx      service.TxManager
+	clock   service.Clock
 }
 
-func NewService(teams service.TeamRepository, users service.UserRepository, tx service.TxManager) *Service {
+func NewService(
+	teams service.TeamRepository,
+	users service.UserRepository,
+	reviews service.ReviewerAssignmentRepository,
+	events service.AssignmentEventRepository,
+	outbox service.WebhookOutbox,
+	tx service.TxManager,
+) *Service {
 	return &Service{
-		teams: teams,
-		users: users,
-		tx:    tx,
+		teams:   teams,
+		users:   users,
+		reviews: reviews,
+		events:  events,
+		outbox:  outbox,
+		tx:      tx,
+		clock:   service.DefaultClock,
 	}
 }
 
+// WithClock позволяет подменять время в тестах.
+func (s *Service) WithClock(clock service.Clock) *Service {
+	s.clock = clock
+	return s
+}
+
 func (s *Service) Add(
 	ctx context.Context,
 	team *modelteam.Team,
 	members []*modeluser.User,
 ) (*modelteam.Team, []*modeluser.User, error) {
+	if team.ReviewerStrategy == "" {
+		team.ReviewerStrategy = modelteam.DefaultReviewerStrategy
+	}
+	if err := team.Validate(); err != nil {
+		return nil, nil, err
+	}
+	for _, m := range members {
+		if m.MaxOpenReviews < 0 {
+			return nil, nil, modeluser.ErrInvalidMaxOpenReviews
+		}
+	}
+
 	var createdTeam *modelteam.Team
 	var createdMembers []*modeluser.User
 
 	err := s.tx.WithinTransaction(ctx, func(txCtx context.Context) error {
-		if err := s.teams.Create(txCtx, team.Name); err != nil {
+		if err := s.teams.Create(txCtx, team); err != nil {
 			return err
 		}
 
@@ -72,3 +109,127 @@ func (s *Service) Get(
 
 	return team, users, nil
 }
+
+// UpdateSettings частично обновляет настройки команды.
+// Ошибки:
+//   - ErrNotFound                          — если команды нет
+//   - team.ErrUnknownReviewerStrategy      — неизвестная стратегия
+//   - team.ErrInvalidReviewerBounds        — некорректные min/max ревьюверов
+func (s *Service) UpdateSettings(
+	ctx context.Context,
+	teamName string,
+	update modelteam.SettingsUpdate,
+) (*modelteam.Team, error) {
+	var updated *modelteam.Team
+
+	err := s.tx.WithinTransaction(ctx, func(txCtx context.Context) error {
+		team, err := s.teams.GetByName(txCtx, teamName)
+		if err != nil {
+			return err
+		}
+
+		update.Apply(team)
+		if err := team.Validate(); err != nil {
+			return err
+		}
+
+		updated, err = s.teams.UpdateSettings(txCtx, team)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// Deactivate деактивирует участников команды (всех или только userIDs) и одной транзакцией
+// переназначает их ревью на открытых PR на активных участников команд авторов.
+// Переназначение выполняется пакетно, а не по одному PR, поэтому стратегия команды не применяется:
+// кандидаты берутся по возрастанию нагрузки по кругу, с учётом лимитов и отсутствий.
+// Ревью, для которых замена не нашлась, остаются за прежним ревьювером и попадают в итог с причиной.
+// Вебхуки reviewer.replaced кладутся в outbox в той же транзакции.
+// Ошибки:
+//   - ErrNotFound            — если команды нет
+//   - user.ErrNotTeamMember  — кто-то из userIDs не состоит в команде
+func (s *Service) Deactivate(
+	ctx context.Context,
+	teamName string,
+	userIDs []string,
+) ([]*modeluser.User, []*modelra.Reassignment, error) {
+	var (
+		deactivated []*modeluser.User
+		results     []*modelra.Reassignment
+	)
+
+	err := s.tx.WithinTransaction(ctx, func(txCtx context.Context) error {
+		if _, err := s.teams.GetByName(txCtx, teamName); err != nil {
+			return err
+		}
+
+		var err error
+		deactivated, err = s.users.DeactivateMembers(txCtx, teamName, userIDs)
+		if err != nil {
+			return err
+		}
+		if len(userIDs) > 0 && len(deactivated) != countUnique(userIDs) {
+			return modeluser.ErrNotTeamMember
+		}
+		if len(deactivated) == 0 {
+			return nil
+		}
+
+		ids := make([]string, 0, len(deactivated))
+		for _, u := range deactivated {
+			ids = append(ids, u.ID)
+		}
+
+		now := s.clock()
+		results, err = s.reviews.ReassignOpenFrom(txCtx, ids, now)
+		if err != nil {
+			return err
+		}
+		events := replacedEvents(results, service.ActorFrom(txCtx), now)
+		if err := s.events.Append(txCtx, events...); err != nil {
+			return err
+		}
+		msgs, err := modelwh.MessagesFor(events)
+		if err != nil {
+			return err
+		}
+		return s.outbox.Enqueue(txCtx, msgs...)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return deactivated, results, nil
+}
+
+// replacedEvents превращает выполненные замены в события истории PR.
+func replacedEvents(results []*modelra.Reassignment, actor string, at time.Time) []*modelra.Event {
+	events := make([]*modelra.Event, 0, len(results))
+	for _, r := range results {
+		if !r.Replaced() {
+			continue
+		}
+		events = append(events, &modelra.Event{
+			PrId:           r.PrId,
+			Type:           modelra.EventReplaced,
+			UserId:         r.NewUserId,
+			PreviousUserId: r.OldUserId,
+			ActorId:        actor,
+			Reason:         modelra.ReasonTeamOffboard,
+			CreatedAt:      at,
+		})
+	}
+	return events
+}
+
+func countUnique(ids []string) int {
+	seen := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		seen[id] = struct{}{}
+	}
+	return len(seen)
+}
@@ -0,0 +1,249 @@
+package team_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zxchelik/avito-test-task/internal/model"
+	modelabsence "github.com/zxchelik/avito-test-task/internal/model/absence"
+	modelauth "github.com/zxchelik/avito-test-task/internal/model/auth"
+	modelpr "github.com/zxchelik/avito-test-task/internal/model/pull_request"
+	modelra "github.com/zxchelik/avito-test-task/internal/model/reviewer_assignment"
+	modelteam "github.com/zxchelik/avito-test-task/internal/model/team"
+	modeluser "github.com/zxchelik/avito-test-task/internal/model/user"
+	modelwh "github.com/zxchelik/avito-test-task/internal/model/webhook"
+	"github.com/zxchelik/avito-test-task/internal/service"
+	prSvc "github.com/zxchelik/avito-test-task/internal/service/pull_request"
+	"github.com/zxchelik/avito-test-task/internal/service/servicetest"
+	teamSvc "github.com/zxchelik/avito-test-task/internal/service/team"
+)
+
+// newDeactivateFixture — PR автора с одним ревьювером; в команде есть кем его заменить.
+func newDeactivateFixture(t *testing.T) (*servicetest.Store, *teamSvc.Service, string) {
+	t.Helper()
+
+	store := servicetest.NewStore()
+	store.AddTeam(t, servicetest.Team("backend", 1), "author", "r1", "r2")
+	store.AddTeam(t, servicetest.Team("frontend", 1), "f1")
+	store.Subscribe(t, "https://hooks.example.com/review", "secret")
+
+	_, reviewers, err := store.PRService().Create(servicetest.System(), &modelpr.PullRequest{
+		ID: "pr-1", Title: "feature", AuthorID: "author", Status: modelpr.PROpen,
+	}, prSvc.CreateOptions{})
+	if err != nil {
+		t.Fatalf("create PR: %v", err)
+	}
+
+	svc := teamSvc.NewService(store.Teams, store.Users, store.Reviews, store.Events, store.Webhooks, store.Tx)
+	return store, svc, reviewers[0].UserId
+}
+
+func TestDeactivatePublishesReplacements(t *testing.T) {
+	store, svc, reviewer := newDeactivateFixture(t)
+
+	_, results, err := svc.Deactivate(context.Background(), "backend", []string{reviewer})
+	if err != nil {
+		t.Fatalf("deactivate: %v", err)
+	}
+	if len(results) != 1 || !results[0].Replaced() {
+		t.Fatalf("results: %+v", results)
+	}
+
+	want := []modelwh.EventType{modelwh.EventPRCreated, modelwh.EventReviewerAssigned, modelwh.EventReviewerReplaced}
+	if got := store.OutboxTypes(); !slices.Equal(got, want) {
+		t.Fatalf("outbox = %v, want %v", got, want)
+	}
+	replaced := store.Outbox()[2]
+	for _, part := range []string{`"reason":"` + modelra.ReasonTeamOffboard + `"`, `"previous_user_id":"` + reviewer + `"`} {
+		if !strings.Contains(string(replaced.Payload), part) {
+			t.Fatalf("payload %s has no %s", replaced.Payload, part)
+		}
+	}
+}
+
+func TestDeactivateRollbackDropsOutbox(t *testing.T) {
+	store, svc, reviewer := newDeactivateFixture(t)
+
+	// f1 не из backend: вся деактивация откатывается вместе с вебхуками
+	_, _, err := svc.Deactivate(context.Background(), "backend", []string{reviewer, "f1"})
+	if !errors.Is(err, modeluser.ErrNotTeamMember) {
+		t.Fatalf("deactivate: got %v, want ErrNotTeamMember", err)
+	}
+
+	want := []modelwh.EventType{modelwh.EventPRCreated, modelwh.EventReviewerAssigned}
+	if got := store.OutboxTypes(); !slices.Equal(got, want) {
+		t.Fatalf("outbox = %v, want %v", got, want)
+	}
+	u, err := store.Users.GetByID(context.Background(), reviewer)
+	if err != nil {
+		t.Fatalf("get user: %v", err)
+	}
+	if !u.IsActive {
+		t.Fatal("reviewer deactivated despite rollback")
+	}
+}
+
+func TestUpdateSettingsReviewerBounds(t *testing.T) {
+	store := servicetest.NewStore()
+	store.AddTeam(t, servicetest.Team("backend", 2), "author")
+	svc := teamSvc.NewService(store.Teams, store.Users, store.Reviews, store.Events, store.Webhooks, store.Tx)
+	ctx := context.Background()
+	count := func(n int) *int { return &n }
+
+	// частичное обновление: min остаётся прежним
+	updated, err := svc.UpdateSettings(ctx, "backend", modelteam.SettingsUpdate{MaxReviewers: count(3)})
+	if err != nil {
+		t.Fatalf("update max: %v", err)
+	}
+	if updated.MinReviewers != 1 || updated.MaxReviewers != 3 {
+		t.Fatalf("updated = %+v, want 1..3", updated)
+	}
+
+	for name, update := range map[string]modelteam.SettingsUpdate{
+		"min above max": {MinReviewers: count(4)},
+		"max below min": {MinReviewers: count(2), MaxReviewers: count(1)},
+		"negative min":  {MinReviewers: count(-1)},
+	} {
+		if _, err := svc.UpdateSettings(ctx, "backend", update); !errors.Is(err, modelteam.ErrInvalidReviewerBounds) {
+			t.Fatalf("%s: got %v, want ErrInvalidReviewerBounds", name, err)
+		}
+	}
+	team, _, err := svc.Get(ctx, "backend")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if team.MinReviewers != 1 || team.MaxReviewers != 3 {
+		t.Fatalf("rejected update changed the team: %+v", team)
+	}
+
+	if _, err := svc.UpdateSettings(ctx, "missing", modelteam.SettingsUpdate{MaxReviewers: count(3)}); !errors.Is(err, model.ErrNotFound) {
+		t.Fatalf("missing team: got %v, want ErrNotFound", err)
+	}
+}
+
+var now = time.Date(2025, 3, 3, 10, 0, 0, 0, time.UTC)
+
+// newOffboardFixture — команда backend уходит целиком: у её автора PR pr-b с ревьюверами b1 и b2,
+// у автора frontend — PR pr-f с ревьювером b1 из резервной команды.
+func newOffboardFixture(t *testing.T) (*servicetest.Store, *teamSvc.Service) {
+	t.Helper()
+
+	store := servicetest.NewStore()
+	store.AddTeam(t, servicetest.Team("backend", 2), "b-author", "b1", "b2")
+	store.AddTeam(t, servicetest.Team("frontend", 1), "f-author", "f1", "f2")
+	ctx := context.Background()
+	for pr, reviewers := range map[*modelpr.PullRequest][]string{
+		{ID: "pr-b", Title: "feature", AuthorID: "b-author", Status: modelpr.PROpen}: {"b1", "b2"},
+		{ID: "pr-f", Title: "feature", AuthorID: "f-author", Status: modelpr.PROpen}: {"b1", "f1"},
+	} {
+		if _, err := store.PRs.Create(ctx, pr); err != nil {
+			t.Fatalf("create %s: %v", pr.ID, err)
+		}
+		for _, id := range reviewers {
+			if err := store.Reviews.Add(ctx, &modelra.ReviewerAssignment{PrId: pr.ID, UserId: id}); err != nil {
+				t.Fatalf("assign %s to %s: %v", id, pr.ID, err)
+			}
+		}
+	}
+	svc := teamSvc.NewService(store.Teams, store.Users, store.Reviews, store.Events, store.Webhooks, store.Tx)
+	return store, svc.WithClock(func() time.Time { return now })
+}
+
+func TestDeactivateWholeTeamSummary(t *testing.T) {
+	store, svc := newOffboardFixture(t)
+
+	ctx := service.WithActor(servicetest.As("admin", modelauth.RoleAdmin), "admin")
+	deactivated, results, err := svc.Deactivate(ctx, "backend", nil)
+	if err != nil {
+		t.Fatalf("deactivate: %v", err)
+	}
+	if len(deactivated) != 3 {
+		t.Fatalf("deactivated = %+v, want the whole team", deactivated)
+	}
+	for _, u := range deactivated {
+		if u.IsActive {
+			t.Fatalf("%s is still active", u.ID)
+		}
+	}
+
+	// замены берутся из команды автора PR: у backend никого не осталось, у frontend свободен f2
+	want := []struct {
+		pr, old, next string
+		reason        error
+	}{
+		{"pr-b", "b1", "", modelra.ErrNoReviewerCandidatesLeft},
+		{"pr-b", "b2", "", modelra.ErrNoReviewerCandidatesLeft},
+		{"pr-f", "b1", "f2", nil},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("results = %+v", results)
+	}
+	for i, w := range want {
+		r := results[i]
+		if r.PrId != w.pr || r.OldUserId != w.old || r.NewUserId != w.next || !errors.Is(r.Reason, w.reason) ||
+			(w.reason == nil && r.Reason != nil) {
+			t.Fatalf("result %d = %+v, want %+v", i, r, w)
+		}
+	}
+
+	// в историю попадает только выполненная замена, с исполнителем и временем операции
+	for pr, wantEvents := range map[string]int{"pr-b": 0, "pr-f": 1} {
+		events, err := store.Events.ListByPR(context.Background(), pr)
+		if err != nil {
+			t.Fatalf("history of %s: %v", pr, err)
+		}
+		if len(events) != wantEvents {
+			t.Fatalf("history of %s = %+v, want %d events", pr, events, wantEvents)
+		}
+		for _, e := range events {
+			if e.Type != modelra.EventReplaced || e.UserId != "f2" || e.PreviousUserId != "b1" ||
+				e.ActorId != "admin" || !e.CreatedAt.Equal(now) {
+				t.Fatalf("event = %+v", e)
+			}
+		}
+	}
+}
+
+func TestDeactivateSkipsAbsentCandidates(t *testing.T) {
+	store, svc := newOffboardFixture(t)
+	if _, err := store.Absences.Create(context.Background(), &modelabsence.Absence{
+		UserID: "f2", StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour), Reason: "vacation",
+	}); err != nil {
+		t.Fatalf("create absence: %v", err)
+	}
+
+	_, results, err := svc.Deactivate(servicetest.System(), "backend", []string{"b1"})
+	if err != nil {
+		t.Fatalf("deactivate: %v", err)
+	}
+	// на pr-b b2 уже назначен; на pr-f назначен f1, а f2 в отпуске
+	for _, r := range results {
+		if r.Replaced() {
+			t.Fatalf("result = %+v, want no replacement", r)
+		}
+	}
+	if len(results) != 2 {
+		t.Fatalf("results = %+v", results)
+	}
+}
+
+func TestDeactivateErrors(t *testing.T) {
+	_, svc := newOffboardFixture(t)
+
+	if _, _, err := svc.Deactivate(servicetest.System(), "missing", nil); !errors.Is(err, model.ErrNotFound) {
+		t.Fatalf("missing team: got %v, want ErrNotFound", err)
+	}
+	if _, _, err := svc.Deactivate(servicetest.System(), "backend", []string{"b1", "ghost"}); !errors.Is(err, modeluser.ErrNotTeamMember) {
+		t.Fatalf("unknown member: got %v, want ErrNotTeamMember", err)
+	}
+
+	// повтор id в запросе — не ошибка
+	deactivated, _, err := svc.Deactivate(servicetest.System(), "backend", []string{"b1", "b1"})
+	if err != nil || len(deactivated) != 1 {
+		t.Fatalf("duplicate ids = %+v, %v", deactivated, err)
+	}
+}
@@ -2,6 +2,31 @@ package service
 
 import "context"
 
+// IsolationLevel — уровень изоляции транзакции.
+type IsolationLevel string
+
+const (
+	IsolationDefault        IsolationLevel = "" // уровень по умолчанию хранилища
+	IsolationReadCommitted  IsolationLevel = "read committed"
+	IsolationRepeatableRead IsolationLevel = "repeatable read"
+	IsolationSerializable   IsolationLevel = "serializable"
+)
+
+// TxOptions — параметры транзакции.
+// Параметры вложенного вызова (точки сохранения) не отклоняются, а игнорируются:
+// действуют параметры внешнего вызова, и повторяет транзакцию только он.
+type TxOptions struct {
+	Isolation IsolationLevel
+	ReadOnly  bool
+	// MaxRetries — сколько раз повторить транзакцию, прерванную конфликтом сериализации
+	// или дедлоком; 0 — значение менеджера по умолчанию, отрицательное — без повторов.
+	MaxRetries int
+}
+
+// TxManager выполняет fn в транзакции, переданной через контекст.
+// Вложенный вызов открывает точку сохранения во внешней транзакции:
+// ошибка fn откатывает только изменения вложенного вызова, а фиксирует всё внешний вызов.
 type TxManager interface {
 	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+	WithinTransactionOptions(ctx context.Context, opts TxOptions, fn func(ctx context.Context) error) error
 }
@@ -2,65 +2,196 @@ package user
 
 import (
 	"context"
+	"errors"
 	modelpr "github.com/zxchelik/avito-test-task/internal/model/pull_request"
+	modelra "github.com/zxchelik/avito-test-task/internal/model/reviewer_assignment"
 	modeluser "github.com/zxchelik/avito-test-task/internal/model/user"
 	"github.com/zxchelik/avito-test-task/internal/service"
 )
 
+// ReviewReassigner заменяет ревьювера на PR по тем же правилам, что и /pullRequest/reassign.
+type ReviewReassigner interface {
+	ReplaceReviewer(ctx context.Context, prID, oldUserID, reason string) (*modelra.ReviewerAssignment, error)
+}
+
 type Service struct {
-	users   service.UserRepository
-	prs     service.PRRepository
-	reviews service.ReviewerAssignmentRepository
+	users      service.UserRepository
+	teams      service.TeamRepository
+	prs        service.PRRepository
+	reviews    service.ReviewerAssignmentRepository
+	reassigner ReviewReassigner
+	tx         service.TxManager
 }
 
 func NewService(
 	users service.UserRepository,
+	teams service.TeamRepository,
 	prs service.PRRepository,
 	reviews service.ReviewerAssignmentRepository,
+	reassigner ReviewReassigner,
+	tx service.TxManager,
 ) *Service {
 	return &Service{
-		users:   users,
-		prs:     prs,
-		reviews: reviews,
+		users:      users,
+		teams:      teams,
+		prs:        prs,
+		reviews:    reviews,
+		reassigner: reassigner,
+		tx:         tx,
 	}
 }
 
 // SetIsActive устанавливает флаг активности пользователя.
+// При деактивации в той же транзакции заменяет пользователя на всех его OPEN PR
+// и возвращает итог по каждому PR: кто стал заменой или почему замены не нашлось
+// (в этом случае пользователь остаётся назначенным).
 // Ошибки:
 //   - ErrNotFound — если пользователя нет
 func (s *Service) SetIsActive(
 	ctx context.Context,
 	userID string,
 	isActive bool,
+) (*modeluser.User, []*modelra.Reassignment, error) {
+	var (
+		user    *modeluser.User
+		results []*modelra.Reassignment
+	)
+
+	err := s.tx.WithinTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		user, err = s.users.SetIsActive(txCtx, userID, isActive)
+		if err != nil {
+			return err
+		}
+		if isActive {
+			return nil
+		}
+
+		results, err = s.releaseOpenReviews(txCtx, userID)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, results, nil
+}
+
+// releaseOpenReviews пытается заменить userID на каждом его OPEN PR.
+// Отсутствие подходящей замены не считается ошибкой и попадает в Reason.
+func (s *Service) releaseOpenReviews(ctx context.Context, userID string) ([]*modelra.Reassignment, error) {
+	open, err := s.prs.ListByReviewer(ctx, modelpr.ReviewFilter{
+		ReviewerID: userID,
+		Statuses:   []modelpr.PRStatus{modelpr.PROpen},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*modelra.Reassignment, 0, len(open))
+	for _, rv := range open {
+		id := rv.PullRequest.ID
+		res := &modelra.Reassignment{PrId: id, OldUserId: userID}
+		next, err := s.reassigner.ReplaceReviewer(ctx, id, userID, modelra.ReasonDeactivated)
+		switch {
+		case err == nil:
+			res.NewUserId = next.UserId
+			res.Fallback = next.Fallback
+		case errors.Is(err, modelra.ErrNoReviewerCandidatesLeft),
+			errors.Is(err, modelra.ErrReviewersAtCapacity),
+			errors.Is(err, modeluser.ErrUserInactive):
+			res.Reason = err
+		default:
+			return nil, err
+		}
+		results = append(results, res)
+	}
+
+	return results, nil
+}
+
+// SetMaxOpenReviews задаёт личный лимит открытых ревью (0 — снять лимит).
+// Ошибки:
+//   - ErrNotFound              — если пользователя нет
+//   - ErrInvalidMaxOpenReviews — отрицательный лимит
+func (s *Service) SetMaxOpenReviews(
+	ctx context.Context,
+	userID string,
+	maxOpenReviews int,
 ) (*modeluser.User, error) {
-	return s.users.SetIsActive(ctx, userID, isActive)
+	if maxOpenReviews < 0 {
+		return nil, modeluser.ErrInvalidMaxOpenReviews
+	}
+	return s.users.SetMaxOpenReviews(ctx, userID, maxOpenReviews)
+}
+
+// ReviewPage — страница ревью пользователя; Next == nil — страница последняя.
+type ReviewPage struct {
+	Reviews []*modelpr.AssignedReview
+	Next    *modelpr.ReviewCursor
 }
 
-// ListUserReviews возвращает все PR, где userID назначен ревьювером.
+// ListUserReviews возвращает PR, где пользователь назначен ревьювером (новые назначения первыми),
+// и его текущую нагрузку относительно лимита. PR выбираются одним запросом вместе с
+// временем назначения и вердиктом пользователя.
 // Ошибки:
-//   - ErrNotFound — если пользователь не найден
+//   - ErrNotFound                        — если пользователь не найден
+//   - pull_request.ErrInvalidListFilter  — неизвестный статус или лимит вне 0..MaxListLimit
 func (s *Service) ListUserReviews(
 	ctx context.Context,
-	userID string,
-) ([]*modelpr.PullRequest, error) {
-	// (опционально) проверяем существование пользователя — удобно для 404.
-	if _, err := s.users.GetByID(ctx, userID); err != nil {
-		return nil, err
+	filter modelpr.ReviewFilter,
+) (*ReviewPage, modeluser.ReviewLoad, error) {
+	var load modeluser.ReviewLoad
+
+	if err := filter.Validate(); err != nil {
+		return nil, load, err
 	}
 
-	prIDs, err := s.reviews.ListPRIDsByReviewer(ctx, userID)
+	// Проверяем существование пользователя — удобно для 404.
+	u, err := s.users.GetByID(ctx, filter.ReviewerID)
 	if err != nil {
-		return nil, err
+		return nil, load, err
 	}
 
-	out := make([]*modelpr.PullRequest, 0, len(prIDs))
-	for _, id := range prIDs {
-		pr, err := s.prs.GetByID(ctx, id)
-		if err != nil {
-			return nil, err
-		}
-		out = append(out, pr)
+	limit := filter.Limit
+	if limit > 0 {
+		filter.Limit++ // лишняя строка показывает, есть ли следующая страница
+	}
+	reviews, err := s.prs.ListByReviewer(ctx, filter)
+	if err != nil {
+		return nil, load, err
+	}
+
+	page := &ReviewPage{}
+	if limit > 0 && len(reviews) > limit {
+		reviews = reviews[:limit]
+		last := reviews[limit-1]
+		page.Next = &modelpr.ReviewCursor{AssignedAt: last.AssignedAt, PRID: last.PullRequest.ID}
+	}
+	page.Reviews = reviews
+
+	load, err = s.reviewLoad(ctx, u)
+	if err != nil {
+		return nil, load, err
+	}
+
+	return page, load, nil
+}
+
+// reviewLoad считает открытые ревью пользователя и его действующий лимит.
+func (s *Service) reviewLoad(ctx context.Context, u *modeluser.User) (modeluser.ReviewLoad, error) {
+	team, err := s.teams.GetByName(ctx, u.TeamName)
+	if err != nil {
+		return modeluser.ReviewLoad{}, err
+	}
+
+	open, err := s.reviews.CountOpenByReviewers(ctx, []string{u.ID})
+	if err != nil {
+		return modeluser.ReviewLoad{}, err
 	}
 
-	return out, nil
+	return modeluser.ReviewLoad{
+		Open:     open[u.ID],
+		Capacity: u.Capacity(team.DefaultMaxOpenReviews),
+	}, nil
 }
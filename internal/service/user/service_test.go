@@ -0,0 +1,299 @@
+package user_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zxchelik/avito-test-task/internal/model"
+	modelpr "github.com/zxchelik/avito-test-task/internal/model/pull_request"
+	modelra "github.com/zxchelik/avito-test-task/internal/model/reviewer_assignment"
+	modeluser "github.com/zxchelik/avito-test-task/internal/model/user"
+	prSvc "github.com/zxchelik/avito-test-task/internal/service/pull_request"
+	"github.com/zxchelik/avito-test-task/internal/service/servicetest"
+	userSvc "github.com/zxchelik/avito-test-task/internal/service/user"
+)
+
+func newService(store *servicetest.Store) *userSvc.Service {
+	return userSvc.NewService(store.Users, store.Teams, store.PRs, store.Reviews, store.PRService(), store.Tx)
+}
+
+// createPR создаёт OPEN PR автора author и возвращает id его ревьюверов.
+func createPR(t *testing.T, store *servicetest.Store, id string) []string {
+	t.Helper()
+
+	pr := &modelpr.PullRequest{ID: id, Title: "feature", AuthorID: "author", Status: modelpr.PROpen}
+	_, reviewers, err := store.PRService().Create(servicetest.System(), pr, prSvc.CreateOptions{})
+	if err != nil {
+		t.Fatalf("create %s: %v", id, err)
+	}
+	ids := make([]string, len(reviewers))
+	for i, r := range reviewers {
+		ids[i] = r.UserId
+	}
+	return ids
+}
+
+func TestListUserReviewsReportsLoad(t *testing.T) {
+	store := servicetest.NewStore()
+	team := servicetest.Team("backend", 1)
+	team.DefaultMaxOpenReviews = 3
+	store.AddTeam(t, team, "author", "r1")
+	svc := newService(store)
+	ctx := context.Background()
+
+	for _, id := range []string{"pr-1", "pr-2"} {
+		createPR(t, store, id)
+	}
+	if _, err := store.PRService().Merge(servicetest.System(), "pr-1"); err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+
+	// смерженный PR остаётся в списке, но не в нагрузке; лимит — лимит команды
+	page, load, err := svc.ListUserReviews(ctx, modelpr.ReviewFilter{ReviewerID: "r1"})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(page.Reviews) != 2 || load != (modeluser.ReviewLoad{Open: 1, Capacity: 3}) {
+		t.Fatalf("reviews = %d, load = %+v, want 2 reviews and 1 of 3 open", len(page.Reviews), load)
+	}
+
+	// личный лимит важнее лимита команды
+	if _, err := svc.SetMaxOpenReviews(ctx, "r1", 1); err != nil {
+		t.Fatalf("set limit: %v", err)
+	}
+	_, load, err = svc.ListUserReviews(ctx, modelpr.ReviewFilter{ReviewerID: "r1"})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if load != (modeluser.ReviewLoad{Open: 1, Capacity: 1}) || !load.AtCapacity() {
+		t.Fatalf("load = %+v, want 1 of 1 open, at capacity", load)
+	}
+}
+
+func TestListUserReviewsPages(t *testing.T) {
+	store := servicetest.NewStore()
+	store.AddTeam(t, servicetest.Team("backend", 1), "author", "r1")
+	svc := newService(store)
+	ctx := context.Background()
+
+	for _, id := range []string{"pr-1", "pr-2", "pr-3"} {
+		createPR(t, store, id)
+	}
+
+	var ids []string
+	filter := modelpr.ReviewFilter{ReviewerID: "r1", Limit: 2}
+	for {
+		page, _, err := svc.ListUserReviews(ctx, filter)
+		if err != nil {
+			t.Fatalf("list: %v", err)
+		}
+		for _, r := range page.Reviews {
+			ids = append(ids, r.PullRequest.ID)
+		}
+		if page.Next == nil {
+			break
+		}
+		// курсор указывает на последнее ревью страницы
+		last := page.Reviews[len(page.Reviews)-1]
+		if page.Next.PRID != last.PullRequest.ID || !page.Next.AssignedAt.Equal(last.AssignedAt) {
+			t.Fatalf("next = %+v, want the last review %s", page.Next, last.PullRequest.ID)
+		}
+		filter.After = page.Next
+	}
+	if !slices.Equal(ids, []string{"pr-3", "pr-2", "pr-1"}) {
+		t.Fatalf("pages = %v, want newest assignments first", ids)
+	}
+
+	// без лимита — всё одной страницей
+	page, _, err := svc.ListUserReviews(ctx, modelpr.ReviewFilter{ReviewerID: "r1"})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(page.Reviews) != 3 || page.Next != nil {
+		t.Fatalf("page = %d reviews, next %+v", len(page.Reviews), page.Next)
+	}
+}
+
+func TestListUserReviewsErrors(t *testing.T) {
+	store := servicetest.NewStore()
+	store.AddTeam(t, servicetest.Team("backend", 1), "author", "r1")
+	svc := newService(store)
+	ctx := context.Background()
+
+	for name, filter := range map[string]modelpr.ReviewFilter{
+		"negative limit": {ReviewerID: "r1", Limit: -1},
+		"limit too big":  {ReviewerID: "r1", Limit: modelpr.MaxListLimit + 1},
+		"unknown status": {ReviewerID: "r1", Statuses: []modelpr.PRStatus{"REVIEWED"}},
+	} {
+		if _, _, err := svc.ListUserReviews(ctx, filter); !errors.Is(err, modelpr.ErrInvalidListFilter) {
+			t.Fatalf("%s: got %v, want ErrInvalidListFilter", name, err)
+		}
+	}
+	if _, _, err := svc.ListUserReviews(ctx, modelpr.ReviewFilter{ReviewerID: "missing"}); !errors.Is(err, model.ErrNotFound) {
+		t.Fatalf("missing user: got %v, want ErrNotFound", err)
+	}
+}
+
+func TestSetMaxOpenReviews(t *testing.T) {
+	store := servicetest.NewStore()
+	store.AddTeam(t, servicetest.Team("backend", 1), "author")
+	svc := newService(store)
+	ctx := context.Background()
+
+	if _, err := svc.SetMaxOpenReviews(ctx, "author", -1); !errors.Is(err, modeluser.ErrInvalidMaxOpenReviews) {
+		t.Fatalf("negative limit: got %v, want ErrInvalidMaxOpenReviews", err)
+	}
+	if _, err := svc.SetMaxOpenReviews(ctx, "missing", 1); !errors.Is(err, model.ErrNotFound) {
+		t.Fatalf("missing user: got %v, want ErrNotFound", err)
+	}
+	u, err := svc.SetMaxOpenReviews(ctx, "author", 4)
+	if err != nil || u.MaxOpenReviews != 4 {
+		t.Fatalf("set limit = %+v, %v", u, err)
+	}
+}
+
+// assign создаёт PR автора author в статусе OPEN с ревьюверами reviewers и переводит его в status.
+func assign(t *testing.T, store *servicetest.Store, prID string, status modelpr.PRStatus, reviewers ...string) {
+	t.Helper()
+
+	ctx := context.Background()
+	pr := &modelpr.PullRequest{ID: prID, Title: "feature", AuthorID: "author", Status: modelpr.PROpen}
+	if _, err := store.PRs.Create(ctx, pr); err != nil {
+		t.Fatalf("create %s: %v", prID, err)
+	}
+	for _, id := range reviewers {
+		if err := store.Reviews.Add(ctx, &modelra.ReviewerAssignment{PrId: prID, UserId: id}); err != nil {
+			t.Fatalf("assign %s to %s: %v", id, prID, err)
+		}
+	}
+	var err error
+	switch status {
+	case modelpr.PRMerged:
+		_, err = store.PRs.MarkMerged(ctx, prID)
+	case modelpr.PRClosed:
+		_, err = store.PRs.UpdateStatus(ctx, prID, modelpr.PROpen, modelpr.PRClosed, time.Now())
+	}
+	if err != nil {
+		t.Fatalf("move %s to %s: %v", prID, status, err)
+	}
+}
+
+func reviewersOf(t *testing.T, store *servicetest.Store, prID string) []string {
+	t.Helper()
+
+	assignments, err := store.Reviews.ListByPR(context.Background(), prID)
+	if err != nil {
+		t.Fatalf("list reviewers of %s: %v", prID, err)
+	}
+	ids := make([]string, len(assignments))
+	for i, a := range assignments {
+		ids[i] = a.UserId
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+func TestDeactivateReassignsOpenReviews(t *testing.T) {
+	store := servicetest.NewStore()
+	store.AddTeam(t, servicetest.Team("backend", 2), "author", "r1", "r2", "r3")
+	svc := newService(store)
+
+	assign(t, store, "pr-1", modelpr.PROpen, "r1")
+	assign(t, store, "pr-2", modelpr.PROpen, "r1", "r2")
+	assign(t, store, "pr-merged", modelpr.PRMerged, "r1")
+	assign(t, store, "pr-closed", modelpr.PRClosed, "r1")
+
+	u, results, err := svc.SetIsActive(servicetest.System(), "r1", false)
+	if err != nil {
+		t.Fatalf("deactivate: %v", err)
+	}
+	if u.IsActive {
+		t.Fatal("user is still active")
+	}
+
+	// только OPEN PR; на pr-2 r2 уже назначен, поэтому замена — r3
+	slices.SortFunc(results, func(a, b *modelra.Reassignment) int { return strings.Compare(a.PrId, b.PrId) })
+	if len(results) != 2 || results[0].PrId != "pr-1" || !results[0].Replaced() ||
+		results[1].PrId != "pr-2" || results[1].NewUserId != "r3" {
+		t.Fatalf("results = %+v", results)
+	}
+	for _, r := range results {
+		if r.OldUserId != "r1" || r.Reason != nil {
+			t.Fatalf("result = %+v", r)
+		}
+	}
+	if got := reviewersOf(t, store, "pr-2"); !slices.Equal(got, []string{"r2", "r3"}) {
+		t.Fatalf("pr-2 reviewers = %v, want [r2 r3]", got)
+	}
+	for _, pr := range []string{"pr-merged", "pr-closed"} {
+		if got := reviewersOf(t, store, pr); !slices.Equal(got, []string{"r1"}) {
+			t.Fatalf("%s reviewers = %v, want [r1]", pr, got)
+		}
+	}
+
+	// замена записана в историю с причиной
+	events, err := store.Events.ListByPR(context.Background(), "pr-1")
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	if len(events) != 1 || events[0].Type != modelra.EventReplaced || events[0].PreviousUserId != "r1" ||
+		events[0].Reason != modelra.ReasonDeactivated {
+		t.Fatalf("history = %+v", events)
+	}
+}
+
+func TestDeactivateWithoutReplacement(t *testing.T) {
+	store := servicetest.NewStore()
+	store.AddTeam(t, servicetest.Team("backend", 1), "author", "r1", "r2")
+	svc := newService(store)
+	if _, err := store.Users.SetMaxOpenReviews(context.Background(), "r2", 1); err != nil {
+		t.Fatalf("set limit: %v", err)
+	}
+
+	assign(t, store, "pr-1", modelpr.PROpen, "r1")
+	assign(t, store, "pr-2", modelpr.PROpen, "r2")
+	assign(t, store, "pr-3", modelpr.PROpen, "r1", "r2")
+
+	_, results, err := svc.SetIsActive(servicetest.System(), "r1", false)
+	if err != nil {
+		t.Fatalf("deactivate: %v", err)
+	}
+
+	// pr-1: r2 подошёл бы, но на пределе; pr-3: r2 уже назначен, кандидатов нет
+	slices.SortFunc(results, func(a, b *modelra.Reassignment) int { return strings.Compare(a.PrId, b.PrId) })
+	if len(results) != 2 ||
+		results[0].PrId != "pr-1" || results[0].Replaced() || !errors.Is(results[0].Reason, modelra.ErrReviewersAtCapacity) ||
+		results[1].PrId != "pr-3" || results[1].Replaced() || !errors.Is(results[1].Reason, modelra.ErrNoReviewerCandidatesLeft) {
+		t.Fatalf("results = %+v", results)
+	}
+	// без замены ревьювер остаётся назначенным, но деактивация проходит
+	if got := reviewersOf(t, store, "pr-1"); !slices.Equal(got, []string{"r1"}) {
+		t.Fatalf("pr-1 reviewers = %v, want [r1]", got)
+	}
+	if u, _ := store.Users.GetByID(context.Background(), "r1"); u.IsActive {
+		t.Fatal("user is still active")
+	}
+}
+
+func TestActivateKeepsReviews(t *testing.T) {
+	store := servicetest.NewStore()
+	store.AddTeam(t, servicetest.Team("backend", 1), "author", "r1", "r2")
+	svc := newService(store)
+	assign(t, store, "pr-1", modelpr.PROpen, "r1")
+
+	u, results, err := svc.SetIsActive(servicetest.System(), "r1", true)
+	if err != nil || !u.IsActive || len(results) != 0 {
+		t.Fatalf("activate = %+v, %+v, %v", u, results, err)
+	}
+	if got := reviewersOf(t, store, "pr-1"); !slices.Equal(got, []string{"r1"}) {
+		t.Fatalf("reviewers = %v, want [r1]", got)
+	}
+
+	if _, _, err := svc.SetIsActive(servicetest.System(), "missing", false); !errors.Is(err, model.ErrNotFound) {
+		t.Fatalf("missing user: got %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,61 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	modelwh "github.com/zxchelik/avito-test-task/internal/model/webhook"
+)
+
+// Заголовки исходящего вебхука.
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery" // id сообщения, одинаковый во всех попытках
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// Sign возвращает подпись тела в формате "sha256=<hex HMAC-SHA256>".
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// HTTPSender доставляет вебхуки POST-запросом; успехом считается любой ответ 2xx.
+type HTTPSender struct {
+	client *http.Client
+}
+
+func NewHTTPSender(client *http.Client) *HTTPSender {
+	return &HTTPSender{client: client}
+}
+
+func (s *HTTPSender) Send(ctx context.Context, d *modelwh.Delivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, string(d.EventType))
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(d.ID, 10))
+	req.Header.Set(HeaderSignature, Sign(d.Secret, d.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	modelwh "github.com/zxchelik/avito-test-task/internal/model/webhook"
+	"github.com/zxchelik/avito-test-task/internal/service"
+)
+
+// DefaultDeadLetterLimit — сколько недоставленных сообщений отдаёт DeadLetters по умолчанию.
+const DefaultDeadLetterLimit = 100
+
+// Sender отправляет одно сообщение получателю.
+type Sender interface {
+	Send(ctx context.Context, d *modelwh.Delivery) error
+}
+
+type Service struct {
+	repo   service.WebhookRepository
+	sender Sender
+	retry  modelwh.RetryPolicy
+	lease  time.Duration
+	clock  service.Clock
+}
+
+func NewService(repo service.WebhookRepository, sender Sender) *Service {
+	return &Service{
+		repo:   repo,
+		sender: sender,
+		retry:  modelwh.DefaultRetryPolicy,
+		lease:  time.Minute,
+		clock:  service.DefaultClock,
+	}
+}
+
+// WithClock позволяет подменять время в тестах.
+func (s *Service) WithClock(clock service.Clock) *Service {
+	s.clock = clock
+	return s
+}
+
+// WithRetryPolicy задаёт число попыток и задержки между ними.
+func (s *Service) WithRetryPolicy(policy modelwh.RetryPolicy) *Service {
+	s.retry = policy
+	return s
+}
+
+// WithLease задаёт, на сколько взятое в работу сообщение скрыто от других воркеров.
+// Должен превышать таймаут отправки.
+func (s *Service) WithLease(lease time.Duration) *Service {
+	s.lease = lease
+	return s
+}
+
+// Subscribe создаёт подписку.
+// Ошибки:
+//   - webhook.ErrInvalidURL, ErrEmptySecret, ErrNoEventTypes, ErrUnknownEventType — некорректная подписка
+func (s *Service) Subscribe(ctx context.Context, sub *modelwh.Subscription) (*modelwh.Subscription, error) {
+	if err := sub.Validate(); err != nil {
+		return nil, err
+	}
+	return s.repo.CreateSubscription(ctx, sub)
+}
+
+// Subscriptions возвращает все подписки.
+func (s *Service) Subscriptions(ctx context.Context) ([]*modelwh.Subscription, error) {
+	return s.repo.ListSubscriptions(ctx)
+}
+
+// Unsubscribe удаляет подписку вместе с её недоставленными сообщениями.
+// Ошибки:
+//   - ErrNotFound — если подписки нет
+func (s *Service) Unsubscribe(ctx context.Context, id int64) error {
+	return s.repo.DeleteSubscription(ctx, id)
+}
+
+// DeadLetters возвращает сообщения, для которых исчерпаны попытки доставки.
+func (s *Service) DeadLetters(ctx context.Context, limit int) ([]*modelwh.Delivery, error) {
+	if limit <= 0 {
+		limit = DefaultDeadLetterLimit
+	}
+	return s.repo.ListDead(ctx, limit)
+}
+
+// Redeliver возвращает сообщение из dead letter в очередь с новым запасом попыток.
+// Ошибки:
+//   - ErrNotFound        — если сообщения нет
+//   - webhook.ErrNotDead — сообщение не в dead letter
+func (s *Service) Redeliver(ctx context.Context, id int64) error {
+	return s.repo.Redeliver(ctx, id, s.clock())
+}
+
+// DispatchResult — итог одного прохода доставки.
+type DispatchResult struct {
+	Delivered int
+	Retried   int
+	Dead      int
+}
+
+// Dispatch отправляет до limit созревших сообщений. Неудачная отправка
+// планируется повторно с экспоненциальной задержкой, а после исчерпания
+// попыток сообщение уходит в dead letter.
+func (s *Service) Dispatch(ctx context.Context, limit int) (DispatchResult, error) {
+	var res DispatchResult
+
+	now := s.clock()
+	due, err := s.repo.ClaimDue(ctx, now, now.Add(s.lease), limit)
+	if err != nil {
+		return res, err
+	}
+
+	for _, d := range due {
+		sendErr := s.sender.Send(ctx, d)
+		if ctx.Err() != nil {
+			// остановка: аренда истечёт, и сообщение возьмут заново
+			return res, ctx.Err()
+		}
+
+		at := s.clock()
+		if sendErr == nil {
+			if err := s.repo.MarkDelivered(ctx, d.ID, at); err != nil {
+				return res, err
+			}
+			res.Delivered++
+			continue
+		}
+
+		attempts := d.Attempts + 1
+		dead := s.retry.Exhausted(attempts)
+		next := at // у DEAD остаётся время последней попытки
+		if !dead {
+			next = at.Add(s.retry.Backoff(attempts))
+		}
+		if err := s.repo.MarkFailed(ctx, d.ID, sendErr.Error(), next, dead); err != nil {
+			return res, err
+		}
+		if dead {
+			res.Dead++
+		} else {
+			res.Retried++
+		}
+	}
+
+	return res, nil
+}
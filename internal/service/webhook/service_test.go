@@ -0,0 +1,194 @@
+package webhook_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	modelra "github.com/zxchelik/avito-test-task/internal/model/reviewer_assignment"
+	modelwh "github.com/zxchelik/avito-test-task/internal/model/webhook"
+	"github.com/zxchelik/avito-test-task/internal/service/servicetest"
+	srvwh "github.com/zxchelik/avito-test-task/internal/service/webhook"
+)
+
+const secret = "subscriber-secret"
+
+var t0 = time.Date(2025, 3, 3, 12, 0, 0, 0, time.UTC)
+
+// receiver — получатель вебхуков, отвечающий заданным статусом.
+type receiver struct {
+	mu       sync.Mutex
+	status   int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, req)
+	r.bodies = append(r.bodies, body)
+	w.WriteHeader(r.status)
+}
+
+func (r *receiver) respond(status int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status = status
+}
+
+func (r *receiver) hits() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.requests)
+}
+
+// fixture — outbox в памяти с одним сообщением reviewer.assigned для подписки на receiver.
+type fixture struct {
+	store *servicetest.Store
+	recv  *receiver
+	svc   *srvwh.Service
+	now   time.Time
+}
+
+func newFixture(t *testing.T, policy modelwh.RetryPolicy) *fixture {
+	t.Helper()
+
+	f := &fixture{store: servicetest.NewStore(), recv: &receiver{status: http.StatusOK}, now: t0}
+	srv := httptest.NewServer(f.recv)
+	t.Cleanup(srv.Close)
+
+	f.store.Subscribe(t, srv.URL, secret)
+	msg, err := modelwh.MessageFor(&modelra.Event{
+		PrId: "pr-1", Type: modelra.EventAssigned, UserId: "u2", CreatedAt: t0,
+	})
+	if err != nil {
+		t.Fatalf("build message: %v", err)
+	}
+	if err := f.store.Webhooks.Enqueue(context.Background(), msg); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+
+	f.svc = srvwh.NewService(f.store.Webhooks, srvwh.NewHTTPSender(srv.Client())).
+		WithClock(func() time.Time { return f.now }).
+		WithRetryPolicy(policy).
+		WithLease(10 * time.Second)
+	return f
+}
+
+func (f *fixture) dispatch(t *testing.T) srvwh.DispatchResult {
+	t.Helper()
+
+	res, err := f.svc.Dispatch(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("dispatch: %v", err)
+	}
+	return res
+}
+
+func (f *fixture) delivery(t *testing.T) *modelwh.Delivery {
+	t.Helper()
+
+	outbox := f.store.Outbox()
+	if len(outbox) != 1 {
+		t.Fatalf("outbox has %d messages, want 1", len(outbox))
+	}
+	return outbox[0]
+}
+
+func TestDispatchSignsRequest(t *testing.T) {
+	f := newFixture(t, modelwh.DefaultRetryPolicy)
+
+	if res := f.dispatch(t); res.Delivered != 1 {
+		t.Fatalf("dispatch: %+v", res)
+	}
+
+	req, body := f.recv.requests[0], f.recv.bodies[0]
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if got, want := req.Header.Get(srvwh.HeaderSignature), "sha256="+hex.EncodeToString(mac.Sum(nil)); got != want {
+		t.Fatalf("signature = %q, want %q", got, want)
+	}
+	if got := req.Header.Get(srvwh.HeaderEvent); got != string(modelwh.EventReviewerAssigned) {
+		t.Fatalf("event header = %q", got)
+	}
+	d := f.delivery(t)
+	if got := req.Header.Get(srvwh.HeaderDelivery); got != strconv.FormatInt(d.ID, 10) {
+		t.Fatalf("delivery header = %q, want %d", got, d.ID)
+	}
+	if d.Status != modelwh.DeliveryDelivered || d.DeliveredAt == nil {
+		t.Fatalf("delivery not marked delivered: %+v", d)
+	}
+
+	// доставленное сообщение больше не отправляется
+	if res := f.dispatch(t); res != (srvwh.DispatchResult{}) {
+		t.Fatalf("second dispatch: %+v", res)
+	}
+}
+
+func TestDispatchRetriesWithBackoffThenDeadLetters(t *testing.T) {
+	policy := modelwh.RetryPolicy{MaxAttempts: 4, BaseDelay: 30 * time.Second, MaxDelay: time.Minute}
+	f := newFixture(t, policy)
+	f.recv.respond(http.StatusInternalServerError)
+
+	// задержки после неудач: 30s, 1m, затем упираются в MaxDelay
+	for attempt, delay := range []time.Duration{30 * time.Second, time.Minute, time.Minute} {
+		if res := f.dispatch(t); res.Retried != 1 {
+			t.Fatalf("attempt %d: %+v", attempt+1, res)
+		}
+		d := f.delivery(t)
+		if d.Attempts != attempt+1 || !d.NextAttemptAt.Equal(f.now.Add(delay)) {
+			t.Fatalf("attempt %d: attempts=%d next=%s, want next=%s",
+				attempt+1, d.Attempts, d.NextAttemptAt, f.now.Add(delay))
+		}
+		if d.LastError != "unexpected status 500" {
+			t.Fatalf("last error = %q", d.LastError)
+		}
+
+		// до истечения задержки сообщение не берётся
+		f.now = f.now.Add(delay - time.Second)
+		if res := f.dispatch(t); res != (srvwh.DispatchResult{}) {
+			t.Fatalf("dispatch before backoff: %+v", res)
+		}
+		f.now = f.now.Add(time.Second)
+	}
+
+	if res := f.dispatch(t); res.Dead != 1 {
+		t.Fatalf("last attempt: %+v", res)
+	}
+	if got := f.recv.hits(); got != policy.MaxAttempts {
+		t.Fatalf("receiver got %d requests, want %d", got, policy.MaxAttempts)
+	}
+
+	dead, err := f.svc.DeadLetters(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("dead letters: %v", err)
+	}
+	if len(dead) != 1 || dead[0].Attempts != policy.MaxAttempts || dead[0].Status != modelwh.DeliveryDead {
+		t.Fatalf("dead letters: %+v", dead)
+	}
+
+	// DEAD не отправляется, пока его не вернут в очередь
+	f.now = f.now.Add(time.Hour)
+	if res := f.dispatch(t); res != (srvwh.DispatchResult{}) {
+		t.Fatalf("dispatch of dead letter: %+v", res)
+	}
+
+	f.recv.respond(http.StatusNoContent)
+	if err := f.svc.Redeliver(context.Background(), dead[0].ID); err != nil {
+		t.Fatalf("redeliver: %v", err)
+	}
+	if res := f.dispatch(t); res.Delivered != 1 {
+		t.Fatalf("dispatch after redeliver: %+v", res)
+	}
+}
@@ -0,0 +1,54 @@
+package webhook
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Worker периодически доставляет сообщения из outbox до отмены контекста.
+type Worker struct {
+	svc      *Service
+	interval time.Duration
+	batch    int
+	log      *slog.Logger
+}
+
+func NewWorker(svc *Service, interval time.Duration, batch int, log *slog.Logger) *Worker {
+	return &Worker{svc: svc, interval: interval, batch: batch, log: log}
+}
+
+// Run блокируется до отмены ctx. Полная пачка означает, что очередь не разобрана,
+// и следующая пачка берётся сразу, без ожидания тика.
+func (w *Worker) Run(ctx context.Context) {
+	w.log.Info("webhook worker started", slog.Duration("interval", w.interval))
+	defer w.log.Info("webhook worker stopped")
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for w.tick(ctx) {
+			}
+		}
+	}
+}
+
+// tick возвращает true, если стоит сразу взять следующую пачку.
+func (w *Worker) tick(ctx context.Context) bool {
+	res, err := w.svc.Dispatch(ctx, w.batch)
+	if err != nil {
+		if ctx.Err() == nil {
+			w.log.Error("webhook dispatch failed", slog.String("error", err.Error()))
+		}
+		return false
+	}
+	if res.Dead > 0 {
+		w.log.Warn("webhook deliveries moved to dead letter", slog.Int("count", res.Dead))
+	}
+	return res.Delivered+res.Retried+res.Dead == w.batch
+}
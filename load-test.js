@@ -1,6 +1,7 @@
 import http from 'k6/http';
 
 const BASE_URL = __ENV.BASE_URL || 'http://localhost:8080';
+const HEADERS = { 'Content-Type': 'application/json', 'Authorization': `Bearer ${__ENV.AUTH_TOKEN}` };
 
 export const options = {
     scenarios: {
@@ -32,8 +33,8 @@ export default function () {
     const req = endpoints[Math.floor(Math.random() * endpoints.length)];
 
     if (req.method === 'GET') {
-        http.get(req.url);
+        http.get(req.url, { headers: HEADERS });
     } else {
-        http.post(req.url, req.body, { headers: { 'Content-Type': 'application/json' } });
+        http.post(req.url, req.body, { headers: HEADERS });
     }
 }
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE teams
+    ADD COLUMN reviewer_strategy TEXT NOT NULL DEFAULT 'least_loaded';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE teams DROP COLUMN IF EXISTS reviewer_strategy;
+
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE teams
+    ADD COLUMN min_reviewers INT NOT NULL DEFAULT 1,
+    ADD COLUMN max_reviewers INT NOT NULL DEFAULT 2,
+    ADD CONSTRAINT teams_reviewer_bounds_check
+        CHECK (min_reviewers >= 0 AND max_reviewers >= min_reviewers);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE teams
+    DROP CONSTRAINT IF EXISTS teams_reviewer_bounds_check,
+    DROP COLUMN IF EXISTS max_reviewers,
+    DROP COLUMN IF EXISTS min_reviewers;
+
+-- +goose StatementEnd
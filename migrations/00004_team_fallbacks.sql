@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE team_fallbacks (
+                                team_name     TEXT NOT NULL REFERENCES teams(name) ON DELETE CASCADE,
+                                fallback_team TEXT NOT NULL REFERENCES teams(name) ON DELETE CASCADE,
+                                position      INT  NOT NULL,
+                                PRIMARY KEY (team_name, fallback_team),
+                                CHECK (team_name <> fallback_team)
+);
+
+ALTER TABLE pull_request_reviewers
+    ADD COLUMN is_fallback BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE pull_request_reviewers DROP COLUMN IF EXISTS is_fallback;
+DROP TABLE IF EXISTS team_fallbacks;
+
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE code_owner_rules (
+                                  id          BIGSERIAL PRIMARY KEY,
+                                  pattern     TEXT NOT NULL,
+                                  owner_users TEXT[] NOT NULL DEFAULT '{}',
+                                  owner_teams TEXT[] NOT NULL DEFAULT '{}',
+                                  position    INT NOT NULL,
+                                  created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_code_owner_rules_position ON code_owner_rules(position, id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_code_owner_rules_position;
+DROP TABLE IF EXISTS code_owner_rules;
+
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE user_absences (
+                               id           BIGSERIAL PRIMARY KEY,
+                               user_id      TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                               starts_at    TIMESTAMPTZ NOT NULL,
+                               ends_at      TIMESTAMPTZ NOT NULL,
+                               reason       TEXT NOT NULL,
+                               created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                               cancelled_at TIMESTAMPTZ NULL,
+                               CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX idx_absences_user_period ON user_absences(user_id, starts_at, ends_at)
+    WHERE cancelled_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_absences_user_period;
+DROP TABLE IF EXISTS user_absences;
+
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TYPE review_verdict AS ENUM ('APPROVED', 'CHANGES_REQUESTED', 'COMMENTED');
+
+ALTER TABLE pull_request_reviewers
+    ADD COLUMN verdict    review_verdict NULL,
+    ADD COLUMN verdict_at TIMESTAMPTZ    NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE pull_request_reviewers
+    DROP COLUMN IF EXISTS verdict_at,
+    DROP COLUMN IF EXISTS verdict;
+
+DROP TYPE IF EXISTS review_verdict;
+
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose NO TRANSACTION
+-- ALTER TYPE ... ADD VALUE нельзя использовать в той же транзакции, где значение добавлено.
+
+-- +goose Up
+ALTER TYPE pr_status ADD VALUE IF NOT EXISTS 'DRAFT' BEFORE 'OPEN';
+ALTER TYPE pr_status ADD VALUE IF NOT EXISTS 'CLOSED';
+ALTER TABLE pull_requests ADD COLUMN closed_at TIMESTAMPTZ NULL;
+
+-- +goose Down
+-- +goose StatementBegin
+
+UPDATE pull_requests SET status = 'OPEN' WHERE status IN ('DRAFT', 'CLOSED');
+
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS closed_at;
+
+ALTER TYPE pr_status RENAME TO pr_status_old;
+CREATE TYPE pr_status AS ENUM ('OPEN', 'MERGED');
+
+ALTER TABLE pull_requests
+    ALTER COLUMN status DROP DEFAULT,
+    ALTER COLUMN status TYPE pr_status USING status::text::pr_status,
+    ALTER COLUMN status SET DEFAULT 'OPEN';
+
+DROP TYPE pr_status_old;
+
+-- +goose StatementEnd
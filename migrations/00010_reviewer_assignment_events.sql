@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE reviewer_assignment_events (
+                                            id               BIGSERIAL PRIMARY KEY,
+                                            pr_id            TEXT NOT NULL REFERENCES pull_requests(id) ON DELETE RESTRICT,
+                                            event_type       TEXT NOT NULL CHECK (event_type IN (
+                                                'ASSIGNED', 'UNASSIGNED', 'REPLACED', 'VERDICT', 'STATUS_CHANGED', 'MERGED'
+                                            )),
+                                            user_id          TEXT NULL,
+                                            previous_user_id TEXT NULL,
+                                            verdict          review_verdict NULL,
+                                            status           pr_status NULL,
+                                            actor_id         TEXT NULL,
+                                            reason           TEXT NOT NULL DEFAULT '',
+                                            created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_ra_events_pr ON reviewer_assignment_events(pr_id, id);
+
+-- Журнал только дописывается.
+CREATE FUNCTION reviewer_assignment_events_immutable() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'reviewer_assignment_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_ra_events_immutable
+    BEFORE UPDATE OR DELETE ON reviewer_assignment_events
+    FOR EACH ROW EXECUTE FUNCTION reviewer_assignment_events_immutable();
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TRIGGER IF EXISTS trg_ra_events_immutable ON reviewer_assignment_events;
+DROP FUNCTION IF EXISTS reviewer_assignment_events_immutable();
+DROP INDEX IF EXISTS idx_ra_events_pr;
+DROP TABLE IF EXISTS reviewer_assignment_events;
+
+-- +goose StatementEnd
@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE teams
+    ADD COLUMN review_sla_hours  INT NULL CHECK (review_sla_hours > 0),
+    ADD COLUMN sla_auto_reassign BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE pull_request_reviewers
+    ADD COLUMN sla_breached_at TIMESTAMPTZ NULL;
+
+-- кандидаты на нарушение SLA: назначения без вердикта и без отметки
+CREATE INDEX idx_reviews_sla_pending ON pull_request_reviewers(assigned_at)
+    WHERE verdict IS NULL AND sla_breached_at IS NULL;
+
+ALTER TABLE reviewer_assignment_events
+    DROP CONSTRAINT reviewer_assignment_events_event_type_check,
+    ADD CONSTRAINT reviewer_assignment_events_event_type_check CHECK (event_type IN (
+        'ASSIGNED', 'UNASSIGNED', 'REPLACED', 'VERDICT', 'STATUS_CHANGED', 'MERGED', 'SLA_BREACHED'
+    ));
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+-- журнал append-only, поэтому события SLA не удаляются, а проверка типа ослабляется только до NOT VALID
+ALTER TABLE reviewer_assignment_events
+    DROP CONSTRAINT reviewer_assignment_events_event_type_check,
+    ADD CONSTRAINT reviewer_assignment_events_event_type_check CHECK (event_type IN (
+        'ASSIGNED', 'UNASSIGNED', 'REPLACED', 'VERDICT', 'STATUS_CHANGED', 'MERGED'
+    )) NOT VALID;
+
+DROP INDEX IF EXISTS idx_reviews_sla_pending;
+
+ALTER TABLE pull_request_reviewers
+    DROP COLUMN IF EXISTS sla_breached_at;
+
+ALTER TABLE teams
+    DROP COLUMN IF EXISTS sla_auto_reassign,
+    DROP COLUMN IF EXISTS review_sla_hours;
+
+-- +goose StatementEnd
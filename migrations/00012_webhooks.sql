@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE webhook_subscriptions (
+                                       id          BIGSERIAL PRIMARY KEY,
+                                       url         TEXT NOT NULL,
+                                       secret      TEXT NOT NULL,
+                                       event_types TEXT[] NOT NULL CHECK (cardinality(event_types) > 0),
+                                       created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TYPE webhook_delivery_status AS ENUM ('PENDING', 'DELIVERED', 'DEAD');
+
+-- Outbox: по строке на пару (событие, подписка), пишется в транзакции изменения.
+CREATE TABLE webhook_deliveries (
+                                    id              BIGSERIAL PRIMARY KEY,
+                                    subscription_id BIGINT NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+                                    event_type      TEXT NOT NULL,
+                                    payload         JSONB NOT NULL,
+                                    status          webhook_delivery_status NOT NULL DEFAULT 'PENDING',
+                                    attempts        INT NOT NULL DEFAULT 0,
+                                    next_attempt_at TIMESTAMPTZ NOT NULL,
+                                    last_error      TEXT NOT NULL DEFAULT '',
+                                    created_at      TIMESTAMPTZ NOT NULL,
+                                    delivered_at    TIMESTAMPTZ NULL
+);
+
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at, id)
+    WHERE status = 'PENDING';
+CREATE INDEX idx_webhook_deliveries_dead ON webhook_deliveries(id)
+    WHERE status = 'DEAD';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TYPE IF EXISTS webhook_delivery_status;
+DROP TABLE IF EXISTS webhook_subscriptions;
+
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE forge_identities (
+                                  forge      TEXT NOT NULL CHECK (forge IN ('github', 'gitlab')),
+                                  username   TEXT NOT NULL,
+                                  user_id    TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                                  PRIMARY KEY (forge, username)
+);
+
+CREATE INDEX idx_forge_identities_user ON forge_identities(user_id);
+
+-- Принятые доставки входящих вебхуков для отсева повторов.
+CREATE TABLE forge_deliveries (
+                                  forge       TEXT NOT NULL,
+                                  delivery_id TEXT NOT NULL,
+                                  received_at TIMESTAMPTZ NOT NULL,
+                                  PRIMARY KEY (forge, delivery_id)
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS forge_deliveries;
+DROP TABLE IF EXISTS forge_identities;
+
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- SLA считается в часах по будням (пн–пт целиком, UTC), а не в рабочих часах команды
+ALTER TABLE teams
+    RENAME COLUMN review_sla_hours TO review_sla_weekday_hours;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE teams
+    RENAME COLUMN review_sla_weekday_hours TO review_sla_hours;
+
+-- +goose StatementEnd
@@ -0,0 +1,174 @@
+// Package codeowners разбирает файлы CODEOWNERS (GitHub/GitLab) и сопоставляет пути с шаблонами.
+package codeowners
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+var ErrInvalidPattern = errors.New("invalid CODEOWNERS pattern")
+
+// Rule — одна строка CODEOWNERS: шаблон и его владельцы в исходном виде (@user, @org/team, email).
+type Rule struct {
+	Pattern string
+	Owners  []string
+	Line    int
+}
+
+// ParseError указывает строку файла с некорректным шаблоном; Err оборачивает ErrInvalidPattern.
+// Ошибки чтения Parse возвращает как есть, без ParseError.
+type ParseError struct {
+	Line int
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Parse читает CODEOWNERS и возвращает правила в порядке следования.
+// Пустые строки, комментарии и заголовки секций GitLab ([Section]) пропускаются.
+// Некорректный шаблон — *ParseError; ошибка чтения, в том числе bufio.ErrTooLong
+// для слишком длинной строки, возвращается обёрнутой как есть.
+func Parse(r io.Reader) ([]Rule, error) {
+	var rules []Rule
+
+	sc := bufio.NewScanner(r)
+	line := 0
+	for sc.Scan() {
+		line++
+		text := stripComment(sc.Text())
+		if text == "" {
+			continue
+		}
+		if strings.HasPrefix(text, "[") || strings.HasPrefix(text, "^[") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		pattern := strings.ReplaceAll(fields[0], `\#`, "#")
+		if _, err := Compile(pattern); err != nil {
+			return nil, &ParseError{Line: line, Err: err}
+		}
+
+		rules = append(rules, Rule{
+			Pattern: pattern,
+			Owners:  fields[1:],
+			Line:    line,
+		})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("read CODEOWNERS after line %d: %w", line, err)
+	}
+
+	return rules, nil
+}
+
+// stripComment отрезает комментарий: '#' в начале строки или после пробела; `\#` — литерал.
+func stripComment(s string) string {
+	for i := 0; i < len(s); i++ {
+		if s[i] != '#' {
+			continue
+		}
+		if i > 0 && s[i-1] == '\\' {
+			continue
+		}
+		if i == 0 || s[i-1] == ' ' || s[i-1] == '\t' {
+			s = s[:i]
+			break
+		}
+	}
+	return strings.TrimSpace(s)
+}
+
+// Pattern — скомпилированный шаблон пути.
+type Pattern struct {
+	raw string
+	re  *regexp.Regexp
+}
+
+// Compile компилирует шаблон по правилам CODEOWNERS:
+//   - ведущий "/" или "/" в середине привязывает шаблон к корню репозитория;
+//   - шаблон без "/" совпадает на любой глубине;
+//   - "*" и "?" не пересекают "/", "**" — пересекает;
+//   - шаблон совпадает с каталогом и всем его содержимым,
+//     кроме шаблонов вида "dir/*", которые не спускаются во вложенные каталоги.
+func Compile(pattern string) (*Pattern, error) {
+	p := strings.TrimSpace(pattern)
+	if p == "" || strings.HasPrefix(p, "!") || strings.ContainsAny(p, "[]") {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidPattern, pattern)
+	}
+
+	dirOnly := strings.HasSuffix(p, "/")
+	p = strings.TrimSuffix(p, "/")
+	anchored := strings.HasPrefix(p, "/") || strings.Contains(p, "/")
+	p = strings.TrimPrefix(p, "/")
+	if p == "" {
+		// "/" — весь репозиторий.
+		p, anchored, dirOnly = "**", true, false
+	}
+
+	var b strings.Builder
+	if anchored {
+		b.WriteString("^")
+	} else {
+		b.WriteString("^(?:.*/)?")
+	}
+
+	for i := 0; i < len(p); i++ {
+		switch c := p[i]; {
+		case c == '*' && strings.HasPrefix(p[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case c == '*' && strings.HasPrefix(p[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	switch {
+	case dirOnly:
+		b.WriteString("/.*$")
+	case strings.HasSuffix(p, "/*"):
+		b.WriteString("$")
+	default:
+		b.WriteString("(?:/.*)?$")
+	}
+
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q: %s", ErrInvalidPattern, pattern, err.Error())
+	}
+
+	return &Pattern{raw: pattern, re: re}, nil
+}
+
+// Match сообщает, попадает ли путь (относительно корня репозитория) под шаблон.
+func (p *Pattern) Match(path string) bool {
+	return p.re.MatchString(NormalizePath(path))
+}
+
+func (p *Pattern) String() string {
+	return p.raw
+}
+
+// NormalizePath приводит путь к виду без ведущих "./" и "/".
+func NormalizePath(path string) string {
+	path = strings.TrimSpace(path)
+	path = strings.TrimPrefix(path, "./")
+	return strings.TrimLeft(path, "/")
+}
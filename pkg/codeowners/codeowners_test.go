@@ -0,0 +1,161 @@
+package codeowners_test
+
+import (
+	"bufio"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/zxchelik/avito-test-task/pkg/codeowners"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		match   []string
+		noMatch []string
+	}{
+		// без "/" — на любой глубине, вместе с содержимым каталога
+		{"README.md", []string{"README.md", "docs/README.md", "README.md/x"}, []string{"README.mdx", "xREADME.md"}},
+		// "*" не пересекает "/"
+		{"*.go", []string{"main.go", "cmd/server/main.go"}, []string{"main.go.txt", "main.goo"}},
+		{"/src/*.go", []string{"src/main.go"}, []string{"src/pkg/main.go", "main.go"}},
+		{"docs/*", []string{"docs/a.md"}, []string{"docs/sub/a.md", "docs"}},
+		{"?.txt", []string{"a.txt", "x/b.txt"}, []string{"ab.txt", "/.txt"}},
+		// "**" пересекает "/", в том числе ноль каталогов
+		{"/src/**/test.go", []string{"src/test.go", "src/a/test.go", "src/a/b/test.go"}, []string{"test.go", "lib/src/test.go"}},
+		{"**/logs", []string{"logs", "logs/app.log", "a/b/logs/app.log"}, []string{"mylogs", "logs.txt"}},
+		{"src/**", []string{"src/a", "src/a/b/c.go"}, []string{"lib/src/a"}},
+		// ведущий "/" или "/" в середине привязывает шаблон к корню
+		{"/build", []string{"build", "build/out.bin"}, []string{"app/build", "builds"}},
+		{"apps/web", []string{"apps/web", "apps/web/index.ts"}, []string{"x/apps/web", "apps/webapp"}},
+		{"build", []string{"build", "app/build/out.bin"}, []string{"builds"}},
+		// шаблон каталога совпадает только с содержимым
+		{"docs/", []string{"docs/a.md", "docs/sub/a.md", "lib/docs/a.md"}, []string{"docs", "docs.md"}},
+		{"/docs/", []string{"docs/a.md"}, []string{"lib/docs/a.md", "docs"}},
+		// "/" — весь репозиторий
+		{"/", []string{"a", "a/b/c"}, nil},
+		// спецсимволы регулярных выражений — литералы
+		{"a+b.txt", []string{"a+b.txt"}, []string{"aab.txt", "a+bxtxt"}},
+		{"#notes.md", []string{"#notes.md", "docs/#notes.md"}, []string{"notes.md"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			p, err := codeowners.Compile(tt.pattern)
+			if err != nil {
+				t.Fatalf("compile: %v", err)
+			}
+			for _, path := range tt.match {
+				if !p.Match(path) {
+					t.Errorf("%q does not match %q", tt.pattern, path)
+				}
+			}
+			for _, path := range tt.noMatch {
+				if p.Match(path) {
+					t.Errorf("%q matches %q", tt.pattern, path)
+				}
+			}
+		})
+	}
+}
+
+func TestMatchNormalizesPath(t *testing.T) {
+	p, err := codeowners.Compile("/src/*.go")
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	for _, path := range []string{"src/main.go", "./src/main.go", "/src/main.go", " src/main.go "} {
+		if !p.Match(path) {
+			t.Errorf("%q does not match %q", p, path)
+		}
+	}
+}
+
+func TestCompileInvalid(t *testing.T) {
+	for _, pattern := range []string{"", "  ", "!vendor/", "[ab].go", "src/]"} {
+		t.Run(pattern, func(t *testing.T) {
+			if _, err := codeowners.Compile(pattern); !errors.Is(err, codeowners.ErrInvalidPattern) {
+				t.Fatalf("got %v, want ErrInvalidPattern", err)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	const file = `# владельцы по умолчанию
+*       @org/platform
+
+# GitLab-секции пропускаются
+[Docs]
+^[Optional docs]
+/docs/  @alice   docs@example.com   # хвостовой комментарий
+docs/\#notes.md @bob
+	*.go	@carol  @org/backend
+src/a#b @dave
+`
+	rules, err := codeowners.Parse(strings.NewReader(file))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	want := []codeowners.Rule{
+		{Pattern: "*", Owners: []string{"@org/platform"}, Line: 2},
+		{Pattern: "/docs/", Owners: []string{"@alice", "docs@example.com"}, Line: 7},
+		{Pattern: "docs/#notes.md", Owners: []string{"@bob"}, Line: 8},
+		{Pattern: "*.go", Owners: []string{"@carol", "@org/backend"}, Line: 9},
+		// "#" не после пробела — часть шаблона, а не комментарий
+		{Pattern: "src/a#b", Owners: []string{"@dave"}, Line: 10},
+	}
+	if len(rules) != len(want) {
+		t.Fatalf("got %d rules, want %d: %+v", len(rules), len(want), rules)
+	}
+	for i, w := range want {
+		r := rules[i]
+		if r.Pattern != w.Pattern || !slices.Equal(r.Owners, w.Owners) || r.Line != w.Line {
+			t.Errorf("rule %d = %+v, want %+v", i, r, w)
+		}
+	}
+}
+
+func TestParseWithoutOwners(t *testing.T) {
+	// правило без владельцев снимает владение с путей, совпавших с ним последними
+	rules, err := codeowners.Parse(strings.NewReader("*  @alice\n/generated/\n"))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(rules) != 2 || rules[1].Pattern != "/generated/" || len(rules[1].Owners) != 0 {
+		t.Fatalf("rules = %+v", rules)
+	}
+}
+
+func TestParseInvalidPattern(t *testing.T) {
+	_, err := codeowners.Parse(strings.NewReader("*  @alice\n\n!vendor/  @bob\n"))
+
+	var perr *codeowners.ParseError
+	if !errors.As(err, &perr) {
+		t.Fatalf("got %v, want *ParseError", err)
+	}
+	if perr.Line != 3 || !errors.Is(err, codeowners.ErrInvalidPattern) {
+		t.Fatalf("got %v (line %d), want ErrInvalidPattern on line 3", err, perr.Line)
+	}
+}
+
+func TestParseReadError(t *testing.T) {
+	long := "*  @alice\n" + strings.Repeat("a", bufio.MaxScanTokenSize) + "  @bob\n"
+	_, err := codeowners.Parse(strings.NewReader(long))
+
+	var perr *codeowners.ParseError
+	if errors.As(err, &perr) || errors.Is(err, codeowners.ErrInvalidPattern) {
+		t.Fatalf("read error reported as a bad pattern: %v", err)
+	}
+	if !errors.Is(err, bufio.ErrTooLong) {
+		t.Fatalf("got %v, want bufio.ErrTooLong", err)
+	}
+
+	errRead := errors.New("read failed")
+	if _, err := codeowners.Parse(iotest.ErrReader(errRead)); !errors.Is(err, errRead) {
+		t.Fatalf("got %v, want the reader's error", err)
+	}
+}
@@ -13,6 +13,11 @@ const (
 	envDev   EnvString = "dev"
 )
 
+// Dev сообщает, что окружение локальное или dev.
+func (e EnvString) Dev() bool {
+	return e == envLocal || e == envDev
+}
+
 // New создаёт и возвращает *slog.Logger в зависимости от env.
 // env: "local" -> TextHandler debug, "dev" -> JSON debug, "prod" -> JSON info.
 func New(env EnvString) *slog.Logger {